@@ -163,6 +163,8 @@ type fetchThread struct {
 
 func (fth *fetchThread) Launch() error {
 	return fth.LaunchImpl(func() int {
+		rs := urcu.NewReadSide()
+		defer rs.Close()
 		return int(C.FetchThread_Run(fth.c))
 	})
 }
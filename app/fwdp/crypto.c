@@ -6,6 +6,179 @@ INIT_ZF_LOG(FwCrypto);
 
 #define FW_CRYPTO_BURST_SIZE 16
 
+static inline FwCryptoOpPriv*
+FwCrypto_GetOpPriv(struct rte_crypto_op* op)
+{
+  return __rte_crypto_op_get_priv_data(op, sizeof(FwCryptoOpPriv));
+}
+
+static void
+FwCrypto_Dispatch(FwCrypto* fwc, Packet* npkt)
+{
+  if (Packet_GetL3PktType(npkt) == L3PktType_Interest) {
+    PInterest* interest = Packet_GetInterestHdr(npkt);
+    InputDemux_Dispatch(&fwc->outputI, npkt, &interest->name);
+  } else {
+    PData* data = Packet_GetDataHdr(npkt);
+    InputDemux_Dispatch(&fwc->output, npkt, &data->name);
+  }
+}
+
+static void
+FwCrypto_DropSigFail(FwCrypto* fwc, Packet* npkt)
+{
+  if (Packet_GetL3PktType(npkt) == L3PktType_Interest) {
+    ++fwc->nInterestSigFails;
+  } else {
+    ++fwc->nDataSigFails;
+  }
+  rte_pktmbuf_free(Packet_ToMbuf(npkt));
+}
+
+/** \brief Create indirect mbufs over signed portion of a packet.
+ *  \return a chain of indirect mbufs, or NULL on allocation failure.
+ */
+static struct rte_mbuf*
+FwCrypto_MakeSignedPortion(FwCrypto* fwc,
+                           struct rte_mbuf* pkt,
+                           const PSignature* sig)
+{
+  struct rte_mbuf* head = NULL;
+  for (int i = 0; i < 2; ++i) {
+    if (sig->signedLen[i] == 0) {
+      continue;
+    }
+
+    MbufLoc ml;
+    MbufLoc_Init(&ml, pkt);
+    MbufLoc_Advance(&ml, sig->signedOff[i]);
+    struct rte_mbuf* m =
+      MbufLoc_MakeIndirect(&ml, sig->signedLen[i], fwc->indirectMp);
+    if (unlikely(m == NULL)) {
+      rte_pktmbuf_free(head);
+      return NULL;
+    }
+
+    if (head == NULL) {
+      head = m;
+    } else if (unlikely(rte_pktmbuf_chain(head, m) != 0)) {
+      rte_pktmbuf_free(head);
+      rte_pktmbuf_free(m);
+      return NULL;
+    }
+  }
+  return head;
+}
+
+/** \brief Prepare signature verification of an Interest or Data.
+ *  \return whether \p op is populated; otherwise, \p npkt has been dispatched or dropped.
+ */
+static bool
+FwCrypto_PrepareVerify(FwCrypto* fwc, Packet* npkt, struct rte_crypto_op* op)
+{
+  struct rte_mbuf* pkt = Packet_ToMbuf(npkt);
+  bool isInterest = Packet_GetL3PktType(npkt) == L3PktType_Interest;
+
+  PSignature sig;
+  uint8_t keyNameV[NAME_MAX_LENGTH];
+  NdnError e = isInterest ? PSignature_FromInterest(&sig, pkt, keyNameV)
+                          : PSignature_FromData(&sig, pkt, keyNameV);
+  if (unlikely(e != NdnError_OK)) {
+    ZF_LOGD("npkt=%p drop=bad-signature error=%d", npkt, e);
+    FwCrypto_DropSigFail(fwc, npkt);
+    return false;
+  }
+
+  if (!isInterest && sig.sigType != SigType_Sha256WithEcdsa &&
+      sig.sigType != SigType_HmacWithSha256) {
+    // Data with other signature types are not verified
+    Packet_GetDataHdr(npkt)->sigChecked = true;
+    FwCrypto_Dispatch(fwc, npkt);
+    return false;
+  }
+
+  LName keyName = { .length = sig.keyNameL, .value = keyNameV };
+  const FwTrustAnchor* anchor =
+    FwVerifier_FindAnchor(fwc->verifier, sig.sigType, keyName);
+  if (unlikely(anchor == NULL)) {
+    ZF_LOGD("npkt=%p drop=no-trust-anchor sig-type=%" PRIu8, npkt, sig.sigType);
+    FwCrypto_DropSigFail(fwc, npkt);
+    return false;
+  }
+  if (unlikely(anchor->sigType == SigType_HmacWithSha256 && sig.valueL != 32)) {
+    ZF_LOGD("npkt=%p drop=bad-hmac-length", npkt);
+    FwCrypto_DropSigFail(fwc, npkt);
+    return false;
+  }
+
+  struct rte_mbuf* signedPortion = FwCrypto_MakeSignedPortion(fwc, pkt, &sig);
+  if (unlikely(signedPortion == NULL)) {
+    ZF_LOGW("npkt=%p FwCrypto_MakeSignedPortion fail", npkt);
+    ++fwc->nDrops;
+    rte_pktmbuf_free(pkt);
+    return false;
+  }
+
+  FwCryptoOpPriv* priv = FwCrypto_GetOpPriv(op);
+  priv->kind = FwCryptoOpKind_Verify;
+  priv->npkt = npkt;
+  priv->anchor = anchor;
+  priv->sigL = sig.valueL;
+  rte_memcpy(priv->sig, sig.value, sig.valueL);
+
+  uint32_t signedLen = PSignature_GetSignedLength(&sig);
+  if (anchor->sigType == SigType_HmacWithSha256) {
+    rte_crypto_op_attach_sym_session(op, anchor->hmacSession);
+    op->sym->m_src = signedPortion;
+    op->sym->auth.data.offset = 0;
+    op->sym->auth.data.length = signedLen;
+    op->sym->auth.digest.data = priv->sig;
+  } else {
+    CryptoOp_PrepareSha256Digest(op, signedPortion, 0, signedLen, priv->digest);
+  }
+  return true;
+}
+
+/** \brief Finish a crypto operation.
+ *  \param op a dequeued or unsubmitted crypto_op; will be freed.
+ *  \return the packet to be dispatched, or NULL if it has been dropped.
+ */
+static Packet*
+FwCrypto_Finish(FwCrypto* fwc, struct rte_crypto_op* op)
+{
+  FwCryptoOpPriv* priv = FwCrypto_GetOpPriv(op);
+  if (priv->kind == FwCryptoOpKind_Digest) {
+    return DataDigest_Finish(op);
+  }
+
+  Packet* npkt = priv->npkt;
+  bool isProcessed = op->status != RTE_CRYPTO_OP_STATUS_NOT_PROCESSED;
+  bool ok = op->status == RTE_CRYPTO_OP_STATUS_SUCCESS;
+  if (ok && priv->anchor->sigType == SigType_Sha256WithEcdsa) {
+    ok = FwTrustAnchor_VerifyEcdsa(
+      priv->anchor, priv->digest, priv->sig, priv->sigL);
+  }
+  rte_pktmbuf_free(op->sym->m_src);
+  rte_crypto_op_free(op);
+
+  if (unlikely(!ok)) {
+    if (isProcessed) {
+      ZF_LOGD("npkt=%p drop=verify-fail", npkt);
+      FwCrypto_DropSigFail(fwc, npkt);
+    } else {
+      rte_pktmbuf_free(Packet_ToMbuf(npkt));
+    }
+    return NULL;
+  }
+
+  if (Packet_GetL3PktType(npkt) == L3PktType_Interest) {
+    Packet_GetInterestHdr(npkt)->sigChecked = true;
+  } else {
+    Packet_GetDataHdr(npkt)->sigChecked = true;
+  }
+  return npkt;
+}
+
 static void
 FwCrypto_InputEnqueue(FwCrypto* fwc,
                       const CryptoQueuePair* cqp,
@@ -18,7 +191,7 @@ FwCrypto_InputEnqueue(FwCrypto* fwc,
 
   uint16_t nEnq = rte_cryptodev_enqueue_burst(cqp->dev, cqp->qp, ops, count);
   for (uint16_t i = nEnq; i < count; ++i) {
-    Packet* npkt = DataDigest_Finish(ops[i]);
+    Packet* npkt = FwCrypto_Finish(fwc, ops[i]);
     RTE_ASSERT(npkt == NULL);
     RTE_SET_USED(npkt);
     ++fwc->nDrops;
@@ -44,20 +217,39 @@ FwCrypto_Input(FwCrypto* fwc)
     return;
   }
 
-  uint16_t posS = 0, posM = nDeq;
+  struct rte_crypto_op* opsS[FW_CRYPTO_BURST_SIZE];
+  struct rte_crypto_op* opsM[FW_CRYPTO_BURST_SIZE];
+  uint16_t nUsed = 0, nS = 0, nM = 0;
   for (uint16_t i = 0; i < nDeq; ++i) {
     Packet* npkt = npkts[i];
-    struct rte_mbuf* pkt = Packet_ToMbuf(npkts[i]);
-    if (likely(pkt->nb_segs == 1)) {
-      DataDigest_Prepare(npkt, ops[posS++]);
+    struct rte_crypto_op* op = ops[nUsed];
+    bool needVerify =
+      fwc->verifier != NULL &&
+      (Packet_GetL3PktType(npkt) == L3PktType_Interest ||
+       (fwc->verifier->verifyData && !Packet_GetDataHdr(npkt)->sigChecked));
+    if (needVerify) {
+      if (!FwCrypto_PrepareVerify(fwc, npkt, op)) {
+        continue;
+      }
     } else {
-      DataDigest_Prepare(npkt, ops[--posM]);
+      FwCrypto_GetOpPriv(op)->kind = FwCryptoOpKind_Digest;
+      DataDigest_Prepare(npkt, op);
     }
+
+    ++nUsed;
+    if (likely(op->sym->m_src->nb_segs == 1)) {
+      opsS[nS++] = op;
+    } else {
+      opsM[nM++] = op;
+    }
+  }
+
+  for (uint16_t i = nUsed; i < nDeq; ++i) {
+    rte_crypto_op_free(ops[i]);
   }
-  assert(posS == posM);
 
-  FwCrypto_InputEnqueue(fwc, &fwc->singleSeg, ops, posS);
-  FwCrypto_InputEnqueue(fwc, &fwc->multiSeg, &ops[posM], nDeq - posM);
+  FwCrypto_InputEnqueue(fwc, &fwc->singleSeg, opsS, nS);
+  FwCrypto_InputEnqueue(fwc, &fwc->multiSeg, opsM, nM);
 }
 
 static void
@@ -70,16 +262,14 @@ FwCrypto_Output(FwCrypto* fwc, const CryptoQueuePair* cqp)
   Packet* npkts[FW_CRYPTO_BURST_SIZE];
   uint16_t nFinish = 0;
   for (uint16_t i = 0; i < nDeq; ++i) {
-    npkts[nFinish] = DataDigest_Finish(ops[i]);
+    npkts[nFinish] = FwCrypto_Finish(fwc, ops[i]);
     if (likely(npkts[nFinish] != NULL)) {
       ++nFinish;
     }
   }
 
   for (uint16_t i = 0; i < nFinish; ++i) {
-    Packet* npkt = npkts[i];
-    PData* data = Packet_GetDataHdr(npkt);
-    InputDemux_Dispatch(&fwc->output, npkt, &data->name);
+    FwCrypto_Dispatch(fwc, npkts[i]);
   }
 }
 
@@ -87,14 +277,15 @@ void
 FwCrypto_Run(FwCrypto* fwc)
 {
   ZF_LOGI("fwc=%p input=%p pool=%p cryptodev-single=%" PRIu8 "-%" PRIu16
-          " cryptodev-multi=%" PRIu8 "-%" PRIu16,
+          " cryptodev-multi=%" PRIu8 "-%" PRIu16 " verifier=%p",
           fwc,
           fwc->input,
           fwc->opPool,
           fwc->singleSeg.dev,
           fwc->singleSeg.qp,
           fwc->multiSeg.dev,
-          fwc->multiSeg.qp);
+          fwc->multiSeg.qp,
+          fwc->verifier);
   while (ThreadStopFlag_ShouldContinue(&fwc->stop)) {
     FwCrypto_Output(fwc, &fwc->singleSeg);
     FwCrypto_Output(fwc, &fwc->multiSeg);
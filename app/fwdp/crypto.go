@@ -9,6 +9,7 @@ import (
 	"unsafe"
 
 	"ndn-dpdk/app/inputdemux"
+	"ndn-dpdk/appinit"
 	"ndn-dpdk/container/ndt"
 	"ndn-dpdk/dpdk"
 )
@@ -16,15 +17,18 @@ import (
 type CryptoConfig struct {
 	InputCapacity  int
 	OpPoolCapacity int
+	Verify         VerifyConfig // signature verification
 }
 
 type Crypto struct {
 	dpdk.ThreadBase
-	id     int
-	c      *C.FwCrypto
-	demuxD inputdemux.Demux
-	devS   dpdk.CryptoDev
-	devM   dpdk.CryptoDev
+	id       int
+	c        *C.FwCrypto
+	demuxD   inputdemux.Demux
+	demuxI   inputdemux.Demux
+	devS     dpdk.CryptoDev
+	devM     dpdk.CryptoDev
+	verifier *verifier
 }
 
 func newCrypto(id int, lc dpdk.LCore, cfg CryptoConfig, ndt *ndt.Ndt, fwds []*Fwd) (fwc *Crypto, e error) {
@@ -43,13 +47,16 @@ func newCrypto(id int, lc dpdk.LCore, cfg CryptoConfig, ndt *ndt.Ndt, fwds []*Fw
 		fwc.c.input = (*C.struct_rte_ring)(input.GetPtr())
 	}
 
-	opPool, e := dpdk.NewCryptoOpPool(fwc.String()+"_pool", cfg.OpPoolCapacity, 0, socket)
+	opPool, e := dpdk.NewCryptoOpPool(fwc.String()+"_pool", cfg.OpPoolCapacity, C.sizeof_FwCryptoOpPriv, socket)
 	if e != nil {
 		return nil, fmt.Errorf("dpdk.NewCryptoOpPool: %v", e)
 	} else {
 		fwc.c.opPool = (*C.struct_rte_mempool)(opPool.GetPtr())
 	}
 
+	indirectMp := appinit.MakePktmbufPool(appinit.MP_IND, socket)
+	fwc.c.indirectMp = (*C.struct_rte_mempool)(indirectMp.GetPtr())
+
 	fwc.devS, e = dpdk.CryptoDevDriverPref_SingleSeg.Create(fmt.Sprintf("fwc%ds", fwc.id), 1, socket)
 	if e != nil {
 		return nil, fmt.Errorf("dpdk.CryptoDevDriverPref_SingleSeg.Create: %v", e)
@@ -66,11 +73,22 @@ func newCrypto(id int, lc dpdk.LCore, cfg CryptoConfig, ndt *ndt.Ndt, fwds []*Fw
 		qp.CopyToC(unsafe.Pointer(&fwc.c.multiSeg))
 	}
 
+	if cfg.Verify.isEnabled() {
+		if fwc.verifier, e = newVerifier(cfg.Verify, []dpdk.CryptoDev{fwc.devS, fwc.devM}, socket); e != nil {
+			return nil, fmt.Errorf("newVerifier: %v", e)
+		}
+		fwc.c.verifier = fwc.verifier.c
+	}
+
 	fwc.demuxD = inputdemux.DemuxFromPtr(unsafe.Pointer(&fwc.c.output))
 	fwc.demuxD.InitNdt(ndt, id)
+	fwc.demuxI = inputdemux.DemuxFromPtr(unsafe.Pointer(&fwc.c.outputI))
+	fwc.demuxI.InitNdt(ndt, id)
 	for i, fwd := range fwds {
 		fwc.demuxD.SetDest(i, fwd.dataQueue)
+		fwc.demuxI.SetDest(i, fwd.interestQueue)
 		fwd.c.crypto = fwc.c.input
+		fwd.c.verifier = fwc.c.verifier
 	}
 
 	return fwc, nil
@@ -92,6 +110,9 @@ func (fwc *Crypto) Stop() error {
 }
 
 func (fwc *Crypto) Close() error {
+	if fwc.verifier != nil {
+		fwc.verifier.Close()
+	}
 	fwc.devM.Close()
 	fwc.devS.Close()
 	dpdk.MempoolFromPtr(unsafe.Pointer(fwc.c.opPool)).Close()
@@ -5,6 +5,27 @@
 
 #include "../../app/inputdemux/demux.h"
 #include "../../dpdk/thread.h"
+#include "verify.h"
+
+/** \brief Kind of crypto operation.
+ */
+typedef enum FwCryptoOpKind
+{
+  FwCryptoOpKind_Digest, ///< compute Data implicit digest
+  FwCryptoOpKind_Verify, ///< verify signature
+} FwCryptoOpKind;
+
+/** \brief Private data of crypto operation.
+ */
+typedef struct FwCryptoOpPriv
+{
+  Packet* npkt;                 ///< Interest or Data being verified
+  const FwTrustAnchor* anchor;  ///< trust anchor used for verification
+  uint8_t kind;                 ///< FwCryptoOpKind
+  uint8_t sigL;                 ///< SignatureValue length
+  uint8_t sig[SIG_MAX_VALUE_LENGTH]; ///< SignatureValue
+  uint8_t digest[32];           ///< digest of signed portion, for ECDSA
+} FwCryptoOpPriv;
 
 /** \brief Forwarder data plane, crypto helper.
  */
@@ -12,9 +33,15 @@ typedef struct FwCrypto
 {
   struct rte_ring* input;
   struct rte_mempool* opPool;
-  InputDemux output;
+  struct rte_mempool* indirectMp; ///< mempool for signed portion indirect mbufs
+  InputDemux output;              ///< output demuxer for Data
+  InputDemux outputI;             ///< output demuxer for Interests
+
+  const FwVerifier* verifier; ///< signature verification settings, may be NULL
 
   uint64_t nDrops;
+  uint64_t nInterestSigFails; ///< Interests dropped due to verification failure
+  uint64_t nDataSigFails;     ///< Data dropped due to verification failure
 
   CryptoQueuePair singleSeg; ///< CryptoDev for single-segment packets
   CryptoQueuePair multiSeg;  ///< CryptoDev for multi-segment packets
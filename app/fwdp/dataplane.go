@@ -26,6 +26,7 @@ type Config struct {
 	Suppress pit.SuppressConfig // PIT suppression config
 
 	Crypto            CryptoConfig
+	Disk              DiskConfig // DiskStore config, as second-tier CS
 	FwdInterestQueue  pktqueue.Config
 	FwdDataQueue      pktqueue.Config
 	FwdNackQueue      pktqueue.Config
@@ -39,6 +40,7 @@ type DataPlane struct {
 	fib    *fib.Fib
 	inputs []*Input
 	crypto *Crypto
+	disk   *disk
 	fwds   []*Fwd
 }
 
@@ -46,6 +48,7 @@ func New(cfg Config) (dp *DataPlane, e error) {
 	dp = new(DataPlane)
 
 	dp.la.Allocator = &dpdk.LCoreAlloc
+	dp.la.EnableDisk = cfg.Disk.isEnabled()
 	if e = dp.la.Alloc(); e != nil {
 		return nil, e
 	}
@@ -76,6 +79,17 @@ func New(cfg Config) (dp *DataPlane, e error) {
 		dp.fwds = append(dp.fwds, fwd)
 	}
 
+	if cfg.Disk.isEnabled() {
+		if dp.disk, e = newDisk(cfg.Disk, dp.la.Disk); e != nil {
+			dp.Close()
+			return nil, fmt.Errorf("newDisk: %v", e)
+		}
+		if e = dp.disk.attach(dp); e != nil {
+			dp.Close()
+			return nil, fmt.Errorf("disk.attach: %v", e)
+		}
+	}
+
 	for i, lc := range dp.la.Inputs {
 		fwi := newInput(i, lc, dp.ndt, dp.fwds)
 		dp.inputs = append(dp.inputs, fwi)
@@ -127,6 +141,9 @@ func (dp *DataPlane) Close() error {
 	for _, fwi := range dp.inputs {
 		fwi.Close()
 	}
+	if dp.disk != nil {
+		dp.disk.Close()
+	}
 	if dp.fib != nil {
 		dp.fib.Close()
 	}
@@ -0,0 +1,112 @@
+package fwdp
+
+/*
+#include "fwd.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+
+	"ndn-dpdk/appinit"
+	"ndn-dpdk/container/cs"
+	"ndn-dpdk/container/diskstore"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/spdk"
+)
+
+// DiskStore config.
+type DiskConfig struct {
+	File           string // backing file of AIO bdev; if empty, use malloc bdev
+	MallocBlocks   int    // number of blocks in malloc bdev; if zero and File is empty, DiskStore is disabled
+	NBlocksPerSlot int    // number of blocks per slot; default is 16
+	ReplyCapacity  int    // capacity of DiskStore reply queue per fwd; default is 1024
+}
+
+func (cfg DiskConfig) isEnabled() bool {
+	return cfg.File != "" || cfg.MallocBlocks > 0
+}
+
+// Second-tier CS storage shared by all fwds.
+type disk struct {
+	cfg   DiskConfig
+	bdi   spdk.BdevInfo
+	store *diskstore.DiskStore
+}
+
+func newDisk(cfg DiskConfig, lc dpdk.LCore) (d *disk, e error) {
+	if cfg.NBlocksPerSlot <= 0 {
+		cfg.NBlocksPerSlot = 16
+	}
+	if cfg.ReplyCapacity <= 0 {
+		cfg.ReplyCapacity = 1024
+	}
+	if lc == dpdk.LCORE_INVALID {
+		return nil, errors.New("no lcore available for DISK")
+	}
+
+	mp := appinit.MakePktmbufPool(appinit.MP_DISK, lc.GetNumaSocket())
+	if cfg.NBlocksPerSlot*diskstore.BLOCK_SIZE > mp.GetDataroom()-dpdk.MBUF_DEFAULT_HEADROOM {
+		return nil, fmt.Errorf("NBlocksPerSlot %d exceeds %s mempool dataroom", cfg.NBlocksPerSlot, appinit.MP_DISK)
+	}
+
+	if e = spdk.Init(lc); e != nil {
+		return nil, fmt.Errorf("spdk.Init: %v", e)
+	}
+	spdk.InitBdevLib()
+
+	d = new(disk)
+	d.cfg = cfg
+	if cfg.File != "" {
+		d.bdi, e = spdk.NewAioBdev(cfg.File, diskstore.BLOCK_SIZE)
+	} else {
+		d.bdi, e = spdk.NewMallocBdev(diskstore.BLOCK_SIZE, cfg.MallocBlocks)
+	}
+	if e != nil {
+		return nil, fmt.Errorf("spdk bdev: %v", e)
+	}
+
+	if d.store, e = diskstore.New(d.bdi, spdk.MainThread, mp, cfg.NBlocksPerSlot); e != nil {
+		d.destroyBdev()
+		return nil, fmt.Errorf("diskstore.New: %v", e)
+	}
+	return d, nil
+}
+
+// Attach DiskStore to fwds' CS, assigning each fwd a distinct range of slot numbers.
+func (d *disk) attach(dp *DataPlane) error {
+	slotMin, slotMax := d.store.GetSlotIdRange()
+	nSlotsPerFwd := (slotMax - slotMin + 1) / uint64(len(dp.fwds))
+	if nSlotsPerFwd == 0 {
+		return errors.New("DiskStore has insufficient slots")
+	}
+
+	for i, fwd := range dp.fwds {
+		reply, e := dpdk.NewRing(fwd.String()+"_disk", d.cfg.ReplyCapacity, fwd.GetNumaSocket(), false, true)
+		if e != nil {
+			return fmt.Errorf("dpdk.NewRing: %v", e)
+		}
+		fwd.c.diskReply = (*C.struct_rte_ring)(reply.GetPtr())
+
+		fwdSlotMin := slotMin + uint64(i)*nSlotsPerFwd
+		theCs := cs.Cs{dp.GetFwdPcct(i)}
+		if e = theCs.SetDiskStore(d.store, fwdSlotMin, fwdSlotMin+nSlotsPerFwd-1, reply.GetCapacity()); e != nil {
+			return fmt.Errorf("Cs.SetDiskStore(%s): %v", fwd, e)
+		}
+	}
+	return nil
+}
+
+func (d *disk) destroyBdev() {
+	if d.cfg.File != "" {
+		spdk.DestroyAioBdev(d.bdi)
+	} else {
+		spdk.DestroyMallocBdev(d.bdi)
+	}
+}
+
+func (d *disk) Close() error {
+	d.store.Close()
+	d.destroyBdev()
+	return nil
+}
@@ -14,8 +14,10 @@ FwFwd_DataUnsolicited(FwFwd* fwd, FwFwdCtx* ctx)
   ctx->pkt = NULL;
 }
 
+/** \brief Pass Data to crypto helper, for digest computation or signature verification.
+ */
 static void
-FwFwd_DataNeedDigest(FwFwd* fwd, FwFwdCtx* ctx)
+FwFwd_DataToCrypto(FwFwd* fwd, FwFwdCtx* ctx)
 {
   if (unlikely(fwd->crypto == NULL)) {
     ZF_LOGD("^ error=crypto-unavailable");
@@ -27,6 +29,7 @@ FwFwd_DataNeedDigest(FwFwd* fwd, FwFwdCtx* ctx)
   int res = rte_ring_enqueue(fwd->crypto, ctx->npkt);
   if (unlikely(res != 0)) {
     ZF_LOGD("^ error=crypto-enqueue-error-%d", res);
+    ++fwd->nCryptoFull;
     rte_pktmbuf_free(ctx->pkt);
     FwFwd_NULLize(ctx->pkt);
   } else {
@@ -100,8 +103,14 @@ FwFwd_RxData(FwFwd* fwd, FwFwdCtx* ctx)
     FwFwd_DataUnsolicited(fwd, ctx);
     return;
   }
+  if (unlikely(fwd->verifier != NULL && fwd->verifier->verifyData &&
+               !Packet_GetDataHdr(ctx->npkt)->sigChecked)) {
+    ZF_LOGD("^ need-verify");
+    FwFwd_DataToCrypto(fwd, ctx);
+    return;
+  }
   if (PitFindResult_Is(pitFound, PIT_FIND_NEED_DIGEST)) {
-    FwFwd_DataNeedDigest(fwd, ctx);
+    FwFwd_DataToCrypto(fwd, ctx);
     return;
   }
 
@@ -6,6 +6,23 @@
 
 INIT_ZF_LOG(FwFwd);
 
+// Reject downstream face and DOWN faces, return number of remaining nexthops.
+static int
+FwFwd_InterestFilterNexthops(FibNexthopFilter* nhFlt,
+                             const FibEntry* entry,
+                             FaceId dnFace)
+{
+  *nhFlt = 0;
+  int nNexthops = FibNexthopFilter_Reject(nhFlt, entry, dnFace);
+  for (uint8_t i = 0; i < entry->nNexthops; ++i) {
+    FaceId nh = entry->nexthops[i];
+    if (unlikely(Face_IsDown(nh))) {
+      nNexthops = FibNexthopFilter_Reject(nhFlt, entry, nh);
+    }
+  }
+  return nNexthops;
+}
+
 static FibEntry*
 FwFwd_InterestLookupFib(FwFwd* fwd, Packet* npkt, FibNexthopFilter* nhFlt)
 {
@@ -17,8 +34,7 @@ FwFwd_InterestLookupFib(FwFwd* fwd, Packet* npkt, FibNexthopFilter* nhFlt)
     if (unlikely(entry == NULL)) {
       return NULL;
     }
-    *nhFlt = 0;
-    int nNexthops = FibNexthopFilter_Reject(nhFlt, entry, dnFace);
+    int nNexthops = FwFwd_InterestFilterNexthops(nhFlt, entry, dnFace);
     if (unlikely(nNexthops == 0)) {
       return NULL;
     }
@@ -36,8 +52,7 @@ FwFwd_InterestLookupFib(FwFwd* fwd, Packet* npkt, FibNexthopFilter* nhFlt)
     if (unlikely(entry == NULL)) {
       continue;
     }
-    *nhFlt = 0;
-    int nNexthops = FibNexthopFilter_Reject(nhFlt, entry, dnFace);
+    int nNexthops = FwFwd_InterestFilterNexthops(nhFlt, entry, dnFace);
     if (unlikely(nNexthops == 0)) {
       continue;
     }
@@ -111,36 +126,77 @@ FwFwd_InterestHitCs(FwFwd* fwd, FwFwdCtx* ctx, CsEntry* csEntry)
   FwFwd_NULLize(ctx->pkt);
 }
 
-void
-FwFwd_RxInterest(FwFwd* fwd, FwFwdCtx* ctx)
+static void
+FwFwd_InterestHitDisk(FwFwd* fwd, FwFwdCtx* ctx, CsEntry* csEntry)
 {
-  PInterest* interest = Packet_GetInterestHdr(ctx->npkt);
-  assert(interest->hopLimit > 0);
-
-  ZF_LOGD("interest-from=%" PRI_FaceId " npkt=%p dn-token=%016" PRIx64,
-          ctx->rxFace,
+  ZF_LOGD("^ cs-entry=%p disk-slot=%" PRIu64 " npkt=%p dn-token=%016" PRIx64,
+          csEntry,
+          csEntry->diskSlot,
           ctx->npkt,
           ctx->rxToken);
+  Cs_ReadDisk(fwd->cs, csEntry, ctx->npkt, fwd->diskReply);
+  FwFwd_NULLize(ctx->npkt); // npkt is owned by DiskStore until it returns
+}
 
-  // query FIB, reply Nack if no FIB match
-  rcu_read_lock();
+/** \brief Pass Interest to crypto helper for signature verification.
+ */
+static void
+FwFwd_InterestToCrypto(FwFwd* fwd, FwFwdCtx* ctx)
+{
+  if (unlikely(fwd->crypto == NULL)) {
+    ZF_LOGD("^ error=crypto-unavailable");
+    rte_pktmbuf_free(ctx->pkt);
+    FwFwd_NULLize(ctx->pkt);
+    return;
+  }
+
+  int res = rte_ring_enqueue(fwd->crypto, ctx->npkt);
+  if (unlikely(res != 0)) {
+    ZF_LOGD("^ error=crypto-enqueue-error-%d", res);
+    ++fwd->nCryptoFull;
+    rte_pktmbuf_free(ctx->pkt);
+    FwFwd_NULLize(ctx->pkt);
+  } else {
+    ZF_LOGD("^ helper=crypto");
+    FwFwd_NULLize(ctx->npkt); // npkt is now owned by FwCrypto
+  }
+}
+
+/** \brief Query FIB, reply Nack if no FIB match.
+ *  \pre rcu_read_lock is held.
+ *  \return whether FIB match is found and saved in ctx->fibEntry.
+ */
+static bool
+FwFwd_InterestQueryFib(FwFwd* fwd, FwFwdCtx* ctx)
+{
   ctx->fibEntry = FwFwd_InterestLookupFib(fwd, ctx->npkt, &ctx->nhFlt);
   if (unlikely(ctx->fibEntry == NULL)) {
     ZF_LOGD("^ drop=no-FIB-match nack-to=%" PRI_FaceId, ctx->rxFace);
     MakeNack(ctx->npkt, NackReason_NoRoute);
     Face_Tx(ctx->rxFace, ctx->npkt);
     ++fwd->nNoFibMatch;
-    rcu_read_unlock();
-    return;
+    return false;
   }
   ZF_LOGD("^ fh-index=%d fib-entry-depth=%" PRIu8 " sg-id=%d",
-          interest->activeFh,
+          Packet_GetInterestHdr(ctx->npkt)->activeFh,
           ctx->fibEntry->nComps,
           ctx->fibEntry->strategy->id);
-  ++ctx->fibEntry->nRxInterests;
+  return true;
+}
 
-  // lookup PIT-CS
+/** \brief Lookup PIT-CS and continue processing accordingly.
+ *  \pre rcu_read_lock is held, and ctx->fibEntry is set.
+ */
+static void
+FwFwd_InterestLookupPitCs(FwFwd* fwd, FwFwdCtx* ctx)
+{
+  PInterest* interest = Packet_GetInterestHdr(ctx->npkt);
   PitInsertResult pitIns = Pit_Insert(fwd->pit, ctx->npkt, ctx->fibEntry);
+  if (unlikely(interest->diskData != NULL)) {
+    // Data loaded from DiskStore was not restored, because CS entry is gone
+    rte_pktmbuf_free(Packet_ToMbuf(interest->diskData));
+    interest->diskData = NULL;
+  }
   switch (PitInsertResult_GetKind(pitIns)) {
     case PIT_INSERT_PIT0:
     case PIT_INSERT_PIT1: {
@@ -150,7 +206,11 @@ FwFwd_RxInterest(FwFwd* fwd, FwFwdCtx* ctx)
     }
     case PIT_INSERT_CS: {
       CsEntry* csEntry = CsEntry_GetDirect(PitInsertResult_GetCsEntry(pitIns));
-      FwFwd_InterestHitCs(fwd, ctx, csEntry);
+      if (unlikely(CsEntry_GetData(csEntry) == NULL)) {
+        FwFwd_InterestHitDisk(fwd, ctx, csEntry);
+      } else {
+        FwFwd_InterestHitCs(fwd, ctx, csEntry);
+      }
       break;
     }
     case PIT_INSERT_FULL:
@@ -162,7 +222,69 @@ FwFwd_RxInterest(FwFwd* fwd, FwFwdCtx* ctx)
       assert(false); // no other cases
       break;
   }
+}
+
+void
+FwFwd_RxInterest(FwFwd* fwd, FwFwdCtx* ctx)
+{
+  PInterest* interest = Packet_GetInterestHdr(ctx->npkt);
+  assert(interest->hopLimit > 0);
+
+  ZF_LOGD("interest-from=%" PRI_FaceId " npkt=%p dn-token=%016" PRIx64,
+          ctx->rxFace,
+          ctx->npkt,
+          ctx->rxToken);
+
+  // ParametersSha256DigestComponent must match ApplicationParameters; this is
+  // checked before verification, and skipped when returning from FwCrypto
+  if (unlikely(interest->hasParams && !interest->sigChecked &&
+               !PInterest_CheckParamsDigest(interest, ctx->pkt))) {
+    ZF_LOGD("^ drop=bad-params-digest");
+    ++fwd->nBadParamsDigest;
+    rte_pktmbuf_free(ctx->pkt);
+    FwFwd_NULLize(ctx->pkt);
+    return;
+  }
+
+  // verify signed Interest under selected prefixes
+  if (unlikely(fwd->verifier != NULL && !interest->sigChecked &&
+               FwVerifier_MatchInterest(fwd->verifier, interest))) {
+    ZF_LOGD("^ need-verify");
+    FwFwd_InterestToCrypto(fwd, ctx);
+    return;
+  }
+
+  rcu_read_lock();
+  if (likely(FwFwd_InterestQueryFib(fwd, ctx))) {
+    ++ctx->fibEntry->nRxInterests;
+    FwFwd_InterestLookupPitCs(fwd, ctx);
+  }
+  FwFwd_NULLize(ctx->fibEntry); // fibEntry is inaccessible upon RCU unlock
+  rcu_read_unlock();
+}
+
+void
+FwFwd_RxInterestFromDisk(FwFwd* fwd, FwFwdCtx* ctx)
+{
+  PInterest* interest = Packet_GetInterestHdr(ctx->npkt);
+  Packet* diskData = interest->diskData;
+
+  ZF_LOGD("interest-from-disk=%" PRI_FaceId " npkt=%p dn-token=%016" PRIx64
+          " disk-slot=%" PRIu64 " disk-data=%p",
+          ctx->rxFace,
+          ctx->npkt,
+          ctx->rxToken,
+          interest->diskSlotId,
+          diskData);
 
+  // FIB entry may have changed while Interest was in DiskStore; Interest was
+  // already counted when it first arrived
+  rcu_read_lock();
+  if (likely(FwFwd_InterestQueryFib(fwd, ctx))) {
+    FwFwd_InterestLookupPitCs(fwd, ctx);
+  } else if (diskData != NULL) {
+    rte_pktmbuf_free(Packet_ToMbuf(diskData));
+  }
   FwFwd_NULLize(ctx->fibEntry); // fibEntry is inaccessible upon RCU unlock
   rcu_read_unlock();
 }
@@ -53,15 +53,37 @@ FwFwd_RxByType(FwFwd* fwd, L3PktType l3type)
   }
 }
 
+static void
+FwFwd_RxDiskReply(FwFwd* fwd)
+{
+  Packet* npkts[PKTQUEUE_BURST_SIZE_MAX];
+  unsigned count = rte_ring_dequeue_burst(
+    fwd->diskReply, (void**)npkts, PKTQUEUE_BURST_SIZE_MAX, NULL);
+  for (unsigned i = 0; i < count; ++i) {
+    FwFwdCtx ctx = {
+      .fwd = fwd,
+      .npkt = npkts[i],
+    };
+    ctx.rxFace = ctx.pkt->port;
+    ctx.rxTime = ctx.pkt->timestamp;
+    ctx.rxToken = Packet_GetLpL3Hdr(ctx.npkt)->pitToken;
+    ctx.eventKind = SGEVT_INTEREST;
+
+    Cs_ReadDiskDone(fwd->cs, ctx.npkt);
+    FwFwd_RxInterestFromDisk(fwd, &ctx);
+  }
+}
+
 void
 FwFwd_Run(FwFwd* fwd)
 {
-  ZF_LOGI("fwdId=%" PRIu8 " fwd=%p fib=%p pit+cs=%p crypto=%p",
+  ZF_LOGI("fwdId=%" PRIu8 " fwd=%p fib=%p pit+cs=%p crypto=%p disk=%p",
           fwd->id,
           fwd,
           fwd->fib,
           fwd->pcct,
-          fwd->crypto);
+          fwd->crypto,
+          fwd->diskReply);
 
   fwd->sgGlobal.tscHz = rte_get_tsc_hz();
   Pit_SetSgTimerCb(fwd->pit, SgTriggerTimer, fwd);
@@ -73,6 +95,9 @@ FwFwd_Run(FwFwd* fwd)
     FwFwd_RxByType(fwd, L3PktType_Interest);
     FwFwd_RxByType(fwd, L3PktType_Data);
     FwFwd_RxByType(fwd, L3PktType_Nack);
+    if (fwd->diskReply != NULL) {
+      FwFwd_RxDiskReply(fwd);
+    }
   }
 
   ZF_LOGI("fwdId=%" PRIu8 " STOP", fwd->id);
@@ -104,6 +104,9 @@ func (fwd *Fwd) Close() error {
 	pktqueue.FromPtr(unsafe.Pointer(&fwd.c.inInterestQueue)).Close()
 	pktqueue.FromPtr(unsafe.Pointer(&fwd.c.inDataQueue)).Close()
 	pktqueue.FromPtr(unsafe.Pointer(&fwd.c.inNackQueue)).Close()
+	if fwd.c.diskReply != nil {
+		dpdk.RingFromPtr(unsafe.Pointer(fwd.c.diskReply)).Close()
+	}
 	pcct.PcctFromPtr(unsafe.Pointer(*C.FwFwd_GetPcctPtr_(fwd.c))).Close()
 	dpdk.Free(fwd.c)
 	return nil
@@ -11,6 +11,7 @@
 #include "../../dpdk/thread.h"
 #include "../../iface/face.h"
 #include "../../strategy/api.h"
+#include "verify.h"
 
 /** \brief Forwarding thread.
  */
@@ -34,16 +35,20 @@ typedef struct FwFwd
   uint8_t id; ///< fwd process id
   ThreadStopFlag stop;
 
-  uint64_t nNoFibMatch;   ///< Interests dropped due to no FIB match
-  uint64_t nDupNonce;     ///< Interests dropped due duplicate nonce
-  uint64_t nSgNoFwd;      ///< Interests not forwarded by strategy
-  uint64_t nNackMismatch; ///< Nack dropped due to outdated nonce
+  uint64_t nNoFibMatch;      ///< Interests dropped due to no FIB match
+  uint64_t nDupNonce;        ///< Interests dropped due duplicate nonce
+  uint64_t nSgNoFwd;         ///< Interests not forwarded by strategy
+  uint64_t nNackMismatch;    ///< Nack dropped due to outdated nonce
+  uint64_t nCryptoFull;      ///< packets dropped due to full crypto queue
+  uint64_t nBadParamsDigest; ///< Interests dropped due to bad params digest
 
   struct rte_mempool* headerMp;   ///< mempool for Interest/Data header
   struct rte_mempool* guiderMp;   ///< mempool for Interest guiders
   struct rte_mempool* indirectMp; ///< mempool for indirect mbufs
 
-  struct rte_ring* crypto; ///< queue to crypto helper
+  struct rte_ring* crypto;    ///< queue to crypto helper
+  const FwVerifier* verifier; ///< signature verification settings, may be NULL
+  struct rte_ring* diskReply; ///< Interests returned from DiskStore
 
   /** \brief Statistics of latency from packet arrival to start processing.
    */
@@ -95,6 +100,13 @@ typedef struct FwFwdCtx
 void
 FwFwd_RxInterest(FwFwd* fwd, FwFwdCtx* ctx);
 
+/** \brief Resume processing an Interest returned from DiskStore.
+ *
+ *  This continues from PIT-CS lookup, without counting the Interest again.
+ */
+void
+FwFwd_RxInterestFromDisk(FwFwd* fwd, FwFwdCtx* ctx);
+
 void
 FwFwd_RxData(FwFwd* fwd, FwFwdCtx* ctx);
 
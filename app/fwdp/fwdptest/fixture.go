@@ -28,7 +28,9 @@ type Fixture struct {
 	Fib       *fib.Fib
 }
 
-func NewFixture(t *testing.T) (fixture *Fixture) {
+// Create a fixture.
+// modifyConfig, if provided, can change the data plane config before the data plane is created.
+func NewFixture(t *testing.T, modifyConfig ...func(cfg *fwdp.Config)) (fixture *Fixture) {
 	fixture = new(Fixture)
 	fixture.require = require.New(t)
 
@@ -57,6 +59,10 @@ func NewFixture(t *testing.T) (fixture *Fixture) {
 
 	dpCfg.LatencySampleFreq = 0
 
+	for _, f := range modifyConfig {
+		f(&dpCfg)
+	}
+
 	theDp, e := fwdp.New(dpCfg)
 	fixture.require.NoError(e)
 	fixture.DataPlane = theDp
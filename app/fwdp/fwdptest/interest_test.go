@@ -379,3 +379,30 @@ func TestImplicitDigest(t *testing.T) {
 	require.Len(face1.TxData, 3)
 	assert.Equal(uint64(0x02a0f62d1828a80c), ndntestutil.GetPitToken(face1.TxData[2]))
 }
+
+func TestParamsDigest(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewFixture(t)
+	defer fixture.Close()
+
+	face1 := fixture.CreateFace()
+	face2 := fixture.CreateFace()
+	fixture.SetFibEntry("/B", "multicast", face2.GetFaceId())
+
+	interest := ndntestutil.MakeInterest("/B/1", ndn.ApplicationParameters{0xC0, 0xC1})
+	face1.Rx(interest)
+	time.Sleep(STEP_DELAY)
+	require.Len(face2.TxInterests, 1)
+
+	// modify the last octet of ApplicationParameters, so that the digest does not match
+	interest = ndntestutil.MakeInterest("/B/2", ndn.ApplicationParameters{0xC0, 0xC1})
+	wire := interest.GetPacket().AsDpdkPacket().ReadAll()
+	ndntestutil.ClosePacket(interest)
+	wire[len(wire)-1] ^= 0x01
+	face1.Rx(ndntestutil.MakeInterest(wire))
+	time.Sleep(STEP_DELAY)
+	assert.Len(face2.TxInterests, 1)
+	assert.Equal(uint64(1), fixture.SumCounter(func(dp *fwdp.DataPlane, i int) uint64 {
+		return dp.ReadFwdInfo(i).NBadParamsDigest
+	}))
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func TestMain(m *testing.M) {
-	dpdktestenv.MakeDirectMp(255, ndn.SizeofPacketPriv(), 2000)
+	dpdktestenv.MakeDirectMp(4095, ndn.SizeofPacketPriv(), 2000)
 	os.Exit(m.Run())
 }
 
@@ -0,0 +1,180 @@
+package fwdptest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"fmt"
+	"testing"
+	"time"
+
+	"ndn-dpdk/app/fwdp"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+// Create a fixture that verifies Data, using crypto_openssl vdev for all crypto operations.
+func newVerifyFixture(t *testing.T, inputCapacity int, anchors ...fwdp.TrustAnchor) *Fixture {
+	singleSeg := dpdk.CryptoDevDriverPref_SingleSeg
+	dpdk.CryptoDevDriverPref_SingleSeg = dpdk.CryptoDevDriverPref{"openssl"}
+	defer func() { dpdk.CryptoDevDriverPref_SingleSeg = singleSeg }()
+
+	return NewFixture(t, func(cfg *fwdp.Config) {
+		cfg.Crypto.InputCapacity = inputCapacity
+		cfg.Crypto.Verify.Data = true
+		cfg.Crypto.Verify.TrustAnchors = anchors
+	})
+}
+
+func makeEcdsaKey(t *testing.T, keyName string) (signer *ndn.Signer, anchor fwdp.TrustAnchor) {
+	_, require := makeAR(t)
+	priv, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(e)
+	privDer, e := x509.MarshalECPrivateKey(priv)
+	require.NoError(e)
+	pubDer, e := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(e)
+
+	signer, e = ndn.NewSigner(ndn.SignerConfig{
+		Type:    ndn.Signer_Ecdsa,
+		KeyName: ndn.MustParseName(keyName),
+		Key:     privDer,
+	}, dpdk.NUMA_SOCKET_ANY)
+	require.NoError(e)
+	anchor = fwdp.TrustAnchor{
+		KeyName: ndn.MustParseName(keyName),
+		Type:    fwdp.TrustAnchor_Ecdsa,
+		Key:     pubDer,
+	}
+	return signer, anchor
+}
+
+func makeHmacKey(t *testing.T, keyName string, key []byte) (signer *ndn.Signer, anchor fwdp.TrustAnchor) {
+	_, require := makeAR(t)
+	signer, e := ndn.NewSigner(ndn.SignerConfig{
+		Type:    ndn.Signer_Hmac,
+		KeyName: ndn.MustParseName(keyName),
+		Key:     key,
+	}, dpdk.NUMA_SOCKET_ANY)
+	require.NoError(e)
+	anchor = fwdp.TrustAnchor{
+		KeyName: ndn.MustParseName(keyName),
+		Type:    fwdp.TrustAnchor_Hmac,
+		Key:     key,
+	}
+	return signer, anchor
+}
+
+// Flip a bit in Data Content, which is within the signed portion.
+func tamperData(data *ndn.Data, content ndn.TlvBytes) *ndn.Data {
+	wire := data.GetPacket().AsDpdkPacket().ReadAll()
+	ndntestutil.ClosePacket(data)
+	wire[bytes.Index(wire, content)] ^= 0x01
+	return ndntestutil.MakeData(wire)
+}
+
+func TestVerifyData(t *testing.T) {
+	assert, require := makeAR(t)
+	ecdsaSigner, ecdsaAnchor := makeEcdsaKey(t, "/operator/KEY/ecdsa")
+	defer ecdsaSigner.Close()
+	hmacSigner, hmacAnchor := makeHmacKey(t, "/operator/KEY/hmac", []byte("secret"))
+	defer hmacSigner.Close()
+	wrongSigner, _ := makeHmacKey(t, "/operator/KEY/hmac", []byte("wrong"))
+	defer wrongSigner.Close()
+	untrustedSigner, _ := makeEcdsaKey(t, "/attacker/KEY/ecdsa")
+	defer untrustedSigner.Close()
+
+	fixture := newVerifyFixture(t, 64, ecdsaAnchor, hmacAnchor)
+	defer fixture.Close()
+
+	face1 := fixture.CreateFace()
+	face2 := fixture.CreateFace()
+	fixture.SetFibEntry("/B", "multicast", face2.GetFaceId())
+
+	content := ndn.TlvBytes("payload-0123456789")
+	tests := []struct {
+		signer   *ndn.Signer
+		tamper   bool
+		accepted bool
+	}{
+		{ecdsaSigner, false, true},
+		{ecdsaSigner, true, false},
+		{hmacSigner, false, true},
+		{hmacSigner, true, false},
+		{wrongSigner, false, false},
+		{untrustedSigner, false, false},
+	}
+	nAccepted, nRejected := 0, 0
+	for i, tt := range tests {
+		name := fmt.Sprintf("/B/%d", i)
+		interest := ndntestutil.MakeInterest(name)
+		ndntestutil.SetPitToken(interest, uint64(0xB000+i))
+		face1.Rx(interest)
+		time.Sleep(STEP_DELAY)
+		require.Len(face2.TxInterests, i+1, i)
+
+		data := ndntestutil.MakeData(name, content, tt.signer)
+		if tt.tamper {
+			data = tamperData(data, content)
+		}
+		ndntestutil.CopyPitToken(data, face2.TxInterests[i])
+		face2.Rx(data)
+		time.Sleep(STEP_DELAY)
+
+		if tt.accepted {
+			nAccepted++
+		} else {
+			nRejected++
+		}
+		assert.Len(face1.TxData, nAccepted, i)
+	}
+
+	cryptoInfo := fixture.DataPlane.ReadCryptoInfo()
+	assert.Equal(uint64(nRejected), cryptoInfo.NDataSigFails)
+	assert.Equal(uint64(0), cryptoInfo.NInterestSigFails)
+	assert.Equal(uint64(0), cryptoInfo.NDrops)
+}
+
+func TestVerifyQueueFull(t *testing.T) {
+	assert, require := makeAR(t)
+	signer, anchor := makeEcdsaKey(t, "/operator/KEY/ecdsa")
+	defer signer.Close()
+
+	fixture := newVerifyFixture(t, 64, anchor)
+	defer fixture.Close()
+
+	face1 := fixture.CreateFace()
+	face2 := fixture.CreateFace()
+	fixture.SetFibEntry("/B", "multicast", face2.GetFaceId())
+
+	const nPackets = 1024
+	for i := 0; i < nPackets; i++ {
+		interest := ndntestutil.MakeInterest(fmt.Sprintf("/B/%d", i))
+		ndntestutil.SetPitToken(interest, uint64(0xB000+i))
+		face1.Rx(interest)
+	}
+	time.Sleep(10 * STEP_DELAY)
+	require.Len(face2.TxInterests, nPackets)
+
+	// ECDSA verification is slower than packet arrival, so that the crypto helper queue overflows
+	datas := make([]*ndn.Data, nPackets)
+	for i, interest := range face2.TxInterests {
+		datas[i] = ndntestutil.MakeData(interest.GetName().String(), signer)
+		ndntestutil.CopyPitToken(datas[i], interest)
+	}
+	for _, data := range datas {
+		face2.Rx(data)
+	}
+	time.Sleep(20 * STEP_DELAY)
+
+	nCryptoFull := fixture.SumCounter(func(dp *fwdp.DataPlane, i int) uint64 {
+		return dp.ReadFwdInfo(i).NCryptoFull
+	})
+	assert.NotZero(nCryptoFull)
+	assert.Less(len(face1.TxData), nPackets)
+	assert.Equal(nPackets, len(face1.TxData)+int(nCryptoFull))
+	assert.Equal(uint64(0), fixture.DataPlane.ReadCryptoInfo().NDataSigFails)
+}
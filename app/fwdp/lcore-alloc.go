@@ -13,16 +13,19 @@ const (
 	LCoreRole_Input  = iface.LCoreRole_RxLoop
 	LCoreRole_Output = iface.LCoreRole_TxLoop
 	LCoreRole_Crypto = "CRYPTO"
+	LCoreRole_Disk   = "DISK"
 	LCoreRole_Fwd    = "FWD"
 )
 
 // LCore allocator for dataplane.
 type DpLCores struct {
-	Allocator *dpdk.LCoreAllocator
+	Allocator  *dpdk.LCoreAllocator
+	EnableDisk bool // whether to allocate an lcore for DISK role
 
 	Inputs  []dpdk.LCore
 	Outputs []dpdk.LCore
 	Crypto  dpdk.LCore
+	Disk    dpdk.LCore
 	Fwds    []dpdk.LCore
 }
 
@@ -38,6 +41,11 @@ func (la *DpLCores) Alloc() (e error) {
 
 	la.Crypto = la.Allocator.Alloc(LCoreRole_Crypto, dpdk.NUMA_SOCKET_ANY)
 
+	la.Disk = dpdk.LCORE_INVALID
+	if la.EnableDisk {
+		la.Disk = la.Allocator.Alloc(LCoreRole_Disk, dpdk.NUMA_SOCKET_ANY)
+	}
+
 	if la.Fwds = la.allocMax(LCoreRole_Fwd); len(la.Fwds) == 0 {
 		return fmt.Errorf("no lcore available for %s", LCoreRole_Fwd)
 	}
@@ -1,6 +1,7 @@
 package fwdp
 
 /*
+#include "crypto.h"
 #include "fwd.h"
 */
 import "C"
@@ -53,10 +54,12 @@ type FwdInfo struct {
 	InputNack     FwdInputCounter
 	InputLatency  running_stat.Snapshot // input latency in nanos
 
-	NNoFibMatch   uint64 // Interests dropped due to no FIB match
-	NDupNonce     uint64 // Interests dropped due duplicate nonce
-	NSgNoFwd      uint64 // Interests not forwarded by strategy
-	NNackMismatch uint64 // Nack dropped due to outdated nonce
+	NNoFibMatch      uint64 // Interests dropped due to no FIB match
+	NDupNonce        uint64 // Interests dropped due duplicate nonce
+	NSgNoFwd         uint64 // Interests not forwarded by strategy
+	NNackMismatch    uint64 // Nack dropped due to outdated nonce
+	NCryptoFull      uint64 // packets dropped due to full crypto helper queue
+	NBadParamsDigest uint64 // Interests dropped due to wrong ParametersSha256DigestComponent
 
 	HeaderMpUsage   int // how many entries are used in header mempool
 	IndirectMpUsage int // how many entries are used in indirect mempool
@@ -90,6 +93,8 @@ func (dp *DataPlane) ReadFwdInfo(i int) (info *FwdInfo) {
 	info.NDupNonce = uint64(fwd.c.nDupNonce)
 	info.NSgNoFwd = uint64(fwd.c.nSgNoFwd)
 	info.NNackMismatch = uint64(fwd.c.nNackMismatch)
+	info.NCryptoFull = uint64(fwd.c.nCryptoFull)
+	info.NBadParamsDigest = uint64(fwd.c.nBadParamsDigest)
 
 	info.HeaderMpUsage = dpdk.MempoolFromPtr(unsafe.Pointer(fwd.c.headerMp)).CountInUse()
 	info.IndirectMpUsage = dpdk.MempoolFromPtr(unsafe.Pointer(fwd.c.indirectMp)).CountInUse()
@@ -106,6 +111,30 @@ func (dp *DataPlane) ReadFwdInfo(i int) (info *FwdInfo) {
 	return info
 }
 
+// Information and counters about the crypto helper.
+type CryptoInfo struct {
+	LCore dpdk.LCore // LCore executing the crypto helper
+
+	NDrops            uint64 // packets dropped due to crypto operation failure
+	NInterestSigFails uint64 // Interests dropped due to signature verification failure
+	NDataSigFails     uint64 // Data dropped due to signature verification failure
+}
+
+// Read information about the crypto helper.
+func (dp *DataPlane) ReadCryptoInfo() (info *CryptoInfo) {
+	if dp.crypto == nil {
+		return nil
+	}
+	fwc := dp.crypto
+
+	info = new(CryptoInfo)
+	info.LCore = fwc.GetLCore()
+	info.NDrops = uint64(fwc.c.nDrops)
+	info.NInterestSigFails = uint64(fwc.c.nInterestSigFails)
+	info.NDataSigFails = uint64(fwc.c.nDataSigFails)
+	return info
+}
+
 // Access the NDT.
 func (dp *DataPlane) GetNdt() *ndt.Ndt {
 	return dp.ndt
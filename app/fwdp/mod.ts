@@ -1,6 +1,53 @@
 import { Counter } from "../../core/mod";
 import * as runningStat from "../../core/running_stat/mod";
 import * as iface from "../../iface/mod";
+import { Name } from "../../ndn/mod";
+
+export interface DiskConfig {
+  File?: string;
+
+  /**
+   * @TJS-type integer
+   * @default 0
+   * @minimum 0
+   */
+  MallocBlocks?: number;
+
+  /**
+   * @TJS-type integer
+   * @default 16
+   * @minimum 1
+   * @maximum 16
+   */
+  NBlocksPerSlot?: number;
+
+  /**
+   * @TJS-type integer
+   * @default 1024
+   * @minimum 1
+   */
+  ReplyCapacity?: number;
+}
+
+export interface VerifyConfig {
+  /**
+   * @default false
+   */
+  Data?: boolean;
+
+  InterestPrefixes?: Name[];
+  TrustAnchors?: TrustAnchor[];
+}
+
+export interface TrustAnchor {
+  KeyName: Name;
+  Type: "ecdsa"|"hmac";
+
+  /**
+   * ECDSA P-256 public key in PKIX format, or HMAC key; base64 encoded.
+   */
+  Key: string;
+}
 
 export interface InputInfo {
   LCore: number;
@@ -19,11 +66,20 @@ export interface FwdInfo {
   NDupNonce: Counter;
   NSgNoFwd: Counter;
   NNackMismatch: Counter;
+  NCryptoFull: Counter;
+  NBadParamsDigest: Counter;
 
   HeaderMpUsage: Counter;
   IndirectMpUsage: Counter;
 }
 
+export interface CryptoInfo {
+  LCore: number;
+  NDrops: Counter;
+  NInterestSigFails: Counter;
+  NDataSigFails: Counter;
+}
+
 export interface FwdInputCounter {
   NDropped: Counter;
   NQueued: Counter;
@@ -0,0 +1,58 @@
+#include "verify.h"
+
+#include <openssl/ec.h>
+#include <openssl/ecdsa.h>
+#include <openssl/obj_mac.h>
+
+const FwTrustAnchor*
+FwVerifier_FindAnchor(const FwVerifier* v, uint8_t sigType, LName keyName)
+{
+  for (uint8_t i = 0; i < v->nAnchors; ++i) {
+    const FwTrustAnchor* anchor = &v->anchors[i];
+    if (anchor->sigType != sigType) {
+      continue;
+    }
+    LName prefix = { .length = anchor->keyNameL, .value = anchor->keyNameV };
+    NameCompareResult cmp = LName_Compare(prefix, keyName);
+    if (cmp == NAMECMP_EQUAL || cmp == NAMECMP_LPREFIX) {
+      return anchor;
+    }
+  }
+  return NULL;
+}
+
+bool
+FwTrustAnchor_SetEcdsaKey(FwTrustAnchor* anchor,
+                          const uint8_t* point,
+                          size_t pointLen)
+{
+  FwTrustAnchor_Clear(anchor);
+  EC_KEY* key = EC_KEY_new_by_curve_name(NID_X9_62_prime256v1);
+  if (key == NULL) {
+    return false;
+  }
+  if (EC_KEY_oct2key(key, point, pointLen, NULL) != 1) {
+    EC_KEY_free(key);
+    return false;
+  }
+  anchor->ecKey = key;
+  return true;
+}
+
+void
+FwTrustAnchor_Clear(FwTrustAnchor* anchor)
+{
+  if (anchor->ecKey != NULL) {
+    EC_KEY_free(anchor->ecKey);
+    anchor->ecKey = NULL;
+  }
+}
+
+bool
+FwTrustAnchor_VerifyEcdsa(const FwTrustAnchor* anchor,
+                          const uint8_t* digest,
+                          const uint8_t* sig,
+                          uint8_t sigLen)
+{
+  return ECDSA_verify(0, digest, 32, sig, sigLen, anchor->ecKey) == 1;
+}
@@ -0,0 +1,141 @@
+package fwdp
+
+/*
+#include "verify.h"
+*/
+import "C"
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/ndn"
+)
+
+// Signature verification config.
+type VerifyConfig struct {
+	Data             bool          // whether to verify Data with SignatureSha256WithEcdsa or SignatureHmacWithSha256
+	InterestPrefixes []*ndn.Name   // verify signed Interests under these prefixes
+	TrustAnchors     []TrustAnchor // trusted keys
+}
+
+func (cfg VerifyConfig) isEnabled() bool {
+	return cfg.Data || len(cfg.InterestPrefixes) > 0
+}
+
+// Trust anchor types.
+const (
+	TrustAnchor_Ecdsa = "ecdsa"
+	TrustAnchor_Hmac  = "hmac"
+)
+
+// A trusted key.
+type TrustAnchor struct {
+	KeyName *ndn.Name // matches KeyLocator names that start with this prefix
+	Type    string    // TrustAnchor_Ecdsa or TrustAnchor_Hmac
+	Key     []byte    // ECDSA P-256 public key in PKIX format, or HMAC secret key
+}
+
+type verifier struct {
+	c            *C.FwVerifier
+	hmacSessions []*dpdk.CryptoSession
+}
+
+func newVerifier(cfg VerifyConfig, devs []dpdk.CryptoDev, socket dpdk.NumaSocket) (v *verifier, e error) {
+	if len(cfg.InterestPrefixes) > C.FW_VERIFY_MAX_PREFIXES {
+		return nil, fmt.Errorf("cannot have more than %d InterestPrefixes", C.FW_VERIFY_MAX_PREFIXES)
+	}
+	if len(cfg.TrustAnchors) > C.FW_VERIFY_MAX_ANCHORS {
+		return nil, fmt.Errorf("cannot have more than %d TrustAnchors", C.FW_VERIFY_MAX_ANCHORS)
+	}
+
+	v = new(verifier)
+	v.c = (*C.FwVerifier)(dpdk.Zmalloc("FwVerifier", C.sizeof_FwVerifier, socket))
+	v.c.verifyData = C.bool(cfg.Data)
+
+	for i, prefix := range cfg.InterestPrefixes {
+		if e = copyNameValue(prefix, unsafe.Pointer(&v.c.interestPrefixV[i]), &v.c.interestPrefixL[i]); e != nil {
+			v.Close()
+			return nil, fmt.Errorf("InterestPrefixes[%d]: %v", i, e)
+		}
+		v.c.nInterestPrefixes++
+	}
+
+	for i, ta := range cfg.TrustAnchors {
+		if e = v.addAnchor(ta, devs); e != nil {
+			v.Close()
+			return nil, fmt.Errorf("TrustAnchors[%d]: %v", i, e)
+		}
+	}
+	return v, nil
+}
+
+func copyNameValue(name *ndn.Name, buf unsafe.Pointer, length *C.uint16_t) error {
+	if name == nil {
+		return errors.New("name is missing")
+	}
+	value := name.GetValue()
+	if len(value) > C.NAME_MAX_LENGTH {
+		return errors.New("name too long")
+	}
+	if len(value) > 0 {
+		C.memcpy(buf, unsafe.Pointer(&value[0]), C.size_t(len(value)))
+	}
+	*length = C.uint16_t(len(value))
+	return nil
+}
+
+func (v *verifier) addAnchor(ta TrustAnchor, devs []dpdk.CryptoDev) error {
+	anchor := &v.c.anchors[v.c.nAnchors]
+	if ta.KeyName == nil || ta.KeyName.Len() == 0 {
+		return errors.New("KeyName is missing")
+	}
+	if e := copyNameValue(ta.KeyName, unsafe.Pointer(&anchor.keyNameV[0]), &anchor.keyNameL); e != nil {
+		return e
+	}
+
+	switch ta.Type {
+	case TrustAnchor_Ecdsa:
+		pub, e := x509.ParsePKIXPublicKey(ta.Key)
+		if e != nil {
+			return e
+		}
+		ecPub, ok := pub.(*ecdsa.PublicKey)
+		if !ok || ecPub.Curve != elliptic.P256() {
+			return errors.New("key is not ECDSA P-256")
+		}
+		point := elliptic.Marshal(ecPub.Curve, ecPub.X, ecPub.Y)
+		if !C.FwTrustAnchor_SetEcdsaKey(anchor, (*C.uint8_t)(unsafe.Pointer(&point[0])), C.size_t(len(point))) {
+			return errors.New("FwTrustAnchor_SetEcdsaKey error")
+		}
+		anchor.sigType = C.SigType_Sha256WithEcdsa
+	case TrustAnchor_Hmac:
+		sess, e := dpdk.NewHmacSha256VerifySession(ta.Key, devs...)
+		if e != nil {
+			return e
+		}
+		v.hmacSessions = append(v.hmacSessions, sess)
+		anchor.hmacSession = (*C.struct_rte_cryptodev_sym_session)(sess.GetPtr())
+		anchor.sigType = C.SigType_HmacWithSha256
+	default:
+		return fmt.Errorf("unknown Type %s", ta.Type)
+	}
+
+	v.c.nAnchors++
+	return nil
+}
+
+func (v *verifier) Close() error {
+	for i := range v.c.anchors {
+		C.FwTrustAnchor_Clear(&v.c.anchors[i])
+	}
+	for _, sess := range v.hmacSessions {
+		sess.Close()
+	}
+	dpdk.Free(unsafe.Pointer(v.c))
+	return nil
+}
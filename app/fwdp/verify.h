@@ -0,0 +1,80 @@
+#ifndef NDN_DPDK_APP_FWDP_VERIFY_H
+#define NDN_DPDK_APP_FWDP_VERIFY_H
+
+/// \file
+
+#include "../../ndn/packet.h"
+#include "../../ndn/signature.h"
+
+#define FW_VERIFY_MAX_ANCHORS 8
+#define FW_VERIFY_MAX_PREFIXES 8
+
+/** \brief A trust anchor for signature verification.
+ */
+typedef struct FwTrustAnchor
+{
+  struct rte_cryptodev_sym_session* hmacSession; ///< for HmacWithSha256
+  void* ecKey;                                   ///< EC_KEY* for Sha256WithEcdsa
+  uint8_t sigType;                               ///< SigType
+  uint16_t keyNameL;
+  uint8_t keyNameV[NAME_MAX_LENGTH]; ///< KeyLocator name prefix
+} FwTrustAnchor;
+
+/** \brief Signature verification settings, shared among FwCrypto and FwFwds.
+ */
+typedef struct FwVerifier
+{
+  bool verifyData; ///< whether to verify Data
+  uint8_t nAnchors;
+  uint8_t nInterestPrefixes;
+  uint16_t interestPrefixL[FW_VERIFY_MAX_PREFIXES];
+  uint8_t interestPrefixV[FW_VERIFY_MAX_PREFIXES][NAME_MAX_LENGTH];
+  FwTrustAnchor anchors[FW_VERIFY_MAX_ANCHORS];
+} FwVerifier;
+
+/** \brief Determine whether an Interest should be verified.
+ */
+static inline bool
+FwVerifier_MatchInterest(const FwVerifier* v, const PInterest* interest)
+{
+  const LName* name = (const LName*)&interest->name;
+  for (uint8_t i = 0; i < v->nInterestPrefixes; ++i) {
+    LName prefix = { .length = v->interestPrefixL[i],
+                     .value = v->interestPrefixV[i] };
+    NameCompareResult cmp = LName_Compare(prefix, *name);
+    if (cmp == NAMECMP_EQUAL || cmp == NAMECMP_LPREFIX) {
+      return true;
+    }
+  }
+  return false;
+}
+
+/** \brief Find a trust anchor whose key name is a prefix of KeyLocator name.
+ *  \return the trust anchor, or NULL if not found.
+ */
+const FwTrustAnchor*
+FwVerifier_FindAnchor(const FwVerifier* v, uint8_t sigType, LName keyName);
+
+/** \brief Set ECDSA P-256 public key of a trust anchor.
+ *  \param point public key in uncompressed point format.
+ */
+bool
+FwTrustAnchor_SetEcdsaKey(FwTrustAnchor* anchor,
+                          const uint8_t* point,
+                          size_t pointLen);
+
+/** \brief Release ECDSA public key of a trust anchor.
+ */
+void
+FwTrustAnchor_Clear(FwTrustAnchor* anchor);
+
+/** \brief Verify ECDSA signature over SHA256 digest.
+ *  \param sig DER-encoded ECDSA signature.
+ */
+bool
+FwTrustAnchor_VerifyEcdsa(const FwTrustAnchor* anchor,
+                          const uint8_t* digest,
+                          const uint8_t* sig,
+                          uint8_t sigLen);
+
+#endif // NDN_DPDK_APP_FWDP_VERIFY_H
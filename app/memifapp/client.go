@@ -0,0 +1,86 @@
+package memifapp
+
+import (
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/ethface"
+	"ndn-dpdk/ndn"
+)
+
+// Client configuration.
+type Config struct {
+	ethface.PortConfig
+	RxLCore dpdk.LCore // lcore for RxLoop
+	TxLCore dpdk.LCore // lcore for TxLoop
+}
+
+// Application side of a memif connection to the forwarder.
+type Client struct {
+	face *ethface.EthFace
+	rxl  *iface.RxLoop
+	txl  *iface.TxLoop
+}
+
+// Connect to a memif face created by the forwarder.
+// onRx is invoked on RxLCore for each burst of received packets, and takes ownership of these packets.
+func Connect(loc ethface.MemifLocator, cfg Config, onRx iface.RxCbFunc) (c *Client, e error) {
+	c = new(Client)
+	if c.face, e = ethface.CreateMemif(loc, ethface.MemifRole_Slave, cfg.PortConfig); e != nil {
+		return nil, e
+	}
+
+	c.rxl = iface.NewRxLoop(c.face.GetNumaSocket())
+	c.rxl.SetLCore(cfg.RxLCore)
+	c.rxl.SetCallback(iface.WrapRxCb(onRx))
+	if e = c.rxl.Launch(); e != nil {
+		c.face.Close()
+		c.rxl.Close()
+		return nil, e
+	}
+	for _, rxg := range c.face.ListRxGroups() {
+		c.rxl.AddRxGroup(rxg)
+	}
+
+	c.txl = iface.NewTxLoop(c.face.GetNumaSocket())
+	c.txl.SetLCore(cfg.TxLCore)
+	if e = c.txl.Launch(); e != nil {
+		c.closeRx()
+		c.face.Close()
+		c.txl.Close()
+		return nil, e
+	}
+	c.txl.AddFace(c.face)
+	return c, nil
+}
+
+// Get the application side face.
+func (c *Client) GetFace() *ethface.EthFace {
+	return c.face
+}
+
+// Send packets to the forwarder.
+// Client takes ownership of these packets.
+func (c *Client) Send(pkts ...ndn.IL3Packet) {
+	list := make([]ndn.Packet, len(pkts))
+	for i, pkt := range pkts {
+		list[i] = pkt.GetPacket()
+	}
+	c.face.TxBurst(list)
+}
+
+func (c *Client) closeRx() {
+	c.rxl.Stop()
+	for _, rxg := range c.rxl.ListRxGroups() {
+		c.rxl.RemoveRxGroup(rxg)
+	}
+	c.rxl.Close()
+}
+
+// Disconnect from the forwarder and stop RxLoop and TxLoop.
+func (c *Client) Close() error {
+	c.closeRx()
+	c.txl.Stop()
+	c.txl.RemoveFace(c.face)
+	c.txl.Close()
+	return c.face.Close()
+}
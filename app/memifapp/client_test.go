@@ -0,0 +1,93 @@
+package memifapp_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ndn-dpdk/app/memifapp"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/ethface"
+	"ndn-dpdk/iface/ifacetestfixture"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+func TestClient(t *testing.T) {
+	assert, require := makeAR(t)
+	slaves := dpdk.ListSlaveLCores()
+
+	dir, e := ioutil.TempDir("", "memifapp-test")
+	require.NoError(e)
+	defer os.RemoveAll(dir)
+
+	loc := ethface.NewMemifLocator(filepath.Join(dir, "memif.sock"), 1)
+	assert.Error(ethface.NewMemifLocator("memif.sock", 1).Validate())
+	assert.Error(ethface.NewMemifLocator(loc.SocketName, -1).Validate())
+
+	// forwarder side
+	fwFace, e := ethface.CreateMemif(loc, ethface.MemifRole_Master, portCfg)
+	require.NoError(e)
+	assert.Equal(loc, fwFace.GetLocator())
+	ifacetestfixture.CheckLocatorMarshal(t, fwFace.GetLocator())
+	_, e = ethface.CreateMemif(loc, ethface.MemifRole_Master, portCfg)
+	assert.Error(e)
+
+	var fwRxNames []string
+	fwRxl := iface.NewRxLoop(fwFace.GetNumaSocket())
+	fwRxl.SetLCore(slaves[0])
+	fwRxl.SetCallback(iface.WrapRxCb(func(burst iface.RxBurst) {
+		for _, interest := range burst.ListInterests() {
+			fwRxNames = append(fwRxNames, interest.GetName().String())
+			ndntestutil.ClosePacket(interest)
+		}
+	}))
+	require.NoError(fwRxl.Launch())
+	for _, rxg := range fwFace.ListRxGroups() {
+		require.NoError(fwRxl.AddRxGroup(rxg))
+	}
+	fwTxl := iface.NewTxLoop(fwFace.GetNumaSocket())
+	fwTxl.SetLCore(slaves[1])
+	require.NoError(fwTxl.Launch())
+	fwTxl.AddFace(fwFace)
+
+	// application side
+	var appRxNames []string
+	client, e := memifapp.Connect(loc, memifapp.Config{
+		PortConfig: portCfg,
+		RxLCore:    slaves[2],
+		TxLCore:    slaves[3],
+	}, func(burst iface.RxBurst) {
+		for _, data := range burst.ListData() {
+			appRxNames = append(appRxNames, data.GetName().String())
+			ndntestutil.ClosePacket(data)
+		}
+	})
+	require.NoError(e)
+	time.Sleep(time.Second) // wait for memif connection
+
+	client.Send(ndntestutil.MakeInterest("/A"))
+	fwFace.TxBurst([]ndn.Packet{ndntestutil.MakeData("/B").GetPacket()})
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal([]string{"/A"}, fwRxNames)
+	assert.Equal([]string{"/B"}, appRxNames)
+	assert.Equal(uint64(1), client.GetFace().ReadCounters().TxInterests)
+	assert.Equal(uint64(1), fwFace.ReadCounters().RxInterests)
+
+	assert.NoError(client.Close())
+
+	fwRxl.Stop()
+	for _, rxg := range fwFace.ListRxGroups() {
+		fwRxl.RemoveRxGroup(rxg)
+	}
+	fwRxl.Close()
+	fwTxl.Stop()
+	fwTxl.RemoveFace(fwFace)
+	fwTxl.Close()
+	devName := fwFace.GetPort().GetEthDev().GetName()
+	assert.NoError(fwFace.Close())
+	assert.False(dpdk.FindEthDev(devName).IsValid())
+}
@@ -0,0 +1,23 @@
+package memifapp_test
+
+import (
+	"os"
+	"testing"
+
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/iface/ethface"
+	"ndn-dpdk/iface/ifacetestfixture"
+)
+
+var portCfg ethface.PortConfig
+
+func TestMain(m *testing.M) {
+	portCfg.RxMp, portCfg.Mempools = ifacetestfixture.MakeMempools()
+	portCfg.RxqFrames = 64
+	portCfg.TxqPkts = 64
+	portCfg.TxqFrames = 64
+
+	os.Exit(m.Run())
+}
+
+var makeAR = dpdktestenv.MakeAR
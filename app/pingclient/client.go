@@ -14,6 +14,7 @@ import (
 
 	"ndn-dpdk/appinit"
 	"ndn-dpdk/container/pktqueue"
+	"ndn-dpdk/core/urcu"
 	"ndn-dpdk/dpdk"
 	"ndn-dpdk/iface"
 	"ndn-dpdk/ndn"
@@ -90,6 +91,9 @@ func (client *Client) AddPattern(cfg Pattern) (index int, e error) {
 	if cfg.HopLimit != 0 {
 		tplArgs = append(tplArgs, uint8(cfg.HopLimit))
 	}
+	if cfg.ParamsLen > 0 {
+		tplArgs = append(tplArgs, make(ndn.ApplicationParameters, cfg.ParamsLen))
+	}
 
 	client.clearCounter(index)
 	rxP := &client.Rx.c.pattern[index]
@@ -197,6 +201,8 @@ type ClientTxThread struct {
 // Launch the TX thread.
 func (tx *ClientTxThread) Launch() error {
 	return tx.LaunchImpl(func() int {
+		rs := urcu.NewReadSide()
+		defer rs.Close()
 		C.PingClientTx_Run(tx.c)
 		return 0
 	})
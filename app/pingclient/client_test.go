@@ -30,6 +30,7 @@ func TestClient(t *testing.T) {
 				MustBeFresh:      true,
 				InterestLifetime: 500,
 				HopLimit:         10,
+				ParamsLen:        100,
 			},
 			{
 				Weight: 45,
@@ -57,7 +58,8 @@ func TestClient(t *testing.T) {
 	face.OnTxInterest(func(interest *ndn.Interest) {
 		interestName := interest.GetName()
 		switch {
-		case interestName.Compare(nameA) == ndn.NAMECMP_RPREFIX && interestName.Len() == 2:
+		case interestName.Compare(nameA) == ndn.NAMECMP_RPREFIX && interestName.Len() == 3:
+			assert.True(interest.HasParams())
 			nInterestsA++
 		case interestName.Compare(nameB) == ndn.NAMECMP_RPREFIX && interestName.Len() == 2:
 			lastComp := interestName.GetComp(interestName.Len() - 1)
@@ -22,6 +22,7 @@ type Pattern struct {
 	MustBeFresh      bool                    // whether to set MustBeFresh
 	InterestLifetime nnduration.Milliseconds // InterestLifetime value, zero means default
 	HopLimit         int                     // HopLimit value, zero means default
+	ParamsLen        int                     // ApplicationParameters length, zero means omitted
 
 	// If non-zero, request cached Data. This must appear after a pattern without SeqNumOffset.
 	// The client derives sequece number by subtracting SeqNumOffset from the previous pattern's
@@ -43,6 +43,14 @@ export interface Pattern {
    */
   HopLimit?: number;
 
+  /**
+   * ApplicationParameters length.
+   * @TJS-type integer
+   * @default 0
+   * @minimum 0
+   */
+  ParamsLen?: number;
+
   /**
    * @TJS-type integer
    */
@@ -65,6 +65,7 @@ PingClientTx_Run(PingClientTx* ct)
 {
   TscTime nextTxBurst = rte_get_tsc_cycles();
   while (ThreadStopFlag_ShouldContinue(&ct->stop)) {
+    rcu_quiescent_state();
     if (rte_get_tsc_cycles() < nextTxBurst) {
       rte_pause();
       continue;
@@ -26,6 +26,7 @@ type Reply struct {
 	Suffix          *ndn.Name               // suffix to append to Interest name
 	FreshnessPeriod nnduration.Milliseconds // FreshnessPeriod value
 	PayloadLen      int                     // Content payload length
+	Signer          *ndn.SignerConfig       // Data signer, nil for fake signature
 
 	Nack ndn.NackReason // if not NackReason_None, reply with Nack instead of Data
 
@@ -36,6 +36,11 @@ interface ReplyData {
    * @minimum 0
    */
   PayloadLen?: number;
+
+  /**
+   * Data signer; if omitted, Data carries a fake DigestSha256 signature.
+   */
+  Signer?: ndn.SignerConfig;
 }
 
 interface ReplyNack {
@@ -51,7 +51,20 @@ PingServer_RespondData(PingServer* server,
     return NULL;
   }
 
-  DataGen_Encode(reply->dataGen, seg0, seg1, *name);
+  struct rte_mbuf* seg2 = NULL;
+  if (DataGen_IsSigned(reply->dataGen)) {
+    seg2 = rte_pktmbuf_alloc(server->dataMp);
+    if (unlikely(seg2 == NULL)) {
+      ZF_LOGW("dataMp-full");
+      ++server->nAllocError;
+      rte_pktmbuf_free(Packet_ToMbuf(npkt));
+      rte_pktmbuf_free(seg0);
+      rte_pktmbuf_free(seg1);
+      return NULL;
+    }
+  }
+
+  DataGen_Encode(reply->dataGen, seg0, seg1, seg2, *name);
   rte_pktmbuf_free(Packet_ToMbuf(npkt));
 
   Packet* response = Packet_FromMbuf(seg0);
@@ -131,6 +144,7 @@ PingServer_Run(PingServer* server)
   Packet* tx[PKTQUEUE_BURST_SIZE_MAX];
 
   while (ThreadStopFlag_ShouldContinue(&server->stop)) {
+    rcu_quiescent_state();
     uint32_t nRx = PktQueue_Pop(&server->rxQueue,
                                 (struct rte_mbuf**)rx,
                                 PKTQUEUE_BURST_SIZE_MAX,
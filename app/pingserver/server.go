@@ -11,6 +11,7 @@ import (
 
 	"ndn-dpdk/appinit"
 	"ndn-dpdk/container/pktqueue"
+	"ndn-dpdk/core/urcu"
 	"ndn-dpdk/dpdk"
 	"ndn-dpdk/iface"
 	"ndn-dpdk/ndn"
@@ -19,8 +20,10 @@ import (
 // Server instance and thread.
 type Server struct {
 	dpdk.ThreadBase
-	c      *C.PingServer
-	seg1Mp dpdk.PktmbufPool
+	c       *C.PingServer
+	seg1Mp  dpdk.PktmbufPool
+	socket  dpdk.NumaSocket
+	signers []*ndn.Signer
 }
 
 func New(face iface.IFace, index int, cfg Config) (server *Server, e error) {
@@ -44,6 +47,7 @@ func New(face iface.IFace, index int, cfg Config) (server *Server, e error) {
 
 	server = new(Server)
 	server.seg1Mp = appinit.MakePktmbufPool(appinit.MP_DATA1, socket)
+	server.socket = socket
 	server.c = serverC
 	server.ResetThreadBase()
 	dpdk.InitStopFlag(unsafe.Pointer(&serverC.stop))
@@ -94,11 +98,18 @@ func (server *Server) AddPattern(cfg Pattern) (index int, e error) {
 			replyC.nackReason = C.uint8_t(reply.Nack)
 		default:
 			replyC.kind = C.PINGSERVER_REPLY_DATA
+			var signer *ndn.Signer
+			if reply.Signer != nil {
+				if signer, e = ndn.NewSigner(*reply.Signer, server.socket); e != nil {
+					return -1, fmt.Errorf("signer for reply definition %d: %s", i, e)
+				}
+				server.signers = append(server.signers, signer)
+			}
 			m, e := server.seg1Mp.Alloc()
 			if e != nil {
 				return -1, fmt.Errorf("cannot allocate from MP_DATA1 for reply definition %d", i)
 			}
-			dataGen := ndn.NewDataGen(m, reply.Suffix, reply.FreshnessPeriod.Duration(), make(ndn.TlvBytes, reply.PayloadLen))
+			dataGen := ndn.NewDataGen(m, reply.Suffix, reply.FreshnessPeriod.Duration(), make(ndn.TlvBytes, reply.PayloadLen), signer)
 			replyC.dataGen = (*C.DataGen)(dataGen.GetPtr())
 		}
 	}
@@ -115,6 +126,8 @@ func (server *Server) GetRxQueue() pktqueue.PktQueue {
 // Launch the thread.
 func (server *Server) Launch() error {
 	return server.LaunchImpl(func() int {
+		rs := urcu.NewReadSide()
+		defer rs.Close()
 		C.PingServer_Run(server.c)
 		return 0
 	})
@@ -129,6 +142,9 @@ func (server *Server) Stop() error {
 // The thread must be stopped before calling this.
 func (server *Server) Close() error {
 	server.GetRxQueue().Close()
+	for _, signer := range server.signers {
+		signer.Close()
+	}
 	dpdk.Free(server.c)
 	return nil
 }
@@ -35,6 +35,7 @@ func TestServer(t *testing.T) {
 						Suffix:          ndn.MustParseName("/Z"),
 						FreshnessPeriod: 100,
 						PayloadLen:      2000,
+						Signer:          &ndn.SignerConfig{Type: ndn.Signer_Sha256},
 					},
 				},
 			},
@@ -135,6 +135,7 @@ const (
 	MP_INT   = "INT"   // TX Ethernet+NDNLP and encoding Interest
 	MP_DATA0 = "DATA0" // TX Ethernet+NDNLP+Data name prefix
 	MP_DATA1 = "DATA1" // TX Data name suffix and payload
+	MP_DISK  = "DISK"  // Data loaded from DiskStore
 )
 
 var SizeofEthLpHeaders = ethface.SizeofTxHeader
@@ -188,6 +189,12 @@ func init() {
 			PrivSize:     0,
 			DataroomSize: dpdk.MBUF_DEFAULT_HEADROOM + ndn.DataGen_GetTailroom1(ndn.NAME_MAX_LENGTH, 1500),
 		})
+	RegisterMempool(MP_DISK,
+		MempoolConfig{
+			Capacity:     65535,
+			PrivSize:     ndn.SizeofPacketPriv(),
+			DataroomSize: dpdk.MBUF_DEFAULT_HEADROOM + 16*512, // 16 DiskStore blocks
+		})
 }
 
 // Provide mempools to createface package.
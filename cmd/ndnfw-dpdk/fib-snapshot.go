@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+
+	"ndn-dpdk/container/fib"
+	"ndn-dpdk/container/strategycode"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/createface"
+	"ndn-dpdk/strategy/strategy_elf"
+)
+
+// Load FIB snapshot at startup, creating faces and loading strategies as needed.
+func loadFibSnapshot(filename string) {
+	logEntry := log.WithField("filename", filename)
+
+	file, e := os.Open(filename)
+	if os.IsNotExist(e) {
+		logEntry.Info("FIB snapshot does not exist")
+		return
+	} else if e != nil {
+		logEntry.WithError(e).Fatal("FIB snapshot open error")
+	}
+	defer file.Close()
+
+	resolveFace := func(loc iface.Locator) (iface.FaceId, error) {
+		if faceId, e := fib.FindSnapshotFace(loc); e == nil {
+			return faceId, nil
+		}
+		face, e := createface.Create(loc)
+		if e != nil {
+			return iface.FACEID_INVALID, e
+		}
+		return face.GetFaceId(), nil
+	}
+
+	resolveStrategy := func(name string) (strategycode.StrategyCode, error) {
+		if sc, e := fib.FindSnapshotStrategy(name); e == nil {
+			return sc, nil
+		}
+		elf, e := strategy_elf.Load(name)
+		if e != nil {
+			return nil, e
+		}
+		return strategycode.Load(name, elf)
+	}
+
+	nInserted, e := theDp.GetFib().Load(file, resolveFace, resolveStrategy)
+	if e != nil {
+		logEntry.WithError(e).Fatal("FIB snapshot load error")
+	}
+	logEntry.WithField("nInserted", nInserted).Info("FIB snapshot loaded")
+}
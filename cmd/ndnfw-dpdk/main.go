@@ -2,11 +2,13 @@ package main
 
 import (
 	"os"
+	"path/filepath"
 
 	"ndn-dpdk/app/fwdp"
 	"ndn-dpdk/container/fib"
 	"ndn-dpdk/container/ndt"
 	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface/createface"
 	"ndn-dpdk/mgmt/hrlog"
 )
 
@@ -20,10 +22,26 @@ func main() {
 	log.WithField("nSlaves", len(dpdk.ListSlaveLCores())).Info("EAL ready")
 	hrlog.Init()
 
+	if initCfg.NfdMgmt {
+		initCfg.Face.EnableMock = true // NFD management face is implemented with a mock face
+	}
 	initCfg.InitConfig.Apply()
 
+	if initCfg.FibSnapshotDir != "" && !filepath.IsAbs(initCfg.FibSnapshotDir) {
+		log.Fatal("FibSnapshotDir must be an absolute path")
+	}
+
 	startDp(initCfg.Ndt, initCfg.Fib, initCfg.Fwdp)
-	startMgmt()
+	if initCfg.FibSnapshot != "" {
+		loadFibSnapshot(initCfg.FibSnapshot)
+	}
+	startMgmt(initCfg.FibSnapshotDir)
+	if e := createface.StartListeners(); e != nil {
+		log.WithError(e).Fatal("socket listener error")
+	}
+	if initCfg.NfdMgmt {
+		startNfdMgmt(initCfg.NfdMgmtTrustAnchors)
+	}
 
 	select {}
 }
@@ -37,6 +55,7 @@ func startDp(ndtCfg ndt.Config, fibCfg fib.Config, dpInit fwdpInitConfig) {
 	// set crypto config
 	dpCfg.Crypto.InputCapacity = 64
 	dpCfg.Crypto.OpPoolCapacity = 1023
+	dpCfg.Crypto.Verify = dpInit.Verify
 
 	// set dataplane config
 	dpCfg.FwdInterestQueue = dpInit.FwdInterestQueue
@@ -46,6 +65,7 @@ func startDp(ndtCfg ndt.Config, fibCfg fib.Config, dpInit fwdpInitConfig) {
 	dpCfg.Pcct.MaxEntries = dpInit.PcctCapacity
 	dpCfg.Pcct.CsCapMd = dpInit.CsCapMd
 	dpCfg.Pcct.CsCapMi = dpInit.CsCapMi
+	dpCfg.Disk = dpInit.CsDisk
 
 	// create dataplane
 	{
@@ -3,20 +3,27 @@ package main
 import (
 	"time"
 
+	"ndn-dpdk/app/fwdp"
 	"ndn-dpdk/appinit"
 	"ndn-dpdk/container/ndt/ndtupdater"
+	"ndn-dpdk/container/rib"
 	"ndn-dpdk/container/strategycode"
+	"ndn-dpdk/dpdk"
 	"ndn-dpdk/mgmt/facemgmt"
 	"ndn-dpdk/mgmt/fibmgmt"
 	"ndn-dpdk/mgmt/fwdpmgmt"
 	"ndn-dpdk/mgmt/hrlog"
 	"ndn-dpdk/mgmt/ndtmgmt"
+	"ndn-dpdk/mgmt/nfdmgmt"
+	"ndn-dpdk/mgmt/ribmgmt"
 	"ndn-dpdk/mgmt/strategymgmt"
 	"ndn-dpdk/mgmt/versionmgmt"
 	"ndn-dpdk/strategy/strategy_elf"
 )
 
-func startMgmt() {
+var theRib *rib.Rib
+
+func startMgmt(fibSnapshotDir string) {
 	appinit.RegisterMgmt(versionmgmt.VersionMgmt{})
 	appinit.RegisterMgmt(hrlog.HrlogMgmt{})
 
@@ -34,16 +41,36 @@ func startMgmt() {
 
 	appinit.RegisterMgmt(strategymgmt.StrategyMgmt{})
 
+	defaultStrategyId := loadStrategy("multicast").GetId()
+	theRib = rib.New(theDp.GetFib(), defaultStrategyId)
 	appinit.RegisterMgmt(fibmgmt.FibMgmt{
 		Fib:               theDp.GetFib(),
-		DefaultStrategyId: loadStrategy("multicast").GetId(),
+		DefaultStrategyId: defaultStrategyId,
+		Rib:               theRib,
+		SnapshotDir:       fibSnapshotDir,
 	})
+	appinit.RegisterMgmt(ribmgmt.RibMgmt{theRib})
 
 	appinit.RegisterMgmt(fwdpmgmt.DpInfoMgmt{theDp})
 
 	appinit.StartMgmt()
 }
 
+// Start NFD management face, which must be called after startMgmt.
+func startNfdMgmt(trustAnchors []fwdp.TrustAnchor) {
+	_, e := nfdmgmt.New(nfdmgmt.Config{
+		Fib:               theDp.GetFib(),
+		Rib:               theRib,
+		DefaultStrategyId: strategycode.Find("multicast").GetId(),
+		DataMp:            appinit.MakePktmbufPool(appinit.MP_ETHRX, dpdk.NUMA_SOCKET_ANY),
+		QueueCapacity:     64,
+		TrustAnchors:      trustAnchors,
+	})
+	if e != nil {
+		log.WithError(e).Fatal("NFD management face init error")
+	}
+}
+
 func loadStrategy(shortname string) strategycode.StrategyCode {
 	logEntry := log.WithField("strategy", shortname)
 
@@ -1,3 +1,4 @@
+import { DiskConfig, TrustAnchor, VerifyConfig } from "../../app/fwdp/mod";
 import { InitConfig as BaseInitConfig } from "../../appinit/mod";
 import { ConfigTemplate as FibConfig } from "../../container/fib/mod";
 import { Config as NdtConfig } from "../../container/ndt/mod";
@@ -13,10 +14,21 @@ export interface FwdpInitConfig {
   PcctCapacity?: number;
   CsCapMd?: number;
   CsCapMi?: number;
+  CsDisk?: DiskConfig;
+  Verify?: VerifyConfig;
 }
 
 export interface InitConfig extends BaseInitConfig {
   Ndt?: NdtConfig;
   Fib?: FibConfig;
   Fwdp?: FwdpInitConfig;
+  FibSnapshot?: string;
+  FibSnapshotDir?: string;
+
+  /**
+   * @default false
+   */
+  NfdMgmt?: boolean;
+
+  NfdMgmtTrustAnchors?: TrustAnchor[];
 }
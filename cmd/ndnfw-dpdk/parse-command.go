@@ -4,6 +4,7 @@ import (
 	"flag"
 	"os"
 
+	"ndn-dpdk/app/fwdp"
 	"ndn-dpdk/appinit"
 	"ndn-dpdk/container/fib"
 	"ndn-dpdk/container/ndt"
@@ -17,6 +18,12 @@ type initConfig struct {
 	Ndt  ndt.Config
 	Fib  fib.Config
 	Fwdp fwdpInitConfig
+
+	FibSnapshot    string // FIB snapshot file to load at startup
+	FibSnapshotDir string // directory where Fib.Save writes snapshots, empty disables Fib.Save
+	NfdMgmt        bool   // whether to enable NFD management face
+
+	NfdMgmtTrustAnchors []fwdp.TrustAnchor // keys trusted to sign NFD management commands
 }
 
 type fwdpInitConfig struct {
@@ -28,6 +35,8 @@ type fwdpInitConfig struct {
 	PcctCapacity      int
 	CsCapMd           int
 	CsCapMi           int
+	CsDisk            fwdp.DiskConfig
+	Verify            fwdp.VerifyConfig
 }
 
 func parseCommand(args []string) (initCfg initConfig, e error) {
@@ -5,8 +5,10 @@ package cs
 */
 import "C"
 import (
+	"errors"
 	"unsafe"
 
+	"ndn-dpdk/container/diskstore"
 	"ndn-dpdk/container/pcct"
 	"ndn-dpdk/ndn"
 )
@@ -46,6 +48,49 @@ func (cs Cs) CountEntries(cslId ListId) int {
 	return int(C.Cs_CountEntries(cs.getPtr(), C.CsListId(cslId)))
 }
 
+// Attach a DiskStore as second-tier storage, using slot numbers within [slotMin, slotMax].
+// maxPendingReads limits Interests waiting for DiskStore, and should not exceed reply queue capacity.
+func (cs Cs) SetDiskStore(store *diskstore.DiskStore, slotMin, slotMax uint64, maxPendingReads int) error {
+	if slotMin == 0 || slotMin > slotMax {
+		return errors.New("invalid slot range")
+	}
+	if maxPendingReads <= 0 {
+		return errors.New("maxPendingReads must be positive")
+	}
+	if !C.Cs_SetDisk(cs.getPtr(), (*C.DiskStore)(store.GetPtr()), C.uint64_t(slotMin), C.uint64_t(slotMax), C.uint32_t(maxPendingReads)) {
+		return errors.New("Cs_SetDisk failed")
+	}
+	return nil
+}
+
+// DiskStore counters.
+type DiskCounters struct {
+	NSlots      uint64 // total number of slots
+	NFreeSlots  uint64 // number of unused slots
+	NSpills     uint64 // Data written to DiskStore upon eviction
+	NSpillDrops uint64 // Data dropped upon eviction due to no free slot
+	NReads      uint64 // reads requested from DiskStore
+	NReadHits   uint64 // Data restored from DiskStore into memory
+	NReadFails  uint64 // reads that did not return usable Data
+	NReadBusy   uint64 // reads not requested due to in-progress I/O
+	NHeldSlots  uint64 // released slots waiting for I/O completion
+}
+
+// Read DiskStore counters.
+func (cs Cs) ReadDiskCounters() (cnt DiskCounters) {
+	csd := &C.Cs_GetPriv(cs.getPtr()).disk
+	cnt.NSlots = uint64(csd.nSlots)
+	cnt.NFreeSlots = uint64(csd.nFreeSlots)
+	cnt.NSpills = uint64(csd.nSpills)
+	cnt.NSpillDrops = uint64(csd.nSpillDrops)
+	cnt.NReads = uint64(csd.nReads)
+	cnt.NReadHits = uint64(csd.nReadHits)
+	cnt.NReadFails = uint64(csd.nReadFails)
+	cnt.NReadBusy = uint64(csd.nReadBusy)
+	cnt.NHeldSlots = uint64(csd.nHeldSlots)
+	return cnt
+}
+
 type iPitFindResult interface {
 	CopyToCPitFindResult(ptr unsafe.Pointer)
 }
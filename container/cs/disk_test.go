@@ -0,0 +1,85 @@
+package cs_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"ndn-dpdk/container/cs"
+	"ndn-dpdk/container/diskstore"
+	"ndn-dpdk/container/pcct"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+	"ndn-dpdk/spdk"
+)
+
+var initSpdkOnce sync.Once
+
+// Initialize SPDK, only needed by DiskStore tests.
+func initSpdk() {
+	initSpdkOnce.Do(func() {
+		spdk.MustInit(dpdk.ListSlaveLCores()[0])
+		spdk.InitBdevLib()
+	})
+}
+
+func TestDisk(t *testing.T) {
+	assert, require := makeAR(t)
+	initSpdk()
+
+	bdi, e := spdk.NewMallocBdev(diskstore.BLOCK_SIZE, 1024)
+	require.NoError(e)
+	defer spdk.DestroyMallocBdev(bdi)
+
+	mp := dpdktestenv.MakeMp("TestCsDisk", 255, ndn.SizeofPacketPriv(), 2560)
+	defer mp.Close()
+
+	store, e := diskstore.New(bdi, spdk.MainThread, mp, 4)
+	require.NoError(e)
+	defer store.Close()
+
+	var cfg pcct.Config
+	cfg.CsCapMd = 100
+	cfg.CsCapMi = 100
+	fixture := NewFixture(cfg)
+	defer fixture.Close()
+
+	assert.Error(fixture.Cs.SetDiskStore(store, 0, 100, 64))
+	assert.Error(fixture.Cs.SetDiskStore(store, 1, 200, 0))
+	require.NoError(fixture.Cs.SetDiskStore(store, 1, 200, 64))
+	cnt := fixture.Cs.ReadDiskCounters()
+	assert.Equal(uint64(200), cnt.NSlots)
+	assert.Equal(uint64(200), cnt.NFreeSlots)
+
+	// insert 1-100, use 1-60, insert 101-130: ARC moves 61-90 from T1 to B1
+	fixture.InsertBulk(1, 100, "/N/%d", "/N/%d")
+	fixture.FindBulk(1, 60, "/N/%d")
+	fixture.InsertBulk(101, 130, "/N/%d", "/N/%d")
+	assert.Equal(30, fixture.Cs.CountEntries(cs.CSL_MD_B1))
+	cnt = fixture.Cs.ReadDiskCounters()
+	assert.Equal(uint64(30), cnt.NSpills)
+	assert.Equal(uint64(0), cnt.NSpillDrops)
+	assert.Equal(uint64(170), cnt.NFreeSlots)
+	time.Sleep(100 * time.Millisecond) // wait for writes to complete
+
+	// B1 entry with Data on disk is reported as match, caller should read from disk
+	csEntry := fixture.Find(ndntestutil.MakeInterest("/N/61"))
+	require.NotNil(csEntry)
+	assert.NotZero(csEntry.GetDiskSlot())
+	cnt = fixture.Cs.ReadDiskCounters()
+	assert.Equal(uint64(1), cnt.NReads)
+	assert.Equal(uint64(0), cnt.NReadBusy)
+
+	// T2 entry has Data in memory
+	csEntry2 := fixture.Find(ndntestutil.MakeInterest("/N/1"))
+	require.NotNil(csEntry2)
+	assert.Zero(csEntry2.GetDiskSlot())
+
+	// erasing entry releases its slot
+	fixture.Cs.Erase(*csEntry)
+	cnt = fixture.Cs.ReadDiskCounters()
+	assert.Equal(uint64(171), cnt.NFreeSlots)
+	assert.Equal(uint64(0), cnt.NHeldSlots)
+}
@@ -41,6 +41,11 @@ func (entry Entry) ListIndirects() (indirects []Entry) {
 	return indirects
 }
 
+// Get DiskStore slot number, or 0 if Data is not in DiskStore.
+func (entry Entry) GetDiskSlot() uint64 {
+	return uint64(C.CsEntry_GetDirect(entry.c).diskSlot)
+}
+
 func (entry Entry) GetData() *ndn.Data {
 	return ndn.PacketFromPtr(unsafe.Pointer(C.CsEntry_GetData(entry.c))).AsData()
 }
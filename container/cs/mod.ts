@@ -0,0 +1,13 @@
+import { Counter } from "../../core/mod";
+
+export interface DiskCounters {
+  NSlots: Counter;
+  NFreeSlots: Counter;
+  NSpills: Counter;
+  NSpillDrops: Counter;
+  NReads: Counter;
+  NReadHits: Counter;
+  NReadFails: Counter;
+  NReadBusy: Counter;
+  NHeldSlots: Counter;
+}
@@ -24,11 +24,23 @@ typedef struct DiskStore_PutDataRequest
 {
   DiskStore* store;
   uint64_t slotId;
+  _Atomic uint32_t* pending;
 } DiskStore_PutDataRequest;
 static_assert(sizeof(DiskStore_PutDataRequest) <=
                 sizeof(((PData*)(NULL))->digest),
               "");
 
+static void
+DiskStore_PutData_Finish(Packet* npkt)
+{
+  PData* data = Packet_GetDataHdr(npkt);
+  DiskStore_PutDataRequest* req = (DiskStore_PutDataRequest*)&data->digest[0];
+  if (req->pending != NULL) {
+    atomic_fetch_sub_explicit(req->pending, 1, memory_order_acq_rel);
+  }
+  rte_pktmbuf_free(Packet_ToMbuf(npkt));
+}
+
 static void
 DiskStore_PutData_End(struct spdk_bdev_io* io, bool success, void* npkt0)
 {
@@ -41,7 +53,7 @@ DiskStore_PutData_End(struct spdk_bdev_io* io, bool success, void* npkt0)
     ZF_LOGW("PutData_End(%" PRIu64 ", %p): fail=io-err", slotId, npkt);
   }
 
-  rte_pktmbuf_free(Packet_ToMbuf(npkt));
+  DiskStore_PutData_Finish(npkt);
   spdk_bdev_free_io(io);
 }
 
@@ -67,26 +79,30 @@ DiskStore_PutData_Begin(void* npkt0)
   if (unlikely(res != 0)) {
     ZF_LOGW(
       "PutData_Begin(%" PRIu64 ", %p): fail=write(%d)", slotId, npkt, res);
-    rte_pktmbuf_free(Packet_ToMbuf(npkt));
+    DiskStore_PutData_Finish(npkt);
   }
 }
 
 void
-DiskStore_PutData(DiskStore* store, uint64_t slotId, Packet* npkt)
+DiskStore_PutData(DiskStore* store,
+                  uint64_t slotId,
+                  Packet* npkt,
+                  _Atomic uint32_t* pending)
 {
   assert(slotId > 0);
-  uint64_t blockCount = DiskStore_ComputeBlockCount(store, npkt);
-  if (unlikely(blockCount > store->nBlocksPerSlot)) {
-    ZF_LOGW("PutData(%" PRIu64 ", %p): fail=packet-too-long", slotId, npkt);
-    rte_pktmbuf_free(Packet_ToMbuf(npkt));
-    return;
-  }
-
   PData* data = Packet_GetDataHdr(npkt);
   data->hasDigest = false;
   DiskStore_PutDataRequest* req = (DiskStore_PutDataRequest*)&data->digest[0];
   req->store = store;
   req->slotId = slotId;
+  req->pending = pending;
+
+  uint64_t blockCount = DiskStore_ComputeBlockCount(store, npkt);
+  if (unlikely(blockCount > store->nBlocksPerSlot)) {
+    ZF_LOGW("PutData(%" PRIu64 ", %p): fail=packet-too-long", slotId, npkt);
+    DiskStore_PutData_Finish(npkt);
+    return;
+  }
   spdk_thread_send_msg(store->th, DiskStore_PutData_Begin, npkt);
 }
 
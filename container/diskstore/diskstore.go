@@ -46,6 +46,11 @@ func New(bdi spdk.BdevInfo, th *spdk.Thread, mp dpdk.PktmbufPool, nBlocksPerSlot
 	return store, nil
 }
 
+// Get native *C.DiskStore pointer to use in other packages.
+func (store *DiskStore) GetPtr() unsafe.Pointer {
+	return unsafe.Pointer(store.c)
+}
+
 func (store *DiskStore) Close() error {
 	store.th.Call(func() { C.spdk_put_io_channel(store.c.ch) })
 	dpdk.Free(store.c)
@@ -58,7 +63,7 @@ func (store *DiskStore) GetSlotIdRange() (min, max uint64) {
 
 // Asynchronously store a Data packet.
 func (store *DiskStore) PutData(slotId uint64, data *ndn.Data) {
-	C.DiskStore_PutData(store.c, C.uint64_t(slotId), (*C.Packet)(data.GetPacket().GetPtr()))
+	C.DiskStore_PutData(store.c, C.uint64_t(slotId), (*C.Packet)(data.GetPacket().GetPtr()), nil)
 }
 
 // Retrieve a Data packet and wait for completion.
@@ -27,11 +27,16 @@ typedef struct DiskStore
 /** \brief Store a Data packet.
  *  \param slotId disk slot number; slot 0 cannot be used.
  *  \param npkt a Data packet. DiskStore takes ownership.
+ *  \param pending if not NULL, it is decremented after the write completes
+ *                 or fails, on the SPDK thread.
  *
  *  This function may be invoked on any thread, including non-SPDK thread.
  */
 void
-DiskStore_PutData(DiskStore* store, uint64_t slotId, Packet* npkt);
+DiskStore_PutData(DiskStore* store,
+                  uint64_t slotId,
+                  Packet* npkt,
+                  _Atomic uint32_t* pending);
 
 /** \brief Retrieve a Data packet.
  *  \param slotId disk slot number.
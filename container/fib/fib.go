@@ -6,12 +6,15 @@ package fib
 import "C"
 import (
 	"errors"
+	"io"
+	"time"
 	"unsafe"
 
 	"ndn-dpdk/container/fib/fibtree"
 	"ndn-dpdk/container/ndt"
 	"ndn-dpdk/core/urcu"
 	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
 	"ndn-dpdk/ndn"
 )
 
@@ -29,6 +32,10 @@ type Fib struct {
 	parts    []*partition
 	tree     *fibtree.Tree
 	commands chan command
+
+	expirations   map[string]expiration // accessed in command loop only
+	expireTimer   *time.Timer
+	faceClosedEvt io.Closer
 }
 
 func New(cfg Config, ndt *ndt.Ndt, numaSockets []dpdk.NumaSocket) (fib *Fib, e error) {
@@ -52,9 +59,14 @@ func New(cfg Config, ndt *ndt.Ndt, numaSockets []dpdk.NumaSocket) (fib *Fib, e e
 	fib.tree = fibtree.New(cfg.StartDepth, ndt.GetPrefixLen(), ndt.CountElements(),
 		func(name *ndn.Name) uint64 { return ndt.GetIndex(ndt.ComputeHash(name)) })
 
+	fib.expirations = make(map[string]expiration)
+	fib.expireTimer = time.NewTimer(time.Hour)
+	fib.expireTimer.Stop()
 	fib.commands = make(chan command)
 	go fib.commandLoop()
 
+	fib.faceClosedEvt = iface.OnFaceClosed(fib.handleFaceClosed)
+
 	return fib, nil
 }
 
@@ -81,10 +93,21 @@ func (fib *Fib) commandLoop() {
 	rs := urcu.NewReadSide()
 	defer rs.Close()
 	rs.Offline()
-	for cmd, ok := <-fib.commands; ok; cmd, ok = <-fib.commands {
-		rs.Online()
-		cmd.done <- cmd.f(rs)
-		rs.Offline()
+	for {
+		select {
+		case cmd, ok := <-fib.commands:
+			if !ok {
+				fib.expireTimer.Stop()
+				return
+			}
+			rs.Online()
+			cmd.done <- cmd.f(rs)
+			rs.Offline()
+		case <-fib.expireTimer.C:
+			rs.Online()
+			fib.expireCmd(rs)
+			rs.Offline()
+		}
 	}
 }
 
@@ -96,6 +119,7 @@ func (fib *Fib) postCommand(f func(rs *urcu.ReadSide) error) error {
 }
 
 func (fib *Fib) Close() (e error) {
+	fib.faceClosedEvt.Close()
 	e = fib.postCommand(fib.doClose)
 	close(fib.commands)
 	return e
@@ -0,0 +1,85 @@
+package fibtest
+
+import (
+	"testing"
+	"time"
+
+	"ndn-dpdk/container/strategycode"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/ndn"
+)
+
+func TestFaceClosed(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewFixture(2, 4, 2)
+	defer fixture.Close()
+
+	face := mockface.New()
+	faceId := face.GetFaceId()
+
+	strategyP := strategycode.MakeEmpty("P")
+	fib := fixture.Fib
+	_, e := fib.Insert(fixture.MakeEntry("/A", strategyP, faceId))
+	require.NoError(e)
+	_, e = fib.Insert(fixture.MakeEntry("/B/C/D/E", strategyP, 9000, faceId, 9001))
+	require.NoError(e)
+	_, e = fib.Insert(fixture.MakeEntry("/F", strategyP, 9000))
+	require.NoError(e)
+
+	face.Close()
+	fixture.CheckEntryNames(assert, []string{"/B/C/D/E", "/F"})
+	if entry := fib.Find(ndn.MustParseName("/B/C/D/E")); assert.NotNil(entry) {
+		assert.Equal([]iface.FaceId{9000, 9001}, entry.GetNexthops())
+		assert.Equal(strategyP.GetId(), entry.GetStrategy().GetId())
+	}
+	if entry := fib.Find(ndn.MustParseName("/F")); assert.NotNil(entry) {
+		assert.Equal([]iface.FaceId{9000}, entry.GetNexthops())
+	}
+}
+
+func TestExpiration(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewFixture(0, 2, 1)
+	defer fixture.Close()
+
+	strategyP := strategycode.MakeEmpty("P")
+	fib := fixture.Fib
+	nameA := ndn.MustParseName("/A")
+	nameB := ndn.MustParseName("/B")
+	nameC := ndn.MustParseName("/C")
+
+	assert.Error(fib.SetExpiration(nameA, time.Now().Add(time.Hour))) // entry does not exist
+
+	_, e := fib.Insert(fixture.MakeEntry("/A", strategyP, 2001))
+	require.NoError(e)
+	_, e = fib.Insert(fixture.MakeEntry("/B", strategyP, 2002))
+	require.NoError(e)
+	_, e = fib.Insert(fixture.MakeEntry("/C", strategyP, 2003))
+	require.NoError(e)
+
+	expA := time.Now().Add(100 * time.Millisecond)
+	require.NoError(fib.SetExpiration(nameA, expA))
+	assert.True(expA.Equal(fib.GetExpiration(nameA)))
+	require.NoError(fib.SetExpiration(nameB, time.Now().Add(200*time.Millisecond)))
+	require.NoError(fib.SetExpiration(nameC, time.Now().Add(100*time.Millisecond)))
+	require.NoError(fib.SetExpiration(nameC, time.Time{})) // cancel expiration
+	assert.True(fib.GetExpiration(nameC).IsZero())
+
+	// replacing an entry retains its expiration time
+	_, e = fib.Insert(fixture.MakeEntry("/B", strategyP, 2004))
+	require.NoError(e)
+	assert.False(fib.GetExpiration(nameB).IsZero())
+
+	time.Sleep(150 * time.Millisecond)
+	fixture.CheckEntryNames(assert, []string{"/B", "/C"})
+	assert.True(fib.GetExpiration(nameA).IsZero())
+
+	time.Sleep(100 * time.Millisecond)
+	fixture.CheckEntryNames(assert, []string{"/C"})
+
+	// re-inserted entry does not inherit expiration of erased entry
+	_, e = fib.Insert(fixture.MakeEntry("/A", strategyP, 2001))
+	require.NoError(e)
+	assert.True(fib.GetExpiration(nameA).IsZero())
+}
@@ -0,0 +1,62 @@
+package fibtest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"ndn-dpdk/container/strategycode"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/ndn"
+)
+
+func TestSnapshot(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewFixture(2, 4, 2)
+	defer fixture.Close()
+
+	face := mockface.New()
+	defer face.Close()
+	faceId := face.GetFaceId()
+
+	strategyP := strategycode.MakeEmpty("P")
+	fib := fixture.Fib
+	_, e := fib.Insert(fixture.MakeEntry("/A", strategyP, faceId))
+	require.NoError(e)
+	_, e = fib.Insert(fixture.MakeEntry("/B/C/D/E", strategyP, 9000, faceId))
+	require.NoError(e)
+	_, e = fib.Insert(fixture.MakeEntry("/F", strategyP, 9000)) // face 9000 does not exist
+	require.NoError(e)
+	_, e = fib.Insert(fixture.MakeEntry("/G", strategyP, faceId))
+	require.NoError(e)
+	expG := time.Now().Add(time.Hour)
+	require.NoError(fib.SetExpiration(ndn.MustParseName("/G"), expG))
+
+	var buf bytes.Buffer
+	require.NoError(fib.Save(&buf))
+	snapshot := buf.String()
+	assert.Equal(4, strings.Count(snapshot, "\n"))
+	assert.Contains(snapshot, `"Strategy":"P"`)
+
+	for _, name := range fib.ListNames() {
+		require.NoError(fib.Erase(name))
+	}
+	assert.Equal(0, fib.Len())
+
+	nInserted, e := fib.Load(strings.NewReader(snapshot), nil, nil)
+	require.NoError(e)
+	assert.Equal(3, nInserted)
+	fixture.CheckEntryNames(assert, []string{"/A", "/B/C/D/E", "/G"})
+	if entry := fib.Find(ndn.MustParseName("/B/C/D/E")); assert.NotNil(entry) {
+		assert.Equal([]iface.FaceId{faceId}, entry.GetNexthops())
+		assert.Equal(strategyP.GetId(), entry.GetStrategy().GetId())
+	}
+
+	assert.True(fib.GetExpiration(ndn.MustParseName("/A")).IsZero())
+	assert.True(fib.GetExpiration(ndn.MustParseName("/G")).Equal(expG))
+
+	_, e = fib.Load(strings.NewReader("{"), nil, nil)
+	assert.Error(e)
+}
@@ -5,10 +5,18 @@ import (
 	"testing"
 
 	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/ndn"
 )
 
 func TestMain(m *testing.M) {
 	dpdktestenv.MakeDirectMp(255, 0, 2000)
+	mockface.FaceMempools = iface.Mempools{
+		IndirectMp: dpdktestenv.MakeIndirectMp(255),
+		NameMp:     dpdktestenv.MakeMp("name", 255, 0, ndn.NAME_MAX_LENGTH),
+		HeaderMp:   dpdktestenv.MakeMp("header", 255, 0, 256),
+	}
 
 	os.Exit(m.Run())
 }
@@ -0,0 +1,129 @@
+package fib
+
+import (
+	"errors"
+	"time"
+
+	"ndn-dpdk/container/fib/fibtree"
+	"ndn-dpdk/core/urcu"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/ndn"
+)
+
+type expiration struct {
+	name *ndn.Name
+	t    time.Time
+}
+
+// Set expiration time of a FIB entry.
+// The entry is erased when it expires; zero time means the entry never expires.
+// Replacing the entry via Insert retains its expiration time.
+func (fib *Fib) SetExpiration(name *ndn.Name, t time.Time) error {
+	logEntry := log.WithFields(makeLogFields("name", name, "expiration", t))
+	return fib.postCommand(func(rs *urcu.ReadSide) error {
+		if fib.findCmd(rs, name) == nil {
+			logEntry.Error("SetExpiration entry does not exist")
+			return errors.New("entry does not exist")
+		}
+
+		if t.IsZero() {
+			delete(fib.expirations, name.String())
+		} else {
+			fib.expirations[name.String()] = expiration{name, t}
+		}
+		fib.scheduleExpiration()
+		logEntry.Info("SetExpiration")
+		return nil
+	})
+}
+
+// Get expiration time of a FIB entry.
+// Return zero time if the entry does not exist or never expires.
+func (fib *Fib) GetExpiration(name *ndn.Name) (t time.Time) {
+	fib.postCommand(func(rs *urcu.ReadSide) error {
+		t = fib.expirations[name.String()].t
+		return nil
+	})
+	return t
+}
+
+// Find an entry in any partition it appears in, executing in the command loop.
+func (fib *Fib) findCmd(rs *urcu.ReadSide, name *ndn.Name) *Entry {
+	return fib.FindInPartition(name, fib.listPartitionsForName(name)[0].index, rs)
+}
+
+// Reset expireTimer to the earliest expiration time, executing in the command loop.
+func (fib *Fib) scheduleExpiration() {
+	if !fib.expireTimer.Stop() {
+		select {
+		case <-fib.expireTimer.C:
+		default:
+		}
+	}
+
+	var earliest time.Time
+	for _, exp := range fib.expirations {
+		if earliest.IsZero() || exp.t.Before(earliest) {
+			earliest = exp.t
+		}
+	}
+	if !earliest.IsZero() {
+		fib.expireTimer.Reset(time.Until(earliest))
+	}
+}
+
+// Erase expired entries, executing in the command loop.
+func (fib *Fib) expireCmd(rs *urcu.ReadSide) {
+	now := time.Now()
+	for _, exp := range fib.expirations {
+		if exp.t.After(now) {
+			continue
+		}
+		fib.eraseCmd(rs, exp.name)
+		delete(fib.expirations, exp.name.String())
+	}
+	fib.scheduleExpiration()
+}
+
+// Remove a closed face from nexthops of all entries.
+// An entry whose only nexthop is the closed face is erased.
+func (fib *Fib) handleFaceClosed(id iface.FaceId) {
+	fib.postCommand(func(rs *urcu.ReadSide) error {
+		var names []*ndn.Name
+		fib.tree.Traverse(func(name *ndn.Name, n *fibtree.Node) bool {
+			if n.IsEntry {
+				names = append(names, name)
+			}
+			return true
+		})
+
+		for _, name := range names {
+			entry := fib.findCmd(rs, name)
+			if entry == nil {
+				continue
+			}
+
+			oldNexthops := entry.GetNexthops()
+			nexthops := make([]iface.FaceId, 0, len(oldNexthops))
+			for _, nh := range oldNexthops {
+				if nh != id {
+					nexthops = append(nexthops, nh)
+				}
+			}
+			if len(nexthops) == len(oldNexthops) {
+				continue
+			}
+
+			if len(nexthops) == 0 {
+				fib.eraseCmd(rs, name)
+				continue
+			}
+			newEntry := new(Entry)
+			newEntry.SetName(name)
+			newEntry.SetNexthops(nexthops)
+			newEntry.SetStrategy(entry.GetStrategy())
+			fib.insertCmd(rs, newEntry)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,173 @@
+package fib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"ndn-dpdk/container/fib/fibtree"
+	"ndn-dpdk/container/strategycode"
+	"ndn-dpdk/core/urcu"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/ndn"
+)
+
+// A FIB entry in snapshot.
+// Nexthops are identified by face locators, because FaceIds are not stable across restarts.
+type SnapshotEntry struct {
+	Name       *ndn.Name
+	Nexthops   []iface.LocatorWrapper
+	Strategy   string
+	Expiration time.Time // zero means never expires
+}
+
+// Resolve a nexthop locator to a FaceId during Fib.Load.
+type SnapshotFaceResolver func(loc iface.Locator) (iface.FaceId, error)
+
+// Resolve a strategy name to a StrategyCode during Fib.Load.
+type SnapshotStrategyResolver func(name string) (strategycode.StrategyCode, error)
+
+// Find an existing face with the same locator.
+// This is the default SnapshotFaceResolver.
+func FindSnapshotFace(loc iface.Locator) (iface.FaceId, error) {
+	for it := iface.IterFaces(); it.Valid(); it.Next() {
+		if reflect.DeepEqual(it.Face.GetLocator(), loc) {
+			return it.Id, nil
+		}
+	}
+	return iface.FACEID_INVALID, errors.New("face not found")
+}
+
+// Find a loaded strategy with the same name.
+// This is the default SnapshotStrategyResolver.
+func FindSnapshotStrategy(name string) (strategycode.StrategyCode, error) {
+	if sc := strategycode.Find(name); sc != nil {
+		return sc, nil
+	}
+	return nil, errors.New("strategy not found")
+}
+
+// Write a snapshot of all FIB entries.
+// The snapshot contains one JSON-encoded SnapshotEntry per line.
+func (fib *Fib) Save(w io.Writer) error {
+	var entries []SnapshotEntry
+	fib.postCommand(func(rs *urcu.ReadSide) error {
+		fib.tree.Traverse(func(name *ndn.Name, n *fibtree.Node) bool {
+			if n.IsEntry {
+				_, partition := fib.ndt.Lookup(name)
+				if entry := fib.FindInPartition(name, int(partition), rs); entry != nil {
+					se := makeSnapshotEntry(entry)
+					se.Expiration = fib.expirations[name.String()].t
+					entries = append(entries, se)
+				}
+			}
+			return true
+		})
+		return nil
+	})
+
+	encoder := json.NewEncoder(w)
+	for _, se := range entries {
+		if e := encoder.Encode(se); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func makeSnapshotEntry(entry *Entry) (se SnapshotEntry) {
+	se.Name = entry.GetName()
+	se.Strategy = entry.GetStrategy().GetName()
+	for _, nh := range entry.GetNexthops() {
+		if face := iface.Get(nh); face != nil {
+			se.Nexthops = append(se.Nexthops, iface.LocatorWrapper{face.GetLocator()})
+		}
+	}
+	return se
+}
+
+// Insert FIB entries from a snapshot written by Save.
+// resolveFace and resolveStrategy map snapshot nexthops and strategies; nil means the default resolver.
+// Nexthops that cannot be resolved are skipped; an entry without resolvable nexthops is skipped.
+// An entry whose expiration time has passed is skipped.
+// Returns the number of inserted entries.
+func (fib *Fib) Load(r io.Reader, resolveFace SnapshotFaceResolver,
+	resolveStrategy SnapshotStrategyResolver) (nInserted int, e error) {
+	if resolveFace == nil {
+		resolveFace = FindSnapshotFace
+	}
+	if resolveStrategy == nil {
+		resolveStrategy = FindSnapshotStrategy
+	}
+
+	decoder := json.NewDecoder(r)
+	for {
+		var se SnapshotEntry
+		if e = decoder.Decode(&se); e == io.EOF {
+			return nInserted, nil
+		} else if e != nil {
+			return nInserted, fmt.Errorf("snapshot decode error: %v", e)
+		}
+
+		logEntry := log.WithFields(makeLogFields("name", se.Name, "strategy", se.Strategy))
+		if !se.Expiration.IsZero() && !se.Expiration.After(time.Now()) {
+			logEntry.Info("snapshot entry expired")
+			continue
+		}
+		entry, e := se.resolve(resolveFace, resolveStrategy)
+		if e != nil {
+			logEntry.WithError(e).Warn("snapshot entry skipped")
+			continue
+		}
+
+		if _, e = fib.Insert(entry); e != nil {
+			return nInserted, fmt.Errorf("Fib.Insert(%s): %v", se.Name, e)
+		}
+		if e = fib.SetExpiration(se.Name, se.Expiration); e != nil {
+			return nInserted, fmt.Errorf("Fib.SetExpiration(%s): %v", se.Name, e)
+		}
+		nInserted++
+	}
+}
+
+func (se SnapshotEntry) resolve(resolveFace SnapshotFaceResolver,
+	resolveStrategy SnapshotStrategyResolver) (entry *Entry, e error) {
+	if se.Name == nil {
+		return nil, errors.New("missing name")
+	}
+
+	var nexthops []iface.FaceId
+	hasNexthop := make(map[iface.FaceId]bool)
+	for _, locw := range se.Nexthops {
+		faceId, e := resolveFace(locw.Locator)
+		if e != nil {
+			log.WithFields(makeLogFields("name", se.Name, "locator", locw)).WithError(e).Warn("snapshot nexthop skipped")
+			continue
+		}
+		if !hasNexthop[faceId] && len(nexthops) < MAX_NEXTHOPS {
+			hasNexthop[faceId] = true
+			nexthops = append(nexthops, faceId)
+		}
+	}
+	if len(nexthops) == 0 {
+		return nil, errors.New("no resolvable nexthop")
+	}
+
+	sc, e := resolveStrategy(se.Strategy)
+	if e != nil {
+		return nil, e
+	}
+
+	entry = new(Entry)
+	if e = entry.SetName(se.Name); e != nil {
+		return nil, e
+	}
+	if e = entry.SetNexthops(nexthops); e != nil {
+		return nil, e
+	}
+	entry.SetStrategy(sc)
+	return entry, nil
+}
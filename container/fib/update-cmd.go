@@ -74,212 +74,227 @@ func (fib *Fib) Insert(entry *Entry) (isNew bool, e error) {
 	if entry.GetStrategy() == nil {
 		return false, errors.New("cannot insert FIB entry with no strategy")
 	}
+	e = fib.postCommand(func(rs *urcu.ReadSide) (e error) {
+		isNew, e = fib.insertCmd(rs, entry)
+		return e
+	})
+	return isNew, e
+}
+
+// Insert a FIB entry, executing in the command loop.
+func (fib *Fib) insertCmd(rs *urcu.ReadSide, entry *Entry) (isNew bool, e error) {
 	name := entry.GetName()
 	virtName := fib.getVirtName(name)
 	logEntry := log.WithFields(makeLogFields("name", name, "nexthops", entry.GetNexthops(), "strategy", entry.GetStrategy().GetId()))
 
-	e = fib.postCommand(func(rs *urcu.ReadSide) error {
-		rs.Lock()
-		defer rs.Unlock()
-
-		// update tree
-		isNewInTree, oldMd, newMd, virtIsEntry := fib.tree.Insert(name)
-		success := false
-		defer func() {
-			if !success && isNewInTree {
-				fib.tree.Erase(name)
-			}
-		}()
-		logEntry = logEntry.WithField("isNew", isNewInTree)
-		isNew = isNewInTree
-
-		// determine what partition(s) should receive new entry
-		parts := fib.listPartitionsForName(name)
-		logEntry = logEntry.WithField("partition", listPartitionNumbers(parts))
-
-		var batch updateBatch
-		for _, part := range parts {
-			// prepare new entry
-			newEntry := part.Alloc(name)
-			if newEntry == nil {
-				return batch.Discard(part)
-			}
-			C.FibEntry_Copy(newEntry, &entry.c)
-			batch = append(batch, updateItem{updateActInsert, part, newEntry, C.Fib_FreeOld_MustNotExist, C.Fib_FreeOld_YesIfExists})
-
-			switch {
-			case name.Len() < fib.cfg.StartDepth:
-				// virtual entry not involved
+	defer func() {
+		if e != nil {
+			logEntry.WithError(e).Error("Insert")
+		} else {
+			logEntry.Info("Insert")
+		}
+	}()
 
-			case name.Len() == fib.cfg.StartDepth && newMd == 0:
-				// no virtual entry necessary
+	rs.Lock()
+	defer rs.Unlock()
 
-			case name.Len() == fib.cfg.StartDepth && newMd > 0:
-				// insert virtual entry before real entry
-				newVirt := part.Alloc(virtName)
-				if newVirt == nil {
-					return batch.Discard(part)
-				}
-				newVirt.maxDepth = C.uint8_t(newMd)
-				*(C.FibEntry_GetRealPtr_(newVirt)) = newEntry
-				batch[len(batch)-1] = updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_YesIfExists, C.Fib_FreeOld_YesIfExists}
+	// update tree
+	isNewInTree, oldMd, newMd, virtIsEntry := fib.tree.Insert(name)
+	success := false
+	defer func() {
+		if !success && isNewInTree {
+			fib.tree.Erase(name)
+		}
+	}()
+	logEntry = logEntry.WithField("isNew", isNewInTree)
+	isNew = isNewInTree
+
+	// determine what partition(s) should receive new entry
+	parts := fib.listPartitionsForName(name)
+	logEntry = logEntry.WithField("partition", listPartitionNumbers(parts))
+
+	var batch updateBatch
+	for _, part := range parts {
+		// prepare new entry
+		newEntry := part.Alloc(name)
+		if newEntry == nil {
+			return isNew, batch.Discard(part)
+		}
+		C.FibEntry_Copy(newEntry, &entry.c)
+		batch = append(batch, updateItem{updateActInsert, part, newEntry, C.Fib_FreeOld_MustNotExist, C.Fib_FreeOld_YesIfExists})
 
-			case name.Len() > fib.cfg.StartDepth && oldMd == newMd:
-				// no virtual entry update necessary
+		switch {
+		case name.Len() < fib.cfg.StartDepth:
+			// virtual entry not involved
 
-			case name.Len() > fib.cfg.StartDepth && oldMd != newMd && !virtIsEntry:
-				// insert or replace virtual entry; no real entry at virtName
-				newVirt := part.Alloc(virtName)
-				if newVirt == nil {
-					return batch.Discard(part)
-				}
-				newVirt.maxDepth = C.uint8_t(newMd)
-				batch = append(batch, updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_YesIfExists, C.Fib_FreeOld_MustNotExist})
-
-			case name.Len() > fib.cfg.StartDepth && oldMd != newMd && virtIsEntry:
-				// insert or replace virtual entry before existing real entry at virtName
-				oldReal := C.FibEntry_GetReal(part.Get(virtName))
-				if oldReal == nil {
-					panic(fmt.Errorf("real entry %s missing in partition %d", virtName, part.index))
-				}
-				newVirt := part.Alloc(virtName)
-				if newVirt == nil {
-					return batch.Discard(part)
-				}
-				newVirt.maxDepth = C.uint8_t(newMd)
-				*(C.FibEntry_GetRealPtr_(newVirt)) = oldReal
-				batch = append(batch, updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_YesIfExists, C.Fib_FreeOld_No})
+		case name.Len() == fib.cfg.StartDepth && newMd == 0:
+			// no virtual entry necessary
 
-			default:
-				panic("unexpected case")
+		case name.Len() == fib.cfg.StartDepth && newMd > 0:
+			// insert virtual entry before real entry
+			newVirt := part.Alloc(virtName)
+			if newVirt == nil {
+				return isNew, batch.Discard(part)
 			}
-		}
-
-		// perform batch updates
-		batch.Apply()
-		success = true
-		return nil
-	})
+			newVirt.maxDepth = C.uint8_t(newMd)
+			*(C.FibEntry_GetRealPtr_(newVirt)) = newEntry
+			batch[len(batch)-1] = updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_YesIfExists, C.Fib_FreeOld_YesIfExists}
+
+		case name.Len() > fib.cfg.StartDepth && oldMd == newMd:
+			// no virtual entry update necessary
+
+		case name.Len() > fib.cfg.StartDepth && oldMd != newMd && !virtIsEntry:
+			// insert or replace virtual entry; no real entry at virtName
+			newVirt := part.Alloc(virtName)
+			if newVirt == nil {
+				return isNew, batch.Discard(part)
+			}
+			newVirt.maxDepth = C.uint8_t(newMd)
+			batch = append(batch, updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_YesIfExists, C.Fib_FreeOld_MustNotExist})
+
+		case name.Len() > fib.cfg.StartDepth && oldMd != newMd && virtIsEntry:
+			// insert or replace virtual entry before existing real entry at virtName
+			oldReal := C.FibEntry_GetReal(part.Get(virtName))
+			if oldReal == nil {
+				panic(fmt.Errorf("real entry %s missing in partition %d", virtName, part.index))
+			}
+			newVirt := part.Alloc(virtName)
+			if newVirt == nil {
+				return isNew, batch.Discard(part)
+			}
+			newVirt.maxDepth = C.uint8_t(newMd)
+			*(C.FibEntry_GetRealPtr_(newVirt)) = oldReal
+			batch = append(batch, updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_YesIfExists, C.Fib_FreeOld_No})
 
-	if e != nil {
-		logEntry.WithError(e).Error("Insert")
-	} else {
-		logEntry.Info("Insert")
+		default:
+			panic("unexpected case")
+		}
 	}
-	return isNew, e
+
+	// perform batch updates
+	batch.Apply()
+	success = true
+	return isNew, nil
 }
 
 // Erase a FIB entry by name.
-func (fib *Fib) Erase(name *ndn.Name) (e error) {
+func (fib *Fib) Erase(name *ndn.Name) error {
+	return fib.postCommand(func(rs *urcu.ReadSide) error {
+		return fib.eraseCmd(rs, name)
+	})
+}
+
+// Erase a FIB entry, executing in the command loop.
+func (fib *Fib) eraseCmd(rs *urcu.ReadSide, name *ndn.Name) (e error) {
 	virtName := fib.getVirtName(name)
 	logEntry := log.WithField("name", name)
 
-	e = fib.postCommand(func(rs *urcu.ReadSide) error {
-		rs.Lock()
-		defer rs.Unlock()
-
-		// update tree
-		isErasedInTree, oldMd, newMd, virtIsEntry := fib.tree.Erase(name)
-		if !isErasedInTree {
-			logEntry = logEntry.WithField("skip", "no-entry")
-			return errors.New("entry does not exist")
+	defer func() {
+		if e != nil {
+			logEntry.WithError(e).Error("Erase")
+		} else {
+			logEntry.Info("Erase")
 		}
-		success := false
-		defer func() {
-			if !success {
-				fib.tree.Insert(name)
-			}
-		}()
-
-		// determine what partition(s) are affected
-		parts := fib.listPartitionsForName(name)
-		logEntry = logEntry.WithField("partition", listPartitionNumbers(parts))
-
-		var batch updateBatch
-		for _, part := range parts {
-			// retrieve old entry
-			oldEntry := part.Get(name)
-			if oldEntry == nil {
-				panic(fmt.Errorf("entry %s missing in partition %d", name, part.index))
-			}
-			batch = append(batch, updateItem{updateActErase, part, oldEntry, C.Fib_FreeOld_MustNotExist, C.Fib_FreeOld_Yes})
+	}()
 
-			switch {
-			case name.Len() < fib.cfg.StartDepth:
-				// virtual entry not involved
+	rs.Lock()
+	defer rs.Unlock()
 
-			case name.Len() == fib.cfg.StartDepth && newMd == 0:
-				// erase real entry; erase virtual entry if exists
-				batch[len(batch)-1] = updateItem{updateActErase, part, oldEntry, C.Fib_FreeOld_YesIfExists, C.Fib_FreeOld_Yes}
+	// update tree
+	isErasedInTree, oldMd, newMd, virtIsEntry := fib.tree.Erase(name)
+	if !isErasedInTree {
+		logEntry = logEntry.WithField("skip", "no-entry")
+		return errors.New("entry does not exist")
+	}
+	success := false
+	defer func() {
+		if !success {
+			fib.tree.Insert(name)
+		}
+	}()
+
+	// determine what partition(s) are affected
+	parts := fib.listPartitionsForName(name)
+	logEntry = logEntry.WithField("partition", listPartitionNumbers(parts))
+
+	var batch updateBatch
+	for _, part := range parts {
+		// retrieve old entry
+		oldEntry := part.Get(name)
+		if oldEntry == nil {
+			panic(fmt.Errorf("entry %s missing in partition %d", name, part.index))
+		}
+		batch = append(batch, updateItem{updateActErase, part, oldEntry, C.Fib_FreeOld_MustNotExist, C.Fib_FreeOld_Yes})
 
-			case name.Len() == fib.cfg.StartDepth && newMd > 0:
-				// erase real entry; keep virtual entry by inserting another virtual entry
-				newVirt := part.Alloc(virtName)
-				if newVirt == nil {
-					return batch.Discard(part)
-				}
-				newVirt.maxDepth = C.uint8_t(newMd)
-				batch[len(batch)-1] = updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_Yes, C.Fib_FreeOld_Yes}
+		switch {
+		case name.Len() < fib.cfg.StartDepth:
+			// virtual entry not involved
 
-			case name.Len() > fib.cfg.StartDepth && oldMd == newMd:
-				// no virtual entry update necessary
+		case name.Len() == fib.cfg.StartDepth && newMd == 0:
+			// erase real entry; erase virtual entry if exists
+			batch[len(batch)-1] = updateItem{updateActErase, part, oldEntry, C.Fib_FreeOld_YesIfExists, C.Fib_FreeOld_Yes}
 
-			case name.Len() > fib.cfg.StartDepth && oldMd != newMd && newMd == 0 && !virtIsEntry:
-				// erase virtual entry; no real entry at virtName
-				oldVirt := part.Get(virtName)
-				if oldVirt == nil || oldVirt.maxDepth == 0 {
-					panic(fmt.Errorf("virtual entry %s missing in partition %d", name, part.index))
-				}
-				batch = append(batch, updateItem{updateActErase, part, oldVirt, C.Fib_FreeOld_Yes, C.Fib_FreeOld_MustNotExist})
+		case name.Len() == fib.cfg.StartDepth && newMd > 0:
+			// erase real entry; keep virtual entry by inserting another virtual entry
+			newVirt := part.Alloc(virtName)
+			if newVirt == nil {
+				return batch.Discard(part)
+			}
+			newVirt.maxDepth = C.uint8_t(newMd)
+			batch[len(batch)-1] = updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_Yes, C.Fib_FreeOld_Yes}
 
-			case name.Len() > fib.cfg.StartDepth && oldMd != newMd && newMd == 0 && virtIsEntry:
-				// erase virtual entry; keep real entry at virtName
-				oldReal := C.FibEntry_GetReal(part.Get(virtName))
-				if oldReal == nil {
-					panic(fmt.Errorf("real entry %s missing in partition %d", virtName, part.index))
-				}
-				batch = append(batch, updateItem{updateActInsertNoDiscard, part, oldReal, C.Fib_FreeOld_Yes, C.Fib_FreeOld_No})
+		case name.Len() > fib.cfg.StartDepth && oldMd == newMd:
+			// no virtual entry update necessary
 
-			case name.Len() > fib.cfg.StartDepth && oldMd != newMd && newMd > 0 && !virtIsEntry:
-				// replace virtual entry; no real entry at virtName
-				newVirt := part.Alloc(virtName)
-				if newVirt == nil {
-					return batch.Discard(part)
-				}
-				newVirt.maxDepth = C.uint8_t(newMd)
-				batch = append(batch, updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_Yes, C.Fib_FreeOld_MustNotExist})
-
-			case name.Len() > fib.cfg.StartDepth && oldMd != newMd && newMd > 0 && virtIsEntry:
-				// replace virtual entry; keep real entry at virtName
-				oldReal := C.FibEntry_GetReal(part.Get(virtName))
-				if oldReal == nil {
-					panic(fmt.Errorf("real entry %s missing in partition %d", virtName, part.index))
-				}
-				newVirt := part.Alloc(virtName)
-				if newVirt == nil {
-					return batch.Discard(part)
-				}
-				newVirt.maxDepth = C.uint8_t(newMd)
-				*(C.FibEntry_GetRealPtr_(newVirt)) = oldReal
-				batch = append(batch, updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_Yes, C.Fib_FreeOld_No})
+		case name.Len() > fib.cfg.StartDepth && oldMd != newMd && newMd == 0 && !virtIsEntry:
+			// erase virtual entry; no real entry at virtName
+			oldVirt := part.Get(virtName)
+			if oldVirt == nil || oldVirt.maxDepth == 0 {
+				panic(fmt.Errorf("virtual entry %s missing in partition %d", name, part.index))
+			}
+			batch = append(batch, updateItem{updateActErase, part, oldVirt, C.Fib_FreeOld_Yes, C.Fib_FreeOld_MustNotExist})
 
-			default:
-				panic("unexpected case")
+		case name.Len() > fib.cfg.StartDepth && oldMd != newMd && newMd == 0 && virtIsEntry:
+			// erase virtual entry; keep real entry at virtName
+			oldReal := C.FibEntry_GetReal(part.Get(virtName))
+			if oldReal == nil {
+				panic(fmt.Errorf("real entry %s missing in partition %d", virtName, part.index))
 			}
-		}
+			batch = append(batch, updateItem{updateActInsertNoDiscard, part, oldReal, C.Fib_FreeOld_Yes, C.Fib_FreeOld_No})
 
-		// perform batch updates
-		batch.Apply()
-		success = true
-		return nil
-	})
+		case name.Len() > fib.cfg.StartDepth && oldMd != newMd && newMd > 0 && !virtIsEntry:
+			// replace virtual entry; no real entry at virtName
+			newVirt := part.Alloc(virtName)
+			if newVirt == nil {
+				return batch.Discard(part)
+			}
+			newVirt.maxDepth = C.uint8_t(newMd)
+			batch = append(batch, updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_Yes, C.Fib_FreeOld_MustNotExist})
+
+		case name.Len() > fib.cfg.StartDepth && oldMd != newMd && newMd > 0 && virtIsEntry:
+			// replace virtual entry; keep real entry at virtName
+			oldReal := C.FibEntry_GetReal(part.Get(virtName))
+			if oldReal == nil {
+				panic(fmt.Errorf("real entry %s missing in partition %d", virtName, part.index))
+			}
+			newVirt := part.Alloc(virtName)
+			if newVirt == nil {
+				return batch.Discard(part)
+			}
+			newVirt.maxDepth = C.uint8_t(newMd)
+			*(C.FibEntry_GetRealPtr_(newVirt)) = oldReal
+			batch = append(batch, updateItem{updateActInsert, part, newVirt, C.Fib_FreeOld_Yes, C.Fib_FreeOld_No})
 
-	if e != nil {
-		logEntry.WithError(e).Error("Erase")
-	} else {
-		logEntry.Info("Erase")
+		default:
+			panic("unexpected case")
+		}
 	}
-	return e
+
+	// perform batch updates
+	batch.Apply()
+	success = true
+	delete(fib.expirations, name.String())
+	return nil
 }
 
 // Callback during relocate operation.
@@ -46,6 +46,12 @@ CsArc_SetP(CsArc* arc, double p)
   CsArc_p1(arc) = RTE_MAX(CsArc_p(arc), 1);
 }
 
+static void
+CsArc_ClearData(void* arg, CsEntry* entry)
+{
+  CsEntry_ClearData(entry);
+}
+
 void
 CsArc_Init(CsArc* arc, uint32_t capacity)
 {
@@ -59,6 +65,9 @@ CsArc_Init(CsArc* arc, uint32_t capacity)
   CsArc_c(arc) = capacity;
   CsArc_2c(arc) = 2 * capacity;
   CsArc_SetP(arc, 0.0);
+
+  arc->moveCb = CsArc_ClearData;
+  arc->moveCbArg = NULL;
 }
 
 static void
@@ -72,7 +81,7 @@ CsArc_Replace(CsArc* arc, bool isB2)
     moving = CsList_GetFront(&arc->T2);
     CsArc_Move(arc, moving, T2, B2);
   }
-  CsEntry_ClearData(moving);
+  (*arc->moveCb)(arc->moveCbArg, moving);
 }
 
 static void
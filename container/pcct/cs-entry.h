@@ -49,6 +49,16 @@ struct CsEntry
 
   CsArcListId arcList : 8;
 
+  /** \brief Length of Data packet stored in DiskStore.
+   *  \pre Valid if entry is direct and diskSlot is non-zero.
+   */
+  uint16_t diskDataLen;
+
+  /** \brief DiskStore slot number, or 0 if Data is not stored in DiskStore.
+   *  \pre Valid if entry is direct.
+   */
+  uint64_t diskSlot;
+
   /** \brief Associated indirect entries.
    *  \pre Valid if entry is indirect.
    */
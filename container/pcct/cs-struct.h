@@ -3,6 +3,7 @@
 
 /// \file
 
+#include "../diskstore/diskstore.h"
 #include "common.h"
 
 /** \brief prev-next pointers common in CsEntry and CsList.
@@ -30,6 +31,10 @@ typedef struct CsArc
   CsList T2;  // stored entries that appeared more than once
   CsList B2;  // tracked entries that appeared more than once
   CsList DEL; // deleted entries
+
+  // invoked when an entry moves from T1/T2 to B1/B2, must release its Data
+  void (*moveCb)(void* arg, struct CsEntry* entry);
+  void* moveCbArg;
   // B1.capacity is c, the total capacity
   // B2.capacity is 2c, twice the total capacity
   // T1.capacity is (uint32_t)p
@@ -47,6 +52,37 @@ typedef enum CsArcListId
   CSL_ARC_DEL,
 } CsArcListId;
 
+/** \brief DiskStore attached to CS as second-tier storage.
+ */
+typedef struct CsDisk
+{
+  DiskStore* store;    ///< DiskStore instance, NULL if disabled
+  uint64_t* freeSlots; ///< stack of unused slot numbers
+  uint32_t nFreeSlots; ///< number of unused slot numbers
+  uint32_t nSlots;     ///< total number of slot numbers
+  uint64_t slotMin;    ///< minimum slot number
+
+  /** \brief Released slot numbers waiting for I/O completion.
+   *
+   *  A slot cannot be reused while a write to it is in progress, or while an
+   *  Interest that read from it has not returned from DiskStore.
+   */
+  uint64_t* heldSlots;
+  uint32_t nHeldSlots;
+
+  _Atomic uint32_t* slotWrites; ///< per-slot in-progress writes
+  uint16_t* slotReads;          ///< per-slot Interests not yet returned
+  uint32_t nPendingReads;       ///< Interests not yet returned
+  uint32_t maxPendingReads;     ///< limit of nPendingReads
+
+  uint64_t nSpills;     ///< Data written to DiskStore upon eviction
+  uint64_t nSpillDrops; ///< Data dropped upon eviction due to no free slot
+  uint64_t nReads;      ///< reads requested from DiskStore
+  uint64_t nReadHits;   ///< Data restored from DiskStore into memory
+  uint64_t nReadFails;  ///< reads that did not return usable Data
+  uint64_t nReadBusy;   ///< reads not requested due to in-progress I/O
+} CsDisk;
+
 /** \brief The Content Store (CS).
  *
  *  Cs* is Pcct*.
@@ -61,6 +97,7 @@ typedef struct CsPriv
 {
   CsArc directArc;    ///< ARC lists of direct entries
   CsList indirectLru; ///< LRU list of indirect entries
+  CsDisk disk;        ///< second-tier DiskStore
 } CsPriv;
 
 #endif // NDN_DPDK_CONTAINER_PCCT_CS_STRUCT_H
@@ -8,6 +8,94 @@ INIT_ZF_LOG(Cs);
 // Bulk size of CS eviction, also the minimum CS capacity.
 #define CS_EVICT_BULK 64
 
+/** \brief Determine whether a slot has in-progress I/O.
+ */
+static inline bool
+CsDisk_IsBusy(CsDisk* csd, uint64_t slot)
+{
+  uint64_t i = slot - csd->slotMin;
+  return atomic_load_explicit(&csd->slotWrites[i], memory_order_acquire) > 0 ||
+         csd->slotReads[i] > 0;
+}
+
+/** \brief Move held slots without in-progress I/O to the free list.
+ */
+static void
+CsDisk_ReclaimSlots(CsDisk* csd)
+{
+  uint32_t nHeld = 0;
+  for (uint32_t i = 0; i < csd->nHeldSlots; ++i) {
+    uint64_t slot = csd->heldSlots[i];
+    if (CsDisk_IsBusy(csd, slot)) {
+      csd->heldSlots[nHeld++] = slot;
+    } else {
+      csd->freeSlots[csd->nFreeSlots++] = slot;
+    }
+  }
+  csd->nHeldSlots = nHeld;
+}
+
+/** \brief Release the DiskStore slot of a direct entry.
+ *
+ *  The slot returns to the free list, or is held until its I/O completes.
+ */
+static inline void
+CsDisk_ReleaseSlot(CsDisk* csd, CsEntry* entry)
+{
+  assert(CsEntry_IsDirect(entry));
+  if (likely(entry->diskSlot == 0)) {
+    return;
+  }
+  assert(csd->nFreeSlots + csd->nHeldSlots < csd->nSlots);
+  if (CsDisk_IsBusy(csd, entry->diskSlot)) {
+    csd->heldSlots[csd->nHeldSlots++] = entry->diskSlot;
+  } else {
+    csd->freeSlots[csd->nFreeSlots++] = entry->diskSlot;
+  }
+  entry->diskSlot = 0;
+}
+
+/** \brief Write Data of a direct entry into DiskStore as it moves to B1/B2.
+ */
+static void
+Cs_SpillToDisk(void* cs0, CsEntry* entry)
+{
+  Cs* cs = (Cs*)cs0;
+  CsDisk* csd = &Cs_GetPriv(cs)->disk;
+  assert(entry->diskSlot == 0);
+  if (unlikely(entry->data == NULL)) {
+    return;
+  }
+
+  if (csd->nFreeSlots == 0) {
+    CsDisk_ReclaimSlots(csd);
+  }
+  uint32_t pktLen = Packet_ToMbuf(entry->data)->pkt_len;
+  if (unlikely(csd->nFreeSlots == 0 ||
+               pktLen > csd->store->nBlocksPerSlot * DISK_STORE_BLOCK_SIZE)) {
+    ZF_LOGD("%p SpillToDisk(%p) drop=%s",
+            cs,
+            entry,
+            csd->nFreeSlots == 0 ? "no-slot" : "too-long");
+    ++csd->nSpillDrops;
+    CsEntry_ClearData(entry);
+    return;
+  }
+
+  entry->diskSlot = csd->freeSlots[--csd->nFreeSlots];
+  entry->diskDataLen = pktLen;
+  ZF_LOGD("%p SpillToDisk(%p) slot=%" PRIu64 " len=%" PRIu16,
+          cs,
+          entry,
+          entry->diskSlot,
+          entry->diskDataLen);
+  _Atomic uint32_t* nWrites = &csd->slotWrites[entry->diskSlot - csd->slotMin];
+  atomic_fetch_add_explicit(nWrites, 1, memory_order_acq_rel);
+  DiskStore_PutData(csd->store, entry->diskSlot, entry->data, nWrites);
+  entry->data = NULL;
+  ++csd->nSpills;
+}
+
 static void
 CsEraseBatch_Append_(PcctEraseBatch* peb,
                      CsEntry* entry,
@@ -50,6 +138,7 @@ CsEraseBatch_AddDirect(PcctEraseBatch* peb, CsEntry* entry)
     CsEraseBatch_Append_(peb, indirect, "indirect-dep");
   }
   entry->nIndirects = 0;
+  CsDisk_ReleaseSlot(&csp->disk, entry);
   CsEntry_Finalize(entry);
   CsEraseBatch_Append_(peb, entry, "direct");
 }
@@ -128,6 +217,7 @@ Cs_Init(Cs* cs, uint32_t capMd, uint32_t capMi)
   CsArc_Init(&csp->directArc, capMd);
   CsList_Init(&csp->indirectLru);
   csp->indirectLru.capacity = capMi;
+  memset(&csp->disk, 0, sizeof(csp->disk));
 
   ZF_LOGI("%p Init() priv=%p cap-md=%" PRIu32 " cap-mi=%" PRIu32,
           cs,
@@ -136,6 +226,54 @@ Cs_Init(Cs* cs, uint32_t capMd, uint32_t capMi)
           capMi);
 }
 
+bool
+Cs_SetDisk(Cs* cs,
+           DiskStore* store,
+           uint64_t slotMin,
+           uint64_t slotMax,
+           uint32_t maxPendingReads)
+{
+  assert(slotMin > 0 && slotMin <= slotMax);
+  CsPriv* csp = Cs_GetPriv(cs);
+  CsDisk* csd = &csp->disk;
+  assert(csd->store == NULL);
+
+  // freeSlots, heldSlots, slotWrites, and slotReads share one allocation
+  uint32_t nSlots = RTE_MIN(slotMax - slotMin + 1, (uint64_t)UINT32_MAX / 32);
+  size_t sizeofSlot = 2 * sizeof(csd->freeSlots[0]) +
+                      sizeof(csd->slotWrites[0]) + sizeof(csd->slotReads[0]);
+  csd->freeSlots =
+    rte_zmalloc_socket("CsDisk",
+                       nSlots * sizeofSlot,
+                       0,
+                       Pcct_ToMempool(Cs_ToPcct(cs))->socket_id);
+  if (unlikely(csd->freeSlots == NULL)) {
+    return false;
+  }
+  csd->heldSlots = &csd->freeSlots[nSlots];
+  csd->slotWrites = (_Atomic uint32_t*)&csd->heldSlots[nSlots];
+  csd->slotReads = (uint16_t*)&csd->slotWrites[nSlots];
+  csd->slotMin = slotMin;
+  csd->maxPendingReads = maxPendingReads;
+
+  // lower slot numbers are allocated first
+  for (uint32_t i = 0; i < nSlots; ++i) {
+    csd->freeSlots[i] = slotMin + nSlots - 1 - i;
+  }
+  csd->nFreeSlots = csd->nSlots = nSlots;
+  csd->store = store;
+
+  csp->directArc.moveCb = Cs_SpillToDisk;
+  csp->directArc.moveCbArg = cs;
+
+  ZF_LOGI("%p SetDisk(%p) slots=%" PRIu64 "-%" PRIu64,
+          cs,
+          store,
+          slotMin,
+          slotMin + nSlots - 1);
+  return true;
+}
+
 uint32_t
 Cs_GetCapacity(const Cs* cs, CsListId cslId)
 {
@@ -183,6 +321,9 @@ Cs_PutDirect(Cs* cs, Packet* npkt, PccEntry* pccEntry)
         }
       }
     }
+    if (CsEntry_IsDirect(entry)) {
+      CsDisk_ReleaseSlot(&csp->disk, entry);
+    }
     CsEntry_Clear(entry);
     CsArc_Add(&csp->directArc, entry);
   } else {
@@ -195,6 +336,7 @@ Cs_PutDirect(Cs* cs, Packet* npkt, PccEntry* pccEntry)
     ZF_LOGD("%p PutDirect(%p, pcc=%p) cs=%p insert", cs, npkt, pccEntry, entry);
     entry->arcList = CSL_ARC_NONE;
     entry->nIndirects = 0;
+    entry->diskSlot = 0;
     CsArc_Add(&csp->directArc, entry);
   }
   entry->data = npkt;
@@ -249,6 +391,9 @@ Cs_PutIndirect(Cs* cs, CsEntry* direct, PccEntry* pccEntry)
     }
     // refresh indirect entry
     // old entry can be either direct without dependency or indirect
+    if (CsEntry_IsDirect(entry)) {
+      CsDisk_ReleaseSlot(&csp->disk, entry);
+    }
     CsEntry_Clear(entry);
     CsList_MoveToLast(&csp->indirectLru, entry);
     ZF_LOGD("%p PutIndirect(%p, pcc=%p) cs=%p count=%" PRIu32 " refresh",
@@ -332,6 +477,71 @@ Cs_Insert(Cs* cs, Packet* npkt, PitFindResult pitFound)
   Cs_Evict(cs);
 }
 
+/** \brief Determine whether a direct entry with Data in DiskStore matches an Interest.
+ */
+static bool
+Cs_MatchDisk_(Cs* cs, CsEntry* direct, PInterest* interest)
+{
+  CsPriv* csp = Cs_GetPriv(cs);
+  CsDisk* csd = &csp->disk;
+
+  if (interest->diskSlotId == 0) {
+    uint64_t i = direct->diskSlot - csd->slotMin;
+    if (unlikely(atomic_load_explicit(&csd->slotWrites[i],
+                                      memory_order_acquire) > 0 ||
+                 csd->slotReads[i] == UINT16_MAX ||
+                 csd->nPendingReads >= csd->maxPendingReads)) {
+      // Data is not yet on disk, or DiskStore reply queue could overflow
+      ZF_LOGD("^ disk-slot=%" PRIu64 " drop=busy", direct->diskSlot);
+      ++csd->nReadBusy;
+      return false;
+    }
+    ZF_LOGD("^ disk-slot=%" PRIu64 " read", direct->diskSlot);
+    ++csd->nReads;
+    return true;
+  }
+
+  if (unlikely(interest->diskSlotId != direct->diskSlot ||
+               interest->diskData == NULL)) {
+    ZF_LOGD("^ disk-slot=%" PRIu64 " drop=read-fail", direct->diskSlot);
+    ++csd->nReadFails;
+    return false;
+  }
+
+  ZF_LOGD("^ disk-slot=%" PRIu64 " restore", direct->diskSlot);
+  direct->data = interest->diskData;
+  interest->diskData = NULL;
+  CsDisk_ReleaseSlot(csd, direct);
+  CsArc_Add(&csp->directArc, direct);
+  ++csd->nReadHits;
+  return true;
+}
+
+void
+Cs_ReadDisk(Cs* cs, CsEntry* entry, Packet* npkt, struct rte_ring* reply)
+{
+  assert(CsEntry_IsDirect(entry) && entry->diskSlot != 0);
+  CsDisk* csd = &Cs_GetPriv(cs)->disk;
+  ++csd->slotReads[entry->diskSlot - csd->slotMin];
+  ++csd->nPendingReads;
+  DiskStore_GetData(
+    csd->store, entry->diskSlot, entry->diskDataLen, npkt, reply);
+}
+
+void
+Cs_ReadDiskDone(Cs* cs, Packet* npkt)
+{
+  CsDisk* csd = &Cs_GetPriv(cs)->disk;
+  uint64_t slot = Packet_GetInterestHdr(npkt)->diskSlotId;
+  assert(slot >= csd->slotMin && slot - csd->slotMin < csd->nSlots);
+  assert(csd->slotReads[slot - csd->slotMin] > 0 && csd->nPendingReads > 0);
+  --csd->slotReads[slot - csd->slotMin];
+  --csd->nPendingReads;
+  if (csd->nHeldSlots > 0) {
+    CsDisk_ReclaimSlots(csd);
+  }
+}
+
 bool
 Cs_MatchInterest_(Cs* cs, PccEntry* pccEntry, Packet* interestNpkt)
 {
@@ -364,6 +574,9 @@ Cs_MatchInterest_(Cs* cs, PccEntry* pccEntry, Packet* interestNpkt)
       CsArc_Add(&csp->directArc, direct);
       return true;
     }
+    if (direct->diskSlot != 0) {
+      return Cs_MatchDisk_(cs, direct, interest);
+    }
   }
   return false;
 }
@@ -36,6 +36,44 @@ Cs_GetPriv(const Cs* cs)
 void
 Cs_Init(Cs* cs, uint32_t capMd, uint32_t capMi);
 
+/** \brief Attach a DiskStore as second-tier storage.
+ *  \param store the DiskStore; it may be shared with other CS instances.
+ *  \param slotMin minimum slot number assigned to this CS, must be positive.
+ *  \param slotMax maximum slot number assigned to this CS.
+ *  \param maxPendingReads maximum number of Interests that have been passed
+ *                         to \c Cs_ReadDisk but not \c Cs_ReadDiskDone; this
+ *                         should not exceed the capacity of reply queue.
+ *  \return whether success.
+ *
+ *  When a direct entry moves from T1/T2 to B1/B2, its Data is written into
+ *  DiskStore instead of being discarded, provided that a slot is available.
+ */
+bool
+Cs_SetDisk(Cs* cs,
+           DiskStore* store,
+           uint64_t slotMin,
+           uint64_t slotMax,
+           uint32_t maxPendingReads);
+
+/** \brief Retrieve Data of a CS entry from DiskStore.
+ *  \param entry a direct entry without Data but with a slot number, as
+ *                returned by Pit_Insert in PIT_INSERT_CS result.
+ *  \param npkt the Interest. DiskStore takes ownership.
+ *  \param reply where to return \p npkt after Data is loaded.
+ *
+ *  \p npkt should be passed to \c Cs_ReadDiskDone and then Pit_Insert again
+ *  after it is returned via \p reply, which would restore the Data into the
+ *  CS entry. The slot is not reused until then.
+ */
+void
+Cs_ReadDisk(Cs* cs, CsEntry* entry, Packet* npkt, struct rte_ring* reply);
+
+/** \brief Indicate an Interest has returned from DiskStore.
+ *  \param npkt an Interest passed to \c Cs_ReadDisk earlier.
+ */
+void
+Cs_ReadDiskDone(Cs* cs, Packet* npkt);
+
 /** \brief Get capacity in number of entries.
  */
 uint32_t
@@ -58,6 +96,11 @@ Cs_Insert(Cs* cs, Packet* npkt, PitFindResult pitFound);
 /** \brief Determine whether the CS entry matches an Interest during PIT insertion.
  *  \param pccEntry the PCC entry containing CS entry
  *  \post the CS entry is erased if it would conflict with a PIT entry for the Interest.
+ *
+ *  If the Data of the matched entry is in DiskStore and \p interestNpkt has
+ *  not been through DiskStore, this returns true but the entry has no Data;
+ *  the caller should invoke \c Cs_ReadDisk. If \p interestNpkt carries Data
+ *  loaded from the entry's slot, the Data is restored into the entry.
  */
 bool
 Cs_MatchInterest_(Cs* cs, PccEntry* pccEntry, Packet* interestNpkt);
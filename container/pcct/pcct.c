@@ -105,6 +105,7 @@ Pcct_Close(Pcct* pcct)
   PcctPriv* pcctp = Pcct_GetPriv(pcct);
   rte_hash_free(pcctp->tokenHt);
   HASH_CLEAR(hh, pcctp->keyHt);
+  rte_free(pcctp->csPriv.disk.freeSlots);
   rte_mempool_free(Pcct_ToMempool(pcct));
 }
 
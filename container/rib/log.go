@@ -0,0 +1,11 @@
+package rib
+
+import (
+	"ndn-dpdk/core/logger"
+)
+
+var (
+	log           = logger.New("Rib")
+	makeLogFields = logger.MakeFields
+	addressOf     = logger.AddressOf
+)
@@ -0,0 +1,40 @@
+import * as iface from "../../iface/mod";
+import * as ndn from "../../ndn/mod";
+
+/**
+ * Route inheritance flags.
+ * 1 is ChildInherit: route applies to longer prefixes.
+ * 2 is Capture: routes of shorter prefixes do not apply to this prefix.
+ * @TJS-type integer
+ * @minimum 0
+ * @maximum 3
+ */
+export type RouteFlags = number;
+
+export interface Route {
+  FaceId: iface.FaceId;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   */
+  Origin: number;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   */
+  Cost: number;
+
+  Flags: RouteFlags;
+
+  /**
+   * expiration time in RFC3339 format, "0001-01-01T00:00:00Z" means never expires
+   */
+  Expiration: string;
+}
+
+export interface Entry {
+  Name: ndn.Name;
+  Routes: Route[];
+}
@@ -0,0 +1,404 @@
+package rib
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"ndn-dpdk/container/fib"
+	"ndn-dpdk/container/strategycode"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/ndn"
+)
+
+// The Routing Information Base.
+type Rib struct {
+	fib               *fib.Fib
+	defaultStrategyId int
+	lock              sync.Mutex
+	entries           map[string]*ribEntry
+	faceClosedEvt     io.Closer
+}
+
+type ribEntry struct {
+	name       *ndn.Name
+	routes     []Route
+	timer      *time.Timer    // expiration timer of the earliest expiring route
+	nexthops   []iface.FaceId // nexthops installed in FIB
+	expiration time.Time      // expiration time installed in FIB
+}
+
+// Create a RIB that pushes computed nexthops into fib.
+// A new FIB entry uses the strategy identified by defaultStrategyId;
+// an existing FIB entry keeps its strategy.
+func New(fib *fib.Fib, defaultStrategyId int) (rib *Rib) {
+	rib = new(Rib)
+	rib.fib = fib
+	rib.defaultStrategyId = defaultStrategyId
+	rib.entries = make(map[string]*ribEntry)
+	rib.faceClosedEvt = iface.OnFaceClosed(rib.handleFaceClosed)
+	return rib
+}
+
+// Stop expiration timers.
+// This does not erase FIB entries.
+func (rib *Rib) Close() error {
+	rib.faceClosedEvt.Close()
+	rib.lock.Lock()
+	defer rib.lock.Unlock()
+	for _, re := range rib.entries {
+		if re.timer != nil {
+			re.timer.Stop()
+		}
+	}
+	rib.entries = make(map[string]*ribEntry)
+	return nil
+}
+
+// Get number of entries.
+func (rib *Rib) Len() int {
+	rib.lock.Lock()
+	defer rib.lock.Unlock()
+	return len(rib.entries)
+}
+
+// List all entries, sorted by name.
+func (rib *Rib) List() (list []Entry) {
+	rib.lock.Lock()
+	defer rib.lock.Unlock()
+	for _, re := range rib.entries {
+		list = append(list, re.export())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name.Compare(list[j].Name) < ndn.NAMECMP_EQUAL
+	})
+	return list
+}
+
+// Find entry by exact name.
+func (rib *Rib) Find(name *ndn.Name) (result Entry, ok bool) {
+	rib.lock.Lock()
+	defer rib.lock.Unlock()
+	if re := rib.entries[name.String()]; re != nil {
+		return re.export(), true
+	}
+	return result, false
+}
+
+func (re *ribEntry) export() Entry {
+	return Entry{
+		Name:   re.name,
+		Routes: append([]Route(nil), re.routes...),
+	}
+}
+
+// Get nexthops computed from routes of a prefix and its ancestors.
+func (rib *Rib) GetNexthops(name *ndn.Name) []iface.FaceId {
+	rib.lock.Lock()
+	defer rib.lock.Unlock()
+	if re := rib.entries[name.String()]; re != nil {
+		nexthops, _ := rib.computeNexthops(re)
+		return nexthops
+	}
+	return nil
+}
+
+// Insert or replace a route.
+// An existing route with same prefix, FaceId, and Origin is replaced.
+func (rib *Rib) Add(name *ndn.Name, route Route) error {
+	if route.FaceId == iface.FACEID_INVALID {
+		return errors.New("invalid FaceId")
+	}
+	if !route.Expiration.IsZero() && !route.Expiration.After(time.Now()) {
+		return errors.New("route already expired")
+	}
+
+	rib.lock.Lock()
+	defer rib.lock.Unlock()
+
+	key := name.String()
+	re := rib.entries[key]
+	if re == nil {
+		re = &ribEntry{name: name}
+		rib.entries[key] = re
+	}
+
+	replaced := false
+	for i, r := range re.routes {
+		if r.sameKey(route) {
+			re.routes[i] = route
+			replaced = true
+		}
+	}
+	if !replaced {
+		re.routes = append(re.routes, route)
+	}
+	return rib.afterChange(re)
+}
+
+// Remove a route.
+// Removing a non-existent route is not an error.
+func (rib *Rib) Remove(name *ndn.Name, faceId iface.FaceId, origin int) error {
+	rib.lock.Lock()
+	defer rib.lock.Unlock()
+
+	re := rib.entries[name.String()]
+	if re == nil {
+		return nil
+	}
+
+	key := Route{FaceId: faceId, Origin: origin}
+	routes := re.routes[:0]
+	for _, r := range re.routes {
+		if !r.sameKey(key) {
+			routes = append(routes, r)
+		}
+	}
+	if len(routes) == len(re.routes) {
+		return nil
+	}
+	re.routes = routes
+	return rib.afterChange(re)
+}
+
+// Change strategy of an existing FIB entry, keeping its nexthops.
+// This is serialized with route changes, so that nexthops computed by the RIB are not lost.
+func (rib *Rib) SetStrategy(name *ndn.Name, sc strategycode.StrategyCode) error {
+	rib.lock.Lock()
+	defer rib.lock.Unlock()
+
+	oldEntry := rib.fib.Find(name)
+	if oldEntry == nil {
+		return errors.New("FIB entry not found")
+	}
+	fibEntry := new(fib.Entry)
+	if e := fibEntry.SetName(name); e != nil {
+		return e
+	}
+	if e := fibEntry.SetNexthops(oldEntry.GetNexthops()); e != nil {
+		return e
+	}
+	fibEntry.SetStrategy(sc)
+	_, e := rib.fib.Insert(fibEntry)
+	return e
+}
+
+// Remove expired routes of an entry.
+func (rib *Rib) expire(re *ribEntry) {
+	rib.lock.Lock()
+	defer rib.lock.Unlock()
+	if rib.entries[re.name.String()] != re {
+		return
+	}
+
+	now := time.Now()
+	routes := re.routes[:0]
+	for _, r := range re.routes {
+		if r.Expiration.IsZero() || r.Expiration.After(now) {
+			routes = append(routes, r)
+		}
+	}
+	re.routes = routes
+	if e := rib.afterChange(re); e != nil {
+		log.WithField("name", re.name).WithError(e).Warn("FIB update error after route expiration")
+	}
+}
+
+// Remove routes of a closed face.
+func (rib *Rib) handleFaceClosed(faceId iface.FaceId) {
+	rib.lock.Lock()
+	defer rib.lock.Unlock()
+
+	for _, re := range rib.entries {
+		routes := re.routes[:0]
+		for _, r := range re.routes {
+			if r.FaceId != faceId {
+				routes = append(routes, r)
+			}
+		}
+		if len(routes) == len(re.routes) {
+			continue
+		}
+		re.routes = routes
+		if e := rib.afterChange(re); e != nil {
+			log.WithFields(makeLogFields("name", re.name, "face", faceId)).WithError(e).Warn("FIB update error after face closed")
+		}
+	}
+}
+
+// Handle route changes in an entry.
+// Caller must hold the lock.
+func (rib *Rib) afterChange(re *ribEntry) (e error) {
+	if re.timer != nil {
+		re.timer.Stop()
+		re.timer = nil
+	}
+
+	if len(re.routes) == 0 {
+		delete(rib.entries, re.name.String())
+		if len(re.nexthops) > 0 {
+			e = rib.eraseFib(re.name)
+		}
+	} else {
+		rib.scheduleExpiration(re)
+	}
+
+	// routes of this prefix may be inherited by longer prefixes
+	for _, d := range rib.entries {
+		if cmp := re.name.Compare(d.name); cmp != ndn.NAMECMP_EQUAL && cmp != ndn.NAMECMP_LPREFIX {
+			continue
+		}
+		nexthops, expiration := rib.computeNexthops(d)
+		if e2 := rib.pushNexthops(d, nexthops, expiration); e2 != nil && e == nil {
+			e = e2
+		}
+	}
+	return e
+}
+
+func (rib *Rib) scheduleExpiration(re *ribEntry) {
+	var earliest time.Time
+	for _, r := range re.routes {
+		if !r.Expiration.IsZero() && (earliest.IsZero() || r.Expiration.Before(earliest)) {
+			earliest = r.Expiration
+		}
+	}
+	if !earliest.IsZero() {
+		re.timer = time.AfterFunc(time.Until(earliest), func() { rib.expire(re) })
+	}
+}
+
+// Compute nexthops of an entry.
+// Nexthops come from routes of the entry itself, and routes with ChildInherit flag on shorter prefixes,
+// up to and including the first prefix with Capture flag.
+// When a face appears in multiple routes, the route on the longest prefix is used,
+// and then the lowest cost among routes of that prefix.
+// Nexthops are sorted by cost, and truncated to fib.MAX_NEXTHOPS.
+// Expiration is the latest expiration time among routes used by nexthops, or zero if any of them never expires.
+// Caller must hold the lock.
+func (rib *Rib) computeNexthops(re *ribEntry) (nexthops []iface.FaceId, expiration time.Time) {
+	used := make(map[iface.FaceId]Route)
+	isCapture := addRoutes(used, re.routes, false)
+	for prefixLen := re.name.Len() - 1; !isCapture && prefixLen >= 0; prefixLen-- {
+		if ancestor := rib.entries[re.name.GetPrefix(prefixLen).String()]; ancestor != nil {
+			isCapture = addRoutes(used, ancestor.routes, true)
+		}
+	}
+
+	for faceId := range used {
+		nexthops = append(nexthops, faceId)
+	}
+	sort.Slice(nexthops, func(i, j int) bool {
+		if ci, cj := used[nexthops[i]].Cost, used[nexthops[j]].Cost; ci != cj {
+			return ci < cj
+		}
+		return nexthops[i] < nexthops[j]
+	})
+	if len(nexthops) > fib.MAX_NEXTHOPS {
+		nexthops = nexthops[:fib.MAX_NEXTHOPS]
+	}
+
+	for i, faceId := range nexthops {
+		t := used[faceId].Expiration
+		if t.IsZero() {
+			return nexthops, time.Time{}
+		}
+		if i == 0 || t.After(expiration) {
+			expiration = t
+		}
+	}
+	return nexthops, expiration
+}
+
+// Add routes of one prefix into the used route of each nexthop face.
+// Return whether any route has Capture flag.
+func addRoutes(used map[iface.FaceId]Route, routes []Route, isInherit bool) (isCapture bool) {
+	own := make(map[iface.FaceId]Route)
+	for _, r := range routes {
+		isCapture = isCapture || r.Flags&RouteFlag_Capture != 0
+		if isInherit && r.Flags&RouteFlag_ChildInherit == 0 {
+			continue
+		}
+		if _, ok := used[r.FaceId]; ok {
+			continue // face has a route on a longer prefix
+		}
+		if o, ok := own[r.FaceId]; !ok || r.Cost < o.Cost {
+			own[r.FaceId] = r
+		}
+	}
+	for faceId, r := range own {
+		used[faceId] = r
+	}
+	return isCapture
+}
+
+// Install computed nexthops and expiration time into FIB, if they differ from previously installed values.
+// Caller must hold the lock.
+func (rib *Rib) pushNexthops(re *ribEntry, nexthops []iface.FaceId, expiration time.Time) error {
+	if equalNexthops(re.nexthops, nexthops) {
+		if len(nexthops) == 0 || re.expiration.Equal(expiration) {
+			return nil
+		}
+		return rib.pushExpiration(re, expiration)
+	}
+
+	if len(nexthops) == 0 {
+		re.nexthops = nil
+		re.expiration = time.Time{}
+		return rib.eraseFib(re.name)
+	}
+
+	fibEntry := new(fib.Entry)
+	if e := fibEntry.SetName(re.name); e != nil {
+		return e
+	}
+	if e := fibEntry.SetNexthops(nexthops); e != nil {
+		return e
+	}
+	if oldEntry := rib.fib.Find(re.name); oldEntry != nil {
+		fibEntry.SetStrategy(oldEntry.GetStrategy())
+	} else if sc := strategycode.Get(rib.defaultStrategyId); sc != nil {
+		fibEntry.SetStrategy(sc)
+	} else {
+		return errors.New("default strategy not found")
+	}
+
+	if _, e := rib.fib.Insert(fibEntry); e != nil {
+		return e
+	}
+	re.nexthops = nexthops
+	return rib.pushExpiration(re, expiration)
+}
+
+// Install expiration time into FIB, so that a FIB snapshot does not outlive its routes.
+// Caller must hold the lock.
+func (rib *Rib) pushExpiration(re *ribEntry, expiration time.Time) error {
+	if e := rib.fib.SetExpiration(re.name, expiration); e != nil {
+		return e
+	}
+	re.expiration = expiration
+	return nil
+}
+
+// Erase a FIB entry.
+// The FIB may have erased the entry already, such as when its last nexthop face is closed.
+func (rib *Rib) eraseFib(name *ndn.Name) error {
+	if rib.fib.Find(name) == nil {
+		return nil
+	}
+	return rib.fib.Erase(name)
+}
+
+func equalNexthops(a, b []iface.FaceId) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
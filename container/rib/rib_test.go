@@ -0,0 +1,109 @@
+package rib_test
+
+import (
+	"testing"
+	"time"
+
+	"ndn-dpdk/container/fib/fibtest"
+	"ndn-dpdk/container/rib"
+	"ndn-dpdk/container/strategycode"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/ndn"
+)
+
+func TestRib(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := fibtest.NewFixture(0, 2, 1)
+	defer fixture.Close()
+	strategyP := strategycode.MakeEmpty("P")
+	strategyQ := strategycode.MakeEmpty("Q")
+
+	r := rib.New(fixture.Fib, strategyP.GetId())
+	defer r.Close()
+
+	fibNexthops := func(uri string) []iface.FaceId {
+		if entry := fixture.Fib.Find(ndn.MustParseName(uri)); entry != nil {
+			return entry.GetNexthops()
+		}
+		return nil
+	}
+
+	nameA := ndn.MustParseName("/A")
+	nameAB := ndn.MustParseName("/A/B")
+
+	require.NoError(r.Add(nameA, rib.Route{FaceId: 1001, Cost: 10, Flags: rib.RouteFlag_ChildInherit}))
+	require.NoError(r.Add(nameA, rib.Route{FaceId: 1002, Cost: 5, Flags: rib.RouteFlag_ChildInherit}))
+	assert.Equal([]iface.FaceId{1002, 1001}, fibNexthops("/A"))
+	assert.Equal(strategyP.GetId(), fixture.Fib.Find(nameA).GetStrategy().GetId())
+
+	// inherit routes from /A
+	require.NoError(r.Add(nameAB, rib.Route{FaceId: 1003, Cost: 1}))
+	assert.Equal([]iface.FaceId{1003, 1002, 1001}, fibNexthops("/A/B"))
+
+	// existing FIB entry keeps its strategy
+	entryAB := fixture.MakeEntry("/A/B", strategyQ, 1003)
+	_, e := fixture.Fib.Insert(entryAB)
+	require.NoError(e)
+
+	// capture blocks inheritance
+	require.NoError(r.Add(nameAB, rib.Route{FaceId: 1004, Origin: 1, Cost: 20, Flags: rib.RouteFlag_Capture}))
+	assert.Equal([]iface.FaceId{1003, 1004}, fibNexthops("/A/B"))
+	assert.Equal(strategyQ.GetId(), fixture.Fib.Find(nameAB).GetStrategy().GetId())
+
+	require.NoError(r.Remove(nameAB, 1004, 1))
+	assert.Equal([]iface.FaceId{1003, 1002, 1001}, fibNexthops("/A/B"))
+
+	// same face with another origin, lowest cost is used
+	require.NoError(r.Add(nameA, rib.Route{FaceId: 1001, Origin: 2, Cost: 1, Flags: rib.RouteFlag_ChildInherit}))
+	assert.Equal([]iface.FaceId{1001, 1002}, fibNexthops("/A"))
+	assert.Equal([]iface.FaceId{1001, 1003, 1002}, fibNexthops("/A/B")) // equal cost sorted by FaceId
+
+	// replace route with same key
+	require.NoError(r.Add(nameA, rib.Route{FaceId: 1002, Cost: 0}))
+	assert.Equal([]iface.FaceId{1002, 1001}, fibNexthops("/A"))
+	assert.Equal([]iface.FaceId{1001, 1003}, fibNexthops("/A/B")) // 1002 route is not ChildInherit
+
+	if entry, ok := r.Find(nameA); assert.True(ok) {
+		assert.Len(entry.Routes, 3)
+	}
+	assert.Len(r.List(), 2)
+
+	// change strategy, keeping nexthops
+	require.NoError(r.SetStrategy(nameA, strategyQ))
+	assert.Equal(strategyQ.GetId(), fixture.Fib.Find(nameA).GetStrategy().GetId())
+	assert.Equal([]iface.FaceId{1002, 1001}, fibNexthops("/A"))
+	assert.Error(r.SetStrategy(ndn.MustParseName("/C"), strategyQ))
+
+	// removing all routes erases FIB entry
+	require.NoError(r.Remove(nameA, 1001, 0))
+	require.NoError(r.Remove(nameA, 1001, 2))
+	require.NoError(r.Remove(nameA, 1002, 0))
+	assert.Nil(fibNexthops("/A"))
+	assert.Equal([]iface.FaceId{1003}, fibNexthops("/A/B"))
+	assert.Equal(1, r.Len())
+	assert.NoError(r.Remove(nameA, 1002, 0))
+}
+
+func TestRibExpiration(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := fibtest.NewFixture(0, 2, 1)
+	defer fixture.Close()
+	strategyP := strategycode.MakeEmpty("P")
+
+	r := rib.New(fixture.Fib, strategyP.GetId())
+	defer r.Close()
+
+	nameC := ndn.MustParseName("/C")
+	assert.Error(r.Add(nameC, rib.Route{FaceId: 1005, Expiration: time.Now().Add(-time.Second)}))
+
+	expC := time.Now().Add(100 * time.Millisecond)
+	require.NoError(r.Add(nameC, rib.Route{FaceId: 1005, Expiration: expC}))
+	assert.True(fixture.Fib.GetExpiration(nameC).Equal(expC))
+	require.NoError(r.Add(nameC, rib.Route{FaceId: 1006}))
+	assert.Len(fixture.Fib.Find(nameC).GetNexthops(), 2)
+	assert.True(fixture.Fib.GetExpiration(nameC).IsZero())
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal([]iface.FaceId{1006}, fixture.Fib.Find(nameC).GetNexthops())
+	assert.Equal([]iface.FaceId{1006}, r.GetNexthops(nameC))
+}
@@ -0,0 +1,42 @@
+package rib
+
+import (
+	"fmt"
+	"time"
+
+	"ndn-dpdk/iface"
+	"ndn-dpdk/ndn"
+)
+
+// Route inheritance flags.
+type RouteFlags int
+
+const (
+	RouteFlag_ChildInherit RouteFlags = 1 << iota // route applies to longer prefixes
+	RouteFlag_Capture                             // routes of shorter prefixes do not apply to this prefix
+)
+
+func (flags RouteFlags) String() string {
+	return fmt.Sprintf("ChildInherit=%t,Capture=%t",
+		flags&RouteFlag_ChildInherit != 0, flags&RouteFlag_Capture != 0)
+}
+
+// A route in the RIB.
+// A route is identified by prefix, FaceId, and Origin.
+type Route struct {
+	FaceId     iface.FaceId
+	Origin     int        // routing source, such as static configuration or a routing protocol
+	Cost       int        // route cost, lower is preferred
+	Flags      RouteFlags // inheritance flags
+	Expiration time.Time  // expiration time, zero means never expires
+}
+
+func (r Route) sameKey(other Route) bool {
+	return r.FaceId == other.FaceId && r.Origin == other.Origin
+}
+
+// A RIB entry, containing all routes of a prefix.
+type Entry struct {
+	Name   *ndn.Name
+	Routes []Route
+}
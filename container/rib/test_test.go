@@ -0,0 +1,16 @@
+package rib_test
+
+import (
+	"os"
+	"testing"
+
+	"ndn-dpdk/dpdk/dpdktestenv"
+)
+
+func TestMain(m *testing.M) {
+	dpdktestenv.InitEal()
+
+	os.Exit(m.Run())
+}
+
+var makeAR = dpdktestenv.MakeAR
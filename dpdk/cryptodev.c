@@ -10,3 +10,21 @@ RTE_INIT(InitSha256DigestXform)
   theSha256DigestXform.auth.algo = RTE_CRYPTO_AUTH_SHA256;
   theSha256DigestXform.auth.digest_length = 32;
 }
+
+int
+CryptoSession_InitHmacSha256Verify(struct rte_cryptodev_sym_session* sess,
+                                   uint8_t dev,
+                                   struct rte_mempool* mp,
+                                   const uint8_t* key,
+                                   uint16_t keyLen)
+{
+  struct rte_crypto_sym_xform xform;
+  memset(&xform, 0, sizeof(xform));
+  xform.type = RTE_CRYPTO_SYM_XFORM_AUTH;
+  xform.auth.op = RTE_CRYPTO_AUTH_OP_VERIFY;
+  xform.auth.algo = RTE_CRYPTO_AUTH_SHA256_HMAC;
+  xform.auth.key.data = key;
+  xform.auth.key.length = keyLen;
+  xform.auth.digest_length = 32;
+  return rte_cryptodev_sym_session_init(dev, sess, &xform, mp);
+}
@@ -7,6 +7,7 @@ import "C"
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"unsafe"
 )
@@ -200,6 +201,53 @@ func (cd CryptoDev) GetQueuePair(i int) (qp CryptoQueuePair, ok bool) {
 	return qp, true
 }
 
+// Symmetric crypto session.
+type CryptoSession struct {
+	c    *C.struct_rte_cryptodev_sym_session
+	devs []CryptoDev
+}
+
+// Create an HMAC-SHA256 verification session usable on one or more crypto devices.
+func NewHmacSha256VerifySession(key []byte, devs ...CryptoDev) (sess *CryptoSession, e error) {
+	if len(devs) == 0 {
+		return nil, errors.New("no crypto device")
+	}
+	if len(key) == 0 || len(key) > math.MaxUint16 {
+		return nil, errors.New("bad HMAC key length")
+	}
+
+	sess = new(CryptoSession)
+	if sess.c = C.rte_cryptodev_sym_session_create(devs[0].sessionPool.c); sess.c == nil {
+		return nil, errors.New("rte_cryptodev_sym_session_create error")
+	}
+
+	keyC := C.CBytes(key)
+	defer C.free(keyC)
+	for _, cd := range devs {
+		if res := C.CryptoSession_InitHmacSha256Verify(sess.c, cd.devId, cd.sessionPool.c,
+			(*C.uint8_t)(keyC), C.uint16_t(len(key))); res < 0 {
+			sess.Close()
+			return nil, fmt.Errorf("rte_cryptodev_sym_session_init(%s) error %d", cd.GetName(), res)
+		}
+		sess.devs = append(sess.devs, cd)
+	}
+	return sess, nil
+}
+
+func (sess *CryptoSession) GetPtr() unsafe.Pointer {
+	return unsafe.Pointer(sess.c)
+}
+
+func (sess *CryptoSession) Close() error {
+	for _, cd := range sess.devs {
+		C.rte_cryptodev_sym_session_clear(cd.devId, sess.c)
+	}
+	if res := C.rte_cryptodev_sym_session_free(sess.c); res < 0 {
+		return fmt.Errorf("rte_cryptodev_sym_session_free error %d", res)
+	}
+	return nil
+}
+
 // Crypto device queue pair.
 type CryptoQueuePair struct {
 	CryptoDev
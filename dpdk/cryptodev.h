@@ -43,6 +43,16 @@ rte_cryptodev_sym_session_pool_create_(const char* name,
 #pragma GCC diagnostic pop
 }
 
+/** \brief Initialize an HMAC-SHA256 verification session on a crypto device.
+ *  \param key HMAC key; it is copied into the session.
+ */
+int
+CryptoSession_InitHmacSha256Verify(struct rte_cryptodev_sym_session* sess,
+                                   uint8_t dev,
+                                   struct rte_mempool* mp,
+                                   const uint8_t* key,
+                                   uint16_t keyLen);
+
 typedef struct CryptoQueuePair
 {
   uint8_t dev;
@@ -70,6 +70,7 @@ type EthDevConfig struct {
 	RxQueues []EthRxQueueConfig
 	TxQueues []EthTxQueueConfig
 	Mtu      int            // if non-zero, change MTU
+	Rss      bool           // whether to distribute frames among RX queues with RSS, ignored if Conf is set
 	Conf     unsafe.Pointer // pointer to rte_eth_conf, nil means default
 }
 
@@ -106,9 +107,14 @@ func (port EthDev) Configure(cfg EthDevConfig) (rxQueues []EthRxQueue, txQueues
 	if conf == nil {
 		conf = new(C.struct_rte_eth_conf)
 		conf.rxmode.max_rx_pkt_len = C.uint32_t(port.GetMtu())
-		if info := port.GetDevInfo(); info.Tx_offload_capa&C.DEV_TX_OFFLOAD_MULTI_SEGS != 0 {
+		info := port.GetDevInfo()
+		if info.Tx_offload_capa&C.DEV_TX_OFFLOAD_MULTI_SEGS != 0 {
 			conf.txmode.offloads = C.DEV_TX_OFFLOAD_MULTI_SEGS
 		}
+		if cfg.Rss && len(cfg.RxQueues) > 1 && info.Flow_type_rss_offloads != 0 {
+			conf.rxmode.mq_mode = C.ETH_MQ_RX_RSS
+			conf.rx_adv_conf.rss_conf.rss_hf = info.Flow_type_rss_offloads
+		}
 	}
 
 	res := C.rte_eth_dev_configure(portId, C.uint16_t(len(cfg.RxQueues)),
@@ -185,6 +191,22 @@ func (port EthDev) IsDown() bool {
 	return bool(C.EthDev_IsDown(C.uint16_t(port)))
 }
 
+// Set link up, if supported by the driver.
+func (port EthDev) SetLinkUp() error {
+	if res := C.rte_eth_dev_set_link_up(C.uint16_t(port)); res != 0 {
+		return Errno(-res)
+	}
+	return nil
+}
+
+// Set link down, if supported by the driver.
+func (port EthDev) SetLinkDown() error {
+	if res := C.rte_eth_dev_set_link_down(C.uint16_t(port)); res != 0 {
+		return Errno(-res)
+	}
+	return nil
+}
+
 func (port EthDev) Start() error {
 	res := C.rte_eth_dev_start(C.uint16_t(port))
 	if res != 0 {
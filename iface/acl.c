@@ -0,0 +1,13 @@
+#include "acl.h"
+
+/** \brief Encoded "localhost" GenericNameComponent.
+ */
+static const uint8_t ACL_LOCALHOST_COMP[] = "\x08\x09localhost";
+#define ACL_LOCALHOST_COMP_SIZE (sizeof(ACL_LOCALHOST_COMP) - 1)
+
+bool
+Acl_IsLocalhostName(LName name)
+{
+  return name.length >= ACL_LOCALHOST_COMP_SIZE &&
+         memcmp(name.value, ACL_LOCALHOST_COMP, ACL_LOCALHOST_COMP_SIZE) == 0;
+}
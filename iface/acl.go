@@ -0,0 +1,184 @@
+package iface
+
+/*
+#include "face.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"unsafe"
+
+	"ndn-dpdk/core/urcu"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/ndn"
+)
+
+const ACL_MAX_RULES = C.ACL_MAX_RULES
+
+// Name prefix rule in access control list.
+type AclRule struct {
+	Prefix *ndn.Name // name prefix; empty prefix matches every packet
+	Deny   bool      // whether matching packets are dropped
+}
+
+// Validate access control list rules.
+func ValidateAcl(rules []AclRule) error {
+	if len(rules) > ACL_MAX_RULES {
+		return fmt.Errorf("too many ACL rules, maximum is %d", ACL_MAX_RULES)
+	}
+	namesL := 0
+	for i, rule := range rules {
+		namesL += rule.Prefix.Size()
+		if rule.Prefix.Size() > ndn.NAME_MAX_LENGTH || namesL > math.MaxUint16 {
+			return fmt.Errorf("ACL rule %d: Prefix too long", i)
+		}
+	}
+	return nil
+}
+
+// ACL counters.
+type AclCounters struct {
+	Hits           []uint64 // packets matching each rule
+	Drops          uint64   // packets dropped by ACL
+	LocalhostDrops uint64   // /localhost packets dropped on non-local face
+}
+
+func (cnt AclCounters) String() string {
+	return fmt.Sprintf("%v %ddrop %dlocalhost", cnt.Hits, cnt.Drops, cnt.LocalhostDrops)
+}
+
+type aclRecord struct {
+	rules    []AclRule
+	hitsBase [ACL_MAX_RULES]uint64 // nAclHits at the time rules were installed
+}
+
+var (
+	aclLock    sync.Mutex
+	aclRecords = make(map[FaceId]*aclRecord)
+)
+
+func makeAcl(rules []AclRule, socket dpdk.NumaSocket) (aclC *C.Acl) {
+	var names []byte
+	for _, rule := range rules {
+		if rule.Prefix != nil {
+			names = append(names, rule.Prefix.GetValue()...)
+		}
+	}
+
+	aclC = (*C.Acl)(dpdk.Zmalloc("Acl", int(C.sizeof_Acl)+len(names), socket))
+	aclC.nRules = C.uint8_t(len(rules))
+	nameOff := 0
+	for i, rule := range rules {
+		ruleC := &aclC.rules[i]
+		ruleC.nameL = C.uint16_t(rule.Prefix.Size())
+		ruleC.nameOff = C.uint16_t(nameOff)
+		ruleC.deny = C.bool(rule.Deny)
+		nameOff += rule.Prefix.Size()
+	}
+	if len(names) > 0 {
+		C.memcpy(unsafe.Pointer(&aclC.names), unsafe.Pointer(&names[0]), C.size_t(len(names)))
+	}
+	return aclC
+}
+
+func (face FaceBase) readAclHits() (hits [ACL_MAX_RULES]uint64) {
+	rxC := &face.getPtr().impl.rx
+	for i := 0; i < C.RXPROC_MAX_THREADS; i++ {
+		for j := 0; j < ACL_MAX_RULES; j++ {
+			hits[j] += uint64(rxC.threads[i].nAclHits[j])
+		}
+	}
+	return hits
+}
+
+// Set name-based access control list on incoming packets.
+// Rules are evaluated in order; the first rule matching the packet name determines whether
+// the packet is allowed or dropped. Packets not matching any rule are allowed.
+// Empty rules remove the access control list.
+func (face *FaceBase) SetAcl(rules []AclRule) error {
+	if e := ValidateAcl(rules); e != nil {
+		return e
+	}
+	faceC := face.getPtr()
+	if faceC.impl == nil {
+		return errors.New("face is closed")
+	}
+
+	var aclC *C.Acl
+	if len(rules) > 0 {
+		aclC = makeAcl(rules, face.GetNumaSocket())
+	}
+
+	aclLock.Lock()
+	defer aclLock.Unlock()
+	oldAclC := urcu.NewPointer(&faceC.impl.rx.acl).Xchg(unsafe.Pointer(aclC))
+	urcu.Synchronize()
+	if oldAclC != nil {
+		dpdk.Free(oldAclC)
+	}
+
+	if aclC == nil {
+		delete(aclRecords, face.id)
+	} else {
+		aclRecords[face.id] = &aclRecord{
+			rules:    append([]AclRule(nil), rules...),
+			hitsBase: face.readAclHits(),
+		}
+	}
+	return nil
+}
+
+// Get name-based access control list rules.
+func (face *FaceBase) GetAcl() (rules []AclRule) {
+	aclLock.Lock()
+	defer aclLock.Unlock()
+	rules = make([]AclRule, 0)
+	if record := aclRecords[face.id]; record != nil {
+		rules = append(rules, record.rules...)
+	}
+	return rules
+}
+
+func (face FaceBase) readAclCounters() (cnt AclCounters) {
+	rxC := &face.getPtr().impl.rx
+	for i := 0; i < C.RXPROC_MAX_THREADS; i++ {
+		rxtC := &rxC.threads[i]
+		cnt.Drops += uint64(rxtC.nAclDrops)
+		cnt.LocalhostDrops += uint64(rxtC.nLocalhostDrops)
+	}
+
+	aclLock.Lock()
+	defer aclLock.Unlock()
+	cnt.Hits = make([]uint64, 0)
+	if record := aclRecords[face.id]; record != nil {
+		hits := face.readAclHits()
+		for i := range record.rules {
+			cnt.Hits = append(cnt.Hits, hits[i]-record.hitsBase[i])
+		}
+	}
+	return cnt
+}
+
+func (face *FaceBase) clearAcl() {
+	aclLock.Lock()
+	defer aclLock.Unlock()
+	delete(aclRecords, face.id)
+	if aclC := face.getPtr().impl.rx.acl; aclC != nil {
+		dpdk.Free(aclC)
+	}
+}
+
+// Determine whether the face is local.
+// A non-local face drops incoming packets under /localhost prefix.
+func (face *FaceBase) IsLocal() bool {
+	return bool(face.getPtr().impl.rx.isLocal)
+}
+
+// Set whether the face is local.
+// Lower layer implementation should call this after InitFaceBase.
+func (face *FaceBase) SetLocal(isLocal bool) {
+	face.getPtr().impl.rx.isLocal = C.bool(isLocal)
+}
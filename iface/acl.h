@@ -0,0 +1,59 @@
+#ifndef NDN_DPDK_IFACE_ACL_H
+#define NDN_DPDK_IFACE_ACL_H
+
+/// \file
+
+#include "common.h"
+
+/** \brief Maximum number of rules in an Acl.
+ */
+#define ACL_MAX_RULES 16
+
+/** \brief Acl evaluation result when no rule matches.
+ */
+#define ACL_NO_MATCH (-1)
+
+/** \brief Name prefix rule in Acl.
+ */
+typedef struct AclRule
+{
+  uint16_t nameL;   ///< prefix TLV-LENGTH
+  uint16_t nameOff; ///< offset of prefix TLV-VALUE within Acl.names
+  bool deny;        ///< whether matching packets are dropped
+} AclRule;
+
+/** \brief Name-based access control list.
+ *
+ *  Rules are evaluated in order, and the first rule whose prefix matches the
+ *  packet name determines the action. Packets not matching any rule are
+ *  allowed.
+ */
+typedef struct Acl
+{
+  uint8_t nRules;
+  AclRule rules[ACL_MAX_RULES];
+  uint8_t names[0]; ///< prefix TLV-VALUEs
+} Acl;
+
+/** \brief Find the first rule matching a name.
+ *  \return rule index, or \c ACL_NO_MATCH.
+ */
+static inline int
+Acl_Match(const Acl* acl, LName name)
+{
+  for (int i = 0; i < acl->nRules; ++i) {
+    const AclRule* rule = &acl->rules[i];
+    if (rule->nameL <= name.length &&
+        memcmp(&acl->names[rule->nameOff], name.value, rule->nameL) == 0) {
+      return i;
+    }
+  }
+  return ACL_NO_MATCH;
+}
+
+/** \brief Determine whether a name starts with /localhost.
+ */
+bool
+Acl_IsLocalhostName(LName name);
+
+#endif // NDN_DPDK_IFACE_ACL_H
@@ -0,0 +1,89 @@
+#include "bundle.h"
+#include "face.h"
+
+#include "../ndn/tlv-element.h"
+#include <rte_jhash.h>
+
+static uint32_t
+Bundle_HashName(Packet* npkt)
+{
+  MbufLoc d;
+  MbufLoc_Init(&d, Packet_ToMbuf(npkt));
+
+  // Interest and Nack start with Interest TLV, Data starts with Data TLV;
+  // Name is the first element in either
+  TlvElement ele;
+  if (unlikely(TlvElement_DecodeTL(&ele, &d, TT_Invalid) != NdnError_OK ||
+               TlvElement_DecodeTL(&ele, &d, TT_Name) != NdnError_OK)) {
+    return 0;
+  }
+
+  MbufLoc vd;
+  TlvElement_MakeValueDecoder(&ele, &vd);
+  uint32_t hash = 0;
+  uint8_t buf[64];
+  uint32_t n;
+  while ((n = MbufLoc_ReadTo(&vd, buf, sizeof(buf))) > 0) {
+    hash = rte_jhash(buf, n, hash);
+  }
+  return hash;
+}
+
+/** \brief Select a member face that is UP.
+ *  \return index in bundle->members, or -1 if no member is UP.
+ */
+static int
+Bundle_SelectMember(Bundle* bundle, Packet* npkt)
+{
+  uint8_t nMembers = bundle->nMembers;
+  if (unlikely(nMembers == 0)) {
+    return -1;
+  }
+
+  uint32_t start;
+  switch (bundle->policy) {
+    case BundlePolicy_RoundRobin:
+      start = atomic_fetch_add_explicit(
+        &bundle->rrNext, 1, memory_order_relaxed);
+      break;
+    default:
+      start = Bundle_HashName(npkt);
+      break;
+  }
+
+  for (uint8_t k = 0; k < nMembers; ++k) {
+    int i = (start + k) % nMembers;
+    if (likely(!Face_IsDown(bundle->members[i]))) {
+      return i;
+    }
+  }
+  return -1;
+}
+
+void
+Bundle_TxBurst(Bundle* bundle, Packet** npkts, uint16_t count)
+{
+  Packet* dest[BUNDLE_MAX_MEMBERS][BUNDLE_BURST_SIZE];
+  uint16_t nDest[BUNDLE_MAX_MEMBERS];
+
+  for (uint16_t offset = 0; offset < count; offset += BUNDLE_BURST_SIZE) {
+    uint16_t n = RTE_MIN(count - offset, BUNDLE_BURST_SIZE);
+    memset(nDest, 0, sizeof(nDest));
+
+    for (uint16_t j = offset; j < offset + n; ++j) {
+      int i = Bundle_SelectMember(bundle, npkts[j]);
+      if (unlikely(i < 0)) {
+        rte_pktmbuf_free(Packet_ToMbuf(npkts[j]));
+        atomic_fetch_add_explicit(&bundle->nNoMember, 1, memory_order_relaxed);
+        continue;
+      }
+      dest[i][nDest[i]++] = npkts[j];
+    }
+
+    for (int i = 0; i < bundle->nMembers; ++i) {
+      if (nDest[i] > 0) {
+        Face_TxBurst(bundle->members[i], dest[i], nDest[i]);
+      }
+    }
+  }
+}
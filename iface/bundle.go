@@ -0,0 +1,212 @@
+package iface
+
+/*
+#include "face.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"ndn-dpdk/core/urcu"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/ndn"
+)
+
+const BUNDLE_MAX_MEMBERS = C.BUNDLE_MAX_MEMBERS
+
+// How a bundle face selects a member face for each packet.
+type BundlePolicy int
+
+const (
+	BundlePolicy_NameHash   BundlePolicy = C.BundlePolicy_NameHash
+	BundlePolicy_RoundRobin BundlePolicy = C.BundlePolicy_RoundRobin
+)
+
+var bundlePolicyStrings = map[BundlePolicy]string{
+	BundlePolicy_NameHash:   "namehash",
+	BundlePolicy_RoundRobin: "roundrobin",
+}
+
+func (policy BundlePolicy) String() string {
+	if s, ok := bundlePolicyStrings[policy]; ok {
+		return s
+	}
+	return fmt.Sprintf("%d", int(policy))
+}
+
+// Parse BundlePolicy from string.
+// Empty string means BundlePolicy_NameHash.
+func ParseBundlePolicy(s string) (BundlePolicy, error) {
+	if s == "" {
+		return BundlePolicy_NameHash, nil
+	}
+	for policy, str := range bundlePolicyStrings {
+		if s == str {
+			return policy, nil
+		}
+	}
+	return BundlePolicy_NameHash, fmt.Errorf("unknown bundle policy %s", s)
+}
+
+type bundleRecord struct {
+	policy        BundlePolicy
+	members       []FaceId
+	nNoMemberBase uint64 // nNoMember from previously installed member lists
+}
+
+var (
+	bundleLock    sync.Mutex
+	bundleRecords = make(map[FaceId]*bundleRecord)
+)
+
+// Check whether faces can become members of a new bundle face.
+func ValidateBundleMembers(members []FaceId) error {
+	bundleLock.Lock()
+	defer bundleLock.Unlock()
+	return validateBundleMembers(FACEID_INVALID, members)
+}
+
+func validateBundleMembers(bundleId FaceId, members []FaceId) error {
+	if len(members) > BUNDLE_MAX_MEMBERS {
+		return fmt.Errorf("too many bundle members, maximum is %d", BUNDLE_MAX_MEMBERS)
+	}
+	isMember := make(map[FaceId]bool)
+	for _, id := range members {
+		if isMember[id] {
+			return fmt.Errorf("face %d is listed more than once", id)
+		}
+		isMember[id] = true
+		if id == bundleId {
+			return errors.New("bundle cannot contain itself")
+		}
+		member := Get(id)
+		if member == nil {
+			return fmt.Errorf("face %d does not exist", id)
+		}
+		if bundleRecords[id] != nil {
+			return fmt.Errorf("face %d is a bundle", id)
+		}
+		if memberOf := FaceId(member.getPtr().bundleId); memberOf != FACEID_INVALID && memberOf != bundleId {
+			return fmt.Errorf("face %d belongs to bundle %d", id, memberOf)
+		}
+	}
+	return nil
+}
+
+// Determine whether the face is a bundle face.
+func (face *FaceBase) IsBundle() bool {
+	return face.getPtr().bundle != nil
+}
+
+// Get FaceId of the bundle face containing this face, or FACEID_INVALID.
+func (face *FaceBase) GetBundleId() FaceId {
+	return FaceId(face.getPtr().bundleId)
+}
+
+// Make this face a bundle face, or change its member list.
+// Outgoing packets are dispatched to members according to policy.
+// Incoming packets on members are presented as if they arrive on this face.
+// A member must be an existing face that is neither a bundle face nor a member of another bundle.
+func (face *FaceBase) SetBundle(policy BundlePolicy, members []FaceId) error {
+	faceC := face.getPtr()
+	if faceC.impl == nil {
+		return errors.New("face is closed")
+	}
+	if _, ok := bundlePolicyStrings[policy]; !ok {
+		return fmt.Errorf("unknown bundle policy %d", int(policy))
+	}
+
+	bundleLock.Lock()
+	defer bundleLock.Unlock()
+	if e := validateBundleMembers(face.id, members); e != nil {
+		return e
+	}
+	isMember := make(map[FaceId]bool)
+	for _, id := range members {
+		isMember[id] = true
+	}
+
+	record := bundleRecords[face.id]
+	if record == nil {
+		record = new(bundleRecord)
+	}
+	oldMembers := record.members
+	record.policy = policy
+	record.members = append([]FaceId{}, members...)
+
+	bundleC := (*C.Bundle)(dpdk.Zmalloc("Bundle", C.sizeof_Bundle, face.GetNumaSocket()))
+	bundleC.policy = C.BundlePolicy(policy)
+	bundleC.nMembers = C.uint8_t(len(members))
+	for i, id := range members {
+		bundleC.members[i] = C.FaceId(id)
+		C.gFaces_[id].bundleId = C.FaceId(face.id)
+	}
+
+	oldBundleC := (*C.Bundle)(urcu.NewPointer(&faceC.bundle).Xchg(unsafe.Pointer(bundleC)))
+	urcu.Synchronize()
+	for _, id := range oldMembers {
+		if !isMember[id] {
+			C.gFaces_[id].bundleId = C.FACEID_INVALID
+		}
+	}
+	if oldBundleC != nil {
+		record.nNoMemberBase += uint64(oldBundleC.nNoMember)
+		dpdk.Free(oldBundleC)
+	}
+
+	bundleRecords[face.id] = record
+	return nil
+}
+
+// Get bundle policy and member list.
+// ok is false if this is not a bundle face.
+func (face *FaceBase) GetBundle() (policy BundlePolicy, members []FaceId, ok bool) {
+	bundleLock.Lock()
+	defer bundleLock.Unlock()
+	record := bundleRecords[face.id]
+	if record == nil {
+		return BundlePolicy_NameHash, nil, false
+	}
+	return record.policy, append([]FaceId{}, record.members...), true
+}
+
+// Read number of packets dropped by a bundle face because no member is UP.
+func (face *FaceBase) ReadBundleNoMember() (n uint64) {
+	bundleLock.Lock()
+	defer bundleLock.Unlock()
+	if record := bundleRecords[face.id]; record != nil {
+		n = record.nNoMemberBase
+	}
+	if bundleC := face.getPtr().bundle; bundleC != nil {
+		n += uint64(bundleC.nNoMember)
+	}
+	return n
+}
+
+// Transmit packets from a thread that is not an RCU read-side thread.
+// Holding bundleLock prevents Face.bundle from being replaced or freed during Face_TxBurst.
+func (face *FaceBase) txBurstLocked(pkts []ndn.Packet) {
+	bundleLock.Lock()
+	defer bundleLock.Unlock()
+	C.Face_TxBurst(C.FaceId(face.id), (**C.Packet)(unsafe.Pointer(&pkts[0])), C.uint16_t(len(pkts)))
+}
+
+func (face *FaceBase) clearBundle() {
+	bundleLock.Lock()
+	defer bundleLock.Unlock()
+	faceC := face.getPtr()
+	if record := bundleRecords[face.id]; record != nil {
+		for _, id := range record.members {
+			C.gFaces_[id].bundleId = C.FACEID_INVALID
+		}
+		delete(bundleRecords, face.id)
+	}
+	if bundleC := faceC.bundle; bundleC != nil {
+		urcu.NewPointer(&faceC.bundle).Xchg(nil)
+		urcu.Synchronize()
+		dpdk.Free(bundleC)
+	}
+}
@@ -0,0 +1,49 @@
+#ifndef NDN_DPDK_IFACE_BUNDLE_H
+#define NDN_DPDK_IFACE_BUNDLE_H
+
+/// \file
+
+#include "faceid.h"
+
+/** \brief Maximum number of member faces in a bundle.
+ */
+#define BUNDLE_MAX_MEMBERS 8
+
+/** \brief Number of packets dispatched at a time.
+ */
+#define BUNDLE_BURST_SIZE 64
+
+/** \brief How a bundle face selects a member face for each packet.
+ */
+typedef enum BundlePolicy
+{
+  BundlePolicy_NameHash = 0,   ///< select by hash of packet name
+  BundlePolicy_RoundRobin = 1, ///< select in round-robin order
+} BundlePolicy;
+
+/** \brief Member list of a bundle face.
+ *
+ *  This struct is immutable except counters. Changing members requires
+ *  replacing the struct in Face.bundle via RCU.
+ */
+typedef struct Bundle
+{
+  BundlePolicy policy;
+  uint8_t nMembers;
+  FaceId members[BUNDLE_MAX_MEMBERS];
+
+  _Atomic uint32_t rrNext;    ///< next round-robin position
+  _Atomic uint64_t nNoMember; ///< packets dropped because no member is UP
+} Bundle;
+
+/** \brief Dispatch a burst of packets to member faces.
+ *  \param npkts array of L3 packets; bundle takes ownership
+ *  \param count size of \p npkts array
+ *
+ *  Packets of the same name are sent on the same member face, unless that
+ *  member is DOWN, in which case the next member that is UP is used.
+ */
+void
+Bundle_TxBurst(Bundle* bundle, Packet** npkts, uint16_t count);
+
+#endif // NDN_DPDK_IFACE_BUNDLE_H
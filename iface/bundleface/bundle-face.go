@@ -0,0 +1,240 @@
+package bundleface
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+)
+
+var errMemberOnly = errors.New("not supported on bundle face, configure member faces instead")
+
+// Serialize member list changes.
+var membersLock sync.Mutex
+
+type BundleFace struct {
+	iface.FaceBase
+	closing bool
+}
+
+// Create a bundle face.
+func New(loc Locator, mempools iface.Mempools) (face *BundleFace, e error) {
+	if e = loc.Validate(); e != nil {
+		return nil, e
+	}
+	policy, _ := iface.ParseBundlePolicy(loc.Policy)
+
+	membersLock.Lock()
+	face, e = newBundleFace(policy, loc.Members, mempools)
+	membersLock.Unlock()
+	if e != nil {
+		return nil, e
+	}
+
+	iface.Put(face)
+	face.updateState()
+	return face, nil
+}
+
+func newBundleFace(policy iface.BundlePolicy, members []iface.FaceId, mempools iface.Mempools) (face *BundleFace, e error) {
+	// validate before FaceBase initialization, so that failures do not emit face events
+	if e = iface.ValidateBundleMembers(members); e != nil {
+		return nil, e
+	}
+
+	face = new(BundleFace)
+	if e = face.InitFaceBase(iface.AllocId(iface.FaceKind_Bundle), 0, dpdk.NUMA_SOCKET_ANY); e != nil {
+		return nil, e
+	}
+	if e = face.FinishInitFaceBase(64, 0, 0, mempools); e != nil {
+		return nil, e
+	}
+	if e = face.SetBundle(policy, members); e != nil {
+		face.CloseFaceBase()
+		return nil, e
+	}
+	return face, nil
+}
+
+func (face *BundleFace) GetLocator() iface.Locator {
+	policy, members, _ := face.GetBundle()
+	return NewLocator(policy, members...)
+}
+
+func (face *BundleFace) Close() error {
+	if face.IsClosed() {
+		return nil
+	}
+	membersLock.Lock()
+	face.closing = true
+	membersLock.Unlock()
+	face.BeforeClose()
+	face.CloseFaceBase()
+	return nil
+}
+
+func (*BundleFace) ListRxGroups() []iface.IRxGroup {
+	return nil
+}
+
+// Add a member face.
+func (face *BundleFace) AddMember(id iface.FaceId) error {
+	membersLock.Lock()
+	policy, members, _ := face.GetBundle()
+	e := face.SetBundle(policy, append(members, id))
+	membersLock.Unlock()
+	if e != nil {
+		return e
+	}
+	face.updateState()
+	return nil
+}
+
+// Remove a member face.
+func (face *BundleFace) RemoveMember(id iface.FaceId) error {
+	membersLock.Lock()
+	policy, members, _ := face.GetBundle()
+	found := false
+	for i, member := range members {
+		if member == id {
+			members = append(members[:i], members[i+1:]...)
+			found = true
+			break
+		}
+	}
+	e := fmt.Errorf("face %d is not a member", id)
+	if found {
+		e = face.SetBundle(policy, members)
+	}
+	membersLock.Unlock()
+	if e != nil {
+		return e
+	}
+	face.updateState()
+	return nil
+}
+
+// Set the bundle face UP if any member is UP, otherwise DOWN.
+func (face *BundleFace) updateState() {
+	membersLock.Lock()
+	_, members, _ := face.GetBundle()
+	isDown := true
+	for _, id := range members {
+		if member := iface.Get(id); member != nil && !member.IsDown() {
+			isDown = false
+			break
+		}
+	}
+	closing := face.closing
+	membersLock.Unlock()
+
+	if !closing {
+		face.SetDown(isDown)
+	}
+}
+
+// Read counters as the sum of current members' counters.
+func (face *BundleFace) ReadCounters() (cnt iface.Counters) {
+	_, members, _ := face.GetBundle()
+	for _, id := range members {
+		if member := iface.Get(id); member != nil {
+			cnt = addCounters(cnt, member.ReadCounters())
+		}
+	}
+	return cnt
+}
+
+func addCounters(cnt, other iface.Counters) iface.Counters {
+	cnt.RxFrames += other.RxFrames
+	cnt.RxOctets += other.RxOctets
+	cnt.L2DecodeErrs += other.L2DecodeErrs
+	cnt.L3DecodeErrs += other.L3DecodeErrs
+	cnt.RxInterests += other.RxInterests
+	cnt.RxData += other.RxData
+	cnt.RxNacks += other.RxNacks
+	cnt.TxInterests += other.TxInterests
+	cnt.TxData += other.TxData
+	cnt.TxNacks += other.TxNacks
+	cnt.FragGood += other.FragGood
+	cnt.FragBad += other.FragBad
+	cnt.TxAllocErrs += other.TxAllocErrs
+	cnt.TxCongMarks += other.TxCongMarks
+	cnt.TxDropped += other.TxDropped
+	cnt.TxFrames += other.TxFrames
+	cnt.TxOctets += other.TxOctets
+	return cnt
+}
+
+type ExCounters struct {
+	Policy        string         // member selection policy
+	Members       []iface.FaceId // current member faces
+	NoMemberDrops uint64         // packets dropped because no member is UP
+}
+
+func (face *BundleFace) ReadExCounters() interface{} {
+	var cnt ExCounters
+	policy, members, _ := face.GetBundle()
+	cnt.Policy = policy.String()
+	cnt.Members = members
+	cnt.NoMemberDrops = face.ReadBundleNoMember()
+	return cnt
+}
+
+// Bundle face has no RxProc or TxProc of its own, so that these features must be configured on members.
+
+func (face *BundleFace) SetAcl(rules []iface.AclRule) error {
+	if len(rules) > 0 {
+		return errMemberOnly
+	}
+	return nil
+}
+
+func (face *BundleFace) SetLpReliability(cfg *iface.LpReliabilityConfig) error {
+	if cfg != nil {
+		return errMemberOnly
+	}
+	return nil
+}
+
+func (face *BundleFace) SetCongMark(cfg *iface.CongMarkConfig) error {
+	if cfg != nil {
+		return errMemberOnly
+	}
+	return nil
+}
+
+func (face *BundleFace) SetRateLimit(cfg *iface.RateLimitConfig) error {
+	if cfg != nil {
+		return errMemberOnly
+	}
+	return nil
+}
+
+func getBundle(memberId iface.FaceId) *BundleFace {
+	member := iface.Get(memberId)
+	if member == nil {
+		return nil
+	}
+	bundle, _ := iface.Get(member.GetBundleId()).(*BundleFace)
+	return bundle
+}
+
+func handleMemberUpDown(id iface.FaceId) {
+	if bundle := getBundle(id); bundle != nil {
+		bundle.updateState()
+	}
+}
+
+func handleMemberClosing(id iface.FaceId) {
+	if bundle := getBundle(id); bundle != nil {
+		bundle.RemoveMember(id)
+	}
+}
+
+var (
+	theMemberUpEvt      = iface.OnFaceUp(handleMemberUpDown)
+	theMemberDownEvt    = iface.OnFaceDown(handleMemberUpDown)
+	theMemberClosingEvt = iface.OnFaceClosing(handleMemberClosing)
+)
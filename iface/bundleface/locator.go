@@ -0,0 +1,40 @@
+package bundleface
+
+import (
+	"errors"
+	"fmt"
+
+	"ndn-dpdk/iface"
+)
+
+const locatorScheme = "bundle"
+
+type Locator struct {
+	iface.LocatorBase
+	Policy  string         `json:",omitempty"` // "namehash" (default) or "roundrobin"
+	Members []iface.FaceId // member faces
+}
+
+func NewLocator(policy iface.BundlePolicy, members ...iface.FaceId) (loc Locator) {
+	loc.Scheme = locatorScheme
+	loc.Policy = policy.String()
+	loc.Members = members
+	return loc
+}
+
+func (loc Locator) Validate() error {
+	if _, e := iface.ParseBundlePolicy(loc.Policy); e != nil {
+		return e
+	}
+	if len(loc.Members) == 0 {
+		return errors.New("bundle must have at least one member")
+	}
+	if len(loc.Members) > iface.BUNDLE_MAX_MEMBERS {
+		return fmt.Errorf("too many bundle members, maximum is %d", iface.BUNDLE_MAX_MEMBERS)
+	}
+	return nil
+}
+
+func init() {
+	iface.RegisterLocatorType(Locator{}, locatorScheme)
+}
@@ -0,0 +1,14 @@
+export interface Locator {
+  Scheme: "bundle";
+
+  /**
+   * @default "namehash"
+   */
+  Policy?: "namehash"|"roundrobin";
+
+  /**
+   * @minItems 1
+   * @maxItems 8
+   */
+  Members: number[];
+}
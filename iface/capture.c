@@ -0,0 +1,149 @@
+#include "capture.h"
+
+#include <rte_memcpy.h>
+#include <rte_ring.h>
+
+static bool
+FaceCapture_Match(const FaceCapture* cap, Packet* npkt)
+{
+  L3PktType l3type = Packet_GetL3PktType(npkt);
+  if (l3type == L3PktType_None || !(cap->l3types & (1 << l3type))) {
+    return false;
+  }
+  if (cap->prefixL == 0) {
+    return true;
+  }
+  const LName* name = Packet_GetL3Name(npkt);
+  return cap->prefixL <= name->length &&
+         memcmp(cap->prefix, name->value, cap->prefixL) == 0;
+}
+
+static bool
+FaceCapture_IsFull(const FaceCapture* cap)
+{
+  return atomic_load_explicit(&cap->nPackets, memory_order_relaxed) >=
+           cap->maxPackets ||
+         atomic_load_explicit(&cap->nBytes, memory_order_relaxed) >=
+           cap->maxBytes;
+}
+
+static void
+FaceCapture_Copy(FaceCaptureRecord* rec,
+                 struct rte_mbuf* pkt,
+                 uint8_t nackReason,
+                 uint16_t snaplen)
+{
+  rec->origLen = pkt->pkt_len;
+  rec->capLen = RTE_MIN(pkt->pkt_len, (uint32_t)snaplen);
+  rec->nackReason = nackReason;
+
+  const void* data = rte_pktmbuf_read(pkt, 0, rec->capLen, rec->data);
+  if (data != rec->data) {
+    rte_memcpy(rec->data, data, rec->capLen);
+  }
+}
+
+/** \brief Allocate a record and copy a packet or frame into it.
+ *  \return the record, or NULL if mempool is exhausted.
+ */
+static FaceCaptureRecord*
+FaceCapture_MakeRecord(FaceCapture* cap,
+                       FaceCaptureDir dir,
+                       TscTime now,
+                       struct rte_mbuf* pkt,
+                       uint8_t nackReason)
+{
+  FaceCaptureRecord* rec = NULL;
+  if (unlikely(rte_mempool_get(cap->mp, (void**)&rec) != 0)) {
+    atomic_fetch_add_explicit(&cap->nDropped, 1, memory_order_relaxed);
+    return NULL;
+  }
+  rec->timestamp = now;
+  rec->dir = dir;
+  FaceCapture_Copy(rec, pkt, nackReason, cap->snaplen);
+  return rec;
+}
+
+/** \brief Enqueue records into the ring, and update counters.
+ */
+static void
+FaceCapture_Enqueue(FaceCapture* cap, FaceCaptureRecord** recs, uint16_t nRecs)
+{
+  if (nRecs == 0) {
+    return;
+  }
+
+  uint64_t nBytes = 0;
+  uint16_t nEnqueued =
+    rte_ring_enqueue_burst(cap->ring, (void**)recs, nRecs, NULL);
+  for (uint16_t i = 0; i < nEnqueued; ++i) {
+    nBytes += recs[i]->capLen;
+  }
+  if (unlikely(nEnqueued < nRecs)) {
+    rte_mempool_put_bulk(cap->mp, (void**)&recs[nEnqueued], nRecs - nEnqueued);
+    atomic_fetch_add_explicit(
+      &cap->nDropped, nRecs - nEnqueued, memory_order_relaxed);
+  }
+  atomic_fetch_add_explicit(&cap->nPackets, nEnqueued, memory_order_relaxed);
+  atomic_fetch_add_explicit(&cap->nBytes, nBytes, memory_order_relaxed);
+}
+
+void
+FaceCapture_Post(FaceCapture* cap,
+                 FaceCaptureDir dir,
+                 Packet** npkts,
+                 uint16_t count)
+{
+  assert(count <= FACECAPTURE_BURST_SIZE);
+  if (cap->frames || !(cap->dirs & dir) || FaceCapture_IsFull(cap)) {
+    return;
+  }
+
+  TscTime now = rte_get_tsc_cycles();
+  FaceCaptureRecord* recs[FACECAPTURE_BURST_SIZE];
+  uint16_t nRecs = 0;
+  for (uint16_t i = 0; i < count; ++i) {
+    Packet* npkt = npkts[i];
+    if (!FaceCapture_Match(cap, npkt)) {
+      continue;
+    }
+
+    uint8_t nackReason = NackReason_None;
+    if (Packet_GetL3PktType(npkt) == L3PktType_Nack) {
+      nackReason = Packet_GetLpL3Hdr(npkt)->nackReason;
+    }
+    FaceCaptureRecord* rec =
+      FaceCapture_MakeRecord(cap, dir, now, Packet_ToMbuf(npkt), nackReason);
+    if (rec != NULL) {
+      recs[nRecs++] = rec;
+    }
+  }
+  FaceCapture_Enqueue(cap, recs, nRecs);
+}
+
+void
+FaceCapture_PostFrames(FaceCapture* cap,
+                       FaceCaptureDir dir,
+                       struct rte_mbuf** frames,
+                       uint16_t count)
+{
+  if (!cap->frames || !(cap->dirs & dir) || FaceCapture_IsFull(cap)) {
+    return;
+  }
+
+  TscTime now = rte_get_tsc_cycles();
+  FaceCaptureRecord* recs[FACECAPTURE_BURST_SIZE];
+  uint16_t nRecs = 0;
+  for (uint16_t i = 0; i < count; ++i) {
+    FaceCaptureRecord* rec =
+      FaceCapture_MakeRecord(cap, dir, now, frames[i], NackReason_None);
+    if (rec != NULL) {
+      recs[nRecs++] = rec;
+    }
+    if (nRecs == FACECAPTURE_BURST_SIZE) {
+      FaceCapture_Enqueue(cap, recs, nRecs);
+      nRecs = 0;
+    }
+  }
+  FaceCapture_Enqueue(cap, recs, nRecs);
+}
@@ -0,0 +1,333 @@
+package iface
+
+/*
+#include "face.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"ndn-dpdk/core/urcu"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/ndn"
+)
+
+const (
+	CAPTURE_DEFAULT_SNAPLEN = 2048
+	CAPTURE_MAX_SNAPLEN     = math.MaxUint16
+
+	captureRingCapacity = 4096
+	captureEtherHdrLen  = 14
+)
+
+// Interval of moving captured packets from ring to file.
+var CapturePollInterval = 10 * time.Millisecond
+
+// Directory where packet capture files are created.
+var CaptureDir = os.TempDir()
+
+// Packet capture configuration.
+type CaptureConfig struct {
+	Rx bool // capture incoming packets
+	Tx bool // capture outgoing packets; if neither Rx nor Tx is set, both directions are captured
+
+	Frames bool // capture L2 frames instead of L3 packets; cannot be used with Prefix or PacketTypes
+
+	Prefix      *ndn.Name // name prefix; empty prefix matches every packet
+	PacketTypes []string  // "Interest", "Data", "Nack"; empty list matches every packet
+
+	Snaplen    int // maximum captured octets per packet, default is CAPTURE_DEFAULT_SNAPLEN
+	MaxPackets int // stop after capturing this many packets, 0 means unlimited
+	MaxBytes   int // stop after capturing this many octets, 0 means unlimited
+}
+
+func (cfg CaptureConfig) parseL3Types() (l3types uint8, e error) {
+	if len(cfg.PacketTypes) == 0 {
+		return 1<<ndn.L3PktType_Interest | 1<<ndn.L3PktType_Data | 1<<ndn.L3PktType_Nack, nil
+	}
+	for _, s := range cfg.PacketTypes {
+		found := false
+		for _, t := range []ndn.L3PktType{ndn.L3PktType_Interest, ndn.L3PktType_Data, ndn.L3PktType_Nack} {
+			if s == t.String() {
+				l3types |= 1 << t
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown packet type %s", s)
+		}
+	}
+	return l3types, nil
+}
+
+// Validate packet capture configuration.
+func (cfg CaptureConfig) Validate() error {
+	if cfg.Prefix.Size() > ndn.NAME_MAX_LENGTH {
+		return errors.New("Prefix too long")
+	}
+	if cfg.Frames && (cfg.Prefix.Size() > 0 || len(cfg.PacketTypes) > 0) {
+		return errors.New("Prefix and PacketTypes cannot be used with Frames")
+	}
+	if cfg.Snaplen < 0 || cfg.Snaplen > CAPTURE_MAX_SNAPLEN {
+		return fmt.Errorf("Snaplen must be between 1 and %d", CAPTURE_MAX_SNAPLEN)
+	}
+	if cfg.MaxPackets < 0 || cfg.MaxBytes < 0 {
+		return errors.New("MaxPackets and MaxBytes must not be negative")
+	}
+	_, e := cfg.parseL3Types()
+	return e
+}
+
+// Packet capture counters.
+type CaptureCounters struct {
+	Packets uint64 // packets written to file
+	Bytes   uint64 // captured octets written to file
+	Dropped uint64 // packets lost because the collector could not keep up
+}
+
+func (cnt CaptureCounters) String() string {
+	return fmt.Sprintf("%dpkts %dbytes %ddropped", cnt.Packets, cnt.Bytes, cnt.Dropped)
+}
+
+type captureRecord struct {
+	cfg    CaptureConfig
+	c      *C.FaceCapture
+	ring   dpdk.Ring
+	mp     dpdk.Mempool
+	file   *os.File
+	w      *pcapngWriter
+	cnt    CaptureCounters
+	err    error // first write error
+	epoch  dpdk.TscTime
+	epochT time.Time
+	stop   chan struct{}
+	finish chan error
+}
+
+var (
+	captureLock    sync.Mutex
+	captureRecords = make(map[FaceId]*captureRecord)
+)
+
+// Create a new capture file in CaptureDir.
+// filename must be a plain file name without path separators, and must not refer to an existing file.
+func createCaptureFile(filename string) (*os.File, error) {
+	if filename == "" || filename == "." || filename == ".." ||
+		strings.ContainsRune(filename, '/') || strings.ContainsRune(filename, filepath.Separator) {
+		return nil, errors.New("filename must not contain path separators")
+	}
+	return os.OpenFile(filepath.Join(CaptureDir, filename), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0640)
+}
+
+// Start capturing packets into a pcapng file.
+// The file is created in CaptureDir; filename must not contain path separators or refer to an existing file.
+// Each L3 packet or L2 frame is written with a synthesized Ethernet header with NDN EtherType.
+// A face can have at most one running capture.
+func (face *FaceBase) StartCapture(filename string, cfg CaptureConfig) (e error) {
+	if e = cfg.Validate(); e != nil {
+		return e
+	}
+	faceC := face.getPtr()
+	if faceC.impl == nil {
+		return errors.New("face is closed")
+	}
+	if faceC.bundle != nil {
+		return errors.New("bundle face cannot capture packets, capture on member faces instead")
+	}
+	if cfg.Snaplen == 0 {
+		cfg.Snaplen = CAPTURE_DEFAULT_SNAPLEN
+	}
+
+	captureLock.Lock()
+	defer captureLock.Unlock()
+	if captureRecords[face.id] != nil {
+		return errors.New("capture is already running")
+	}
+
+	record := &captureRecord{cfg: cfg}
+	if record.file, e = createCaptureFile(filename); e != nil {
+		return e
+	}
+	defer func() {
+		if e != nil {
+			record.file.Close()
+			os.Remove(record.file.Name())
+		}
+	}()
+	if record.w, e = newPcapngWriter(record.file, fmt.Sprintf("face%d", face.id), captureEtherHdrLen+cfg.Snaplen); e != nil {
+		return e
+	}
+
+	socket := face.GetNumaSocket()
+	name := fmt.Sprintf("FaceCapture%d", face.id)
+	if record.ring, e = dpdk.NewRing(name, captureRingCapacity, socket, false, true); e != nil {
+		return e
+	}
+	if record.mp, e = dpdk.NewMempool(name, captureRingCapacity-1, int(C.sizeof_FaceCaptureRecord)+cfg.Snaplen, socket); e != nil {
+		record.ring.Close()
+		return e
+	}
+
+	record.c = makeFaceCapture(cfg, record.ring, record.mp, socket)
+	record.epoch = dpdk.TscNow()
+	record.epochT = record.epoch.ToTime()
+	record.stop = make(chan struct{})
+	record.finish = make(chan error, 1)
+	go record.collectLoop()
+
+	captureRecords[face.id] = record
+	urcu.NewPointer(&faceC.capture).Xchg(unsafe.Pointer(record.c))
+	return nil
+}
+
+func makeFaceCapture(cfg CaptureConfig, ring dpdk.Ring, mp dpdk.Mempool, socket dpdk.NumaSocket) (capC *C.FaceCapture) {
+	var prefix []byte
+	if cfg.Prefix != nil {
+		prefix = cfg.Prefix.GetValue()
+	}
+	capC = (*C.FaceCapture)(dpdk.Zmalloc("FaceCapture", int(C.sizeof_FaceCapture)+len(prefix), socket))
+	capC.ring = (*C.struct_rte_ring)(ring.GetPtr())
+	capC.mp = (*C.struct_rte_mempool)(mp.GetPtr())
+	capC.maxPackets = C.uint64_t(math.MaxUint64)
+	if cfg.MaxPackets > 0 {
+		capC.maxPackets = C.uint64_t(cfg.MaxPackets)
+	}
+	capC.maxBytes = C.uint64_t(math.MaxUint64)
+	if cfg.MaxBytes > 0 {
+		capC.maxBytes = C.uint64_t(cfg.MaxBytes)
+	}
+	capC.snaplen = C.uint16_t(cfg.Snaplen)
+	capC.frames = C.bool(cfg.Frames)
+	if cfg.Rx || !cfg.Tx {
+		capC.dirs |= C.FaceCaptureDir_Rx
+	}
+	if cfg.Tx || !cfg.Rx {
+		capC.dirs |= C.FaceCaptureDir_Tx
+	}
+	l3types, _ := cfg.parseL3Types()
+	capC.l3types = C.uint8_t(l3types)
+	capC.prefixL = C.uint16_t(len(prefix))
+	if len(prefix) > 0 {
+		C.memcpy(unsafe.Pointer(&capC.prefix), unsafe.Pointer(&prefix[0]), C.size_t(len(prefix)))
+	}
+	return capC
+}
+
+// Stop capturing packets, and close the pcapng file.
+func (face *FaceBase) StopCapture() (cnt CaptureCounters, e error) {
+	captureLock.Lock()
+	defer captureLock.Unlock()
+	record := captureRecords[face.id]
+	if record == nil {
+		return cnt, errors.New("capture is not running")
+	}
+	return face.stopCapture(record)
+}
+
+// Get packet capture configuration, nil if not capturing.
+func (face *FaceBase) GetCapture() *CaptureConfig {
+	captureLock.Lock()
+	defer captureLock.Unlock()
+	if record := captureRecords[face.id]; record != nil {
+		cfg := record.cfg
+		return &cfg
+	}
+	return nil
+}
+
+// Detach FaceCapture and wait for the collector to write remaining packets.
+// Caller must hold captureLock.
+func (face *FaceBase) stopCapture(record *captureRecord) (cnt CaptureCounters, e error) {
+	urcu.NewPointer(&face.getPtr().capture).Xchg(nil)
+	urcu.Synchronize()
+	close(record.stop)
+	e = <-record.finish
+
+	cnt = record.cnt
+	cnt.Dropped = uint64(record.c.nDropped)
+	dpdk.Free(record.c)
+	record.mp.Close()
+	record.ring.Close()
+	delete(captureRecords, face.id)
+	return cnt, e
+}
+
+func (face *FaceBase) clearCapture() {
+	captureLock.Lock()
+	defer captureLock.Unlock()
+	if record := captureRecords[face.id]; record != nil {
+		face.stopCapture(record)
+	}
+}
+
+func (record *captureRecord) collectLoop() {
+	ticker := time.NewTicker(CapturePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-record.stop:
+			record.collect()
+			if e := record.w.Flush(); record.err == nil {
+				record.err = e
+			}
+			if e := record.file.Close(); record.err == nil {
+				record.err = e
+			}
+			record.finish <- record.err
+			return
+		case <-ticker.C:
+			record.collect()
+		}
+	}
+}
+
+// Write captured packets from ring to file.
+func (record *captureRecord) collect() {
+	recs := make([]unsafe.Pointer, C.FACECAPTURE_BURST_SIZE)
+	for {
+		n, _ := record.ring.BurstDequeue(recs)
+		for _, ptr := range recs[:n] {
+			record.write((*C.FaceCaptureRecord)(ptr))
+			record.mp.Free(ptr)
+		}
+		if n < len(recs) {
+			return
+		}
+	}
+}
+
+func (record *captureRecord) write(rec *C.FaceCaptureRecord) {
+	if (record.cfg.MaxPackets > 0 && record.cnt.Packets >= uint64(record.cfg.MaxPackets)) ||
+		(record.cfg.MaxBytes > 0 && record.cnt.Bytes >= uint64(record.cfg.MaxBytes)) {
+		return
+	}
+	record.cnt.Packets++
+	record.cnt.Bytes += uint64(rec.capLen)
+	if record.err != nil {
+		return
+	}
+
+	data := make([]byte, captureEtherHdrLen, captureEtherHdrLen+int(rec.capLen))
+	data[12] = byte(ndn.NDN_ETHERTYPE >> 8)
+	data[13] = byte(ndn.NDN_ETHERTYPE)
+	data = append(data, C.GoBytes(unsafe.Pointer(&rec.data), C.int(rec.capLen))...)
+
+	t := record.epochT.Add(dpdk.TscTime(rec.timestamp).Sub(record.epoch))
+	flags := uint32(pcapngFlagInbound)
+	if rec.dir == C.FaceCaptureDir_Tx {
+		flags = pcapngFlagOutbound
+	}
+	var comment string
+	if reason := ndn.NackReason(rec.nackReason); reason != ndn.NackReason_None {
+		comment = fmt.Sprintf("Nack~%s", reason)
+	}
+	record.err = record.w.WritePacket(t, data, captureEtherHdrLen+int(rec.origLen), flags, comment)
+}
@@ -0,0 +1,84 @@
+#ifndef NDN_DPDK_IFACE_CAPTURE_H
+#define NDN_DPDK_IFACE_CAPTURE_H
+
+/// \file
+
+#include "common.h"
+
+/** \brief Maximum number of packets posted to FaceCapture at a time.
+ */
+#define FACECAPTURE_BURST_SIZE 64
+
+/** \brief Direction of a captured packet.
+ */
+typedef enum FaceCaptureDir
+{
+  FaceCaptureDir_Rx = 1, ///< incoming packet
+  FaceCaptureDir_Tx = 2, ///< outgoing packet
+} FaceCaptureDir;
+
+/** \brief Copy of a captured L3 packet or L2 frame.
+ */
+typedef struct FaceCaptureRecord
+{
+  TscTime timestamp;
+  uint32_t origLen;   ///< L3 packet or L2 frame length
+  uint16_t capLen;    ///< captured length, no more than snaplen
+  uint8_t dir;        ///< FaceCaptureDir
+  uint8_t nackReason; ///< NackReason, or NackReason_None
+  uint8_t data[0];    ///< first capLen octets of L3 packet or L2 frame
+} FaceCaptureRecord;
+
+/** \brief Per-face packet capture.
+ *
+ *  Matching packets are copied into records allocated from \c mp, and
+ *  posted to \c ring for a collector to write into a file.
+ *  If \c frames is set, L2 frames are captured instead of L3 packets.
+ *  This struct is immutable except counters. It is attached to
+ *  Face.capture via RCU.
+ */
+typedef struct FaceCapture
+{
+  struct rte_ring* ring;     ///< multi-producer ring of FaceCaptureRecord*
+  struct rte_mempool* mp;    ///< mempool of FaceCaptureRecord
+  uint64_t maxPackets;       ///< stop after posting this many records
+  uint64_t maxBytes;         ///< stop after posting this many captured octets
+  _Atomic uint64_t nPackets; ///< posted records
+  _Atomic uint64_t nBytes;   ///< captured octets in posted records
+  _Atomic uint64_t nDropped; ///< packets lost due to full ring or mempool
+  uint16_t snaplen;          ///< maximum captured length
+  uint8_t dirs;              ///< bitmask of FaceCaptureDir
+  bool frames;               ///< whether to capture L2 frames
+  uint8_t l3types;           ///< bitmask of (1 << L3PktType)
+  uint16_t prefixL;          ///< name prefix TLV-LENGTH, 0 matches every name
+  uint8_t prefix[0];         ///< name prefix TLV-VALUE
+} FaceCapture;
+
+/** \brief Capture a burst of L3 packets.
+ *  \param npkts L3 packets; they are copied, not consumed
+ *  \param count size of \p npkts, no more than FACECAPTURE_BURST_SIZE
+ *  \pre Calling thread holds rcu_read_lock that protects \p cap.
+ *
+ *  This has no effect if \p cap captures L2 frames.
+ */
+void
+FaceCapture_Post(FaceCapture* cap,
+                 FaceCaptureDir dir,
+                 Packet** npkts,
+                 uint16_t count);
+
+/** \brief Capture a burst of L2 frames.
+ *  \param frames L2 frames starting from NDNLP header; they are copied, not
+ *                consumed
+ *  \param count size of \p frames
+ *  \pre Calling thread holds rcu_read_lock that protects \p cap.
+ *
+ *  This has no effect if \p cap captures L3 packets.
+ */
+void
+FaceCapture_PostFrames(FaceCapture* cap,
+                       FaceCaptureDir dir,
+                       struct rte_mbuf** frames,
+                       uint16_t count);
+
+#endif // NDN_DPDK_IFACE_CAPTURE_H
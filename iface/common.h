@@ -7,4 +7,22 @@
 #include "../ndn/packet.h"
 #include "../ndn/protonum.h"
 
+/** \brief Get name of an Interest, a Data, or the Interest carried in a Nack.
+ */
+static inline const LName*
+Packet_GetL3Name(Packet* npkt)
+{
+  switch (Packet_GetL3PktType(npkt)) {
+    case L3PktType_Interest:
+      return (const LName*)&Packet_GetInterestHdr(npkt)->name;
+    case L3PktType_Data:
+      return (const LName*)&Packet_GetDataHdr(npkt)->name;
+    case L3PktType_Nack:
+      return (const LName*)&Packet_GetNackHdr(npkt)->interest.name;
+    default:
+      assert(false);
+      return NULL;
+  }
+}
+
 #endif // NDN_DPDK_IFACE_COMMON_H
@@ -0,0 +1,39 @@
+#include "cong-mark.h"
+
+static TscTime
+CongMark_ControlLaw(CongMark* cm, TscTime t)
+{
+  return t + (TscDuration)(cm->interval / sqrt(cm->count));
+}
+
+bool
+CongMark_CheckAbove_(CongMark* cm, TscTime now)
+{
+  if (cm->firstAboveTime == 0) {
+    cm->firstAboveTime = now + cm->interval;
+    return false;
+  }
+  if (now < cm->firstAboveTime) {
+    return false;
+  }
+
+  if (cm->marking) {
+    if (now < cm->nextMarkTime) {
+      return false;
+    }
+    ++cm->count;
+    cm->nextMarkTime = CongMark_ControlLaw(cm, cm->nextMarkTime);
+    return true;
+  }
+
+  cm->marking = true;
+  uint32_t delta = cm->count - cm->lastCount;
+  if (delta > 1 && now - cm->nextMarkTime < 16 * cm->interval) {
+    cm->count = delta;
+  } else {
+    cm->count = 1;
+  }
+  cm->lastCount = cm->count;
+  cm->nextMarkTime = CongMark_ControlLaw(cm, now);
+  return true;
+}
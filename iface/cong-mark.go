@@ -0,0 +1,106 @@
+package iface
+
+/*
+#include "face.h"
+*/
+import "C"
+import (
+	"errors"
+	"sync"
+	"time"
+	"unsafe"
+
+	"ndn-dpdk/core/nnduration"
+	"ndn-dpdk/core/urcu"
+	"ndn-dpdk/dpdk"
+)
+
+// Congestion marking configuration.
+type CongMarkConfig struct {
+	Target        nnduration.Nanoseconds // TX queue sojourn time target, default 5ms
+	Interval      nnduration.Nanoseconds // sliding window to observe sojourn time, default 100ms
+	MarkInterests bool                   // whether to mark Interests, in addition to Data and Nacks
+}
+
+func (cfg CongMarkConfig) Validate() error {
+	c := cfg.applyDefaults()
+	if c.Target >= c.Interval {
+		return errors.New("Target must be less than Interval")
+	}
+	// sojourn time is measured with low 32 bits of TSC
+	if c.Interval.Duration() > time.Second {
+		return errors.New("Interval must not exceed 1s")
+	}
+	return nil
+}
+
+func (cfg CongMarkConfig) applyDefaults() CongMarkConfig {
+	if cfg.Target == 0 {
+		cfg.Target = nnduration.Nanoseconds(5 * time.Millisecond)
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = nnduration.Nanoseconds(100 * time.Millisecond)
+	}
+	return cfg
+}
+
+var (
+	congMarkLock    sync.Mutex
+	congMarkConfigs = make(map[FaceId]CongMarkConfig)
+)
+
+// Enable, reconfigure, or disable congestion marking on outgoing packets.
+// When enabled, TX queue sojourn time is measured, and NDNLPv2 CongestionMark is set on
+// outgoing Data and Nacks (and optionally Interests) when sojourn time stays above target.
+// nil cfg disables congestion marking.
+func (face *FaceBase) SetCongMark(cfg *CongMarkConfig) error {
+	faceC := face.getPtr()
+	if faceC.impl == nil {
+		return errors.New("face is closed")
+	}
+
+	congMarkLock.Lock()
+	defer congMarkLock.Unlock()
+
+	var cmC *C.CongMark
+	if cfg != nil {
+		if e := cfg.Validate(); e != nil {
+			return e
+		}
+		c := cfg.applyDefaults()
+		cmC = (*C.CongMark)(dpdk.Zmalloc("CongMark", C.sizeof_CongMark, face.GetNumaSocket()))
+		cmC.target = C.TscDuration(dpdk.ToTscDuration(c.Target.Duration()))
+		cmC.interval = C.TscDuration(dpdk.ToTscDuration(c.Interval.Duration()))
+		cmC.markInterests = C.bool(c.MarkInterests)
+		congMarkConfigs[face.id] = c
+	} else {
+		delete(congMarkConfigs, face.id)
+	}
+
+	oldCmC := urcu.NewPointer(&faceC.impl.tx.congMark).Xchg(unsafe.Pointer(cmC))
+	urcu.Synchronize()
+	if oldCmC != nil {
+		dpdk.Free(oldCmC)
+	}
+	return nil
+}
+
+// Get congestion marking configuration.
+// Returns nil if congestion marking is disabled.
+func (face *FaceBase) GetCongMark() *CongMarkConfig {
+	congMarkLock.Lock()
+	defer congMarkLock.Unlock()
+	if cfg, ok := congMarkConfigs[face.id]; ok {
+		return &cfg
+	}
+	return nil
+}
+
+func (face *FaceBase) clearCongMark() {
+	congMarkLock.Lock()
+	defer congMarkLock.Unlock()
+	delete(congMarkConfigs, face.id)
+	if cmC := face.getPtr().impl.tx.congMark; cmC != nil {
+		dpdk.Free(cmC)
+	}
+}
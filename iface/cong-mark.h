@@ -0,0 +1,78 @@
+#ifndef NDN_DPDK_IFACE_CONG_MARK_H
+#define NDN_DPDK_IFACE_CONG_MARK_H
+
+/// \file
+
+#include "common.h"
+
+/** \brief Congestion marking on outgoing packets.
+ *
+ *  This uses a CoDel-style algorithm on TX queue sojourn time: when sojourn
+ *  time stays above \c target for at least \c interval, packets are marked
+ *  with NDNLPv2 CongestionMark at a rate that increases with the square root
+ *  of the number of marks, until sojourn time falls below \c target.
+ *
+ *  Frames rejected by the NIC never contribute to sojourn time, so a TX
+ *  burst with rejected frames is treated as being above \c target.
+ */
+typedef struct CongMark
+{
+  TscDuration target;   ///< sojourn time target
+  TscDuration interval; ///< sliding window to observe sojourn time
+  bool markInterests;   ///< whether to mark Interests, in addition to Data/Nack
+  bool txRejected;      ///< whether the NIC rejected frames in last TX burst
+
+  bool marking;           ///< whether in marking state
+  TscTime firstAboveTime; ///< when marking may start, 0 if below target
+  TscTime nextMarkTime;   ///< when to mark next packet
+  uint32_t count;         ///< marks since entering marking state
+  uint32_t lastCount;     ///< count upon last entering marking state
+} CongMark;
+
+/** \brief Record TX queue enqueue time on packets.
+ *
+ *  Low 32 bits of TSC are stored in mbuf->hash.usr, because mbuf->timestamp
+ *  carries arrival time for TX latency statistics.
+ */
+static inline void
+CongMark_StampEnqueue(Packet** npkts, uint16_t count, TscTime now)
+{
+  for (uint16_t i = 0; i < count; ++i) {
+    Packet_ToMbuf(npkts[i])->hash.usr = (uint32_t)now;
+  }
+}
+
+/** \brief Compute TX queue sojourn time of a packet.
+ *  \pre Enqueue time has been recorded by \c CongMark_StampEnqueue.
+ */
+static inline TscDuration
+CongMark_GetSojourn(Packet* npkt, TscTime now)
+{
+  return (uint32_t)((uint32_t)now - Packet_ToMbuf(npkt)->hash.usr);
+}
+
+bool
+CongMark_CheckAbove_(CongMark* cm, TscTime now);
+
+/** \brief Determine whether to mark an outgoing packet.
+ *  \param npkt packet dequeued from TX queue.
+ *  \param l3type L3 type of \p npkt.
+ *  \return whether to set CongestionMark on \p npkt.
+ */
+static inline bool
+CongMark_Check(CongMark* cm, Packet* npkt, L3PktType l3type, TscTime now)
+{
+  if (l3type == L3PktType_Interest && !cm->markInterests) {
+    return false;
+  }
+
+  if (likely(CongMark_GetSojourn(npkt, now) < cm->target &&
+             !cm->txRejected)) {
+    cm->firstAboveTime = 0;
+    cm->marking = false;
+    return false;
+  }
+  return CongMark_CheckAbove_(cm, now);
+}
+
+#endif // NDN_DPDK_IFACE_CONG_MARK_H
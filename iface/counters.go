@@ -18,13 +18,15 @@ type Counters struct {
 	RxFrames uint64 // RX total frames
 	RxOctets uint64 // RX total bytes
 
-	L2DecodeErrs uint64 // L2 decode errors
-	Reass        InOrderReassemblerCounters
+	L2DecodeErrs uint64              // L2 decode errors
+	Reass        ReassemblerCounters // reassembler counters, aggregated over RX threads
+	ReassUnavail uint64              // fragments dropped due to RX thread without reassembler
 
 	L3DecodeErrs uint64 // L3 decode errors
 	RxInterests  uint64 // RX Interest packets
 	RxData       uint64 // RX Data packets
 	RxNacks      uint64 // RX Nack packets
+	Acl          AclCounters
 
 	InterestLatency running_stat.Snapshot
 	DataLatency     running_stat.Snapshot
@@ -37,15 +39,19 @@ type Counters struct {
 	FragGood    uint64 // fragmentated L3 packets
 	FragBad     uint64 // fragmentation failures
 	TxAllocErrs uint64 // allocation errors during TX
+	TxCongMarks uint64 // L3 packets marked with CongestionMark due to TX queue sojourn time
 	TxDropped   uint64 // L2 frames dropped due to full queue
+	Shaper      ShaperCounters
 	TxFrames    uint64 // sent total frames
 	TxOctets    uint64 // sent total bytes
+
+	Reliability LpReliabilityCounters
 }
 
 func (cnt Counters) String() string {
-	return fmt.Sprintf("RX %dfrm %db %dI %dD %dN reass=(%v) l2=%derr l3=%derr TX %dfrm %db %dI %dD %dN frag=(%dgood %dbad) alloc=%derr %ddropped",
-		cnt.RxFrames, cnt.RxOctets, cnt.RxInterests, cnt.RxData, cnt.RxNacks, cnt.Reass, cnt.L2DecodeErrs, cnt.L3DecodeErrs,
-		cnt.TxFrames, cnt.TxOctets, cnt.TxInterests, cnt.TxData, cnt.TxNacks, cnt.FragGood, cnt.FragBad, cnt.TxAllocErrs, cnt.TxDropped)
+	return fmt.Sprintf("RX %dfrm %db %dI %dD %dN reass=(%v) %dunavail l2=%derr l3=%derr acl=(%v) TX %dfrm %db %dI %dD %dN frag=(%dgood %dbad) alloc=%derr %dcongmark %ddropped shaper=(%v) rel=(%v)",
+		cnt.RxFrames, cnt.RxOctets, cnt.RxInterests, cnt.RxData, cnt.RxNacks, cnt.Reass, cnt.ReassUnavail, cnt.L2DecodeErrs, cnt.L3DecodeErrs, cnt.Acl,
+		cnt.TxFrames, cnt.TxOctets, cnt.TxInterests, cnt.TxData, cnt.TxNacks, cnt.FragGood, cnt.FragBad, cnt.TxAllocErrs, cnt.TxCongMarks, cnt.TxDropped, cnt.Shaper, cnt.Reliability)
 }
 
 func (face FaceBase) ReadCounters() (cnt Counters) {
@@ -55,9 +61,10 @@ func (face FaceBase) ReadCounters() (cnt Counters) {
 	}
 
 	rxC := &faceC.impl.rx
-	cnt.Reass = InOrderReassemblerFromPtr(unsafe.Pointer(&rxC.reassembler)).ReadCounters()
 	for i := 0; i < C.RXPROC_MAX_THREADS; i++ {
 		rxtC := &rxC.threads[i]
+		cnt.Reass = cnt.Reass.add(ReassemblerFromPtr(unsafe.Pointer(rxtC.reassembler)).ReadCounters())
+		cnt.ReassUnavail += uint64(rxtC.nReassUnavail)
 		cnt.RxFrames += uint64(rxtC.nFrames[ndn.L3PktType_None])
 		cnt.RxOctets += uint64(rxtC.nOctets)
 		cnt.L2DecodeErrs += uint64(rxtC.nL2DecodeErr)
@@ -66,6 +73,7 @@ func (face FaceBase) ReadCounters() (cnt Counters) {
 		cnt.RxData += uint64(rxtC.nFrames[ndn.L3PktType_Data])
 		cnt.RxNacks += uint64(rxtC.nFrames[ndn.L3PktType_Nack])
 	}
+	cnt.Acl = face.readAclCounters()
 
 	txC := &faceC.impl.tx
 
@@ -82,10 +90,14 @@ func (face FaceBase) ReadCounters() (cnt Counters) {
 	cnt.FragGood = uint64(txC.nL3Fragmented)
 	cnt.FragBad = uint64(txC.nL3OverLength + txC.nAllocFails)
 	cnt.TxAllocErrs = uint64(txC.nAllocFails)
+	cnt.TxCongMarks = uint64(txC.nCongMarked)
 	cnt.TxDropped = uint64(txC.nDroppedFrames)
 	cnt.TxFrames = uint64(txC.nFrames - txC.nDroppedFrames)
 	cnt.TxOctets = uint64(txC.nOctets - txC.nDroppedOctets)
 
+	cnt.Shaper = face.readShaperCounters()
+	cnt.Reliability = face.readLpRelCounters()
+
 	return cnt
 }
 
@@ -2,10 +2,14 @@ package createface
 
 import (
 	"errors"
+	"path/filepath"
 
+	"ndn-dpdk/core/nnduration"
 	"ndn-dpdk/dpdk"
 	"ndn-dpdk/iface"
 	"ndn-dpdk/iface/ethface"
+	"ndn-dpdk/iface/socketface"
+	"ndn-dpdk/iface/wsface"
 )
 
 var (
@@ -21,20 +25,29 @@ var (
 type Config struct {
 	Disabled bool // whether to disable this package
 
-	EnableEth        bool // whether to enable Ethernet faces
-	EthDisableRxFlow bool // whether to disable RxFlow dispatching
-	EthMtu           int  // Ethernet device MTU
-	EthRxqFrames     int  // Ethernet RX queue capacity
-	EthTxqPkts       int  // Ethernet before-TX queue capacity
-	EthTxqFrames     int  // Ethernet after-TX queue capacity
+	EnableEth              bool                    // whether to enable Ethernet faces
+	EthDisableRxFlow       bool                    // whether to disable RxFlow dispatching
+	EthMtu                 int                     // Ethernet device MTU
+	EthRxqFrames           int                     // Ethernet RX queue capacity
+	EthTxqPkts             int                     // Ethernet before-TX queue capacity
+	EthTxqFrames           int                     // Ethernet after-TX queue capacity
+	EthRxQueues            int                     // number of RX queues serving each Ethernet face
+	EthOnDemand            bool                    // whether to create on-demand unicast Ethernet faces
+	EthOnDemandMaxFaces    int                     // max number of on-demand Ethernet faces per port
+	EthOnDemandIdleTimeout nnduration.Milliseconds // idle timeout of on-demand Ethernet faces
 
 	EnableSock    bool // whether to enable socket faces
 	SockTxqPkts   int  // socket before-TX queue capacity
 	SockTxqFrames int  // socket after-TX queue capacity
 
+	SockListeners []socketface.ListenerConfig // socket listeners started by StartListeners
+	WsListeners   []wsface.ListenerConfig     // WebSocket listeners started by StartListeners
+
 	EnableMock bool // whether to enable mock faces
 
 	ChanRxgFrames int // ChanRxGroup queue capacity
+
+	CaptureDir string // directory of packet capture files, empty means system temporary directory
 }
 
 func GetDefaultConfig() (cfg Config) {
@@ -46,6 +59,10 @@ func GetDefaultConfig() (cfg Config) {
 	cfg.EthRxqFrames = 4096
 	cfg.EthTxqPkts = 256
 	cfg.EthTxqFrames = 4096
+	cfg.EthRxQueues = 1
+	cfg.EthOnDemand = false
+	cfg.EthOnDemandMaxFaces = 64
+	cfg.EthOnDemandIdleTimeout = 60000
 
 	cfg.EnableSock = true
 	cfg.SockTxqPkts = 256
@@ -69,6 +86,12 @@ func (cfg Config) Verify() error {
 		if cfg.EthTxqFrames < 64 {
 			return errors.New("cfg.EthTxqFrames must be at least 64")
 		}
+		if cfg.EthRxQueues < 1 || cfg.EthRxQueues > iface.RXPROC_MAX_THREADS {
+			return errors.New("cfg.EthRxQueues must be between 1 and RXPROC_MAX_THREADS")
+		}
+		if cfg.EthOnDemand && (cfg.EthOnDemandMaxFaces < 1 || cfg.EthOnDemandMaxFaces > 256) {
+			return errors.New("cfg.EthOnDemandMaxFaces must be between 1 and 256")
+		}
 	}
 	if cfg.EnableSock {
 		if cfg.SockTxqPkts < 64 {
@@ -77,12 +100,25 @@ func (cfg Config) Verify() error {
 		if cfg.SockTxqFrames < 64 {
 			return errors.New("cfg.SockTxqFrames must be at least 64")
 		}
+		for _, lc := range cfg.SockListeners {
+			if e := lc.Validate(); e != nil {
+				return e
+			}
+		}
+		for _, lc := range cfg.WsListeners {
+			if e := lc.Validate(); e != nil {
+				return e
+			}
+		}
 	}
 	if cfg.EnableSock || cfg.EnableMock {
 		if cfg.ChanRxgFrames < 64 {
 			return errors.New("cfg.ChanRxgFrames must be at least 64")
 		}
 	}
+	if cfg.CaptureDir != "" && !filepath.IsAbs(cfg.CaptureDir) {
+		return errors.New("cfg.CaptureDir must be an absolute path")
+	}
 	return nil
 }
 
@@ -93,6 +129,9 @@ func (cfg Config) Apply() error {
 	theConfig = cfg
 	ethface.DisableRxFlow = cfg.EthDisableRxFlow
 	iface.TheChanRxGroup.SetQueueCapacity(cfg.ChanRxgFrames)
+	if cfg.CaptureDir != "" {
+		iface.CaptureDir = cfg.CaptureDir
+	}
 	return nil
 }
 
@@ -152,8 +191,14 @@ func AddTxLoop(txl *iface.TxLoop) {
 	theTxls = append(theTxls, txl)
 }
 
-// Close all faces and stop RxLoops and TxLoops.
+// Close all listeners and faces, and stop RxLoops and TxLoops.
 func CloseAll() (threads []dpdk.IThread) {
+	for _, l := range ListListeners() {
+		CloseListener(l)
+	}
+	for _, l := range ListWsListeners() {
+		CloseWsListener(l)
+	}
 	iface.CloseAll()
 	for _, rxl := range theRxls {
 		rxl.Stop()
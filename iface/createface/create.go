@@ -5,6 +5,7 @@ import (
 
 	"ndn-dpdk/dpdk"
 	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/bundleface"
 	"ndn-dpdk/iface/ethface"
 	"ndn-dpdk/iface/mockface"
 	"ndn-dpdk/iface/socketface"
@@ -18,38 +19,130 @@ func Create(loc iface.Locator) (face iface.IFace, e error) {
 	if e = loc.Validate(); e != nil {
 		return nil, e
 	}
+	if e = iface.ValidateAcl(loc.GetAcl()); e != nil {
+		return nil, e
+	}
+	if rel := loc.GetReliability(); rel != nil {
+		if e = rel.Validate(); e != nil {
+			return nil, e
+		}
+	}
+	if cm := loc.GetCongMark(); cm != nil {
+		if e = cm.Validate(); e != nil {
+			return nil, e
+		}
+	}
+	if rl := loc.GetRateLimit(); rl != nil {
+		if e = rl.Validate(); e != nil {
+			return nil, e
+		}
+	}
 	createDestroyLock.Lock()
 	defer createDestroyLock.Unlock()
 
 	switch loc.GetScheme() {
 	case "ether":
-		return createEth(loc.(ethface.Locator))
+		face, e = createEth(loc.(ethface.Locator))
+	case "udpe":
+		face, e = createUdpe(loc.(ethface.UdpLocator))
+	case "memif":
+		face, e = createMemif(loc.(ethface.MemifLocator))
 	case "mock":
-		return createMock()
+		face, e = createMock()
+	case "bundle":
+		face, e = createBundle(loc.(bundleface.Locator))
+	case "ws":
+		e = errors.New("WebSocket faces can only be created by a WebSocket listener")
+	default:
+		face, e = createSock(loc.(socketface.Locator))
+	}
+	if e != nil {
+		return nil, e
+	}
+
+	if acl := loc.GetAcl(); len(acl) > 0 {
+		if e = face.SetAcl(acl); e != nil {
+			face.Close()
+			return nil, e
+		}
+	}
+	if rel := loc.GetReliability(); rel != nil {
+		if e = face.SetLpReliability(rel); e != nil {
+			face.Close()
+			return nil, e
+		}
+	}
+	if cm := loc.GetCongMark(); cm != nil {
+		if e = face.SetCongMark(cm); e != nil {
+			face.Close()
+			return nil, e
+		}
 	}
-	return createSock(loc.(socketface.Locator))
+	if rl := loc.GetRateLimit(); rl != nil {
+		if e = face.SetRateLimit(rl); e != nil {
+			face.Close()
+			return nil, e
+		}
+	}
+	return face, nil
 }
 
 func createEth(loc ethface.Locator) (face iface.IFace, e error) {
+	cfg, e := makeEthPortConfig(loc.Port)
+	if e != nil {
+		return nil, e
+	}
+	return ethface.Create(loc, cfg)
+}
+
+func createUdpe(loc ethface.UdpLocator) (face iface.IFace, e error) {
+	cfg, e := makeEthPortConfig(loc.Port)
+	if e != nil {
+		return nil, e
+	}
+	return ethface.CreateUdp(loc, cfg)
+}
+
+// Construct PortConfig for an Ethernet port.
+func makeEthPortConfig(portName string) (cfg ethface.PortConfig, e error) {
 	if !theConfig.EnableEth {
-		return nil, errors.New("Ethernet face feature is disabled")
+		return cfg, errors.New("Ethernet face feature is disabled")
 	}
 
-	dev := dpdk.FindEthDev(loc.Port)
+	dev := dpdk.FindEthDev(portName)
 	if dev == dpdk.ETHDEV_INVALID {
-		return nil, errors.New("EthDev not found")
+		return cfg, errors.New("EthDev not found")
 	}
 
 	numaSocket := dev.GetNumaSocket()
-	var cfg ethface.PortConfig
 	if cfg.RxMp, cfg.Mempools, e = getMempools(numaSocket); e != nil {
-		return nil, e
+		return cfg, e
 	}
 	cfg.RxqFrames = theConfig.EthRxqFrames
 	cfg.TxqPkts = theConfig.EthTxqPkts
 	cfg.TxqFrames = theConfig.EthTxqFrames
 	cfg.Mtu = theConfig.EthMtu
-	return ethface.Create(loc, cfg)
+	cfg.RxQueues = theConfig.EthRxQueues
+	cfg.OnDemand = theConfig.EthOnDemand
+	cfg.OnDemandMaxFaces = theConfig.EthOnDemandMaxFaces
+	cfg.OnDemandIdleTimeout = theConfig.EthOnDemandIdleTimeout
+	cfg.Locker = &createDestroyLock
+	return cfg, nil
+}
+
+func createMemif(loc ethface.MemifLocator) (face iface.IFace, e error) {
+	if !theConfig.EnableEth {
+		return nil, errors.New("Ethernet face feature is disabled")
+	}
+
+	var cfg ethface.PortConfig
+	if cfg.RxMp, cfg.Mempools, e = getMempools(dpdk.NUMA_SOCKET_ANY); e != nil {
+		return nil, e
+	}
+	cfg.RxqFrames = theConfig.EthRxqFrames
+	cfg.TxqPkts = theConfig.EthTxqPkts
+	cfg.TxqFrames = theConfig.EthTxqFrames
+	return ethface.CreateMemif(loc, ethface.MemifRole_Master, cfg)
 }
 
 func createSock(loc socketface.Locator) (face iface.IFace, e error) {
@@ -66,6 +159,14 @@ func createSock(loc socketface.Locator) (face iface.IFace, e error) {
 	return socketface.Create(loc, cfg)
 }
 
+func createBundle(loc bundleface.Locator) (face iface.IFace, e error) {
+	_, mempools, e := getMempools(dpdk.NUMA_SOCKET_ANY)
+	if e != nil {
+		return nil, e
+	}
+	return bundleface.New(loc, mempools)
+}
+
 var hasMockFaces = false
 
 func createMock() (face iface.IFace, e error) {
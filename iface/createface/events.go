@@ -13,6 +13,11 @@ func chooseRxl(rxg iface.IRxGroup) *iface.RxLoop {
 		return CustomGetRxl(rxg)
 	}
 
+	rxgFaces := make(map[iface.FaceId]bool)
+	for _, faceId := range rxg.ListFaces() {
+		rxgFaces[faceId] = true
+	}
+
 	var bestRxl *iface.RxLoop
 	bestScore := math.MaxInt32
 	for _, rxl := range theRxls {
@@ -20,6 +25,12 @@ func chooseRxl(rxg iface.IRxGroup) *iface.RxLoop {
 		if !rxl.GetNumaSocket().Match(rxg.GetNumaSocket()) {
 			score += 1000000
 		}
+		for _, faceId := range rxl.ListFaces() {
+			if rxgFaces[faceId] { // prefer spreading RX queues of a face over RxLoops
+				score += 100000
+				break
+			}
+		}
 
 		if score <= bestScore {
 			bestRxl = rxl
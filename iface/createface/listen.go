@@ -0,0 +1,124 @@
+package createface
+
+import (
+	"errors"
+	"sync"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface/socketface"
+	"ndn-dpdk/iface/wsface"
+)
+
+var (
+	theListeners     []*socketface.Listener
+	theWsListeners   []*wsface.Listener
+	theListenersLock sync.Mutex
+)
+
+// Start a socket listener that creates faces for accepted peers.
+func Listen(lc socketface.ListenerConfig) (l *socketface.Listener, e error) {
+	if theConfig.Disabled {
+		return nil, errors.New("createface package is disabled")
+	}
+	if !theConfig.EnableSock {
+		return nil, errors.New("socket face feature is disabled")
+	}
+
+	var cfg socketface.Config
+	if cfg.RxMp, cfg.Mempools, e = getMempools(dpdk.NUMA_SOCKET_ANY); e != nil {
+		return nil, e
+	}
+	cfg.TxqPkts = theConfig.SockTxqPkts
+	cfg.TxqFrames = theConfig.SockTxqFrames
+	cfg.Locker = &createDestroyLock
+	if l, e = socketface.Listen(lc, cfg); e != nil {
+		return nil, e
+	}
+
+	theListenersLock.Lock()
+	defer theListenersLock.Unlock()
+	theListeners = append(theListeners, l)
+	return l, nil
+}
+
+// Start a WebSocket listener that creates faces for accepted clients.
+// WebSocket faces are enabled together with socket faces, and share their queue capacities.
+func ListenWs(lc wsface.ListenerConfig) (l *wsface.Listener, e error) {
+	if theConfig.Disabled {
+		return nil, errors.New("createface package is disabled")
+	}
+	if !theConfig.EnableSock {
+		return nil, errors.New("socket face feature is disabled")
+	}
+
+	var cfg wsface.Config
+	if cfg.RxMp, cfg.Mempools, e = getMempools(dpdk.NUMA_SOCKET_ANY); e != nil {
+		return nil, e
+	}
+	cfg.TxqPkts = theConfig.SockTxqPkts
+	cfg.TxqFrames = theConfig.SockTxqFrames
+	cfg.Locker = &createDestroyLock
+	if l, e = wsface.Listen(lc, cfg); e != nil {
+		return nil, e
+	}
+
+	theListenersLock.Lock()
+	defer theListenersLock.Unlock()
+	theWsListeners = append(theWsListeners, l)
+	return l, nil
+}
+
+// Start listeners specified in Config.SockListeners and Config.WsListeners.
+func StartListeners() error {
+	for _, lc := range theConfig.SockListeners {
+		if _, e := Listen(lc); e != nil {
+			return e
+		}
+	}
+	for _, lc := range theConfig.WsListeners {
+		if _, e := ListenWs(lc); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// List active socket listeners.
+func ListListeners() []*socketface.Listener {
+	theListenersLock.Lock()
+	defer theListenersLock.Unlock()
+	return append([]*socketface.Listener(nil), theListeners...)
+}
+
+// Stop a socket listener and close its faces.
+func CloseListener(l *socketface.Listener) error {
+	theListenersLock.Lock()
+	for i, ll := range theListeners {
+		if ll == l {
+			theListeners = append(theListeners[:i], theListeners[i+1:]...)
+			break
+		}
+	}
+	theListenersLock.Unlock()
+	return l.Close()
+}
+
+// List active WebSocket listeners.
+func ListWsListeners() []*wsface.Listener {
+	theListenersLock.Lock()
+	defer theListenersLock.Unlock()
+	return append([]*wsface.Listener(nil), theWsListeners...)
+}
+
+// Stop a WebSocket listener and close its faces.
+func CloseWsListener(l *wsface.Listener) error {
+	theListenersLock.Lock()
+	for i, ll := range theWsListeners {
+		if ll == l {
+			theWsListeners = append(theWsListeners[:i], theWsListeners[i+1:]...)
+			break
+		}
+	}
+	theListenersLock.Unlock()
+	return l.Close()
+}
@@ -1,3 +1,7 @@
+import { Milliseconds } from "../../core/nnduration/mod";
+import * as socketface from "../socketface/mod";
+import * as wsface from "../wsface/mod";
+
 export interface Config {
   EnableEth?: boolean;
   EthDisableRxFlow?: boolean;
@@ -5,12 +9,20 @@ export interface Config {
   EthRxqFrames?: number;
   EthTxqPkts?: number;
   EthTxqFrames?: number;
+  EthRxQueues?: number;
+  EthOnDemand?: boolean;
+  EthOnDemandMaxFaces?: number;
+  EthOnDemandIdleTimeout?: Milliseconds;
 
   EnableSock?: boolean;
   SockTxqPkts?: number;
   SockTxqFrames?: number;
+  SockListeners?: socketface.ListenerConfig[];
+  WsListeners?: wsface.ListenerConfig[];
 
   EnableMock?: boolean;
 
   ChanRxgFrames?: number;
+
+  CaptureDir?: string;
 }
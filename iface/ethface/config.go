@@ -6,7 +6,9 @@ package ethface
 import "C"
 import (
 	"errors"
+	"sync"
 
+	"ndn-dpdk/core/nnduration"
 	"ndn-dpdk/dpdk"
 	"ndn-dpdk/iface"
 	"ndn-dpdk/ndn"
@@ -14,7 +16,7 @@ import (
 
 // Minimum dataroom of PortConfig.HeaderMp.
 func SizeofTxHeader() int {
-	return int(C.sizeof_EthFaceEtherHdr) + ndn.PrependLpHeader_GetHeadroom()
+	return int(C.ETHFACE_MAX_HDRLEN) + ndn.PrependLpHeader_GetHeadroom()
 }
 
 // Port creation arguments.
@@ -26,6 +28,14 @@ type PortConfig struct {
 	TxqFrames int              // after-TX queue capacity
 	Mtu       int              // set MTU, 0 to keep default
 	Local     dpdk.EtherAddr   // local address, zero for hardware default
+	RxQueues  int              // number of RX queues serving each face, default 1
+
+	OnDemand            bool                    // whether to create on-demand faces for unknown unicast senders
+	OnDemandMaxFaces    int                     // max number of on-demand faces, default 64
+	OnDemandIdleTimeout nnduration.Milliseconds // idle timeout of on-demand faces, default 60000ms
+
+	// If not nil, this lock is held while Port creates an on-demand face.
+	Locker sync.Locker
 }
 
 func (cfg PortConfig) check() error {
@@ -35,5 +45,26 @@ func (cfg PortConfig) check() error {
 	if cfg.HeaderMp.GetDataroom() < SizeofTxHeader() {
 		return errors.New("HeaderMp dataroom is too small")
 	}
+	if cfg.RxQueues < 1 || cfg.RxQueues > iface.RXPROC_MAX_THREADS {
+		return errors.New("RxQueues is out of range")
+	}
+	if cfg.OnDemandMaxFaces < 0 || cfg.OnDemandMaxFaces > 256 {
+		return errors.New("OnDemandMaxFaces is out of range")
+	}
+	if cfg.OnDemandIdleTimeout.Duration() < 0 {
+		return errors.New("OnDemandIdleTimeout must not be negative")
+	}
 	return nil
 }
+
+func (cfg *PortConfig) applyDefaults() {
+	if cfg.RxQueues == 0 {
+		cfg.RxQueues = 1
+	}
+	if cfg.OnDemandMaxFaces == 0 {
+		cfg.OnDemandMaxFaces = 64
+	}
+	if cfg.OnDemandIdleTimeout == 0 {
+		cfg.OnDemandIdleTimeout = 60000
+	}
+}
@@ -6,11 +6,12 @@ EthFaceEtherHdr_Init(EthFaceEtherHdr* hdr,
                      const struct rte_ether_addr* local,
                      const struct rte_ether_addr* remote,
                      uint16_t vlan0,
-                     uint16_t vlan1)
+                     uint16_t vlan1,
+                     uint16_t etherType)
 {
-  hdr->eth.ether_type = rte_cpu_to_be_16(NDN_ETHERTYPE);
-  hdr->vlan0.eth_proto = rte_cpu_to_be_16(NDN_ETHERTYPE);
-  hdr->vlan1.eth_proto = rte_cpu_to_be_16(NDN_ETHERTYPE);
+  hdr->eth.ether_type = rte_cpu_to_be_16(etherType);
+  hdr->vlan0.eth_proto = rte_cpu_to_be_16(etherType);
+  hdr->vlan1.eth_proto = rte_cpu_to_be_16(etherType);
 
   rte_ether_addr_copy(remote, &hdr->eth.d_addr);
   rte_ether_addr_copy(local, &hdr->eth.s_addr);
@@ -30,12 +31,86 @@ EthFaceEtherHdr_Init(EthFaceEtherHdr* hdr,
   return sizeof(EthFaceEtherHdr);
 }
 
+uint8_t
+EthFaceUdpHdr_Init(uint8_t* room,
+                   int ipVersion,
+                   const uint8_t* localIp,
+                   const uint8_t* remoteIp,
+                   uint16_t localPort,
+                   uint16_t remotePort)
+{
+  uint8_t ipLen = 0;
+  if (ipVersion == 4) {
+    struct rte_ipv4_hdr* ip = (struct rte_ipv4_hdr*)room;
+    memset(ip, 0, sizeof(*ip));
+    ip->version_ihl = 0x45;
+    ip->fragment_offset = rte_cpu_to_be_16(RTE_IPV4_HDR_DF_FLAG);
+    ip->time_to_live = 64;
+    ip->next_proto_id = IPPROTO_UDP;
+    rte_memcpy(&ip->src_addr, localIp, sizeof(ip->src_addr));
+    rte_memcpy(&ip->dst_addr, remoteIp, sizeof(ip->dst_addr));
+    ipLen = sizeof(*ip);
+  } else {
+    struct rte_ipv6_hdr* ip = (struct rte_ipv6_hdr*)room;
+    memset(ip, 0, sizeof(*ip));
+    ip->vtc_flow = rte_cpu_to_be_32(6 << 28);
+    ip->proto = IPPROTO_UDP;
+    ip->hop_limits = 64;
+    rte_memcpy(ip->src_addr, localIp, sizeof(ip->src_addr));
+    rte_memcpy(ip->dst_addr, remoteIp, sizeof(ip->dst_addr));
+    ipLen = sizeof(*ip);
+  }
+
+  struct rte_udp_hdr* udp = (struct rte_udp_hdr*)RTE_PTR_ADD(room, ipLen);
+  udp->src_port = rte_cpu_to_be_16(localPort);
+  udp->dst_port = rte_cpu_to_be_16(remotePort);
+  udp->dgram_len = 0;
+  udp->dgram_cksum = 0;
+  return ipLen + sizeof(*udp);
+}
+
 INIT_ZF_LOG(EthFace);
 
 // EthFace currently only supports one TX queue,
 // so queue number is hardcoded with this macro.
 #define TX_QUEUE_0 0
 
+/** \brief Fill length and checksum fields in IP and UDP headers.
+ *  \param pkt a packet with all headers prepended.
+ */
+static void
+EthFace_FinishUdpHdr(EthFacePriv* priv, struct rte_mbuf* pkt)
+{
+  uint8_t* ipHdr = rte_pktmbuf_mtod_offset(pkt, uint8_t*, priv->ethHdrLen);
+  uint16_t ipTotalLen = pkt->pkt_len - priv->ethHdrLen;
+
+  if (priv->ipVersion == 4) {
+    struct rte_ipv4_hdr* ip = (struct rte_ipv4_hdr*)ipHdr;
+    struct rte_udp_hdr* udp = (struct rte_udp_hdr*)(ip + 1);
+    ip->total_length = rte_cpu_to_be_16(ipTotalLen);
+    ip->hdr_checksum = 0;
+    ip->hdr_checksum = rte_ipv4_cksum(ip);
+    udp->dgram_len = rte_cpu_to_be_16(ipTotalLen - sizeof(*ip));
+    return;
+  }
+
+  // UDP checksum is mandatory over IPv6
+  struct rte_ipv6_hdr* ip = (struct rte_ipv6_hdr*)ipHdr;
+  struct rte_udp_hdr* udp = (struct rte_udp_hdr*)(ip + 1);
+  uint16_t udpLen = ipTotalLen - sizeof(*ip);
+  ip->payload_len = rte_cpu_to_be_16(udpLen);
+  udp->dgram_len = ip->payload_len;
+  udp->dgram_cksum = 0;
+
+  uint16_t sum = 0;
+  rte_raw_cksum_mbuf(pkt, priv->ethHdrLen + sizeof(*ip), udpLen, &sum);
+  uint32_t cksum = (uint32_t)sum + rte_ipv6_phdr_cksum(ip, 0);
+  cksum = (cksum & 0xFFFF) + (cksum >> 16);
+  cksum = (cksum & 0xFFFF) + (cksum >> 16);
+  cksum = (~cksum) & 0xFFFF;
+  udp->dgram_cksum = cksum == 0 ? 0xFFFF : cksum;
+}
+
 uint16_t
 EthFace_TxBurst(Face* face, struct rte_mbuf** pkts, uint16_t nPkts)
 {
@@ -44,15 +119,22 @@ EthFace_TxBurst(Face* face, struct rte_mbuf** pkts, uint16_t nPkts)
   for (uint16_t i = 0; i < nPkts; ++i) {
     char* room = rte_pktmbuf_prepend(pkts[i], priv->txHdrLen);
     assert(room != NULL); // enough headroom is required
-    rte_memcpy(room, &priv->txHdr, priv->txHdrLen);
+    rte_memcpy(room, priv->txHdr, priv->txHdrLen);
+    if (priv->ipVersion != 0) {
+      EthFace_FinishUdpHdr(priv, pkts[i]);
+    }
   }
   return rte_eth_tx_burst(priv->port, TX_QUEUE_0, pkts, nPkts);
 }
 
 struct rte_flow*
-EthFace_SetupFlow(EthFacePriv* priv, struct rte_flow_error* error)
+EthFace_SetupFlow(EthFacePriv* priv,
+                  const uint16_t* queues,
+                  int nQueues,
+                  struct rte_flow_error* error)
 {
-  const EthFaceEtherHdr* hdr = &priv->txHdr;
+  const EthFaceEtherHdr* hdr = (const EthFaceEtherHdr*)priv->txHdr;
+  const uint8_t* ipHdr = RTE_PTR_ADD(priv->txHdr, priv->ethHdrLen);
   struct rte_flow_attr attr = {
     .group = 0,
     .priority = 1,
@@ -69,6 +151,10 @@ EthFace_SetupFlow(EthFacePriv* priv, struct rte_flow_error* error)
     rte_ether_addr_copy(&hdr->eth.s_addr, &ethSpec.dst);
     rte_ether_addr_copy(&hdr->eth.d_addr, &ethSpec.src);
   }
+  if (priv->ipVersion != 0) {
+    // IP packets may be routed through different next hops
+    memset(&ethMask.src, 0x00, sizeof(ethMask.src));
+  }
   struct rte_flow_item_vlan vlanMask = { .tci = rte_cpu_to_be_16(0x0FFF),
                                          .inner_type = 0xFFFF };
   struct rte_flow_item_vlan vlanSpec0 = { .tci = hdr->vlan0.vlan_tci,
@@ -76,32 +162,91 @@ EthFace_SetupFlow(EthFacePriv* priv, struct rte_flow_error* error)
   struct rte_flow_item_vlan vlanSpec1 = { .tci = hdr->vlan1.vlan_tci,
                                           .inner_type = hdr->vlan1.eth_proto };
 
-  struct rte_flow_item pattern[4] = {
+  // remote and local are swapped: TX header describes outgoing packets
+  struct rte_flow_item_ipv4 ipv4Mask = {
+    .hdr = { .version_ihl = 0xFF,
+             .fragment_offset = rte_cpu_to_be_16(0x3FFF),
+             .next_proto_id = 0xFF,
+             .src_addr = 0xFFFFFFFF,
+             .dst_addr = 0xFFFFFFFF },
+  };
+  struct rte_flow_item_ipv4 ipv4Spec = {
+    .hdr = { .version_ihl = 0x45, .next_proto_id = IPPROTO_UDP },
+  };
+  struct rte_flow_item_ipv6 ipv6Mask = { .hdr = { .proto = 0xFF } };
+  memset(ipv6Mask.hdr.src_addr, 0xFF, sizeof(ipv6Mask.hdr.src_addr));
+  memset(ipv6Mask.hdr.dst_addr, 0xFF, sizeof(ipv6Mask.hdr.dst_addr));
+  struct rte_flow_item_ipv6 ipv6Spec = { .hdr = { .proto = IPPROTO_UDP } };
+  struct rte_flow_item_udp udpMask = {
+    .hdr = { .src_port = 0xFFFF, .dst_port = 0xFFFF },
+  };
+  struct rte_flow_item_udp udpSpec = { 0 };
+
+  struct rte_flow_item pattern[6] = {
     {
       .type = RTE_FLOW_ITEM_TYPE_ETH,
       .mask = &ethMask,
       .spec = &ethSpec,
     },
-    {
-      .type = priv->txHdrLen > offsetof(EthFaceEtherHdr, vlan0)
-                ? RTE_FLOW_ITEM_TYPE_VLAN
-                : RTE_FLOW_ITEM_TYPE_END,
+  };
+  int nItems = 1;
+  if (priv->ethHdrLen > offsetof(EthFaceEtherHdr, vlan0)) {
+    pattern[nItems++] = (struct rte_flow_item){
+      .type = RTE_FLOW_ITEM_TYPE_VLAN,
       .mask = &vlanMask,
       .spec = &vlanSpec0,
-    },
-    {
-      .type = priv->txHdrLen > offsetof(EthFaceEtherHdr, vlan1)
-                ? RTE_FLOW_ITEM_TYPE_VLAN
-                : RTE_FLOW_ITEM_TYPE_END,
+    };
+  }
+  if (priv->ethHdrLen > offsetof(EthFaceEtherHdr, vlan1)) {
+    pattern[nItems++] = (struct rte_flow_item){
+      .type = RTE_FLOW_ITEM_TYPE_VLAN,
       .mask = &vlanMask,
       .spec = &vlanSpec1,
-    },
-    {
-      .type = RTE_FLOW_ITEM_TYPE_END,
-    },
-  };
+    };
+  }
+
+  const struct rte_udp_hdr* udp = NULL;
+  if (priv->ipVersion == 4) {
+    const struct rte_ipv4_hdr* ip = (const struct rte_ipv4_hdr*)ipHdr;
+    ipv4Spec.hdr.src_addr = ip->dst_addr;
+    ipv4Spec.hdr.dst_addr = ip->src_addr;
+    pattern[nItems++] = (struct rte_flow_item){
+      .type = RTE_FLOW_ITEM_TYPE_IPV4,
+      .mask = &ipv4Mask,
+      .spec = &ipv4Spec,
+    };
+    udp = (const struct rte_udp_hdr*)(ip + 1);
+  } else if (priv->ipVersion == 6) {
+    const struct rte_ipv6_hdr* ip = (const struct rte_ipv6_hdr*)ipHdr;
+    rte_memcpy(ipv6Spec.hdr.src_addr, ip->dst_addr, sizeof(ip->dst_addr));
+    rte_memcpy(ipv6Spec.hdr.dst_addr, ip->src_addr, sizeof(ip->src_addr));
+    pattern[nItems++] = (struct rte_flow_item){
+      .type = RTE_FLOW_ITEM_TYPE_IPV6,
+      .mask = &ipv6Mask,
+      .spec = &ipv6Spec,
+    };
+    udp = (const struct rte_udp_hdr*)(ip + 1);
+  }
+  if (udp != NULL) {
+    udpSpec.hdr.src_port = udp->dst_port;
+    udpSpec.hdr.dst_port = udp->src_port;
+    pattern[nItems++] = (struct rte_flow_item){
+      .type = RTE_FLOW_ITEM_TYPE_UDP,
+      .mask = &udpMask,
+      .spec = &udpSpec,
+    };
+  }
+  pattern[nItems].type = RTE_FLOW_ITEM_TYPE_END;
 
-  struct rte_flow_action_queue queue = { .index = priv->rxQueue };
+  struct rte_flow_action_queue queue = { .index = queues[0] };
+  struct rte_flow_action_rss rss = {
+    .func = RTE_ETH_HASH_FUNCTION_DEFAULT,
+    .level = 0,
+    .types = 0, // PMD default
+    .key_len = 0,
+    .queue_num = nQueues,
+    .queue = queues,
+  };
 
   struct rte_flow_action actions[2] = {
     { .type = RTE_FLOW_ACTION_TYPE_QUEUE, .conf = &queue },
@@ -109,22 +254,32 @@ EthFace_SetupFlow(EthFacePriv* priv, struct rte_flow_error* error)
       .type = RTE_FLOW_ACTION_TYPE_END,
     },
   };
+  if (nQueues > 1) {
+    actions[0].type = RTE_FLOW_ACTION_TYPE_RSS;
+    actions[0].conf = &rss;
+  }
 
   return rte_flow_create(priv->port, &attr, pattern, actions, error);
 }
 
 uint16_t
-EthFace_FlowRxBurst(RxGroup* flowRxg, struct rte_mbuf** pkts, uint16_t nPkts)
+EthRxFlow_RxBurst(RxGroup* rxg, struct rte_mbuf** pkts, uint16_t nPkts)
 {
-  EthFacePriv* priv = container_of(flowRxg, EthFacePriv, flowRxg);
-  uint16_t nRx = rte_eth_rx_burst(priv->port, priv->rxQueue, pkts, nPkts);
+  EthRxFlow* rxf = (EthRxFlow*)rxg;
+  uint16_t nRx = rte_eth_rx_burst(rxf->port, rxf->queue, pkts, nPkts);
   uint64_t now = rte_get_tsc_cycles();
+  uint16_t nAccepted = 0;
   for (uint16_t i = 0; i < nRx; ++i) {
     struct rte_mbuf* frame = pkts[i];
-    frame->port = priv->faceId;
+    frame->port = rxf->faceId;
     // TODO offload timestamping to hardware where available
     frame->timestamp = now;
-    rte_pktmbuf_adj(frame, priv->txHdrLen);
+    rte_pktmbuf_adj(frame, rxf->hdrLen);
+    if (rxf->ipLen > 0 && unlikely(!EthFace_StripUdp(frame, rxf->ipLen))) {
+      rte_pktmbuf_free(frame);
+      continue;
+    }
+    pkts[nAccepted++] = frame;
   }
-  return nRx;
+  return nAccepted;
 }
@@ -7,6 +7,8 @@ import "C"
 import (
 	"errors"
 	"fmt"
+	"net"
+	"unsafe"
 
 	"ndn-dpdk/iface"
 	"ndn-dpdk/ndn"
@@ -16,16 +18,27 @@ type EthFace struct {
 	iface.FaceBase
 	port *Port
 	loc  Locator
+	udp  *UdpLocator // non-nil if face uses UDP encapsulation
 	rxf  *RxFlow
+
+	onDemand bool // whether face was created by on-demand port
 }
 
 func New(port *Port, loc Locator) (face *EthFace, e error) {
+	return newFace(port, loc, nil, false)
+}
+
+func newFace(port *Port, loc Locator, udp *UdpLocator, onDemand bool) (face *EthFace, e error) {
 	if !loc.Local.IsZero() && !loc.Local.Equal(port.cfg.Local) {
 		return nil, errors.New("port has a different local address")
 	}
 	loc.Local = port.cfg.Local
 
 	switch {
+	case udp != nil:
+		if face = port.findUdpFace(*udp); face != nil {
+			return nil, fmt.Errorf("port has another face %d with same UDP endpoints", face.GetFaceId())
+		}
 	case loc.Remote.IsZero():
 		loc.Remote = ndn.NDN_ETHER_MCAST_ADDR
 		fallthrough
@@ -47,6 +60,7 @@ func New(port *Port, loc Locator) (face *EthFace, e error) {
 	}
 	face.port = port
 	face.loc = loc
+	face.onDemand = onDemand
 
 	priv := face.getPriv()
 	priv.port = C.uint16_t(face.port.dev)
@@ -54,21 +68,52 @@ func New(port *Port, loc Locator) (face *EthFace, e error) {
 
 	vlan := make([]uint16, 2)
 	copy(vlan, loc.Vlan)
-	priv.txHdrLen = C.EthFaceEtherHdr_Init(&priv.txHdr,
+	etherType := C.uint16_t(ndn.NDN_ETHERTYPE)
+	var ipVersion int
+	var localIP, remoteIP net.IP
+	if udp != nil {
+		face.udp = udp
+		ipVersion, localIP, remoteIP = udp.getIPs()
+		etherType = C.RTE_ETHER_TYPE_IPV6
+		if ipVersion == 4 {
+			etherType = C.RTE_ETHER_TYPE_IPV4
+		}
+	}
+	priv.ethHdrLen = C.EthFaceEtherHdr_Init((*C.EthFaceEtherHdr)(unsafe.Pointer(&priv.txHdr[0])),
 		(*C.struct_rte_ether_addr)(port.cfg.Local.GetPtr()),
 		(*C.struct_rte_ether_addr)(face.loc.Remote.GetPtr()),
-		C.uint16_t(vlan[0]), C.uint16_t(vlan[1]))
+		C.uint16_t(vlan[0]), C.uint16_t(vlan[1]), etherType)
+	priv.txHdrLen = priv.ethHdrLen
+
+	mtu, headroom := port.cfg.Mtu, int(C.sizeof_struct_rte_ether_hdr)
+	if udp != nil {
+		priv.ipVersion = C.uint8_t(ipVersion)
+		udpHdrLen := C.EthFaceUdpHdr_Init(&priv.txHdr[priv.ethHdrLen], C.int(ipVersion),
+			(*C.uint8_t)(&localIP[0]), (*C.uint8_t)(&remoteIP[0]),
+			C.uint16_t(udp.LocalUDP), C.uint16_t(udp.RemoteUDP))
+		priv.txHdrLen += udpHdrLen
+		if mtu > 0 {
+			mtu -= int(udpHdrLen)
+		}
+		headroom = int(priv.txHdrLen)
+	}
 
 	faceC := face.getPtr()
 	faceC.txBurstOp = (C.FaceImpl_TxBurst)(C.EthFace_TxBurst)
 
-	face.FinishInitFaceBase(port.cfg.TxqPkts, port.cfg.Mtu, int(C.sizeof_struct_rte_ether_hdr), port.cfg.Mempools)
+	face.FinishInitFaceBase(port.cfg.TxqPkts, mtu, headroom, port.cfg.Mempools)
+	if e = face.EnableRxThreads(port.cfg.RxQueues); e != nil {
+		return nil, e
+	}
 
 	if e = face.port.startFace(face, false); e != nil {
 		return nil, e
 	}
 
 	iface.Put(face)
+	if port.IsLinkDown() {
+		face.SetDown(true)
+	}
 	return face, nil
 }
 
@@ -84,7 +129,16 @@ func (face *EthFace) GetPort() *Port {
 	return face.port
 }
 
+// Get face locator.
+// This returns MemifLocator if the face is on a memif virtual device,
+// UdpLocator if the face uses UDP encapsulation, otherwise Locator.
 func (face *EthFace) GetLocator() iface.Locator {
+	if face.port.memif != nil {
+		return face.port.memif.loc
+	}
+	if face.udp != nil {
+		return *face.udp
+	}
 	return face.loc
 }
 
@@ -92,28 +146,43 @@ func (face *EthFace) Close() error {
 	face.BeforeClose()
 	face.port.stopFace(face)
 	face.CloseFaceBase()
+	if face.port.memif != nil {
+		face.port.Close()
+	}
 	return nil
 }
 
-func (face *EthFace) ListRxGroups() []iface.IRxGroup {
+func (face *EthFace) ListRxGroups() (list []iface.IRxGroup) {
 	switch impl := face.port.impl.(type) {
 	case *rxFlowImpl:
-		_, rxf := impl.findQueue(func(rxf *RxFlow) bool { return rxf != nil && rxf.face == face })
-		return []iface.IRxGroup{rxf}
+		for _, index := range impl.findQueues(func(rxf *RxFlow) bool { return rxf != nil && rxf.face == face }) {
+			list = append(list, impl.queueFlow[index])
+		}
+		return list
 	case *rxTableImpl:
-		return []iface.IRxGroup{impl.rxt}
+		for _, rxt := range impl.rxts {
+			list = append(list, rxt)
+		}
+		return list
 	}
 	panic(face.port.impl)
 }
 
 type ExCounters struct {
-	RxQueue int
+	RxQueues []int // RX queues serving this face, indexed by RX thread
 }
 
 // EthFace extended counters are available at Port granularity.
 // This function provides information to locate relevant fields in EthStats.
 func (face *EthFace) ReadExCounters() interface{} {
 	var cnt ExCounters
-	cnt.RxQueue = int(face.getPriv().rxQueue)
+	for _, rxg := range face.ListRxGroups() {
+		switch rxg := rxg.(type) {
+		case *RxFlow:
+			cnt.RxQueues = append(cnt.RxQueues, rxg.queue)
+		case *RxTable:
+			cnt.RxQueues = append(cnt.RxQueues, int(rxg.c.queue))
+		}
+	}
 	return cnt
 }
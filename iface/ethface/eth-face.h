@@ -7,6 +7,8 @@
 #include "../face.h"
 #include "../rxloop.h"
 #include <rte_flow.h>
+#include <rte_ip.h>
+#include <rte_udp.h>
 
 typedef struct EthFaceEtherHdr
 {
@@ -15,36 +17,110 @@ typedef struct EthFaceEtherHdr
   struct rte_vlan_hdr vlan1;
 } __rte_packed __rte_aligned(2) EthFaceEtherHdr;
 
+/** \brief Initialize Ethernet and VLAN headers.
+ *  \param etherType EtherType of the payload, in host byte order.
+ *  \return length of Ethernet and VLAN headers.
+ */
 uint8_t
 EthFaceEtherHdr_Init(EthFaceEtherHdr* hdr,
                      const struct rte_ether_addr* local,
                      const struct rte_ether_addr* remote,
                      uint16_t vlan0,
-                     uint16_t vlan1);
+                     uint16_t vlan1,
+                     uint16_t etherType);
+
+/** \brief Initialize IPv4 or IPv6 header and UDP header.
+ *  \param room buffer after Ethernet and VLAN headers.
+ *  \param ipVersion either 4 or 6.
+ *  \param localIp local address, 4 or 16 octets.
+ *  \param remoteIp remote address, 4 or 16 octets.
+ *  \return length of IP and UDP headers.
+ */
+uint8_t
+EthFaceUdpHdr_Init(uint8_t* room,
+                   int ipVersion,
+                   const uint8_t* localIp,
+                   const uint8_t* remoteIp,
+                   uint16_t localPort,
+                   uint16_t remotePort);
+
+/** \brief Maximum length of headers prepended by EthFace.
+ */
+#define ETHFACE_MAX_HDRLEN                                                     \
+  (sizeof(EthFaceEtherHdr) + sizeof(struct rte_ipv6_hdr) +                     \
+   sizeof(struct rte_udp_hdr))
 
 /** \brief Ethernet face private data.
- *
- *  This struct doubles as RxGroup when not using EthRxTable.
  */
 typedef struct EthFacePriv
 {
-  RxGroup flowRxg;
-  EthFaceEtherHdr txHdr;
+  /** \brief Ethernet header, optionally followed by IP and UDP headers.
+   *
+   *  This starts with EthFaceEtherHdr, but the IP header is placed right
+   *  after the last VLAN header in use.
+   */
+  uint8_t txHdr[ETHFACE_MAX_HDRLEN];
   uint16_t port;
-  uint16_t rxQueue;
   FaceId faceId;
-  uint8_t txHdrLen;
-} EthFacePriv;
+  uint8_t txHdrLen;  ///< length of all headers
+  uint8_t ethHdrLen; ///< length of Ethernet and VLAN headers
+  uint8_t ipVersion; ///< 4 or 6 for UDP encapsulation, 0 for NDN over Ethernet
+} __rte_aligned(2) EthFacePriv;
+
+/** \brief Strip IP and UDP headers and Ethernet padding.
+ *  \param frame a frame whose Ethernet and VLAN headers have been removed.
+ *  \param ipLen length of IP header.
+ *  \retval false UDP header is invalid, frame should be dropped.
+ */
+static inline bool
+EthFace_StripUdp(struct rte_mbuf* frame, uint16_t ipLen)
+{
+  if (unlikely(frame->data_len < ipLen + sizeof(struct rte_udp_hdr))) {
+    return false;
+  }
+  const struct rte_udp_hdr* udp =
+    rte_pktmbuf_mtod_offset(frame, const struct rte_udp_hdr*, ipLen);
+  uint16_t udpLen = rte_be_to_cpu_16(udp->dgram_len);
+  if (unlikely(udpLen < sizeof(struct rte_udp_hdr) ||
+               ipLen + udpLen > frame->pkt_len)) {
+    return false;
+  }
+
+  rte_pktmbuf_adj(frame, ipLen + sizeof(struct rte_udp_hdr));
+  uint16_t payloadLen = udpLen - sizeof(struct rte_udp_hdr);
+  if (frame->pkt_len > payloadLen) {
+    rte_pktmbuf_trim(frame, frame->pkt_len - payloadLen);
+  }
+  return true;
+}
 
 uint16_t
 EthFace_TxBurst(Face* face, struct rte_mbuf** pkts, uint16_t nPkts);
 
 /** \brief Setup rte_flow on EthDev for hardware dispatching.
+ *  \param queues RX queues that receive frames of this face.
+ *  \param nQueues number of RX queues; if greater than one, frames are
+ *                 distributed among \p queues with RSS.
  */
 struct rte_flow*
-EthFace_SetupFlow(EthFacePriv* priv, struct rte_flow_error* error);
+EthFace_SetupFlow(EthFacePriv* priv,
+                  const uint16_t* queues,
+                  int nQueues,
+                  struct rte_flow_error* error);
+
+/** \brief rte_flow-based hardware RX dispatching on one RX queue.
+ */
+typedef struct EthRxFlow
+{
+  RxGroup base;
+  uint16_t port;
+  uint16_t queue;
+  FaceId faceId;
+  uint8_t hdrLen; ///< length of Ethernet and VLAN headers
+  uint8_t ipLen;  ///< length of IP header, 0 for NDN over Ethernet
+} EthRxFlow;
 
 uint16_t
-EthFace_FlowRxBurst(RxGroup* flowRxg, struct rte_mbuf** pkts, uint16_t nPkts);
+EthRxFlow_RxBurst(RxGroup* rxg, struct rte_mbuf** pkts, uint16_t nPkts);
 
 #endif // NDN_DPDK_IFACE_ETHFACE_ETH_FACE_H
@@ -28,7 +28,7 @@ type iImpl interface {
 var impls = []iImpl{&rxFlowImpl{}, &rxTableImpl{}}
 
 // Start EthDev (called by impl).
-func startDev(port *Port, nRxQueues int, promisc bool) error {
+func startDev(port *Port, nRxQueues int, promisc bool, rss bool) error {
 	var cfg dpdk.EthDevConfig
 	numaSocket := port.dev.GetNumaSocket()
 	for i := 0; i < nRxQueues; i++ {
@@ -43,6 +43,7 @@ func startDev(port *Port, nRxQueues int, promisc bool) error {
 		Socket:   numaSocket,
 	})
 	cfg.Mtu = port.cfg.Mtu
+	cfg.Rss = rss
 	if _, _, e := port.dev.Configure(cfg); e != nil {
 		return e
 	}
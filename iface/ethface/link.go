@@ -0,0 +1,52 @@
+package ethface
+
+import (
+	"time"
+)
+
+// Interval of polling link status on each port.
+var LinkPollInterval = 200 * time.Millisecond
+
+// Poll link status until port is closed.
+func (port *Port) pollLink(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(LinkPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			port.checkLink()
+		}
+	}
+}
+
+// Check link status, and update face states upon change.
+func (port *Port) checkLink() {
+	isDown := port.dev.IsDown()
+
+	port.mutex.Lock()
+	if isDown == port.linkDown {
+		port.mutex.Unlock()
+		return
+	}
+	port.linkDown = isDown
+	faces := make([]*EthFace, 0, len(port.faces))
+	for _, face := range port.faces {
+		faces = append(faces, face)
+	}
+	port.mutex.Unlock()
+
+	port.logger.WithField("down", isDown).Info("link status changed")
+	for _, face := range faces {
+		face.SetDown(isDown)
+	}
+}
+
+// Determine whether the link is down, as of last poll.
+func (port *Port) IsLinkDown() bool {
+	port.mutex.Lock()
+	defer port.mutex.Unlock()
+	return port.linkDown
+}
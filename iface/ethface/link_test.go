@@ -0,0 +1,61 @@
+package ethface_test
+
+import (
+	"testing"
+	"time"
+
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/ethface"
+	"ndn-dpdk/iface/ifacetestfixture"
+)
+
+func TestLinkStatus(t *testing.T) {
+	assert, require := dpdktestenv.MakeAR(t)
+
+	mp, mempools := ifacetestfixture.MakeMempools()
+
+	var pairCfg dpdktestenv.EthDevPairConfig
+	pairCfg.NQueues = 1
+	pair := dpdktestenv.NewEthDevPair(pairCfg)
+	defer pair.Close()
+	pair.StartPortB()
+
+	var cfg ethface.PortConfig
+	cfg.Mempools = mempools
+	cfg.RxMp = mp
+	cfg.RxqFrames = 64
+	cfg.TxqPkts = 64
+	cfg.TxqFrames = 64
+
+	face, e := ethface.Create(ethface.NewLocator(pair.PortA), cfg)
+	require.NoError(e)
+	defer face.GetPort().Close()
+	defer face.Close()
+	time.Sleep(2 * ethface.LinkPollInterval)
+	assert.False(face.IsDown())
+
+	var downEvents, upEvents int
+	defer iface.OnFaceDown(func(id iface.FaceId) {
+		if id == face.GetFaceId() {
+			downEvents++
+		}
+	}).Close()
+	defer iface.OnFaceUp(func(id iface.FaceId) {
+		if id == face.GetFaceId() {
+			upEvents++
+		}
+	}).Close()
+
+	require.NoError(pair.PortA.SetLinkDown())
+	time.Sleep(2 * ethface.LinkPollInterval)
+	assert.True(face.GetPort().IsLinkDown())
+	assert.True(face.IsDown())
+	assert.Equal(1, downEvents)
+
+	require.NoError(pair.PortA.SetLinkUp())
+	time.Sleep(2 * ethface.LinkPollInterval)
+	assert.False(face.GetPort().IsLinkDown())
+	assert.False(face.IsDown())
+	assert.Equal(1, upEvents)
+}
@@ -61,20 +61,25 @@ func Create(loc Locator, cfg PortConfig) (face *EthFace, e error) {
 		return nil, e
 	}
 
+	port, e := findOrCreatePort(loc, cfg)
+	if e != nil {
+		return nil, e
+	}
+	return New(port, loc)
+}
+
+// Find the Port named in locator, or create it with cfg.
+func findOrCreatePort(loc Locator, cfg PortConfig) (port *Port, e error) {
 	dev := dpdk.FindEthDev(loc.Port)
 	if !dev.IsValid() {
 		return nil, errors.New("EthDev not found")
 	}
 
-	port := FindPort(dev)
-	if port == nil {
-		if cfg.Local.IsZero() {
-			cfg.Local = loc.Local
-		}
-		if port, e = NewPort(dev, cfg); e != nil {
-			return nil, e
-		}
+	if port = FindPort(dev); port != nil {
+		return port, nil
 	}
-
-	return New(port, loc)
+	if cfg.Local.IsZero() {
+		cfg.Local = loc.Local
+	}
+	return NewPort(dev, cfg)
 }
@@ -0,0 +1,104 @@
+package ethface
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"path/filepath"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+)
+
+const memifLocatorScheme = "memif"
+
+// Role of a memif endpoint.
+type MemifRole string
+
+const (
+	MemifRole_Master MemifRole = "master" // forwarder side, creates the control socket
+	MemifRole_Slave  MemifRole = "slave"  // application side, connects to the control socket
+)
+
+// Locator of a face on a shared memory packet interface (memif).
+type MemifLocator struct {
+	iface.LocatorBase
+	SocketName string // control socket path
+	Id         int    // interface ID, distinguishes interfaces on the same control socket
+}
+
+func NewMemifLocator(socketName string, id int) (loc MemifLocator) {
+	loc.Scheme = memifLocatorScheme
+	loc.SocketName = socketName
+	loc.Id = id
+	return loc
+}
+
+func (loc MemifLocator) Validate() error {
+	if !filepath.IsAbs(loc.SocketName) {
+		return errors.New("SocketName must be an absolute path")
+	}
+	if loc.Id < 0 || int64(loc.Id) > math.MaxUint32 {
+		return errors.New("Id is out of range")
+	}
+	return nil
+}
+
+// Get virtual device name.
+// This must start with "net_memif" so that DPDK selects memif driver.
+func (loc MemifLocator) getVdevName(role MemifRole) string {
+	h := fnv.New32a()
+	h.Write([]byte(loc.SocketName))
+	return fmt.Sprintf("net_memif_%s_%08x_%d", role, h.Sum32(), loc.Id)
+}
+
+func (loc MemifLocator) getVdevArgs(role MemifRole) string {
+	return fmt.Sprintf("role=%s,id=%d,socket=%s", role, loc.Id, loc.SocketName)
+}
+
+func init() {
+	iface.RegisterLocatorType(MemifLocator{}, memifLocatorScheme)
+}
+
+// Memif information on a Port.
+type memifPort struct {
+	loc  MemifLocator
+	role MemifRole
+}
+
+// Create a face on a memif virtual device.
+// This creates a memif virtual device, and a Port with one face that uses NDN multicast address.
+// The virtual device is destroyed when the face is closed.
+//
+// The forwarder should use MemifRole_Master, and the application should use MemifRole_Slave.
+func CreateMemif(loc MemifLocator, role MemifRole, cfg PortConfig) (face *EthFace, e error) {
+	if e = loc.Validate(); e != nil {
+		return nil, e
+	}
+	if role != MemifRole_Master && role != MemifRole_Slave {
+		return nil, fmt.Errorf("unknown memif role %s", role)
+	}
+
+	name := loc.getVdevName(role)
+	if dpdk.FindEthDev(name).IsValid() {
+		return nil, errors.New("memif already exists")
+	}
+	if e = dpdk.CreateVdev(name, loc.getVdevArgs(role)); e != nil {
+		return nil, fmt.Errorf("CreateVdev: %v", e)
+	}
+	dev := dpdk.FindEthDev(name)
+
+	port, e := NewPort(dev, cfg)
+	if e != nil {
+		dpdk.DestroyVdev(name)
+		return nil, e
+	}
+	port.memif = &memifPort{loc: loc, role: role}
+
+	if face, e = New(port, NewLocator(dev)); e != nil {
+		port.Close()
+		return nil, e
+	}
+	return face, nil
+}
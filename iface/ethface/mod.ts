@@ -11,3 +11,37 @@ export interface Locator {
    */
   Vlan?: number[];
 }
+
+export interface UdpLocator extends Omit<Locator, "Scheme"> {
+  Scheme: "udpe";
+  LocalIP: string;
+  RemoteIP: string;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   * @maximum 65535
+   * @default 6363
+   */
+  LocalUDP?: number;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   * @maximum 65535
+   * @default 6363
+   */
+  RemoteUDP?: number;
+}
+
+export interface MemifLocator {
+  Scheme: "memif";
+  SocketName: string;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   * @maximum 4294967295
+   */
+  Id: number;
+}
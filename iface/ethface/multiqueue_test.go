@@ -0,0 +1,77 @@
+package ethface_test
+
+import (
+	"testing"
+	"time"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/ethface"
+	"ndn-dpdk/iface/ifacetestfixture"
+)
+
+func TestMultiQueue(t *testing.T) {
+	assert, require := dpdktestenv.MakeAR(t)
+
+	mp, mempools := ifacetestfixture.MakeMempools()
+
+	var pairCfg dpdktestenv.EthDevPairConfig
+	pairCfg.NQueues = 2
+	pair := dpdktestenv.NewEthDevPair(pairCfg)
+	defer pair.Close()
+	pair.StartPortB()
+
+	var cfg ethface.PortConfig
+	cfg.Mempools = mempools
+	cfg.RxMp = mp
+	cfg.RxqFrames = 64
+	cfg.TxqPkts = 64
+	cfg.TxqFrames = 64
+	cfg.RxQueues = 2
+
+	face, e := ethface.Create(ethface.NewLocator(pair.PortA), cfg)
+	require.NoError(e)
+	defer face.GetPort().Close()
+	defer face.Close()
+
+	rxgs := face.ListRxGroups()
+	require.Len(rxgs, 2)
+	assert.Equal([]int{0, 1}, face.ReadExCounters().(ethface.ExCounters).RxQueues)
+
+	slaves := dpdk.ListSlaveLCores()
+	for i, rxg := range rxgs {
+		rxl := iface.NewRxLoop(dpdk.NUMA_SOCKET_ANY)
+		rxl.SetLCore(slaves[i])
+		rxl.SetCallback(iface.WrapRxCb(func(burst iface.RxBurst) {}))
+		require.NoError(rxl.Launch())
+		defer rxl.Close()
+		defer rxl.Stop()
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(rxl.AddRxGroup(rxg))
+	}
+
+	// each peer TX queue delivers to the corresponding RX queue on PortA
+	for i := 0; i < 10; i++ {
+		for _, txq := range pair.TxqB {
+			frame := dpdktestenv.PacketFromHex("01005E0017AA 020000000001 8624 050B 0703080141 0A04A0A1A2A3")
+			txq.TxBurst([]dpdk.Packet{frame})
+		}
+	}
+
+	// fragments arriving on RX queue 1 are reassembled
+	for _, lpHex := range []string{
+		"6419 5108 0000000000000010 520100 530102 5007 050B0703080141",
+		"6418 5108 0000000000000011 520101 530102 5006 0A04A0A1A2A3",
+	} {
+		frame := dpdktestenv.PacketFromHex("01005E0017AA 020000000001 8624 " + lpHex)
+		pair.TxqB[1].TxBurst([]dpdk.Packet{frame})
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	cnt := face.ReadCounters()
+	assert.Equal(uint64(22), cnt.RxFrames)
+	assert.Equal(uint64(21), cnt.RxInterests)
+	assert.Equal(uint64(1), cnt.Reass.Delivered)
+	assert.Equal(uint64(0), cnt.ReassUnavail)
+}
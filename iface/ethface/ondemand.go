@@ -0,0 +1,194 @@
+package ethface
+
+import (
+	"encoding/binary"
+	"time"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/ndn"
+)
+
+// Interval of processing unicast frames from unknown senders on an on-demand port.
+var OnDemandPollInterval = 10 * time.Millisecond
+
+// Parse sender address and VLAN tags of an Ethernet frame.
+func parseNewcomer(frame dpdk.Packet) (remote dpdk.EtherAddr, vlan []uint16, ok bool) {
+	var hdr [22]byte
+	if frame.ReadTo(0, hdr[:]) < 14 {
+		return remote, nil, false
+	}
+	copy(remote.Bytes[:], hdr[6:12])
+	vid0 := binary.BigEndian.Uint16(hdr[14:16]) & 0xFFF
+	vid1 := binary.BigEndian.Uint16(hdr[18:20]) & 0xFFF
+	if binary.BigEndian.Uint16(hdr[12:14]) == ndn.NDN_ETHERTYPE {
+		return remote, nil, true
+	} else if binary.BigEndian.Uint16(hdr[16:18]) == ndn.NDN_ETHERTYPE {
+		return remote, []uint16{vid0}, true
+	} else if binary.BigEndian.Uint16(hdr[20:22]) == ndn.NDN_ETHERTYPE {
+		return remote, []uint16{vid0, vid1}, true
+	}
+	return remote, nil, false
+}
+
+// Create on-demand faces and close idle ones, until port is closed.
+func (port *Port) runOnDemand(stop <-chan struct{}) {
+	idleTimeout := port.cfg.OnDemandIdleTimeout.Duration()
+	pollTicker := time.NewTicker(OnDemandPollInterval)
+	defer pollTicker.Stop()
+	idleTicker := time.NewTicker(idleTimeout / 4)
+	defer idleTicker.Stop()
+	activity := make(map[iface.FaceId]onDemandActivity)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-pollTicker.C:
+			port.withLocker(port.acceptNewcomers)
+		case now := <-idleTicker.C:
+			port.closeIdleFaces(activity, now, idleTimeout)
+		}
+	}
+}
+
+// Invoke f while holding cfg.Locker, if it is set.
+func (port *Port) withLocker(f func()) {
+	if port.cfg.Locker != nil {
+		port.cfg.Locker.Lock()
+		defer port.cfg.Locker.Unlock()
+	}
+	f()
+}
+
+// Get on-demand RxTable of a queue, or nil if port is closed or does not use on-demand RxTable.
+// Caller must hold port.mutex.
+func (port *Port) getOnDemandRxTable(queue int) *RxTable {
+	if port.closed {
+		return nil
+	}
+	impl, ok := port.impl.(*rxTableImpl)
+	if !ok || queue >= len(impl.rxts) || impl.rxts[queue].c.newcomers == nil {
+		return nil
+	}
+	return impl.rxts[queue]
+}
+
+// Create faces for unicast frames from unknown senders, and readmit those frames.
+func (port *Port) acceptNewcomers() {
+	for queue := 0; queue < port.cfg.RxQueues; queue++ {
+		port.acceptNewcomersOnQueue(queue)
+	}
+}
+
+func (port *Port) acceptNewcomersOnQueue(queue int) {
+	frames := make([]dpdk.Packet, onDemandRingCapacity)
+	port.mutex.Lock()
+	rxt := port.getOnDemandRxTable(queue)
+	if rxt == nil {
+		port.mutex.Unlock()
+		return
+	}
+	n, _ := rxt.newcomers.BurstDequeue(frames)
+	port.mutex.Unlock()
+
+	readmitted := frames[:0]
+	for _, frame := range frames[:n] {
+		if port.acceptNewcomer(frame) {
+			readmitted = append(readmitted, frame)
+		} else {
+			frame.Close()
+		}
+	}
+	if len(readmitted) == 0 {
+		return
+	}
+
+	port.mutex.Lock()
+	defer port.mutex.Unlock()
+	nEnqueued := 0
+	if rxt = port.getOnDemandRxTable(queue); rxt != nil {
+		nEnqueued, _ = rxt.readmitted.BurstEnqueue(readmitted)
+	}
+	for _, frame := range readmitted[nEnqueued:] {
+		frame.Close()
+	}
+}
+
+// Find or create a face for a newcomer frame.
+// Return true if the frame should be readmitted.
+func (port *Port) acceptNewcomer(frame dpdk.Packet) bool {
+	remote, vlan, ok := parseNewcomer(frame)
+	if !ok || !remote.IsUnicast() {
+		return false
+	}
+
+	nOnDemand := 0
+	for _, face := range port.ListFaces() {
+		if face.udp != nil {
+			continue
+		}
+		if face.loc.Remote.Equal(remote) {
+			return true // face created for an earlier frame
+		}
+		if face.loc.Remote.IsUnicast() && face.loc.Remote.Bytes[5] == remote.Bytes[5] {
+			return false // another face occupies the RxTable slot
+		}
+		if face.onDemand {
+			nOnDemand++
+		}
+	}
+	logEntry := port.logger.WithField("remote", remote)
+	if nOnDemand >= port.cfg.OnDemandMaxFaces {
+		logEntry.Debug("on-demand face limit reached")
+		return false
+	}
+
+	var loc Locator
+	loc.Scheme = locatorScheme
+	loc.Port = port.dev.GetName()
+	loc.Remote = remote
+	loc.Vlan = vlan
+	face, e := newFace(port, loc, nil, true)
+	if e != nil {
+		logEntry.WithError(e).Warn("on-demand face creation error")
+		return false
+	}
+	logEntry.WithField("face", face.GetFaceId()).Info("on-demand face created")
+	return true
+}
+
+type onDemandActivity struct {
+	rxFrames uint64
+	lastRx   time.Time
+}
+
+// Close on-demand faces that have not received a frame for idleTimeout.
+func (port *Port) closeIdleFaces(activity map[iface.FaceId]onDemandActivity, now time.Time, idleTimeout time.Duration) {
+	seen := make(map[iface.FaceId]bool)
+	for _, face := range port.ListFaces() {
+		if !face.onDemand {
+			continue
+		}
+		id := face.GetFaceId()
+		seen[id] = true
+		rxFrames := face.ReadCounters().RxFrames
+		if act, ok := activity[id]; !ok || act.rxFrames != rxFrames {
+			activity[id] = onDemandActivity{rxFrames, now}
+		} else if now.Sub(act.lastRx) > idleTimeout {
+			delete(activity, id)
+			port.logger.WithField("face", id).Info("closing idle on-demand face")
+			go face.Close()
+		}
+	}
+	for id := range activity {
+		if !seen[id] {
+			delete(activity, id)
+		}
+	}
+}
+
+// Determine whether the face was created on-demand.
+func (face *EthFace) IsOnDemand() bool {
+	return face.onDemand
+}
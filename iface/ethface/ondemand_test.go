@@ -0,0 +1,92 @@
+package ethface_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"ndn-dpdk/core/nnduration"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/ethface"
+	"ndn-dpdk/iface/ifacetestfixture"
+)
+
+func TestOnDemand(t *testing.T) {
+	assert, require := dpdktestenv.MakeAR(t)
+
+	mp, mempools := ifacetestfixture.MakeMempools()
+
+	var pairCfg dpdktestenv.EthDevPairConfig
+	pairCfg.NQueues = 1
+	pair := dpdktestenv.NewEthDevPair(pairCfg)
+	defer pair.Close()
+	pair.StartPortB()
+
+	var cfg ethface.PortConfig
+	cfg.Mempools = mempools
+	cfg.RxMp = mp
+	cfg.RxqFrames = 64
+	cfg.TxqPkts = 64
+	cfg.TxqFrames = 64
+	cfg.OnDemand = true
+	cfg.OnDemandMaxFaces = 1
+
+	badCfg := cfg
+	require.NoError(badCfg.OnDemandIdleTimeout.UnmarshalJSON([]byte(`"-1s"`)))
+	_, e := ethface.Create(ethface.NewLocator(pair.PortA), badCfg)
+	assert.Error(e)
+
+	cfg.OnDemandIdleTimeout = 400
+	faceM, e := ethface.Create(ethface.NewLocator(pair.PortA), cfg)
+	require.NoError(e)
+	port := faceM.GetPort()
+	defer port.Close()
+	defer faceM.Close()
+	assert.Equal("RxTable", port.GetImplName())
+
+	rxl := iface.NewRxLoop(dpdk.NUMA_SOCKET_ANY)
+	rxl.SetLCore(dpdk.ListSlaveLCores()[0])
+	rxl.SetCallback(iface.WrapRxCb(func(burst iface.RxBurst) {}))
+	require.NoError(rxl.Launch())
+	defer rxl.Close()
+	defer rxl.Stop()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(rxl.AddRxGroup(faceM.ListRxGroups()[0]))
+
+	macA := fmt.Sprintf("%X", pair.PortA.GetMacAddr().Bytes[:])
+	sendFrame := func(srcAndType string) {
+		frame := dpdktestenv.PacketFromHex(macA + srcAndType + "050B 0703080141 0A04A0A1A2A3")
+		pair.TxqB[0].TxBurst([]dpdk.Packet{frame})
+	}
+	findOnDemand := func() (found *ethface.EthFace) {
+		for _, face := range port.ListFaces() {
+			if face.IsOnDemand() {
+				found = face
+			}
+		}
+		return found
+	}
+
+	// first frames from unknown sender create an on-demand face with VLAN, and are readmitted
+	sendFrame("020000000001 8100 0064 8624")
+	sendFrame("020000000001 8100 0064 8624")
+	time.Sleep(200 * time.Millisecond)
+	face1 := findOnDemand()
+	require.NotNil(face1)
+	loc1 := face1.GetLocator().(ethface.Locator)
+	assert.Equal("02:00:00:00:00:01", loc1.Remote.String())
+	assert.Equal([]uint16{100}, loc1.Vlan)
+	assert.Equal(uint64(2), face1.ReadCounters().RxFrames)
+
+	// another sender is refused due to OnDemandMaxFaces
+	sendFrame("020000000002 8624")
+	time.Sleep(200 * time.Millisecond)
+	assert.Len(port.ListFaces(), 2)
+
+	// idle on-demand face is closed
+	time.Sleep(800 * time.Millisecond)
+	assert.Nil(findOnDemand())
+	assert.Len(port.ListFaces(), 1)
+}
@@ -6,6 +6,7 @@ package ethface
 import "C"
 import (
 	"errors"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 
@@ -34,10 +35,19 @@ type Port struct {
 	faces    map[iface.FaceId]*EthFace
 	impl     iImpl
 	nextImpl int
+	memif    *memifPort // memif information, nil if port is not a memif virtual device
+
+	mutex        sync.Mutex    // protects faces, linkDown, and closed against pollers
+	linkDown     bool          // whether link is down, as of last poll
+	linkPollStop chan struct{} // closed to stop link status poller
+	linkPollDone chan struct{} // closed when link status poller stops
+	closed       bool          // whether port has been closed
+	onDemandStop chan struct{} // closed to stop on-demand face creator
 }
 
 // Open a port.
 func NewPort(dev dpdk.EthDev, cfg PortConfig) (port *Port, e error) {
+	cfg.applyDefaults()
 	if e = cfg.check(); e != nil {
 		return nil, e
 	}
@@ -54,6 +64,14 @@ func NewPort(dev dpdk.EthDev, cfg PortConfig) (port *Port, e error) {
 	port.dev = dev
 	port.faces = make(map[iface.FaceId]*EthFace)
 
+	port.linkPollStop = make(chan struct{})
+	port.linkPollDone = make(chan struct{})
+	go port.pollLink(port.linkPollStop, port.linkPollDone)
+	if cfg.OnDemand {
+		port.onDemandStop = make(chan struct{})
+		go port.runOnDemand(port.onDemandStop)
+	}
+
 	port.logger.Debug("opening")
 	portByEthDev[port.dev] = port
 	return port, nil
@@ -64,11 +82,31 @@ func (port *Port) GetEthDev() dpdk.EthDev {
 }
 
 func (port *Port) Close() (e error) {
+	if port.linkPollStop != nil {
+		close(port.linkPollStop)
+		<-port.linkPollDone
+		port.linkPollStop = nil
+	}
+	port.mutex.Lock()
+	if port.onDemandStop != nil {
+		// on-demand face creator may be waiting for cfg.Locker, so it is not awaited
+		close(port.onDemandStop)
+		port.onDemandStop = nil
+	}
+	port.closed = true
+	port.mutex.Unlock()
 	if port.impl != nil {
 		e = port.impl.Close()
 	}
 	delete(portByEthDev, port.dev)
 	port.logger.Debug("closing")
+	if port.memif != nil {
+		name := port.dev.GetName()
+		port.dev.Close()
+		if e := dpdk.DestroyVdev(name); e != nil {
+			port.logger.WithError(e).Warn("DestroyVdev error")
+		}
+	}
 	return nil
 }
 
@@ -83,14 +121,22 @@ func (port *Port) findFace(filter func(face *EthFace) bool) *EthFace {
 
 // FindFace(nil) returns a face with multicast address.
 // FindFace(unicastAddr) returns a face with matching address.
+// UDP faces are not considered.
 func (port *Port) FindFace(query *dpdk.EtherAddr) *EthFace {
 	if query == nil {
 		return port.findFace(func(face *EthFace) bool {
-			return face.loc.Remote.IsGroup()
+			return face.udp == nil && face.loc.Remote.IsGroup()
 		})
 	}
 	return port.findFace(func(face *EthFace) bool {
-		return face.loc.Remote.Equal(*query)
+		return face.udp == nil && face.loc.Remote.Equal(*query)
+	})
+}
+
+// Find a UDP face that would receive the same packets as loc.
+func (port *Port) findUdpFace(loc UdpLocator) *EthFace {
+	return port.findFace(func(face *EthFace) bool {
+		return face.udp != nil && face.udp.conflictsWith(loc)
 	})
 }
 
@@ -133,7 +179,7 @@ func (port *Port) fallbackImpl() error {
 			logEntry.WithField("face", faceId).WithError(e).Info("face restart error, trying next impl")
 			return port.fallbackImpl()
 		}
-		face.SetDown(false)
+		face.SetDown(port.IsLinkDown())
 	}
 
 	logEntry.Info("impl initialized")
@@ -154,19 +200,25 @@ func (port *Port) startFace(face *EthFace, forceFallback bool) error {
 	}
 
 	port.logger.WithFields(makeLogFields("impl", port.impl.String(), "face", face.GetFaceId())).Info("face started")
+	port.mutex.Lock()
+	defer port.mutex.Unlock()
 	port.faces[face.GetFaceId()] = face
 	return nil
 }
 
 // Stop face in impl (called by EthFace.Close).
 func (port *Port) stopFace(face *EthFace) (e error) {
+	port.mutex.Lock()
 	delete(port.faces, face.GetFaceId())
+	port.mutex.Unlock()
 	e = port.impl.Stop(face)
 	port.logger.WithError(e).Info("face stopped")
 	return nil
 }
 
 func (port *Port) ListFaces() (list []*EthFace) {
+	port.mutex.Lock()
+	defer port.mutex.Unlock()
 	for _, face := range port.faces {
 		list = append(list, face)
 	}
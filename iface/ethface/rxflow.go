@@ -54,6 +54,9 @@ func (impl *rxFlowImpl) Init() error {
 	if DisableRxFlow {
 		return errors.New("disabled")
 	}
+	if impl.port.cfg.OnDemand {
+		return errors.New("on-demand faces require RxTable")
+	}
 
 	if e := impl.setIsolate(true); e != nil {
 		return e
@@ -64,12 +67,15 @@ func (impl *rxFlowImpl) Init() error {
 	if nRxQueues == 0 {
 		return errors.New("unable to retrieve max_rx_queues")
 	}
-	const maxRxQueues = 4 // C.RTE_MAX_QUEUES_PER_PORT
+	maxRxQueues := 4 * impl.port.cfg.RxQueues // up to 4 faces; C.RTE_MAX_QUEUES_PER_PORT
 	if nRxQueues > maxRxQueues {
 		nRxQueues = maxRxQueues
 	}
+	if nRxQueues < impl.port.cfg.RxQueues {
+		return errors.New("insufficient RX queues")
+	}
 
-	if e := startDev(impl.port, nRxQueues, false); e != nil {
+	if e := startDev(impl.port, nRxQueues, false, false); e != nil {
 		return e
 	}
 
@@ -77,46 +83,64 @@ func (impl *rxFlowImpl) Init() error {
 	return nil
 }
 
-func (impl *rxFlowImpl) findQueue(filter func(rxf *RxFlow) bool) (i int, rxf *RxFlow) {
-	for i, rxf = range impl.queueFlow {
+// Find queues that satisfy a filter.
+func (impl *rxFlowImpl) findQueues(filter func(rxf *RxFlow) bool) (indices []int) {
+	for i, rxf := range impl.queueFlow {
 		if filter(rxf) {
-			return
+			indices = append(indices, i)
 		}
 	}
-	return -1, nil
+	return indices
 }
 
 func (impl *rxFlowImpl) Start(face *EthFace) error {
-	index, _ := impl.findQueue(func(rxf *RxFlow) bool { return rxf == nil })
-	if index < 0 {
+	indices := impl.findQueues(func(rxf *RxFlow) bool { return rxf == nil })
+	if len(indices) < impl.port.cfg.RxQueues {
 		// TODO reclaim deferred-destroy queues
 		return errors.New("no available queue")
 	}
+	indices = indices[:impl.port.cfg.RxQueues]
 
-	rxf, e := newRxFlow(face, index)
-	if e != nil {
-		return e
+	queues := make([]C.uint16_t, len(indices))
+	for i, index := range indices {
+		queues[i] = C.uint16_t(index)
+	}
+	var flowErr C.struct_rte_flow_error
+	flow := C.EthFace_SetupFlow(face.getPriv(), &queues[0], C.int(len(queues)), &flowErr)
+	if flow == nil {
+		return readFlowErr(flowErr)
 	}
 
-	impl.port.logger.WithFields(makeLogFields("rx-queue", index, "face", face.GetFaceId())).Debug("create RxFlow")
-	impl.queueFlow[index] = rxf
-	iface.EmitRxGroupAdd(rxf)
+	for thread, index := range indices {
+		rxf := newRxFlow(face, flow, index, thread)
+		impl.port.logger.WithFields(makeLogFields("rx-queue", index, "rx-thread", thread, "face", face.GetFaceId())).Debug("create RxFlow")
+		impl.queueFlow[index] = rxf
+		iface.EmitRxGroupAdd(rxf)
+	}
 	return nil
 }
 
 func (impl *rxFlowImpl) Stop(face *EthFace) error {
-	index, rxf := impl.findQueue(func(rxf *RxFlow) bool { return rxf != nil && rxf.face == face })
-	if index < 0 {
+	indices := impl.findQueues(func(rxf *RxFlow) bool { return rxf != nil && rxf.face == face })
+	if len(indices) == 0 {
 		return nil
 	}
-	iface.EmitRxGroupRemove(rxf)
+	for _, index := range indices {
+		iface.EmitRxGroupRemove(impl.queueFlow[index])
+	}
 
-	if e := impl.destroyFlow(rxf); e != nil {
-		impl.port.logger.WithField("rx-queue", index).WithError(e).Debug("destroy RxFlow deferred")
-		rxf.face = nil
+	logEntry := impl.port.logger.WithField("rx-queues", indices)
+	if e := impl.destroyFlow(impl.queueFlow[indices[0]]); e != nil {
+		logEntry.WithError(e).Debug("destroy RxFlow deferred")
+		for _, index := range indices {
+			impl.queueFlow[index].face = nil
+		}
 	} else {
-		impl.port.logger.WithField("rx-queue", index).Debug("destroy RxFlow success")
-		impl.queueFlow[index] = nil
+		logEntry.Debug("destroy RxFlow success")
+		for _, index := range indices {
+			impl.queueFlow[index].close()
+			impl.queueFlow[index] = nil
+		}
 	}
 	return nil
 }
@@ -130,10 +154,16 @@ func (impl *rxFlowImpl) destroyFlow(rxf *RxFlow) error {
 }
 
 func (impl *rxFlowImpl) Close() error {
+	destroyed := make(map[*C.struct_rte_flow]bool)
 	for _, rxf := range impl.queueFlow {
-		if rxf != nil {
+		if rxf == nil {
+			continue
+		}
+		if !destroyed[rxf.flow] {
 			impl.destroyFlow(rxf)
+			destroyed[rxf.flow] = true
 		}
+		rxf.close()
 	}
 	impl.queueFlow = nil
 	impl.port.dev.Stop()
@@ -141,35 +171,46 @@ func (impl *rxFlowImpl) Close() error {
 	return nil
 }
 
-// rte_flow-based hardware RX dispatching.
+// rte_flow-based hardware RX dispatching on one RX queue.
+// A face has one RxFlow per RX queue; all RxFlows of a face share the same rte_flow.
 type RxFlow struct {
 	iface.RxGroupBase
-	face *EthFace
-	flow *C.struct_rte_flow
+	c     *C.EthRxFlow
+	face  *EthFace
+	flow  *C.struct_rte_flow
+	queue int
 }
 
-func newRxFlow(face *EthFace, queue int) (rxf *RxFlow, e error) {
+func newRxFlow(face *EthFace, flow *C.struct_rte_flow, queue int, thread int) (rxf *RxFlow) {
 	priv := face.getPriv()
-	priv.rxQueue = C.uint16_t(queue)
-	var flowErr C.struct_rte_flow_error
-	flow := C.EthFace_SetupFlow(priv, &flowErr)
-	if flow == nil {
-		return nil, readFlowErr(flowErr)
-	}
-
 	rxf = new(RxFlow)
-	rxf.InitRxgBase(unsafe.Pointer(&priv.flowRxg))
+	rxf.c = (*C.EthRxFlow)(dpdk.Zmalloc("EthRxFlow", C.sizeof_EthRxFlow, face.GetNumaSocket()))
+	rxf.InitRxgBase(unsafe.Pointer(rxf.c))
 	rxf.face = face
 	rxf.flow = flow
-	priv.flowRxg.rxBurstOp = C.RxGroup_RxBurst(C.EthFace_FlowRxBurst)
-	priv.flowRxg.rxThread = 0
-	return rxf, nil
+	rxf.queue = queue
+
+	rxf.c.base.rxBurstOp = C.RxGroup_RxBurst(C.EthRxFlow_RxBurst)
+	rxf.c.base.rxThread = C.int(thread)
+	rxf.c.port = priv.port
+	rxf.c.queue = C.uint16_t(queue)
+	rxf.c.faceId = priv.faceId
+	rxf.c.hdrLen = priv.ethHdrLen
+	if priv.ipVersion != 0 {
+		rxf.c.ipLen = priv.txHdrLen - priv.ethHdrLen - C.sizeof_struct_rte_udp_hdr
+	}
+	return rxf
+}
+
+func (rxf *RxFlow) close() {
+	dpdk.Free(rxf.c)
+	rxf.c = nil
 }
 
 func (rxf *RxFlow) GetNumaSocket() dpdk.NumaSocket {
-	return rxf.face.GetNumaSocket()
+	return dpdk.EthDev(rxf.c.port).GetNumaSocket()
 }
 
 func (rxf *RxFlow) ListFaces() []iface.FaceId {
-	return []iface.FaceId{rxf.face.GetFaceId()}
+	return []iface.FaceId{iface.FaceId(rxf.c.faceId)}
 }
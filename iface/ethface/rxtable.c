@@ -1,32 +1,106 @@
 #include "rxtable.h"
 #include "eth-face.h"
 
+static bool
+EthRxTable_AcceptUdp(EthRxTable* rxt,
+                     struct rte_mbuf* frame,
+                     uint16_t ethHdrLen,
+                     rte_be16_t etherType)
+{
+  rte_pktmbuf_adj(frame, ethHdrLen);
+  const uint8_t* remoteIp;
+  const uint8_t* localIp;
+  uint8_t ipVersion;
+  uint16_t ipLen;
+  if (etherType == rte_cpu_to_be_16(RTE_ETHER_TYPE_IPV4)) {
+    const struct rte_ipv4_hdr* ip =
+      rte_pktmbuf_mtod(frame, const struct rte_ipv4_hdr*);
+    if (frame->data_len < sizeof(*ip) || ip->version_ihl != 0x45 ||
+        ip->next_proto_id != IPPROTO_UDP ||
+        (ip->fragment_offset & rte_cpu_to_be_16(0x3FFF)) != 0) {
+      return false;
+    }
+    remoteIp = (const uint8_t*)&ip->src_addr;
+    localIp = (const uint8_t*)&ip->dst_addr;
+    ipVersion = 4;
+    ipLen = sizeof(*ip);
+  } else {
+    const struct rte_ipv6_hdr* ip =
+      rte_pktmbuf_mtod(frame, const struct rte_ipv6_hdr*);
+    if (frame->data_len < sizeof(*ip) || ip->proto != IPPROTO_UDP) {
+      return false;
+    }
+    remoteIp = ip->src_addr;
+    localIp = ip->dst_addr;
+    ipVersion = 6;
+    ipLen = sizeof(*ip);
+  }
+  uint8_t ipAddrLen = ipVersion == 4 ? 4 : 16;
+
+  EthRxUdpSlot* slot = &rxt->udp[remoteIp[ipAddrLen - 1]];
+  frame->port = atomic_load_explicit(&slot->faceId, memory_order_acquire);
+  if (frame->port == FACEID_INVALID || slot->ipVersion != ipVersion ||
+      memcmp(slot->remoteIp, remoteIp, ipAddrLen) != 0 ||
+      memcmp(slot->localIp, localIp, ipAddrLen) != 0 ||
+      frame->data_len < ipLen + sizeof(struct rte_udp_hdr)) {
+    return false;
+  }
+  const struct rte_udp_hdr* udp =
+    rte_pktmbuf_mtod_offset(frame, const struct rte_udp_hdr*, ipLen);
+  if (udp->src_port != slot->remotePort || udp->dst_port != slot->localPort) {
+    return false;
+  }
+  return EthFace_StripUdp(frame, ipLen);
+}
+
 static bool
 EthRxTable_Accept(EthRxTable* rxt, struct rte_mbuf* frame, uint64_t now)
 {
   assert(frame->data_len >= sizeof(EthFaceEtherHdr));
   const EthFaceEtherHdr* hdr = rte_pktmbuf_mtod(frame, const EthFaceEtherHdr*);
 
-  if (rte_is_multicast_ether_addr(&hdr->eth.d_addr)) {
-    frame->port = atomic_load_explicit(&rxt->multicast, memory_order_relaxed);
+  uint16_t hdrLen;
+  rte_be16_t etherType;
+  if (hdr->eth.ether_type != rte_cpu_to_be_16(RTE_ETHER_TYPE_VLAN) &&
+      hdr->eth.ether_type != rte_cpu_to_be_16(RTE_ETHER_TYPE_QINQ)) {
+    hdrLen = offsetof(EthFaceEtherHdr, vlan0);
+    etherType = hdr->eth.ether_type;
+  } else if (hdr->vlan0.eth_proto != rte_cpu_to_be_16(RTE_ETHER_TYPE_VLAN)) {
+    hdrLen = offsetof(EthFaceEtherHdr, vlan1);
+    etherType = hdr->vlan0.eth_proto;
   } else {
-    uint8_t srcLastOctet = hdr->eth.s_addr.addr_bytes[5];
-    frame->port =
-      atomic_load_explicit(&rxt->unicast[srcLastOctet], memory_order_relaxed);
+    hdrLen = sizeof(EthFaceEtherHdr);
+    etherType = hdr->vlan1.eth_proto;
   }
 
-  if (likely(hdr->eth.ether_type == rte_cpu_to_be_16(NDN_ETHERTYPE))) {
-    rte_pktmbuf_adj(frame, offsetof(EthFaceEtherHdr, vlan0));
-  } else if (likely(hdr->vlan0.eth_proto == rte_cpu_to_be_16(NDN_ETHERTYPE))) {
-    rte_pktmbuf_adj(frame, offsetof(EthFaceEtherHdr, vlan1));
-  } else if (likely(hdr->vlan1.eth_proto == rte_cpu_to_be_16(NDN_ETHERTYPE))) {
-    rte_pktmbuf_adj(frame, sizeof(EthFaceEtherHdr));
-  } else {
+  if (unlikely(etherType != rte_cpu_to_be_16(NDN_ETHERTYPE))) {
+    if ((etherType == rte_cpu_to_be_16(RTE_ETHER_TYPE_IPV4) ||
+         etherType == rte_cpu_to_be_16(RTE_ETHER_TYPE_IPV6)) &&
+        EthRxTable_AcceptUdp(rxt, frame, hdrLen, etherType)) {
+      frame->timestamp = now;
+      return true;
+    }
     rte_pktmbuf_free(frame);
     return false;
   }
 
   frame->timestamp = now;
+  if (rte_is_multicast_ether_addr(&hdr->eth.d_addr)) {
+    frame->port = atomic_load_explicit(&rxt->multicast, memory_order_relaxed);
+  } else {
+    uint8_t srcLastOctet = hdr->eth.s_addr.addr_bytes[5];
+    frame->port =
+      atomic_load_explicit(&rxt->unicast[srcLastOctet], memory_order_relaxed);
+    if (unlikely(frame->port == FACEID_INVALID) && rxt->newcomers != NULL &&
+        rte_is_same_ether_addr(&hdr->eth.d_addr, &rxt->local)) {
+      if (unlikely(rte_ring_enqueue(rxt->newcomers, frame) != 0)) {
+        rte_pktmbuf_free(frame);
+      }
+      return false;
+    }
+  }
+
+  rte_pktmbuf_adj(frame, hdrLen);
   return true;
 }
 
@@ -34,7 +108,13 @@ uint16_t
 EthRxTable_RxBurst(RxGroup* rxg, struct rte_mbuf** pkts, uint16_t nPkts)
 {
   EthRxTable* rxt = (EthRxTable*)rxg;
-  uint16_t nInput = rte_eth_rx_burst(rxt->port, rxt->queue, pkts, nPkts);
+  uint16_t nInput = 0;
+  if (unlikely(rxt->readmitted != NULL)) {
+    nInput =
+      rte_ring_dequeue_burst(rxt->readmitted, (void**)pkts, nPkts, NULL);
+  }
+  nInput +=
+    rte_eth_rx_burst(rxt->port, rxt->queue, &pkts[nInput], nPkts - nInput);
   uint64_t now = rte_get_tsc_cycles();
   uint16_t nRx = 0;
   for (uint16_t i = 0; i < nInput; ++i) {
@@ -5,6 +5,7 @@ package ethface
 */
 import "C"
 import (
+	"encoding/binary"
 	"fmt"
 	"unsafe"
 
@@ -14,7 +15,7 @@ import (
 
 type rxTableImpl struct {
 	port *Port
-	rxt  *RxTable
+	rxts []*RxTable // one RxTable per RX queue
 }
 
 func (*rxTableImpl) String() string {
@@ -27,40 +28,70 @@ func (*rxTableImpl) New(port *Port) iImpl {
 	return impl
 }
 
-func (impl *rxTableImpl) Init() error {
-	if e := startDev(impl.port, 1, true); e != nil {
+func (impl *rxTableImpl) Init() (e error) {
+	if e = startDev(impl.port, impl.port.cfg.RxQueues, true, true); e != nil {
 		return e
 	}
-	impl.rxt = newRxTable(impl.port)
+	for queue := 0; queue < impl.port.cfg.RxQueues; queue++ {
+		rxt, e := newRxTable(impl.port, queue)
+		if e != nil {
+			return e
+		}
+		impl.rxts = append(impl.rxts, rxt)
+	}
 	return nil
 }
 
-func (impl *rxTableImpl) setFace(slot *C.FaceId, faceId iface.FaceId) error {
-	oldFaceId := iface.FaceId(*slot)
-	if impl.port.faces[oldFaceId] != nil {
-		return fmt.Errorf("new face %d conflicts with old face %d", faceId, oldFaceId)
+// Get pointer to the slot of a face in every RxTable.
+func (impl *rxTableImpl) getSlots(face *EthFace) (slots []*C.FaceId) {
+	for _, rxt := range impl.rxts {
+		switch {
+		case face.udp != nil:
+			slots = append(slots, &rxt.getUdpSlot(*face.udp).faceId)
+		case face.loc.Remote.IsGroup():
+			slots = append(slots, &rxt.c.multicast)
+		default:
+			slots = append(slots, &rxt.c.unicast[face.loc.Remote.Bytes[5]])
+		}
 	}
-	*slot = C.FaceId(faceId)
-	return nil
+	return slots
 }
 
 func (impl *rxTableImpl) Start(face *EthFace) error {
-	if face.loc.Remote.IsGroup() {
-		return impl.setFace(&impl.rxt.c.multicast, face.GetFaceId())
+	faceId := face.GetFaceId()
+	slots := impl.getSlots(face)
+	for _, slot := range slots {
+		oldFaceId := iface.FaceId(*slot)
+		if impl.port.faces[oldFaceId] != nil {
+			return fmt.Errorf("new face %d conflicts with old face %d", faceId, oldFaceId)
+		}
+	}
+	if face.udp != nil {
+		// fill UDP slots before publishing FaceId
+		for _, rxt := range impl.rxts {
+			rxt.initUdpSlot(*face.udp)
+		}
+	}
+	for _, slot := range slots {
+		*slot = C.FaceId(faceId)
 	}
-	lastOctet := face.loc.Remote.Bytes[5]
-	return impl.setFace(&impl.rxt.c.unicast[lastOctet], face.GetFaceId())
+	return nil
 }
 
 func (impl *rxTableImpl) Stop(face *EthFace) error {
+	for _, slot := range impl.getSlots(face) {
+		if iface.FaceId(*slot) == face.GetFaceId() {
+			*slot = C.FaceId(iface.FACEID_INVALID)
+		}
+	}
 	return nil
 }
 
 func (impl *rxTableImpl) Close() error {
-	if impl.rxt != nil {
-		impl.rxt.Close()
-		impl.rxt = nil
+	for _, rxt := range impl.rxts {
+		rxt.Close()
 	}
+	impl.rxts = nil
 	impl.port.dev.Stop()
 	return nil
 }
@@ -68,29 +99,97 @@ func (impl *rxTableImpl) Close() error {
 // Table-based software RX dispatching.
 type RxTable struct {
 	iface.RxGroupBase
-	c *C.EthRxTable
+	c          *C.EthRxTable
+	newcomers  dpdk.Ring // unicast frames from unknown senders, only if port is on-demand
+	readmitted dpdk.Ring // frames to be dispatched again, only if port is on-demand
 }
 
-func newRxTable(port *Port) (rxt *RxTable) {
+func newRxTable(port *Port, queue int) (rxt *RxTable, e error) {
 	rxt = new(RxTable)
 	rxt.c = (*C.EthRxTable)(dpdk.Zmalloc("EthRxTable", C.sizeof_EthRxTable, port.dev.GetNumaSocket()))
 	rxt.InitRxgBase(unsafe.Pointer(rxt.c))
 
 	rxt.c.port = C.uint16_t(port.dev)
-	rxt.c.queue = 0
+	rxt.c.queue = C.uint16_t(queue)
 	rxt.c.base.rxBurstOp = C.RxGroup_RxBurst(C.EthRxTable_RxBurst)
-	rxt.c.base.rxThread = 0
+	rxt.c.base.rxThread = C.int(queue)
+	port.cfg.Local.CopyToC(unsafe.Pointer(&rxt.c.local))
+
+	if port.cfg.OnDemand {
+		if e = rxt.makeOnDemandRings(port, queue); e != nil {
+			rxt.freeOnDemandRings()
+			dpdk.Free(rxt.c)
+			return nil, e
+		}
+	}
 
 	iface.EmitRxGroupAdd(rxt)
-	return rxt
+	return rxt, nil
+}
+
+const onDemandRingCapacity = 64
+
+func (rxt *RxTable) makeOnDemandRings(port *Port, queue int) (e error) {
+	socket := port.dev.GetNumaSocket()
+	if rxt.newcomers, e = dpdk.NewRing(fmt.Sprintf("EthRxTable%d-%d_newcomers", port.dev, queue),
+		onDemandRingCapacity, socket, true, true); e != nil {
+		return e
+	}
+	rxt.c.newcomers = (*C.struct_rte_ring)(rxt.newcomers.GetPtr())
+	if rxt.readmitted, e = dpdk.NewRing(fmt.Sprintf("EthRxTable%d-%d_readmitted", port.dev, queue),
+		onDemandRingCapacity, socket, true, true); e != nil {
+		return e
+	}
+	rxt.c.readmitted = (*C.struct_rte_ring)(rxt.readmitted.GetPtr())
+	return nil
+}
+
+func (rxt *RxTable) freeOnDemandRings() {
+	for _, r := range []*dpdk.Ring{&rxt.newcomers, &rxt.readmitted} {
+		if r.GetPtr() == nil {
+			continue
+		}
+		frames := make([]dpdk.Packet, onDemandRingCapacity)
+		for {
+			n, _ := r.BurstDequeue(frames)
+			if n == 0 {
+				break
+			}
+			for _, frame := range frames[:n] {
+				frame.Close()
+			}
+		}
+		r.Close()
+		*r = dpdk.Ring{}
+	}
+	rxt.c.newcomers = nil
+	rxt.c.readmitted = nil
 }
 
 func (rxt *RxTable) Close() error {
 	iface.EmitRxGroupRemove(rxt)
+	rxt.freeOnDemandRings()
 	dpdk.Free(rxt.c)
 	return nil
 }
 
+// Get the slot of a UDP face.
+func (rxt *RxTable) getUdpSlot(loc UdpLocator) *C.EthRxUdpSlot {
+	_, _, remoteIP := loc.getIPs()
+	return &rxt.c.udp[remoteIP[len(remoteIP)-1]]
+}
+
+// Fill the slot of a UDP face, except FaceId.
+func (rxt *RxTable) initUdpSlot(loc UdpLocator) {
+	slot := rxt.getUdpSlot(loc)
+	ipVersion, localIP, remoteIP := loc.getIPs()
+	slot.ipVersion = C.uint8_t(ipVersion)
+	binary.BigEndian.PutUint16((*[2]byte)(unsafe.Pointer(&slot.localPort))[:], uint16(loc.LocalUDP))
+	binary.BigEndian.PutUint16((*[2]byte)(unsafe.Pointer(&slot.remotePort))[:], uint16(loc.RemoteUDP))
+	copy((*[16]byte)(unsafe.Pointer(&slot.localIp))[:], localIP)
+	copy((*[16]byte)(unsafe.Pointer(&slot.remoteIp))[:], remoteIP)
+}
+
 func (rxt *RxTable) GetNumaSocket() dpdk.NumaSocket {
 	return dpdk.EthDev(rxt.c.port).GetNumaSocket()
 }
@@ -103,6 +202,9 @@ func (rxt *RxTable) ListFaces() (list []iface.FaceId) {
 		if rxt.c.unicast[j] != 0 {
 			list = append(list, iface.FaceId(rxt.c.unicast[j]))
 		}
+		if rxt.c.udp[j].faceId != 0 {
+			list = append(list, iface.FaceId(rxt.c.udp[j].faceId))
+		}
 	}
 	return list
 }
@@ -3,8 +3,21 @@
 
 /// \file
 
+#include "../../dpdk/ethdev.h"
 #include "../rxloop.h"
 
+/** \brief RxTable slot of a UDP face.
+ */
+typedef struct EthRxUdpSlot
+{
+  _Atomic FaceId faceId;
+  uint8_t ipVersion;
+  rte_be16_t localPort;
+  rte_be16_t remotePort;
+  uint8_t localIp[16];
+  uint8_t remoteIp[16];
+} EthRxUdpSlot;
+
 /** \brief Table-based software RX dispatching.
  */
 typedef struct EthRxTable
@@ -15,6 +28,18 @@ typedef struct EthRxTable
   _Atomic FaceId multicast; ///< multicast face
   _Atomic FaceId
     unicast[256]; ///< unicast faces, by last octet of sender address
+  EthRxUdpSlot udp[256]; ///< UDP faces, by last octet of remote IP address
+
+  struct rte_ether_addr local; ///< local address
+  /** \brief Unicast frames from unknown senders.
+   *
+   *  This is NULL if on-demand faces are disabled.
+   *  Frames are enqueued with Ethernet header intact.
+   */
+  struct rte_ring* newcomers;
+  /** \brief Frames to be dispatched again after creating on-demand faces.
+   */
+  struct rte_ring* readmitted;
 } EthRxTable;
 
 uint16_t
@@ -0,0 +1,116 @@
+package ethface
+
+import (
+	"errors"
+	"net"
+
+	"ndn-dpdk/iface"
+)
+
+const udpLocatorScheme = "udpe"
+
+// Default UDP port number of NDN.
+const UDP_PORT_DEFAULT = 6363
+
+// Locator of a face that encapsulates NDN packets in UDP/IP over a DPDK Ethernet port.
+//
+// Remote is the MAC address of the next hop toward RemoteIP, which could be a router or the peer itself.
+// LocalIP and RemoteIP must be both IPv4 or both IPv6.
+type UdpLocator struct {
+	Locator
+	LocalIP   net.IP
+	RemoteIP  net.IP
+	LocalUDP  int `json:",omitempty"` // local UDP port, default 6363
+	RemoteUDP int `json:",omitempty"` // remote UDP port, default 6363
+}
+
+func NewUdpLocator(loc Locator, localIP, remoteIP net.IP) (udpLoc UdpLocator) {
+	udpLoc.Locator = loc
+	udpLoc.Scheme = udpLocatorScheme
+	udpLoc.LocalIP = localIP
+	udpLoc.RemoteIP = remoteIP
+	return udpLoc
+}
+
+func (loc UdpLocator) Validate() error {
+	if e := loc.Locator.Validate(); e != nil {
+		return e
+	}
+	if !loc.Remote.IsUnicast() {
+		return errors.New("Remote is not unicast")
+	}
+	localIP, remoteIP := loc.LocalIP.To4(), loc.RemoteIP.To4()
+	if (localIP == nil) != (remoteIP == nil) {
+		return errors.New("LocalIP and RemoteIP must have the same address family")
+	}
+	if localIP == nil && (len(loc.LocalIP) != net.IPv6len || len(loc.RemoteIP) != net.IPv6len) {
+		return errors.New("LocalIP and RemoteIP must be IPv4 or IPv6 addresses")
+	}
+	for _, ip := range []net.IP{loc.LocalIP, loc.RemoteIP} {
+		if !ip.IsGlobalUnicast() && !ip.IsLinkLocalUnicast() && !ip.IsLoopback() {
+			return errors.New("LocalIP and RemoteIP must be unicast addresses")
+		}
+	}
+	for _, port := range []int{loc.LocalUDP, loc.RemoteUDP} {
+		if port < 0 || port > 0xFFFF {
+			return errors.New("UDP port is out of range")
+		}
+	}
+	return nil
+}
+
+func (loc UdpLocator) applyDefaults() UdpLocator {
+	if loc.LocalUDP == 0 {
+		loc.LocalUDP = UDP_PORT_DEFAULT
+	}
+	if loc.RemoteUDP == 0 {
+		loc.RemoteUDP = UDP_PORT_DEFAULT
+	}
+	return loc
+}
+
+// Get IP version (4 or 6) and addresses in wire format.
+func (loc UdpLocator) getIPs() (ipVersion int, localIP, remoteIP net.IP) {
+	if localIP, remoteIP = loc.LocalIP.To4(), loc.RemoteIP.To4(); localIP != nil {
+		return 4, localIP, remoteIP
+	}
+	return 6, loc.LocalIP.To16(), loc.RemoteIP.To16()
+}
+
+// Determine whether two UDP locators would receive the same packets.
+func (loc UdpLocator) conflictsWith(other UdpLocator) bool {
+	return loc.RemoteIP.Equal(other.RemoteIP) && loc.LocalIP.Equal(other.LocalIP) &&
+		loc.LocalUDP == other.LocalUDP && loc.RemoteUDP == other.RemoteUDP
+}
+
+func init() {
+	iface.RegisterLocatorType(UdpLocator{}, udpLocatorScheme)
+}
+
+// Create a UDP face from locator.
+// cfg is only used for initial port creation, and would be ignored if port exists.
+func CreateUdp(loc UdpLocator, cfg PortConfig) (face *EthFace, e error) {
+	if e = loc.Validate(); e != nil {
+		return nil, e
+	}
+
+	port, e := findOrCreatePort(loc.Locator, cfg)
+	if e != nil {
+		return nil, e
+	}
+	return NewUdp(port, loc)
+}
+
+// Create a UDP face on an existing port.
+func NewUdp(port *Port, loc UdpLocator) (face *EthFace, e error) {
+	if e = loc.Validate(); e != nil {
+		return nil, e
+	}
+	loc = loc.applyDefaults()
+	return newFace(port, loc.Locator, &loc, false)
+}
+
+// Determine whether the face uses UDP encapsulation.
+func (face *EthFace) IsUdp() bool {
+	return face.udp != nil
+}
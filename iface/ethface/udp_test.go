@@ -0,0 +1,102 @@
+package ethface_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/ethface"
+	"ndn-dpdk/iface/ifacetestfixture"
+)
+
+func TestUdp(t *testing.T) {
+	assert, require := dpdktestenv.MakeAR(t)
+
+	mp, mempools := ifacetestfixture.MakeMempools()
+
+	var evnCfg dpdktestenv.EthVNetConfig
+	evnCfg.NNodes = 2
+	evnCfg.NQueues = 1
+	evn := dpdktestenv.NewEthVNet(evnCfg)
+	defer func() {
+		for _, port := range ethface.ListPorts() {
+			port.Close()
+		}
+		evn.Close()
+	}()
+
+	macA, _ := dpdk.ParseEtherAddr("02:00:00:00:00:01")
+	macB, _ := dpdk.ParseEtherAddr("02:00:00:00:00:02")
+
+	var cfg ethface.PortConfig
+	cfg.Mempools = mempools
+	cfg.RxMp = mp
+	cfg.RxqFrames = 64
+	cfg.TxqPkts = 64
+	cfg.TxqFrames = 64
+	cfg.Mtu = 1500
+
+	makeLoc := func(dev dpdk.EthDev, local, remote dpdk.EtherAddr, localIP, remoteIP string) ethface.UdpLocator {
+		loc := ethface.NewLocator(dev)
+		loc.Local = local
+		loc.Remote = remote
+		return ethface.NewUdpLocator(loc, net.ParseIP(localIP), net.ParseIP(remoteIP))
+	}
+
+	locBad := makeLoc(evn.Ports[0], macA, macB, "192.0.2.1", "2001:db8::2")
+	assert.Error(locBad.Validate())
+	locBad = makeLoc(evn.Ports[0], macA, macB, "192.0.2.1", "224.0.0.1")
+	assert.Error(locBad.Validate())
+	locBad = makeLoc(evn.Ports[0], macA, dpdk.EtherAddr{}, "192.0.2.1", "192.0.2.2")
+	assert.Error(locBad.Validate())
+	locBad = makeLoc(evn.Ports[0], macA, macB, "192.0.2.1", "192.0.2.2")
+	locBad.RemoteUDP = 65536
+	assert.Error(locBad.Validate())
+
+	makeFace := func(loc ethface.UdpLocator) *ethface.EthFace {
+		face, e := ethface.CreateUdp(loc, cfg)
+		require.NoError(e, "%s %s", loc.LocalIP, loc.RemoteIP)
+		return face
+	}
+
+	faceA4 := makeFace(makeLoc(evn.Ports[0], macA, macB, "192.0.2.1", "192.0.2.2"))
+	faceB4 := makeFace(makeLoc(evn.Ports[1], macB, macA, "192.0.2.2", "192.0.2.1"))
+	faceA6 := makeFace(makeLoc(evn.Ports[0], macA, macB, "2001:db8::1", "2001:db8::2"))
+	faceB6 := makeFace(makeLoc(evn.Ports[1], macB, macA, "2001:db8::2", "2001:db8::1"))
+
+	_, e := ethface.CreateUdp(makeLoc(evn.Ports[0], macA, macB, "192.0.2.1", "192.0.2.2"), cfg)
+	assert.Error(e) // same endpoints as faceA4
+
+	loc4 := faceA4.GetLocator().(ethface.UdpLocator)
+	assert.Equal("udpe", loc4.Scheme)
+	assert.True(loc4.Remote.Equal(macB))
+	assert.Equal(6363, loc4.LocalUDP)
+	assert.Equal(6363, loc4.RemoteUDP)
+	assert.True(faceA4.IsUdp())
+
+	// ByteBurst must not be less than MTU
+	assert.Error(faceA4.SetRateLimit(&iface.RateLimitConfig{BytesPerSecond: 1000000, ByteBurst: 1000}))
+	assert.NoError(faceA4.SetRateLimit(&iface.RateLimitConfig{BytesPerSecond: 1000000, ByteBurst: 1500}))
+	assert.NoError(faceA4.SetRateLimit(nil))
+
+	// NDN over Ethernet face can coexist with UDP faces toward the same MAC address
+	locEther := ethface.NewLocator(evn.Ports[0])
+	locEther.Remote = macB
+	faceAe, e := ethface.Create(locEther, cfg)
+	require.NoError(e)
+	assert.False(faceAe.IsUdp())
+
+	evn.LaunchBridge(dpdk.ListSlaveLCores()[3])
+	time.Sleep(time.Second)
+
+	fixture4 := ifacetestfixture.New(t, faceA4, faceB4)
+	fixture4.RunTest()
+	fixture4.CheckCounters()
+
+	fixture6 := ifacetestfixture.New(t, faceA6, faceB6)
+	fixture6.RunTest()
+	fixture6.CheckCounters()
+}
@@ -93,7 +93,8 @@ func (face *FaceBase) FinishInitFaceBase(txQueueCapacity, mtu, headroom int, mem
 		return dpdk.Errno(res)
 	}
 
-	if res := C.RxProc_Init(&faceC.impl.rx, (*C.struct_rte_mempool)(mempools.NameMp.GetPtr())); res != 0 {
+	if res := C.RxProc_Init(&faceC.impl.rx, (*C.struct_rte_mempool)(mempools.NameMp.GetPtr()),
+		C.int(face.GetNumaSocket())); res != 0 {
 		face.clear()
 		return dpdk.Errno(res)
 	}
@@ -101,6 +102,18 @@ func (face *FaceBase) FinishInitFaceBase(txQueueCapacity, mtu, headroom int, mem
 	return nil
 }
 
+// Enable NDNLPv2 reassembly on RX threads 0 to nThreads-1.
+// FinishInitFaceBase enables RX thread 0 only.
+// A lower layer that receives frames on multiple RX threads must call this function,
+// otherwise fragments arriving on other RX threads are dropped.
+func (face *FaceBase) EnableRxThreads(nThreads int) error {
+	faceC := face.getPtr()
+	if res := C.RxProc_EnableThreads(&faceC.impl.rx, C.int(nThreads), C.int(face.GetNumaSocket())); res != 0 {
+		return dpdk.Errno(res)
+	}
+	return nil
+}
+
 func (face *FaceBase) GetFaceId() FaceId {
 	return face.id
 }
@@ -126,6 +139,13 @@ func (face *FaceBase) clear() {
 	faceC := face.getPtr()
 	faceC.state = C.FACESTA_REMOVED
 	if faceC.impl != nil {
+		face.clearAcl()
+		face.clearLpReliability()
+		face.clearCongMark()
+		face.clearShaper()
+		face.clearBundle()
+		face.clearCapture()
+		C.RxProc_Close(&faceC.impl.rx)
 		dpdk.Free(faceC.impl)
 	}
 	if faceC.txQueue != nil {
@@ -166,5 +186,5 @@ func (face *FaceBase) TxBurst(pkts []ndn.Packet) {
 	if len(pkts) == 0 {
 		return
 	}
-	C.Face_TxBurst(C.FaceId(face.id), (**C.Packet)(unsafe.Pointer(&pkts[0])), C.uint16_t(len(pkts)))
+	face.txBurstLocked(pkts)
 }
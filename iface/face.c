@@ -20,10 +20,21 @@ FaceImpl_RxBurst(FaceRxBurst* burst,
       continue;
     }
 
+    FaceCapture* capture = rcu_dereference(face->capture);
+    if (unlikely(capture != NULL)) {
+      FaceCapture_PostFrames(capture, FaceCaptureDir_Rx, &frame, 1);
+    }
+
     Packet* npkt = RxProc_Input(&face->impl->rx, rxThread, frame);
     if (npkt == NULL) {
       continue;
     }
+    if (unlikely(capture != NULL)) {
+      FaceCapture_Post(capture, FaceCaptureDir_Rx, &npkt, 1);
+    }
+    if (face->bundleId != FACEID_INVALID) {
+      Packet_ToMbuf(npkt)->port = face->bundleId;
+    }
 
     L3PktType l3type = Packet_GetL3PktType(npkt);
     switch (l3type) {
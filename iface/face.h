@@ -3,6 +3,8 @@
 
 /// \file
 
+#include "bundle.h"
+#include "capture.h"
 #include "faceid.h"
 #include "rx-proc.h"
 #include "rxburst.h"
@@ -42,6 +44,11 @@ typedef struct Face
 
   struct rte_ring* txQueue;
   struct cds_hlist_node txlNode;
+
+  Bundle* bundle;  ///< (RCU) member list, non-NULL if this is a bundle face
+  FaceId bundleId; ///< bundle face containing this face, or FACEID_INVALID
+
+  FaceCapture* capture; ///< (RCU) packet capture, or NULL
 } __rte_cache_aligned Face;
 
 static inline void*
@@ -85,16 +92,28 @@ typedef void (*Face_RxCb)(FaceRxBurst* burst, void* cbarg);
  *  \param count size of \p npkts array
  *
  *  This function is thread-safe.
+ *  \pre Calling thread is registered as RCU read-side thread, or holds the
+ *       Go bundle lock.
  */
 static inline void
 Face_TxBurst(FaceId faceId, Packet** npkts, uint16_t count)
 {
   Face* face = Face_Get_(faceId);
+  rcu_read_lock();
+  Bundle* bundle = rcu_dereference(face->bundle);
+  if (unlikely(bundle != NULL)) {
+    Bundle_TxBurst(bundle, npkts, count);
+    rcu_read_unlock();
+    return;
+  }
+  rcu_read_unlock();
+
   if (unlikely(face->state != FACESTA_UP)) {
     FreeMbufs((struct rte_mbuf**)npkts, count);
     return;
   }
 
+  CongMark_StampEnqueue(npkts, count, rte_get_tsc_cycles());
   uint16_t nQueued =
     rte_ring_enqueue_burst(face->txQueue, (void**)npkts, count, NULL);
   uint16_t nRejects = count - nQueued;
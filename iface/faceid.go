@@ -15,6 +15,8 @@ const (
 	FaceKind_None   FaceKind = -1
 	FaceKind_Mock   FaceKind = 0x0
 	FaceKind_Eth    FaceKind = 0x1
+	FaceKind_Bundle FaceKind = 0xB
+	FaceKind_Ws     FaceKind = 0xD
 	FaceKind_Socket FaceKind = 0xE
 )
 
@@ -22,6 +24,8 @@ var faceKinds = map[FaceKind]string{
 	FaceKind_None:   "none",
 	FaceKind_Mock:   "mock",
 	FaceKind_Eth:    "eth",
+	FaceKind_Bundle: "bundle",
+	FaceKind_Ws:     "ws",
 	FaceKind_Socket: "socket",
 }
 
@@ -36,6 +36,46 @@ type IFace interface {
 	// Determine whether the face is DOWN or UP.
 	IsDown() bool
 
+	// Determine whether the face is local.
+	// A non-local face drops incoming packets under /localhost prefix.
+	IsLocal() bool
+
+	// Get name-based access control list rules.
+	GetAcl() []AclRule
+
+	// Set name-based access control list on incoming packets.
+	SetAcl(rules []AclRule) error
+
+	// Get NDNLPv2 link-layer reliability configuration, nil if disabled.
+	GetLpReliability() *LpReliabilityConfig
+
+	// Enable, reconfigure, or disable NDNLPv2 link-layer reliability.
+	SetLpReliability(cfg *LpReliabilityConfig) error
+
+	// Get congestion marking configuration, nil if disabled.
+	GetCongMark() *CongMarkConfig
+
+	// Enable, reconfigure, or disable congestion marking on outgoing packets.
+	SetCongMark(cfg *CongMarkConfig) error
+
+	// Get rate limit configuration, nil if disabled.
+	GetRateLimit() *RateLimitConfig
+
+	// Enable, reconfigure, or disable rate limiting on outgoing packets.
+	SetRateLimit(cfg *RateLimitConfig) error
+
+	// Get packet capture configuration, nil if not capturing.
+	GetCapture() *CaptureConfig
+
+	// Start capturing packets into a pcapng file.
+	StartCapture(filename string, cfg CaptureConfig) error
+
+	// Stop capturing packets, and close the pcapng file.
+	StopCapture() (CaptureCounters, error)
+
+	// Get FaceId of the bundle face containing this face, or FACEID_INVALID.
+	GetBundleId() FaceId
+
 	// Get RxGroups that contain this face.
 	ListRxGroups() []IRxGroup
 
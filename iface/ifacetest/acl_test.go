@@ -0,0 +1,84 @@
+package ifacetest
+
+import (
+	"testing"
+	"time"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+func TestAcl(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := mockface.New()
+	defer face.Close()
+	assert.True(face.IsLocal())
+	face.SetLocal(false)
+	assert.False(face.IsLocal())
+
+	assert.Error(face.SetAcl(make([]iface.AclRule, iface.ACL_MAX_RULES+1)))
+	require.NoError(face.SetAcl([]iface.AclRule{
+		{Prefix: ndn.MustParseName("/A/B"), Deny: true},
+		{Prefix: ndn.MustParseName("/A")},
+		{Prefix: ndn.MustParseName("/"), Deny: true},
+	}))
+	if acl := face.GetAcl(); assert.Len(acl, 3) {
+		ndntestutil.NameEqual(assert, "/A/B", acl[0].Prefix)
+		assert.True(acl[0].Deny)
+	}
+
+	var rxNames []string
+	rxl := iface.NewRxLoop(dpdk.NUMA_SOCKET_ANY)
+	rxl.SetLCore(dpdk.ListSlaveLCores()[0])
+	rxl.SetCallback(iface.WrapRxCb(func(burst iface.RxBurst) {
+		for _, interest := range burst.ListInterests() {
+			rxNames = append(rxNames, interest.GetName().String())
+			ndntestutil.ClosePacket(interest)
+		}
+		for _, data := range burst.ListData() {
+			rxNames = append(rxNames, data.GetName().String())
+			ndntestutil.ClosePacket(data)
+		}
+		for _, nack := range burst.ListNacks() {
+			rxNames = append(rxNames, nack.GetInterest().GetName().String())
+			ndntestutil.ClosePacket(nack)
+		}
+	}))
+	require.NoError(rxl.Launch())
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(rxl.AddRxGroup(iface.TheChanRxGroup))
+
+	face.Rx(ndntestutil.MakeInterest("/A/B/1"))
+	face.Rx(ndntestutil.MakeInterest("/A/1"))
+	face.Rx(ndntestutil.MakeData("/A/2"))
+	face.Rx(ndn.MakeNackFromInterest(ndntestutil.MakeInterest("/A/B/3"), ndn.NackReason_NoRoute))
+	face.Rx(ndntestutil.MakeInterest("/C"))
+	face.Rx(ndntestutil.MakeInterest("/localhost/A"))
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal([]string{"/A/1", "/A/2"}, rxNames)
+	cnt := face.ReadCounters()
+	assert.Equal([]uint64{2, 2, 1}, cnt.Acl.Hits)
+	assert.Equal(uint64(3), cnt.Acl.Drops)
+	assert.Equal(uint64(1), cnt.Acl.LocalhostDrops)
+
+	require.NoError(face.SetAcl(nil))
+	assert.Len(face.GetAcl(), 0)
+	face.SetLocal(true)
+	face.Rx(ndntestutil.MakeInterest("/C"))
+	face.Rx(ndntestutil.MakeInterest("/localhost/A"))
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal([]string{"/A/1", "/A/2", "/C", "/localhost/A"}, rxNames)
+	cnt = face.ReadCounters()
+	assert.Len(cnt.Acl.Hits, 0)
+	assert.Equal(uint64(3), cnt.Acl.Drops)
+	assert.Equal(uint64(1), cnt.Acl.LocalhostDrops)
+
+	rxl.Stop()
+	rxl.Close()
+}
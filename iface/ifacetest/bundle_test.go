@@ -0,0 +1,162 @@
+package ifacetest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/bundleface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+func TestBundle(t *testing.T) {
+	assert, require := makeAR(t)
+
+	faceA := mockface.New()
+	defer faceA.Close()
+	faceB := mockface.New()
+	defer faceB.Close()
+	faceC := mockface.New()
+	defer faceC.Close()
+	faceD := mockface.New()
+	defer faceD.Close()
+
+	_, e := bundleface.New(bundleface.NewLocator(iface.BundlePolicy_NameHash), mockface.FaceMempools)
+	assert.Error(e)
+	_, e = bundleface.New(bundleface.NewLocator(iface.BundlePolicy_NameHash, faceA.GetFaceId(), faceA.GetFaceId()), mockface.FaceMempools)
+	assert.Error(e)
+
+	bundle, e := bundleface.New(bundleface.NewLocator(iface.BundlePolicy_NameHash, faceA.GetFaceId(), faceB.GetFaceId()), mockface.FaceMempools)
+	require.NoError(e)
+	defer bundle.Close()
+	assert.Equal(iface.FaceKind_Bundle, bundle.GetFaceId().GetKind())
+	assert.Equal(bundle.GetFaceId(), faceA.GetBundleId())
+	assert.Equal(bundle.GetFaceId(), faceB.GetBundleId())
+	assert.Equal(iface.FACEID_INVALID, faceC.GetBundleId())
+	assert.False(bundle.IsDown())
+	assert.Error(bundle.SetAcl([]iface.AclRule{{Prefix: ndn.MustParseName("/")}}))
+
+	// a member cannot join another bundle, and a bundle cannot be a member
+	_, e = bundleface.New(bundleface.NewLocator(iface.BundlePolicy_NameHash, faceA.GetFaceId()), mockface.FaceMempools)
+	assert.Error(e)
+	assert.Error(bundle.AddMember(bundle.GetFaceId()))
+
+	txl := iface.NewTxLoop(dpdk.NUMA_SOCKET_ANY)
+	txl.SetLCore(dpdk.ListSlaveLCores()[0])
+	require.NoError(txl.Launch())
+	defer txl.Close()
+	defer txl.Stop()
+	time.Sleep(10 * time.Millisecond)
+	txl.AddFace(faceA)
+	txl.AddFace(faceB)
+	txl.AddFace(faceC)
+	txl.AddFace(faceD)
+	time.Sleep(10 * time.Millisecond)
+
+	makeInterests := func(prefix string, n int) (pkts []ndn.Packet) {
+		for i := 0; i < n; i++ {
+			pkts = append(pkts, ndntestutil.MakeInterest(fmt.Sprintf("%s/%d", prefix, i)).GetPacket())
+		}
+		return pkts
+	}
+	countNames := func(faces ...*mockface.MockFace) map[string]int {
+		names := make(map[string]int)
+		for _, face := range faces {
+			for _, interest := range face.TxInterests {
+				names[fmt.Sprintf("%d%s", face.GetFaceId(), interest.GetName())]++
+			}
+		}
+		return names
+	}
+	resetTx := func() {
+		for _, face := range []*mockface.MockFace{faceA, faceB, faceC, faceD} {
+			for _, interest := range face.TxInterests {
+				ndntestutil.ClosePacket(interest)
+			}
+			face.TxInterests = nil
+		}
+	}
+
+	// name hash: packets with the same name go to the same member
+	bundle.TxBurst(makeInterests("/H", 20))
+	bundle.TxBurst(makeInterests("/H", 20))
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(faceA.TxInterests, 40-len(faceB.TxInterests))
+	assert.Len(countNames(faceA, faceB), 20)
+	resetTx()
+
+	// removed member can join another bundle
+	require.NoError(bundle.RemoveMember(faceB.GetFaceId()))
+	assert.Error(bundle.RemoveMember(faceB.GetFaceId()))
+	require.NoError(bundle.AddMember(faceC.GetFaceId()))
+	assert.Equal([]iface.FaceId{faceA.GetFaceId(), faceC.GetFaceId()}, bundle.GetLocator().(bundleface.Locator).Members)
+	bundle2, e := bundleface.New(bundleface.NewLocator(iface.BundlePolicy_RoundRobin, faceB.GetFaceId()), mockface.FaceMempools)
+	require.NoError(e)
+	defer bundle2.Close()
+	assert.Error(bundle2.AddMember(faceB.GetFaceId()))
+	require.NoError(bundle2.AddMember(faceD.GetFaceId()))
+
+	// round robin: packets are spread evenly
+	bundle2.TxBurst(makeInterests("/R", 10))
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(faceB.TxInterests, 5)
+	assert.Len(faceD.TxInterests, 5)
+	if exCnt, ok := bundle2.ReadExCounters().(bundleface.ExCounters); assert.True(ok) {
+		assert.Equal("roundrobin", exCnt.Policy)
+		assert.Equal(uint64(0), exCnt.NoMemberDrops)
+	}
+	resetTx()
+
+	// DOWN member is skipped
+	faceA.SetDown(true)
+	assert.False(bundle.IsDown())
+	bundle.TxBurst(makeInterests("/D", 10))
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(faceA.TxInterests, 0)
+	assert.Len(faceC.TxInterests, 10)
+	resetTx()
+
+	// bundle is DOWN when all members are DOWN
+	faceC.SetDown(true)
+	assert.True(bundle.IsDown())
+	bundle.TxBurst(makeInterests("/N", 3))
+	assert.Equal(uint64(3), bundle.ReadExCounters().(bundleface.ExCounters).NoMemberDrops)
+	faceA.SetDown(false)
+	assert.False(bundle.IsDown())
+	faceC.SetDown(false)
+
+	// closed member is removed
+	txl.RemoveFace(faceC)
+	faceC.Close()
+	assert.Equal([]iface.FaceId{faceA.GetFaceId()}, bundle.GetLocator().(bundleface.Locator).Members)
+
+	// RX on member is presented as RX on bundle
+	var rxFaces []iface.FaceId
+	rxl := iface.NewRxLoop(dpdk.NUMA_SOCKET_ANY)
+	rxl.SetLCore(dpdk.ListSlaveLCores()[1])
+	rxl.SetCallback(iface.WrapRxCb(func(burst iface.RxBurst) {
+		for _, interest := range burst.ListInterests() {
+			rxFaces = append(rxFaces, iface.FaceId(interest.GetPacket().AsDpdkPacket().GetPort()))
+			ndntestutil.ClosePacket(interest)
+		}
+	}))
+	require.NoError(rxl.Launch())
+	defer rxl.Close()
+	defer rxl.Stop()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(rxl.AddRxGroup(iface.TheChanRxGroup))
+
+	faceA.Rx(ndntestutil.MakeInterest("/X/1"))
+	faceB.Rx(ndntestutil.MakeInterest("/X/2"))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal([]iface.FaceId{bundle.GetFaceId(), bundle2.GetFaceId()}, rxFaces)
+	assert.Equal(uint64(1), bundle.ReadCounters().RxInterests)
+
+	// closing bundle releases members
+	bundle.Close()
+	assert.Equal(iface.FACEID_INVALID, faceA.GetBundleId())
+}
@@ -0,0 +1,147 @@
+package ifacetest
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+func TestCapture(t *testing.T) {
+	assert, require := makeAR(t)
+
+	dir, e := ioutil.TempDir("", "capture")
+	require.NoError(e)
+	defer os.RemoveAll(dir)
+	iface.CaptureDir = dir
+	filename := "capture.pcapng"
+
+	face := mockface.New()
+	defer face.Close()
+
+	txl := iface.NewTxLoop(dpdk.NUMA_SOCKET_ANY)
+	txl.SetLCore(dpdk.ListSlaveLCores()[0])
+	require.NoError(txl.Launch())
+	defer txl.Close()
+	defer txl.Stop()
+	time.Sleep(10 * time.Millisecond)
+	txl.AddFace(face)
+
+	rxl := iface.NewRxLoop(dpdk.NUMA_SOCKET_ANY)
+	rxl.SetLCore(dpdk.ListSlaveLCores()[1])
+	rxl.SetCallback(iface.WrapRxCb(func(burst iface.RxBurst) {
+		for _, interest := range burst.ListInterests() {
+			ndntestutil.ClosePacket(interest)
+		}
+		for _, data := range burst.ListData() {
+			ndntestutil.ClosePacket(data)
+		}
+	}))
+	require.NoError(rxl.Launch())
+	defer rxl.Close()
+	defer rxl.Stop()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(rxl.AddRxGroup(iface.TheChanRxGroup))
+
+	_, e = face.StopCapture()
+	assert.Error(e)
+	assert.Error(face.StartCapture(filename, iface.CaptureConfig{PacketTypes: []string{"Ack"}}))
+	assert.Error(face.StartCapture(filename, iface.CaptureConfig{Frames: true, PacketTypes: []string{"Data"}}))
+	assert.Error(face.StartCapture("../"+filename, iface.CaptureConfig{}))
+	assert.Error(face.StartCapture("/tmp/"+filename, iface.CaptureConfig{}))
+	assert.Error(face.StartCapture("..", iface.CaptureConfig{}))
+	assert.Nil(face.GetCapture())
+
+	cfg := iface.CaptureConfig{
+		Prefix:      ndn.MustParseName("/A"),
+		PacketTypes: []string{"Interest", "Data"},
+		MaxPackets:  4,
+	}
+	require.NoError(face.StartCapture(filename, cfg))
+	assert.Error(face.StartCapture(filename, cfg))
+	if cfg := face.GetCapture(); assert.NotNil(cfg) {
+		assert.Equal(4, cfg.MaxPackets)
+		assert.Equal(iface.CAPTURE_DEFAULT_SNAPLEN, cfg.Snaplen)
+	}
+
+	// outgoing packets under /A are captured
+	face.TxBurst([]ndn.Packet{
+		ndntestutil.MakeInterest("/A/0").GetPacket(),
+		ndntestutil.MakeInterest("/B/0").GetPacket(),
+		ndntestutil.MakeInterest("/A/1").GetPacket(),
+		ndntestutil.MakeInterest("/A/2").GetPacket(),
+	})
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(face.TxInterests, 4)
+
+	// incoming packets under /A are captured until MaxPackets
+	face.Rx(ndntestutil.MakeData("/A/3"))
+	face.Rx(ndntestutil.MakeInterest("/B/3"))
+	face.Rx(ndntestutil.MakeData("/A/4"))
+	time.Sleep(100 * time.Millisecond)
+
+	cnt, e := face.StopCapture()
+	require.NoError(e)
+	assert.Equal(uint64(4), cnt.Packets)
+	assert.Equal(uint64(0), cnt.Dropped)
+	assert.Nil(face.GetCapture())
+
+	// existing file is not overwritten
+	assert.Error(face.StartCapture(filename, iface.CaptureConfig{}))
+
+	content, e := ioutil.ReadFile(filepath.Join(dir, filename))
+	require.NoError(e)
+	epbs := readCaptureEpbs(t, content)
+	require.Len(epbs, 4)
+	for i, epb := range epbs {
+		capLen := int(binary.LittleEndian.Uint32(epb[20:]))
+		assert.True(capLen > 14, i)
+		assert.Equal(ndn.NDN_ETHERTYPE, binary.BigEndian.Uint16(epb[40:]), i)
+	}
+	assert.Equal(byte(0x05), epbs[0][42]) // TX Interest
+	assert.Equal(byte(0x06), epbs[3][42]) // RX Data
+
+	// L2 frames are captured
+	filename = "frames.pcapng"
+	require.NoError(face.StartCapture(filename, iface.CaptureConfig{Frames: true}))
+	face.TxBurst([]ndn.Packet{
+		ndntestutil.MakeInterest("/A/5").GetPacket(),
+		ndntestutil.MakeInterest("/B/5").GetPacket(),
+	})
+	face.Rx(ndntestutil.MakeData("/B/6"))
+	time.Sleep(100 * time.Millisecond)
+
+	cnt, e = face.StopCapture()
+	require.NoError(e)
+	assert.Equal(uint64(3), cnt.Packets)
+
+	content, e = ioutil.ReadFile(filepath.Join(dir, filename))
+	require.NoError(e)
+	assert.Len(readCaptureEpbs(t, content), 3)
+}
+
+// Extract Enhanced Packet Blocks from pcapng file content.
+func readCaptureEpbs(t *testing.T, content []byte) (epbs [][]byte) {
+	assert, require := makeAR(t)
+	for off := 0; off+12 <= len(content); {
+		blockType := binary.LittleEndian.Uint32(content[off:])
+		blockLen := int(binary.LittleEndian.Uint32(content[off+4:]))
+		require.True(blockLen >= 12 && off+blockLen <= len(content))
+		if off == 0 {
+			assert.Equal(uint32(0x0A0D0D0A), blockType)
+		}
+		if blockType == 6 {
+			epbs = append(epbs, content[off:off+blockLen])
+		}
+		off += blockLen
+	}
+	return epbs
+}
@@ -0,0 +1,123 @@
+package ifacetest
+
+import (
+	"testing"
+	"time"
+
+	"ndn-dpdk/core/nnduration"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+func TestCongMark(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := mockface.New()
+	defer face.Close()
+	assert.Nil(face.GetCongMark())
+	assert.Error(face.SetCongMark(&iface.CongMarkConfig{
+		Target:   nnduration.Nanoseconds(200 * time.Millisecond),
+		Interval: nnduration.Nanoseconds(100 * time.Millisecond),
+	}))
+	assert.Error(face.SetCongMark(&iface.CongMarkConfig{
+		Interval: nnduration.Nanoseconds(2 * time.Second),
+	}))
+	require.NoError(face.SetCongMark(&iface.CongMarkConfig{
+		Interval: nnduration.Nanoseconds(20 * time.Millisecond),
+	}))
+	if cfg := face.GetCongMark(); assert.NotNil(cfg) {
+		assert.Equal(5*time.Millisecond, cfg.Target.Duration())
+		assert.Equal(20*time.Millisecond, cfg.Interval.Duration())
+		assert.False(cfg.MarkInterests)
+	}
+
+	txl := iface.NewTxLoop(dpdk.NUMA_SOCKET_ANY)
+	txl.SetLCore(dpdk.ListSlaveLCores()[0])
+	require.NoError(txl.Launch())
+	time.Sleep(10 * time.Millisecond)
+
+	// packets stay in TX queue while face is not served by TxLoop
+	stallTx := func(pkts ...ndn.Packet) {
+		face.TxBurst(pkts)
+		time.Sleep(30 * time.Millisecond)
+		txl.AddFace(face)
+		time.Sleep(20 * time.Millisecond)
+		txl.RemoveFace(face)
+	}
+
+	// sojourn time goes above target, but not yet for an interval
+	stallTx(ndntestutil.MakeData("/D1").GetPacket())
+	// sojourn time has been above target for an interval, Data is marked but Interest is not
+	stallTx(ndntestutil.MakeInterest("/I2").GetPacket(), ndntestutil.MakeData("/D2").GetPacket())
+	// sojourn time falls below target
+	txl.AddFace(face)
+	time.Sleep(20 * time.Millisecond)
+	face.TxBurst([]ndn.Packet{ndntestutil.MakeData("/D3").GetPacket()})
+	time.Sleep(20 * time.Millisecond)
+
+	if assert.Len(face.TxInterests, 1) {
+		assert.Equal(ndn.CongMark(0), face.TxInterests[0].GetPacket().GetLpL3().GetCongMark())
+	}
+	if assert.Len(face.TxData, 3) {
+		assert.Equal(ndn.CongMark(0), face.TxData[0].GetPacket().GetLpL3().GetCongMark())
+		assert.Equal(ndn.CongMark(1), face.TxData[1].GetPacket().GetLpL3().GetCongMark())
+		assert.Equal(ndn.CongMark(0), face.TxData[2].GetPacket().GetLpL3().GetCongMark())
+	}
+	assert.Equal(uint64(1), face.ReadCounters().TxCongMarks)
+
+	require.NoError(face.SetCongMark(nil))
+	assert.Nil(face.GetCongMark())
+
+	txl.Stop()
+	txl.Close()
+}
+
+func TestCongMarkTxReject(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := mockface.New()
+	defer face.Close()
+	require.NoError(face.SetCongMark(&iface.CongMarkConfig{
+		Interval: nnduration.Nanoseconds(20 * time.Millisecond),
+	}))
+
+	txl := iface.NewTxLoop(dpdk.NUMA_SOCKET_ANY)
+	txl.SetLCore(dpdk.ListSlaveLCores()[0])
+	require.NoError(txl.Launch())
+	txl.AddFace(face)
+	time.Sleep(10 * time.Millisecond)
+
+	// throttled TX ring accepts one frame per burst, so sojourn time stays low but frames are rejected
+	face.TxLimit = 1
+	for i := 0; i < 40; i++ {
+		face.TxBurst([]ndn.Packet{ndntestutil.MakeData("/D").GetPacket(), ndntestutil.MakeData("/D").GetPacket()})
+		time.Sleep(2 * time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// TX ring is no longer throttled
+	face.TxLimit = 0
+	for i := 0; i < 2; i++ {
+		face.TxBurst([]ndn.Packet{ndntestutil.MakeData("/E").GetPacket()})
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cnt := face.ReadCounters()
+	assert.InDelta(40, cnt.TxDropped, 5)
+	assert.NotZero(cnt.TxCongMarks)
+	if assert.Len(face.TxData, 42) {
+		nMarked := 0
+		for _, data := range face.TxData {
+			nMarked += int(data.GetPacket().GetLpL3().GetCongMark())
+		}
+		assert.Equal(ndn.CongMark(0), face.TxData[0].GetPacket().GetLpL3().GetCongMark())
+		assert.EqualValues(cnt.TxCongMarks, nMarked)
+		assert.Equal(ndn.CongMark(0), face.TxData[41].GetPacket().GetLpL3().GetCongMark())
+	}
+
+	txl.Stop()
+	txl.Close()
+}
@@ -0,0 +1,80 @@
+package ifacetest
+
+import (
+	"testing"
+	"time"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+func TestLpReliability(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := mockface.New()
+	defer face.Close()
+	assert.Nil(face.GetLpReliability())
+	assert.Error(face.SetLpReliability(&iface.LpReliabilityConfig{MaxRetx: 256}))
+	require.NoError(face.SetLpReliability(&iface.LpReliabilityConfig{Rto: 100, MaxRetx: 1}))
+	if cfg := face.GetLpReliability(); assert.NotNil(cfg) {
+		assert.EqualValues(100, cfg.Rto)
+		assert.Equal(1, cfg.MaxRetx)
+	}
+
+	slaves := dpdk.ListSlaveLCores()
+	rxl := iface.NewRxLoop(dpdk.NUMA_SOCKET_ANY)
+	rxl.SetLCore(slaves[0])
+	rxl.SetCallback(iface.WrapRxCb(func(burst iface.RxBurst) {}))
+	require.NoError(rxl.Launch())
+	txl := iface.NewTxLoop(dpdk.NUMA_SOCKET_ANY)
+	txl.SetLCore(slaves[1])
+	require.NoError(txl.Launch())
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(rxl.AddRxGroup(iface.TheChanRxGroup))
+	txl.AddFace(face)
+	time.Sleep(50 * time.Millisecond)
+
+	// first frame has TxSequence=1, and is acknowledged by peer before RTO
+	face.TxBurst([]ndn.Packet{ndntestutil.MakeInterest("/A").GetPacket()})
+	time.Sleep(20 * time.Millisecond)
+	ackFrame := dpdktestenv.PacketFromHex("6418 FD034408 0000000000000001 FD034808 0000000000000099")
+	ackFrame.SetPort(uint16(face.GetFaceId()))
+	iface.TheChanRxGroup.Rx(ackFrame)
+	time.Sleep(300 * time.Millisecond)
+
+	assert.Len(face.TxInterests, 1)
+	assert.Len(face.TxBadPkts, 1) // Ack-only frame for TxSequence=0x99
+	cnt := face.ReadCounters().Reliability
+	assert.Equal(uint64(1), cnt.Acked)
+	assert.Equal(uint64(0), cnt.Retransmitted)
+	assert.Equal(uint64(0), cnt.Lost)
+	assert.Equal(uint64(1), cnt.AckOnly)
+
+	// second frame is not acknowledged, retransmitted once, then given up
+	face.TxBurst([]ndn.Packet{ndntestutil.MakeInterest("/B").GetPacket()})
+	time.Sleep(400 * time.Millisecond)
+
+	if assert.Len(face.TxInterests, 3) {
+		ndntestutil.NameEqual(assert, "/B", face.TxInterests[1])
+		ndntestutil.NameEqual(assert, "/B", face.TxInterests[2])
+	}
+	cnt = face.ReadCounters().Reliability
+	assert.Equal(uint64(1), cnt.Acked)
+	assert.Equal(uint64(1), cnt.Retransmitted)
+	assert.Equal(uint64(1), cnt.Lost)
+
+	// counters are retained after disabling
+	require.NoError(face.SetLpReliability(nil))
+	assert.Nil(face.GetLpReliability())
+	cnt = face.ReadCounters().Reliability
+	assert.Equal(uint64(1), cnt.Retransmitted)
+
+	txl.Stop()
+	txl.Close()
+	rxl.Stop()
+	rxl.Close()
+}
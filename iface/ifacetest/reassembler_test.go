@@ -2,16 +2,20 @@ package ifacetest
 
 import (
 	"testing"
+	"time"
 
+	"ndn-dpdk/dpdk"
 	"ndn-dpdk/dpdk/dpdktestenv"
 	"ndn-dpdk/iface"
 	"ndn-dpdk/ndn"
 )
 
-func TestInOrderReassembler(t *testing.T) {
+func TestReassembler(t *testing.T) {
 	assert, require := makeAR(t)
 
-	reassembler := iface.NewInOrderReassembler()
+	reassembler, e := iface.NewReassembler(2, 100*time.Millisecond, dpdk.NUMA_SOCKET_ANY)
+	require.NoError(e)
+	defer reassembler.Close()
 
 	steps := []struct {
 		input  string
@@ -21,22 +25,26 @@ func TestInOrderReassembler(t *testing.T) {
 			""}, // accepted
 		{"6414 seq=5108A0A1A2A3A4A5A601 fragindex=520101 fragcount=530102 payload=5002B2B3",
 			"B0B1B2B3"}, // accepted, delivering
-		{"6414 seq=5108A0A1A2A3A4A5A611 fragindex=520101 fragcount=530102 payload=5002C2C3",
-			""}, // out of order (not first fragment)
+		{"6414 seq=5108A0A1A2A3A4A5A622 fragindex=520102 fragcount=530103 payload=5002D4D5",
+			""}, // accepted, out of order
 		{"6414 seq=5108A0A1A2A3A4A5A620 fragindex=520100 fragcount=530103 payload=5002D0D1",
 			""}, // accepted
-		{"6414 seq=5108A0A1A2A3A4A5A622 fragindex=520102 fragcount=530103 payload=5002D6D7",
-			""}, // out of order
 		{"6414 seq=5108A0A1A2A3A4A5A621 fragindex=520101 fragcount=530103 payload=5002D2D3",
-			""}, // accepted
-		{"6414 seq=5108A0A1A2A3A4A5A622 fragindex=520102 fragcount=530103 payload=5002D4D5",
 			"D0D1D2D3D4D5"}, // accepted, delivering
-		{"6414 seq=5108A0A1A2A3A4A5A630 fragindex=520100 fragcount=530102 payload=5002E0E1",
+		{"6414 seq=5108A0A1A2A3A4A5A631 fragindex=520101 fragcount=530102 payload=5002E2E3",
 			""}, // accepted
+		{"6414 seq=5108A0A1A2A3A4A5A631 fragindex=520101 fragcount=530102 payload=5002E2E3",
+			""}, // duplicate
+		{"6414 seq=5108A0A1A2A3A4A5A630 fragindex=520100 fragcount=530103 payload=5002E0E1",
+			""}, // inconsistent FragCount
 		{"6414 seq=5108A0A1A2A3A4A5A640 fragindex=520100 fragcount=530102 payload=5002F0F1",
-			""}, // accepted, discarding buffer
-		{"6414 seq=5108A0A1A2A3A4A5A641 fragindex=520101 fragcount=530102 payload=5002F2F3",
-			"F0F1F2F3"}, // accepted, delivering
+			""}, // accepted
+		{"6414 seq=5108A0A1A2A3A4A5A650 fragindex=520100 fragcount=530102 payload=5002C0C1",
+			""}, // table full
+		{"6414 seq=5108A0A1A2A3A4A5A660 fragindex=520100 fragcount=530111 payload=5002A0A1",
+			""}, // too many fragments
+		{"6414 seq=5108A0A1A2A3A4A5A630 fragindex=520100 fragcount=530102 payload=5002E0E1",
+			"E0E1E2E3"}, // accepted, delivering
 	}
 	for _, step := range steps {
 		fragPkt := ndn.PacketFromDpdk(dpdktestenv.PacketFromHex(step.input))
@@ -49,12 +57,21 @@ func TestInOrderReassembler(t *testing.T) {
 		} else if assert.NotNil(reassPkt.GetPtr(), step.input) {
 			payload := reassPkt.AsDpdkPacket().ReadAll()
 			assert.Equal(dpdktestenv.BytesFromHex(step.output), payload, step.input)
+			reassPkt.AsDpdkPacket().Close()
 		}
 	}
 
+	// partial packet with Sequence A0A1A2A3A4A5A640 expires
+	time.Sleep(300 * time.Millisecond)
+	reassembler.ExpireTimers()
+
 	counters := reassembler.ReadCounters()
 	assert.Equal(uint64(8), counters.Accepted)
-	assert.Equal(uint64(2), counters.OutOfOrder)
 	assert.Equal(uint64(3), counters.Delivered)
-	assert.Equal(uint64(1), counters.Incomplete)
+	assert.Equal(uint64(1), counters.Timeouts)
+	assert.Equal(uint64(1), counters.Duplicates)
+	assert.Equal(uint64(1), counters.BadFragCount)
+	assert.Equal(uint64(1), counters.TooManyFrags)
+	assert.Equal(uint64(1), counters.TableFull)
+	assert.Equal(uint64(0), counters.ChainErrs)
 }
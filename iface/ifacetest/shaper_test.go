@@ -0,0 +1,77 @@
+package ifacetest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+func TestShaper(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := mockface.New()
+	defer face.Close()
+	assert.Nil(face.GetRateLimit())
+	assert.Error(face.SetRateLimit(&iface.RateLimitConfig{}))
+	assert.Error(face.SetRateLimit(&iface.RateLimitConfig{PacketsPerSecond: 100, MaxDelay: 2000}))
+	require.NoError(face.SetRateLimit(&iface.RateLimitConfig{PacketsPerSecond: 100, PacketBurst: 5, MaxDelay: 1000}))
+	if cfg := face.GetRateLimit(); assert.NotNil(cfg) {
+		assert.Equal(uint64(0), cfg.BytesPerSecond)
+		assert.Equal(uint64(0), cfg.ByteBurst)
+		assert.Equal(uint64(100), cfg.PacketsPerSecond)
+		assert.Equal(uint64(5), cfg.PacketBurst)
+		assert.EqualValues(1000, cfg.MaxDelay)
+	}
+
+	txl := iface.NewTxLoop(dpdk.NUMA_SOCKET_ANY)
+	txl.SetLCore(dpdk.ListSlaveLCores()[0])
+	require.NoError(txl.Launch())
+	time.Sleep(10 * time.Millisecond)
+	txl.AddFace(face)
+	time.Sleep(10 * time.Millisecond)
+
+	makeInterests := func(prefix string, n int) (pkts []ndn.Packet) {
+		for i := 0; i < n; i++ {
+			pkts = append(pkts, ndntestutil.MakeInterest(fmt.Sprintf("%s/%d", prefix, i)).GetPacket())
+		}
+		return pkts
+	}
+
+	// 5 packets are sent immediately, then 1 packet per 10ms
+	face.TxBurst(makeInterests("/A", 20))
+	time.Sleep(50 * time.Millisecond)
+	assert.True(len(face.TxInterests) >= 5)
+	assert.True(len(face.TxInterests) < 20)
+	time.Sleep(250 * time.Millisecond)
+	if assert.Len(face.TxInterests, 20) {
+		ndntestutil.NameEqual(assert, "/A/19", face.TxInterests[19])
+	}
+	cnt := face.ReadCounters().Shaper
+	assert.Equal(uint64(15), cnt.Delayed)
+	assert.Equal(uint64(0), cnt.Dropped)
+
+	// packets waiting longer than MaxDelay are dropped
+	require.NoError(face.SetRateLimit(&iface.RateLimitConfig{PacketsPerSecond: 5, PacketBurst: 1, MaxDelay: 100}))
+	face.TxBurst(makeInterests("/B", 10))
+	time.Sleep(300 * time.Millisecond)
+	cnt = face.ReadCounters().Shaper
+	nSentB := len(face.TxInterests) - 20
+	assert.True(nSentB >= 1)
+	assert.True(nSentB <= 2)
+	assert.Equal(uint64(10-nSentB), cnt.Dropped)
+
+	// counters are retained after disabling
+	require.NoError(face.SetRateLimit(nil))
+	assert.Nil(face.GetRateLimit())
+	cnt = face.ReadCounters().Shaper
+	assert.Equal(uint64(10-nSentB), cnt.Dropped)
+
+	txl.Stop()
+	txl.Close()
+}
@@ -16,11 +16,33 @@ type Locator interface {
 
 	// Check whether Locator fields are correct according to the chosen Scheme.
 	Validate() error
+
+	// Get initial access control list rules.
+	GetAcl() []AclRule
+
+	// Get initial link-layer reliability configuration.
+	GetReliability() *LpReliabilityConfig
+
+	// Get initial congestion marking configuration.
+	GetCongMark() *CongMarkConfig
+
+	// Get initial rate limit configuration.
+	GetRateLimit() *RateLimitConfig
 }
 
 // Base type to implement Locator interface.
 type LocatorBase struct {
 	Scheme string
+	Acl    []AclRule `json:",omitempty"` // initial access control list applied upon face creation
+
+	// initial link-layer reliability configuration applied upon face creation
+	Reliability *LpReliabilityConfig `json:",omitempty"`
+
+	// initial congestion marking configuration applied upon face creation
+	CongMark *CongMarkConfig `json:",omitempty"`
+
+	// initial rate limit configuration applied upon face creation
+	RateLimit *RateLimitConfig `json:",omitempty"`
 }
 
 func (LocatorBase) isLocator() {
@@ -30,6 +52,22 @@ func (loc LocatorBase) GetScheme() string {
 	return loc.Scheme
 }
 
+func (loc LocatorBase) GetAcl() []AclRule {
+	return loc.Acl
+}
+
+func (loc LocatorBase) GetReliability() *LpReliabilityConfig {
+	return loc.Reliability
+}
+
+func (loc LocatorBase) GetCongMark() *CongMarkConfig {
+	return loc.CongMark
+}
+
+func (loc LocatorBase) GetRateLimit() *RateLimitConfig {
+	return loc.RateLimit
+}
+
 // Parse Locator from JSON string.
 func ParseLocator(input string) (loc Locator, e error) {
 	var locw LocatorWrapper
@@ -108,6 +146,24 @@ func (locw *LocatorWrapper) UnmarshalJSON(data []byte) error {
 	if e := loc.Validate(); e != nil {
 		return e
 	}
+	if e := ValidateAcl(loc.GetAcl()); e != nil {
+		return e
+	}
+	if rel := loc.GetReliability(); rel != nil {
+		if e := rel.Validate(); e != nil {
+			return e
+		}
+	}
+	if cm := loc.GetCongMark(); cm != nil {
+		if e := cm.Validate(); e != nil {
+			return e
+		}
+	}
+	if rl := loc.GetRateLimit(); rl != nil {
+		if e := rl.Validate(); e != nil {
+			return e
+		}
+	}
 
 	locw.Locator = loc
 	return nil
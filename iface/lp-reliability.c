@@ -0,0 +1,114 @@
+#include "lp-reliability.h"
+
+#include "../core/logger.h"
+
+INIT_ZF_LOG(LpReliability);
+
+#define LPREL_MASK (LPREL_CAPACITY - 1)
+
+static_assert(RTE_IS_POWER_OF_2(LPREL_CAPACITY), "");
+
+// max Acks processed per LpReliability_ProcessAcked invocation
+static const int LPREL_ACKED_BURST = 64;
+
+void
+LpReliability_CollectAcks(LpReliability* rel, LpL2* l2)
+{
+  void* objs[LP_MAX_ACKS];
+  l2->nAcks =
+    rte_ring_dequeue_burst(rel->ackQueue, objs, LP_MAX_ACKS, NULL);
+  for (uint8_t i = 0; i < l2->nAcks; ++i) {
+    l2->acks[i] = (uint64_t)(uintptr_t)objs[i];
+  }
+}
+
+void
+LpReliability_Tx(LpReliability* rel,
+                 LpHeader* lph,
+                 struct rte_mbuf* payload,
+                 uint8_t nRetx,
+                 struct rte_mempool* indirectMp,
+                 TscTime now)
+{
+  LpReliability_CollectAcks(rel, &lph->l2);
+
+  struct rte_mbuf* clone = rte_pktmbuf_clone(payload, indirectMp);
+  if (unlikely(clone == NULL)) {
+    lph->l2.txSeqNum = 0;
+    return;
+  }
+
+  uint64_t txSeqNum = ++rel->lastTxSeqNum;
+  LpRelEntry* entry = &rel->entries[txSeqNum & LPREL_MASK];
+  if (unlikely(entry->payload != NULL)) {
+    // window is full, oldest frame is given up
+    ZF_LOGD("window-full txSeq=%" PRIu64, entry->txSeqNum);
+    ++rel->nLost;
+    rte_pktmbuf_free(entry->payload);
+  }
+
+  entry->payload = clone;
+  entry->sendTime = now;
+  entry->txSeqNum = txSeqNum;
+  entry->l3 = lph->l3;
+  entry->seqNum = lph->l2.seqNum;
+  entry->fragIndex = lph->l2.fragIndex;
+  entry->fragCount = lph->l2.fragCount;
+  entry->nRetx = nRetx;
+  lph->l2.txSeqNum = txSeqNum;
+}
+
+void
+LpReliability_ProcessAcked(LpReliability* rel)
+{
+  void* objs[LPREL_ACKED_BURST];
+  unsigned count =
+    rte_ring_dequeue_burst(rel->ackedQueue, objs, LPREL_ACKED_BURST, NULL);
+  for (unsigned i = 0; i < count; ++i) {
+    uint64_t txSeqNum = (uint64_t)(uintptr_t)objs[i];
+    if (unlikely(txSeqNum == 0 || txSeqNum > rel->lastTxSeqNum ||
+                 rel->lastTxSeqNum - txSeqNum >= LPREL_CAPACITY)) {
+      continue;
+    }
+
+    LpRelEntry* entry = &rel->entries[txSeqNum & LPREL_MASK];
+    if (entry->payload == NULL || entry->txSeqNum != txSeqNum) {
+      continue;
+    }
+    rte_pktmbuf_free(entry->payload);
+    entry->payload = NULL;
+    ++rel->nAcked;
+  }
+}
+
+LpRelEntry*
+LpReliability_GetExpired(LpReliability* rel, TscTime now)
+{
+  // TxSequence is assigned in order of transmission time, so that entries
+  // expire in TxSequence order
+  for (; rel->oldestTxSeqNum <= rel->lastTxSeqNum; ++rel->oldestTxSeqNum) {
+    LpRelEntry* entry = &rel->entries[rel->oldestTxSeqNum & LPREL_MASK];
+    if (entry->payload == NULL || entry->txSeqNum != rel->oldestTxSeqNum) {
+      continue;
+    }
+    if (now - entry->sendTime < rel->rto) {
+      return NULL;
+    }
+    ZF_LOGV("expired txSeq=%" PRIu64 " nRetx=%" PRIu8, entry->txSeqNum,
+            entry->nRetx);
+    return entry;
+  }
+  return NULL;
+}
+
+void
+LpReliability_Clear(LpReliability* rel)
+{
+  for (int i = 0; i < LPREL_CAPACITY; ++i) {
+    LpRelEntry* entry = &rel->entries[i];
+    if (entry->payload != NULL) {
+      rte_pktmbuf_free(entry->payload);
+      entry->payload = NULL;
+    }
+  }
+}
@@ -0,0 +1,190 @@
+package iface
+
+/*
+#include "face.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"unsafe"
+
+	"ndn-dpdk/core/nnduration"
+	"ndn-dpdk/core/urcu"
+	"ndn-dpdk/dpdk"
+)
+
+const LPREL_CAPACITY = C.LPREL_CAPACITY
+
+// NDNLPv2 link-layer reliability configuration.
+type LpReliabilityConfig struct {
+	Rto     nnduration.Milliseconds // retransmission timeout, default is 200ms
+	MaxRetx int                     // max retransmissions per frame, default is 3
+}
+
+func (cfg LpReliabilityConfig) Validate() error {
+	if cfg.MaxRetx < 0 || cfg.MaxRetx > math.MaxUint8 {
+		return errors.New("MaxRetx is out of range")
+	}
+	return nil
+}
+
+func (cfg LpReliabilityConfig) applyDefaults() LpReliabilityConfig {
+	if cfg.Rto == 0 {
+		cfg.Rto = 200
+	}
+	if cfg.MaxRetx == 0 {
+		cfg.MaxRetx = 3
+	}
+	return cfg
+}
+
+// Link-layer reliability counters.
+type LpReliabilityCounters struct {
+	Acked         uint64 // acknowledged frames
+	Retransmitted uint64 // retransmitted frames
+	Lost          uint64 // frames given up without acknowledgement
+	AckOnly       uint64 // sent frames carrying only Acks
+}
+
+func (cnt LpReliabilityCounters) String() string {
+	return fmt.Sprintf("%dacked %dretx %dlost %dack-only", cnt.Acked, cnt.Retransmitted, cnt.Lost, cnt.AckOnly)
+}
+
+func (cnt LpReliabilityCounters) add(other LpReliabilityCounters) LpReliabilityCounters {
+	cnt.Acked += other.Acked
+	cnt.Retransmitted += other.Retransmitted
+	cnt.Lost += other.Lost
+	cnt.AckOnly += other.AckOnly
+	return cnt
+}
+
+type lpRelRecord struct {
+	cfg     *LpReliabilityConfig  // current config, nil if disabled
+	cntBase LpReliabilityCounters // counters from previously installed instances
+	gen     int                   // number of installed instances, for ring naming
+}
+
+var (
+	lpRelLock    sync.Mutex
+	lpRelRecords = make(map[FaceId]*lpRelRecord)
+)
+
+func lpRelCountersFromC(relC *C.LpReliability) (cnt LpReliabilityCounters) {
+	if relC == nil {
+		return cnt
+	}
+	cnt.Acked = uint64(relC.nAcked)
+	cnt.Retransmitted = uint64(relC.nRetx)
+	cnt.Lost = uint64(relC.nLost)
+	cnt.AckOnly = uint64(relC.nAckOnly)
+	return cnt
+}
+
+func (face *FaceBase) makeLpReliability(cfg LpReliabilityConfig, gen int) (relC *C.LpReliability, e error) {
+	socket := face.GetNumaSocket()
+	ackQueue, e := dpdk.NewRing(fmt.Sprintf("LpRelAck_%d_%d", face.id, gen),
+		LPREL_CAPACITY, socket, false, true)
+	if e != nil {
+		return nil, e
+	}
+	ackedQueue, e := dpdk.NewRing(fmt.Sprintf("LpRelAcked_%d_%d", face.id, gen),
+		LPREL_CAPACITY, socket, false, true)
+	if e != nil {
+		ackQueue.Close()
+		return nil, e
+	}
+
+	relC = (*C.LpReliability)(dpdk.Zmalloc("LpReliability", C.sizeof_LpReliability, socket))
+	relC.ackQueue = (*C.struct_rte_ring)(ackQueue.GetPtr())
+	relC.ackedQueue = (*C.struct_rte_ring)(ackedQueue.GetPtr())
+	relC.rto = C.TscDuration(dpdk.ToTscDuration(cfg.Rto.Duration()))
+	relC.maxRetx = C.uint8_t(cfg.MaxRetx)
+	relC.oldestTxSeqNum = 1
+	return relC, nil
+}
+
+func freeLpReliability(relC *C.LpReliability) {
+	C.LpReliability_Clear(relC)
+	dpdk.RingFromPtr(unsafe.Pointer(relC.ackQueue)).Close()
+	dpdk.RingFromPtr(unsafe.Pointer(relC.ackedQueue)).Close()
+	dpdk.Free(relC)
+}
+
+// Enable, reconfigure, or disable NDNLPv2 link-layer reliability.
+// When enabled, outgoing frames carry TxSequence, received frames are acknowledged,
+// and frames that are not acknowledged within the retransmission timeout are retransmitted.
+// The peer should enable link-layer reliability as well.
+// nil cfg disables link-layer reliability.
+func (face *FaceBase) SetLpReliability(cfg *LpReliabilityConfig) error {
+	faceC := face.getPtr()
+	if faceC.impl == nil {
+		return errors.New("face is closed")
+	}
+
+	lpRelLock.Lock()
+	defer lpRelLock.Unlock()
+	record := lpRelRecords[face.id]
+	if record == nil {
+		record = new(lpRelRecord)
+	}
+
+	var relC *C.LpReliability
+	if cfg != nil {
+		if e := cfg.Validate(); e != nil {
+			return e
+		}
+		c := cfg.applyDefaults()
+		var e error
+		if relC, e = face.makeLpReliability(c, record.gen); e != nil {
+			return e
+		}
+		record.cfg = &c
+		record.gen++
+	} else {
+		record.cfg = nil
+	}
+
+	oldRelC := (*C.LpReliability)(urcu.NewPointer(&faceC.impl.tx.rel).Xchg(unsafe.Pointer(relC)))
+	urcu.NewPointer(&faceC.impl.rx.rel).Xchg(unsafe.Pointer(relC))
+	urcu.Synchronize()
+	if oldRelC != nil {
+		record.cntBase = record.cntBase.add(lpRelCountersFromC(oldRelC))
+		freeLpReliability(oldRelC)
+	}
+
+	lpRelRecords[face.id] = record
+	return nil
+}
+
+// Get NDNLPv2 link-layer reliability configuration.
+// Returns nil if link-layer reliability is disabled.
+func (face *FaceBase) GetLpReliability() *LpReliabilityConfig {
+	lpRelLock.Lock()
+	defer lpRelLock.Unlock()
+	if record := lpRelRecords[face.id]; record != nil && record.cfg != nil {
+		cfg := *record.cfg
+		return &cfg
+	}
+	return nil
+}
+
+func (face FaceBase) readLpRelCounters() (cnt LpReliabilityCounters) {
+	lpRelLock.Lock()
+	defer lpRelLock.Unlock()
+	if record := lpRelRecords[face.id]; record != nil {
+		cnt = record.cntBase
+	}
+	return cnt.add(lpRelCountersFromC(face.getPtr().impl.tx.rel))
+}
+
+func (face *FaceBase) clearLpReliability() {
+	lpRelLock.Lock()
+	defer lpRelLock.Unlock()
+	delete(lpRelRecords, face.id)
+	if relC := face.getPtr().impl.tx.rel; relC != nil {
+		freeLpReliability(relC)
+	}
+}
@@ -0,0 +1,119 @@
+#ifndef NDN_DPDK_IFACE_LP_RELIABILITY_H
+#define NDN_DPDK_IFACE_LP_RELIABILITY_H
+
+/// \file
+
+#include "common.h"
+
+/** \brief Capacity of LpReliability retransmission window.
+ *
+ *  This must be a power of 2.
+ */
+#define LPREL_CAPACITY 1024
+
+/** \brief Unacknowledged frame in LpReliability.
+ */
+typedef struct LpRelEntry
+{
+  struct rte_mbuf* payload; ///< clone of LpPayload, NULL if slot is unused
+  TscTime sendTime;         ///< time of last transmission
+  uint64_t txSeqNum;        ///< TxSequence of last transmission
+  LpL3 l3;                  ///< L3 fields
+  uint64_t seqNum;          ///< fragmentation Sequence
+  uint16_t fragIndex;       ///< fragmentation FragIndex
+  uint16_t fragCount;       ///< fragmentation FragCount
+  uint8_t nRetx;            ///< number of retransmissions so far
+} LpRelEntry;
+
+/** \brief NDNLPv2 link-layer reliability.
+ *
+ *  RX thread passes received TxSequence numbers and Acks to TX thread via
+ *  \c ackQueue and \c ackedQueue. Other functions are called by TX thread:
+ *  it assigns TxSequence to outgoing frames, piggybacks Acks, and retransmits
+ *  unacknowledged frames.
+ */
+typedef struct LpReliability
+{
+  struct rte_ring* ackQueue;   ///< received TxSequence numbers to acknowledge
+  struct rte_ring* ackedQueue; ///< received Acks
+
+  TscDuration rto;         ///< retransmission timeout
+  uint8_t maxRetx;         ///< max retransmissions per frame
+  uint64_t lastTxSeqNum;   ///< last assigned TxSequence
+  uint64_t oldestTxSeqNum; ///< TxSequence of oldest possibly unacked frame
+
+  uint64_t nAcked;   ///< acknowledged frames
+  uint64_t nRetx;    ///< retransmitted frames
+  uint64_t nLost;    ///< frames given up without acknowledgement
+  uint64_t nAckOnly; ///< sent frames that carry only Acks
+
+  LpRelEntry entries[LPREL_CAPACITY];
+} LpReliability;
+
+/** \brief Process TxSequence and Acks of an incoming L2 frame.
+ *
+ *  This is called by RX thread. If a queue is full, the Ack or TxSequence is
+ *  discarded, which could cause a spurious retransmission.
+ */
+static inline void
+LpReliability_Rx(LpReliability* rel, const LpL2* l2)
+{
+  if (l2->nAcks > 0) {
+    void* objs[LP_MAX_ACKS];
+    for (uint8_t i = 0; i < l2->nAcks; ++i) {
+      objs[i] = (void*)(uintptr_t)l2->acks[i];
+    }
+    rte_ring_enqueue_bulk(rel->ackedQueue, objs, l2->nAcks, NULL);
+  }
+
+  if (l2->txSeqNum != 0) {
+    rte_ring_enqueue(rel->ackQueue, (void*)(uintptr_t)l2->txSeqNum);
+  }
+}
+
+/** \brief Move up to \c LP_MAX_ACKS pending Acks into an outgoing L2 frame.
+ *  \param[out] l2 L2 fields; l2.nAcks and l2.acks are set.
+ */
+void
+LpReliability_CollectAcks(LpReliability* rel, LpL2* l2);
+
+/** \brief Assign TxSequence and piggyback Acks to an outgoing L2 frame.
+ *  \param[inout] lph LpHeader of the frame; l2.txSeqNum and l2.acks are set.
+ *  \param payload LpPayload of the frame; a clone is retained for retransmission.
+ *  \param nRetx 0 for first transmission, or retransmission count of this payload.
+ *  \param indirectMp mempool for indirect mbufs.
+ *
+ *  If cloning fails, the frame is sent without TxSequence, and would not be
+ *  retransmitted.
+ */
+void
+LpReliability_Tx(LpReliability* rel,
+                 LpHeader* lph,
+                 struct rte_mbuf* payload,
+                 uint8_t nRetx,
+                 struct rte_mempool* indirectMp,
+                 TscTime now);
+
+/** \brief Release acknowledged frames.
+ */
+void
+LpReliability_ProcessAcked(LpReliability* rel);
+
+/** \brief Find the oldest frame whose retransmission timer has expired.
+ *  \return the entry, or NULL if no timer has expired.
+ *
+ *  Caller should take \c entry->payload and set it to NULL, and then either
+ *  retransmit it via \c LpReliability_Tx or give up on it.
+ */
+LpRelEntry*
+LpReliability_GetExpired(LpReliability* rel, TscTime now);
+
+/** \brief Free unacknowledged frames.
+ *
+ *  This should be called after the LpReliability is detached from TX and RX
+ *  procedures.
+ */
+void
+LpReliability_Clear(LpReliability* rel);
+
+#endif // NDN_DPDK_IFACE_LP_RELIABILITY_H
@@ -36,6 +36,10 @@ type MockFace struct {
 	TxData      []*ndn.Data     // sent Data packets
 	TxNacks     []*ndn.Nack     // sent Nack packets
 	TxBadPkts   []ndn.Packet    // sent unparsable packets
+
+	// TxLimit is the maximum number of frames accepted per TX burst, 0 means unlimited.
+	// Excess frames are rejected, as if the NIC TX ring is full.
+	TxLimit int
 }
 
 func New() (face *MockFace) {
@@ -47,6 +51,7 @@ func New() (face *MockFace) {
 	if e := face.InitFaceBase(iface.AllocId(iface.FaceKind_Mock), 0, dpdk.NUMA_SOCKET_ANY); e != nil {
 		panic(e)
 	}
+	face.SetLocal(true)
 	iface.TheChanRxGroup.AddFace(face)
 
 	faceC := face.getPtr()
@@ -173,6 +178,9 @@ func (face *MockFace) handleTx(pkt ndn.Packet) {
 //export go_MockFace_TxBurst
 func go_MockFace_TxBurst(faceC *C.Face, pkts **C.struct_rte_mbuf, nPkts C.uint16_t) C.uint16_t {
 	face := iface.Get(iface.FaceId(faceC.id)).(*MockFace)
+	if face.TxLimit > 0 && int(nPkts) > face.TxLimit {
+		nPkts = C.uint16_t(face.TxLimit)
+	}
 	for i := C.uint16_t(0); i < nPkts; i++ {
 		pktsEle := (**C.struct_rte_mbuf)(unsafe.Pointer(uintptr(unsafe.Pointer(pkts)) +
 			uintptr(i)*unsafe.Sizeof(*pkts)))
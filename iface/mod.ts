@@ -1,8 +1,12 @@
 import { Counter } from "../core/mod";
+import { Milliseconds, Nanoseconds } from "../core/nnduration/mod";
 import * as runningStat from "../core/running_stat/mod";
+import { Name } from "../ndn/mod";
+import * as bundleface from "./bundleface/mod";
 import * as ethface from "./ethface/mod";
 import * as mockface from "./mockface/mod";
 import * as socketface from "./socketface/mod";
+import * as wsface from "./wsface/mod";
 
 /**
  * @TJS-type integer
@@ -11,13 +15,165 @@ import * as socketface from "./socketface/mod";
  */
 export type FaceId = number;
 
-export type Locator = ethface.Locator | socketface.Locator | mockface.Locator;
+export interface AclRule {
+  Prefix: Name;
 
-export interface InOrderReassemblerCounters {
+  /**
+   * @default false
+   */
+  Deny?: boolean;
+}
+
+export interface LpReliabilityConfig {
+  /**
+   * @default 200
+   */
+  Rto?: Milliseconds;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   * @maximum 255
+   * @default 3
+   */
+  MaxRetx?: number;
+}
+
+export interface CongMarkConfig {
+  /**
+   * @default 5000000
+   */
+  Target?: Nanoseconds;
+
+  /**
+   * @default 100000000
+   */
+  Interval?: Nanoseconds;
+
+  /**
+   * @default false
+   */
+  MarkInterests?: boolean;
+}
+
+export interface RateLimitConfig {
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   */
+  BytesPerSecond?: number;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   */
+  ByteBurst?: number;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   */
+  PacketsPerSecond?: number;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   */
+  PacketBurst?: number;
+
+  /**
+   * @default 100
+   */
+  MaxDelay?: Milliseconds;
+}
+
+export type CapturePacketType = "Interest" | "Data" | "Nack";
+
+export interface CaptureConfig {
+  /**
+   * @default false
+   */
+  Rx?: boolean;
+
+  /**
+   * @default false
+   */
+  Tx?: boolean;
+
+  /**
+   * @default false
+   */
+  Frames?: boolean;
+
+  Prefix?: Name;
+
+  PacketTypes?: CapturePacketType[];
+
+  /**
+   * @TJS-type integer
+   * @minimum 1
+   * @maximum 65535
+   * @default 2048
+   */
+  Snaplen?: number;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   * @default 0
+   */
+  MaxPackets?: number;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   * @default 0
+   */
+  MaxBytes?: number;
+}
+
+export interface LocatorBase {
+  Acl?: AclRule[];
+  Reliability?: LpReliabilityConfig;
+  CongMark?: CongMarkConfig;
+  RateLimit?: RateLimitConfig;
+}
+
+export type Locator = (ethface.Locator | ethface.UdpLocator | ethface.MemifLocator | socketface.Locator | wsface.Locator | mockface.Locator | bundleface.Locator) & LocatorBase;
+
+export interface ReassemblerCounters {
   Accepted: Counter;
-  OutOfOrder: Counter;
   Delivered: Counter;
-  Incomplete: Counter;
+  Timeouts: Counter;
+  Duplicates: Counter;
+  BadFragCount: Counter;
+  TooManyFrags: Counter;
+  TableFull: Counter;
+  ChainErrs: Counter;
+}
+
+export interface AclCounters {
+  Hits: Counter[];
+  Drops: Counter;
+  LocalhostDrops: Counter;
+}
+
+export interface LpReliabilityCounters {
+  Acked: Counter;
+  Retransmitted: Counter;
+  Lost: Counter;
+  AckOnly: Counter;
+}
+
+export interface ShaperCounters {
+  Delayed: Counter;
+  Dropped: Counter;
+}
+
+export interface CaptureCounters {
+  Packets: Counter;
+  Bytes: Counter;
+  Dropped: Counter;
 }
 
 export interface Counters {
@@ -25,12 +181,14 @@ export interface Counters {
   RxOctets: Counter;
 
   L2DecodeErrs: Counter;
-  Reass: InOrderReassemblerCounters;
+  Reass: ReassemblerCounters;
+  ReassUnavail: Counter;
 
   L3DecodeErrs: Counter;
   RxInterests: Counter;
   RxData: Counter;
   RxNacks: Counter;
+  Acl: AclCounters;
 
   InterestLatency: runningStat.Snapshot;
   DataLatency: runningStat.Snapshot;
@@ -43,7 +201,11 @@ export interface Counters {
   FragGood: Counter;
   FragBad: Counter;
   TxAllocErrs: Counter;
+  TxCongMarks: Counter;
   TxDropped: Counter;
+  Shaper: ShaperCounters;
   TxFrames: Counter;
   TxOctets: Counter;
+
+  Reliability: LpReliabilityCounters;
 }
@@ -0,0 +1,123 @@
+package iface
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// pcapng block types and option codes.
+const (
+	pcapngBlockSHB = 0x0A0D0D0A
+	pcapngBlockIDB = 0x00000001
+	pcapngBlockEPB = 0x00000006
+
+	pcapngOptEndOfOpt = 0
+	pcapngOptComment  = 1
+	pcapngOptUserAppl = 4 // shb_userappl
+	pcapngOptIfName   = 2 // if_name
+	pcapngOptTsResol  = 9 // if_tsresol
+	pcapngOptEpbFlags = 2 // epb_flags
+
+	pcapngLinkTypeEthernet = 1
+	pcapngFlagInbound      = 1
+	pcapngFlagOutbound     = 2
+)
+
+// Writer of pcapng file with a single interface.
+// Timestamps have nanosecond resolution.
+type pcapngWriter struct {
+	w *bufio.Writer
+}
+
+func newPcapngWriter(w io.Writer, ifName string, snaplen int) (pw *pcapngWriter, e error) {
+	pw = &pcapngWriter{bufio.NewWriter(w)}
+
+	var shb []byte
+	shb = pcapngAppendUint32(shb, 0x1A2B3C4D) // byte-order magic
+	shb = pcapngAppendUint16(shb, 1)          // major version
+	shb = pcapngAppendUint16(shb, 0)          // minor version
+	shb = pcapngAppendUint64(shb, ^uint64(0)) // section length unspecified
+	shb = pcapngAppendOption(shb, pcapngOptUserAppl, []byte("ndn-dpdk"))
+	shb = pcapngAppendOption(shb, pcapngOptEndOfOpt, nil)
+	if e = pw.writeBlock(pcapngBlockSHB, shb); e != nil {
+		return nil, e
+	}
+
+	var idb []byte
+	idb = pcapngAppendUint16(idb, pcapngLinkTypeEthernet)
+	idb = pcapngAppendUint16(idb, 0) // reserved
+	idb = pcapngAppendUint32(idb, uint32(snaplen))
+	idb = pcapngAppendOption(idb, pcapngOptIfName, []byte(ifName))
+	idb = pcapngAppendOption(idb, pcapngOptTsResol, []byte{9})
+	idb = pcapngAppendOption(idb, pcapngOptEndOfOpt, nil)
+	if e = pw.writeBlock(pcapngBlockIDB, idb); e != nil {
+		return nil, e
+	}
+	return pw, nil
+}
+
+// Write an Enhanced Packet Block.
+// flags is epb_flags value; comment is omitted if empty.
+func (pw *pcapngWriter) WritePacket(t time.Time, data []byte, origLen int, flags uint32, comment string) error {
+	ts := uint64(t.UnixNano())
+	var epb []byte
+	epb = pcapngAppendUint32(epb, 0) // interface ID
+	epb = pcapngAppendUint32(epb, uint32(ts>>32))
+	epb = pcapngAppendUint32(epb, uint32(ts))
+	epb = pcapngAppendUint32(epb, uint32(len(data)))
+	epb = pcapngAppendUint32(epb, uint32(origLen))
+	epb = pcapngAppendPadded(epb, data)
+	epb = pcapngAppendOption(epb, pcapngOptEpbFlags, pcapngAppendUint32(nil, flags))
+	if comment != "" {
+		epb = pcapngAppendOption(epb, pcapngOptComment, []byte(comment))
+	}
+	epb = pcapngAppendOption(epb, pcapngOptEndOfOpt, nil)
+	return pw.writeBlock(pcapngBlockEPB, epb)
+}
+
+func (pw *pcapngWriter) Flush() error {
+	return pw.w.Flush()
+}
+
+func (pw *pcapngWriter) writeBlock(blockType uint32, body []byte) error {
+	totalLength := uint32(12 + len(body))
+	var block []byte
+	block = pcapngAppendUint32(block, blockType)
+	block = pcapngAppendUint32(block, totalLength)
+	block = append(block, body...)
+	block = pcapngAppendUint32(block, totalLength)
+	_, e := pw.w.Write(block)
+	return e
+}
+
+func pcapngAppendUint16(b []byte, v uint16) []byte {
+	var buf [2]byte
+	binary.LittleEndian.PutUint16(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func pcapngAppendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func pcapngAppendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// Append bytes and pad to 32-bit boundary.
+func pcapngAppendPadded(b []byte, v []byte) []byte {
+	b = append(b, v...)
+	return append(b, make([]byte, (4-len(v)%4)%4)...)
+}
+
+func pcapngAppendOption(b []byte, code uint16, value []byte) []byte {
+	b = pcapngAppendUint16(b, code)
+	b = pcapngAppendUint16(b, uint16(len(value)))
+	return pcapngAppendPadded(b, value)
+}
@@ -0,0 +1,208 @@
+#include "reassembler.h"
+
+#include "../core/logger.h"
+
+INIT_ZF_LOG(Reassembler);
+
+#undef uthash_malloc
+#undef uthash_free
+#undef HASH_INITIAL_NUM_BUCKETS
+#undef HASH_INITIAL_NUM_BUCKETS_LOG2
+#undef HASH_BKT_CAPACITY_THRESH
+#undef HASH_EXPAND_BUCKETS
+#define uthash_malloc(sz) rte_malloc("Reassembler.uthash", (sz), 0)
+#define uthash_free(ptr, sz) rte_free((ptr))
+#define HASH_INITIAL_NUM_BUCKETS (r->nBuckets)
+#define HASH_INITIAL_NUM_BUCKETS_LOG2 (rte_log2_u32(HASH_INITIAL_NUM_BUCKETS))
+#define HASH_BKT_CAPACITY_THRESH UINT_MAX
+#define HASH_EXPAND_BUCKETS(hh, tbl, oomed) Reassembler_Expand_(tbl)
+
+// number of MinSched slots is (1 << REASSEMBLER_SCHED_SLOT_BITS)
+static const int REASSEMBLER_SCHED_SLOT_BITS = 4;
+// MinSched interval is timeout divided by this number
+static const int REASSEMBLER_SCHED_SLOTS_PER_TIMEOUT = 8;
+
+static void
+Reassembler_Expand_(UT_hash_table* tbl)
+{
+  // table size is bounded by capacity, so that expansion is unnecessary
+}
+
+static void
+Reassembler_Release(Reassembler* r, ReassemblerPacket* pkt)
+{
+  HASH_DEL(r->table, pkt);
+  MinTmr_Cancel(&pkt->tmr);
+  r->free[r->nFree++] = pkt;
+}
+
+static void
+Reassembler_Discard(Reassembler* r, ReassemblerPacket* pkt)
+{
+  for (uint16_t i = 0; i < pkt->fragCount; ++i) {
+    if (pkt->frags[i] != NULL) {
+      rte_pktmbuf_free(pkt->frags[i]);
+    }
+  }
+  Reassembler_Release(r, pkt);
+}
+
+static void
+Reassembler_Timeout(MinTmr* tmr, void* cbarg)
+{
+  Reassembler* r = (Reassembler*)cbarg;
+  ReassemblerPacket* pkt = container_of(tmr, ReassemblerPacket, tmr);
+  ZF_LOGD("%016" PRIX64 " timeout nReceived=%" PRIu16 " fragCount=%" PRIu16,
+          pkt->seqNumBase,
+          pkt->nReceived,
+          pkt->fragCount);
+  ++r->nTimeouts;
+  Reassembler_Discard(r, pkt);
+}
+
+Reassembler*
+Reassembler_New(uint32_t capacity, TscDuration timeout, int numaSocket)
+{
+  assert(capacity > 0);
+  Reassembler* r = rte_zmalloc_socket(
+    "Reassembler",
+    sizeof(Reassembler) +
+      capacity * (sizeof(ReassemblerPacket) + sizeof(ReassemblerPacket*)),
+    0,
+    numaSocket);
+  if (unlikely(r == NULL)) {
+    rte_errno = ENOMEM;
+    return NULL;
+  }
+
+  r->sched = MinSched_New(REASSEMBLER_SCHED_SLOT_BITS,
+                          timeout / REASSEMBLER_SCHED_SLOTS_PER_TIMEOUT,
+                          Reassembler_Timeout,
+                          r);
+  if (unlikely(r->sched == NULL)) {
+    rte_free(r);
+    rte_errno = ENOMEM;
+    return NULL;
+  }
+  assert(timeout < MinSched_GetMaxDelay(r->sched));
+
+  r->timeout = timeout;
+  r->capacity = capacity;
+  r->nBuckets = rte_align32pow2(capacity);
+  r->free = (ReassemblerPacket**)RTE_PTR_ADD(
+    r->entries, capacity * sizeof(ReassemblerPacket));
+  for (uint32_t i = 0; i < capacity; ++i) {
+    r->free[i] = &r->entries[i];
+  }
+  r->nFree = capacity;
+  return r;
+}
+
+void
+Reassembler_Close(Reassembler* r)
+{
+  ReassemblerPacket* pkt;
+  ReassemblerPacket* tmp;
+  HASH_ITER(hh, r->table, pkt, tmp)
+  {
+    Reassembler_Discard(r, pkt);
+  }
+  HASH_CLEAR(hh, r->table);
+  MinSched_Close(r->sched);
+  rte_free(r);
+}
+
+static Packet*
+Reassembler_Deliver(Reassembler* r, ReassemblerPacket* pkt)
+{
+  struct rte_mbuf* head = pkt->frags[0];
+  struct rte_mbuf* tail = rte_pktmbuf_lastseg(head);
+  for (uint16_t i = 1; i < pkt->fragCount; ++i) {
+    struct rte_mbuf* frag = pkt->frags[i];
+    struct rte_mbuf* newTail = rte_pktmbuf_lastseg(frag);
+    if (unlikely(Packet_Chain(head, tail, frag) != 0)) {
+      ZF_LOGD("%016" PRIX64 " chain-error", pkt->seqNumBase);
+      ++r->nChainErrs;
+      pkt->frags[0] = NULL;
+      rte_pktmbuf_free(head);
+      for (uint16_t j = 1; j < i; ++j) {
+        pkt->frags[j] = NULL; // already chained onto head
+      }
+      Reassembler_Discard(r, pkt);
+      return NULL;
+    }
+    tail = newTail;
+  }
+
+  ZF_LOGD("%016" PRIX64 " deliver", pkt->seqNumBase);
+  Reassembler_Release(r, pkt);
+  ++r->nDelivered;
+  return Packet_FromMbuf(head);
+}
+
+Packet*
+Reassembler_Receive(Reassembler* r, Packet* npkt)
+{
+  Reassembler_ExpireTimers(r);
+
+  struct rte_mbuf* frame = Packet_ToMbuf(npkt);
+  LpL2* lpl2 = &Packet_GetLpHdr(npkt)->l2;
+  assert(lpl2->fragCount > 1);
+#define PKTDBG(fmt, ...)                                                       \
+  ZF_LOGD("%016" PRIX64 ",%" PRIu16 ",%" PRIu16 " " fmt,                       \
+          lpl2->seqNum,                                                        \
+          lpl2->fragIndex,                                                     \
+          lpl2->fragCount,                                                     \
+          ##__VA_ARGS__)
+
+  if (unlikely(lpl2->fragCount > REASSEMBLER_MAX_FRAGMENTS)) {
+    PKTDBG("too-many-fragments");
+    ++r->nTooManyFrags;
+    rte_pktmbuf_free(frame);
+    return NULL;
+  }
+
+  uint64_t seqNumBase = lpl2->seqNum - lpl2->fragIndex;
+  ReassemblerPacket* pkt = NULL;
+  HASH_FIND(hh, r->table, &seqNumBase, sizeof(seqNumBase), pkt);
+
+  if (pkt == NULL) {
+    if (unlikely(r->nFree == 0)) {
+      PKTDBG("table-full");
+      ++r->nTableFull;
+      rte_pktmbuf_free(frame);
+      return NULL;
+    }
+    pkt = r->free[--r->nFree];
+    pkt->seqNumBase = seqNumBase;
+    pkt->fragCount = lpl2->fragCount;
+    pkt->nReceived = 0;
+    memset(pkt->frags, 0, sizeof(pkt->frags));
+    HASH_ADD(hh, r->table, seqNumBase, sizeof(pkt->seqNumBase), pkt);
+    MinTmr_Init(&pkt->tmr);
+    MinTmr_After(&pkt->tmr, r->timeout, r->sched);
+  } else if (unlikely(pkt->fragCount != lpl2->fragCount)) {
+    PKTDBG("bad-fragcount expected=%" PRIu16, pkt->fragCount);
+    ++r->nBadFragCount;
+    rte_pktmbuf_free(frame);
+    return NULL;
+  }
+
+  if (unlikely(pkt->frags[lpl2->fragIndex] != NULL)) {
+    PKTDBG("duplicate");
+    ++r->nDuplicates;
+    rte_pktmbuf_free(frame);
+    return NULL;
+  }
+
+  ++r->nAccepted;
+  pkt->frags[lpl2->fragIndex] = frame;
+  if (++pkt->nReceived < pkt->fragCount) {
+    PKTDBG("accepted");
+    return NULL;
+  }
+
+  PKTDBG("accepted-last");
+  return Reassembler_Deliver(r, pkt);
+#undef PKTDBG
+}
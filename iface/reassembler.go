@@ -0,0 +1,96 @@
+package iface
+
+/*
+#include "reassembler.h"
+*/
+import "C"
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/ndn"
+)
+
+const (
+	REASSEMBLER_MAX_FRAGMENTS    = C.REASSEMBLER_MAX_FRAGMENTS
+	REASSEMBLER_DEFAULT_CAPACITY = C.REASSEMBLER_DEFAULT_CAPACITY
+	REASSEMBLER_DEFAULT_TIMEOUT  = C.REASSEMBLER_DEFAULT_TIMEOUT * time.Millisecond
+)
+
+type Reassembler struct {
+	c *C.Reassembler
+}
+
+func NewReassembler(capacity int, timeout time.Duration, socket dpdk.NumaSocket) (r Reassembler, e error) {
+	r.c = C.Reassembler_New(C.uint32_t(capacity), C.TscDuration(dpdk.ToTscDuration(timeout)), C.int(socket))
+	if r.c == nil {
+		return r, dpdk.GetErrno()
+	}
+	return r, nil
+}
+
+func ReassemblerFromPtr(ptr unsafe.Pointer) Reassembler {
+	return Reassembler{(*C.Reassembler)(ptr)}
+}
+
+func (r Reassembler) Close() error {
+	C.Reassembler_Close(r.c)
+	return nil
+}
+
+func (r Reassembler) Receive(pkt ndn.Packet) ndn.Packet {
+	res := C.Reassembler_Receive(r.c, (*C.Packet)(pkt.GetPtr()))
+	return ndn.PacketFromPtr(unsafe.Pointer(res))
+}
+
+// Discard partial packets whose timers have expired.
+func (r Reassembler) ExpireTimers() {
+	C.Reassembler_ExpireTimers(r.c)
+}
+
+type ReassemblerCounters struct {
+	Accepted  uint64 // fragments received and accepted
+	Delivered uint64 // L3 packets delivered
+	Timeouts  uint64 // partial packets discarded due to timeout
+
+	Duplicates   uint64 // duplicate fragments dropped
+	BadFragCount uint64 // fragments dropped due to inconsistent FragCount
+	TooManyFrags uint64 // fragments dropped due to FragCount over REASSEMBLER_MAX_FRAGMENTS
+	TableFull    uint64 // fragments dropped due to capacity limit
+	ChainErrs    uint64 // L3 packets dropped due to too many segments
+}
+
+func (cnt ReassemblerCounters) String() string {
+	return fmt.Sprintf("%dacpt %ddlvr %dtimeout %ddup %dbadcount %dtoomany %dfull %dchainerr",
+		cnt.Accepted, cnt.Delivered, cnt.Timeouts, cnt.Duplicates, cnt.BadFragCount, cnt.TooManyFrags,
+		cnt.TableFull, cnt.ChainErrs)
+}
+
+func (cnt ReassemblerCounters) add(other ReassemblerCounters) ReassemblerCounters {
+	cnt.Accepted += other.Accepted
+	cnt.Delivered += other.Delivered
+	cnt.Timeouts += other.Timeouts
+	cnt.Duplicates += other.Duplicates
+	cnt.BadFragCount += other.BadFragCount
+	cnt.TooManyFrags += other.TooManyFrags
+	cnt.TableFull += other.TableFull
+	cnt.ChainErrs += other.ChainErrs
+	return cnt
+}
+
+func (r Reassembler) ReadCounters() (cnt ReassemblerCounters) {
+	if r.c == nil {
+		return cnt
+	}
+	cnt.Accepted = uint64(r.c.nAccepted)
+	cnt.Delivered = uint64(r.c.nDelivered)
+	cnt.Timeouts = uint64(r.c.nTimeouts)
+	cnt.Duplicates = uint64(r.c.nDuplicates)
+	cnt.BadFragCount = uint64(r.c.nBadFragCount)
+	cnt.TooManyFrags = uint64(r.c.nTooManyFrags)
+	cnt.TableFull = uint64(r.c.nTableFull)
+	cnt.ChainErrs = uint64(r.c.nChainErrs)
+	return cnt
+}
@@ -0,0 +1,89 @@
+#ifndef NDN_DPDK_IFACE_REASSEMBLER_H
+#define NDN_DPDK_IFACE_REASSEMBLER_H
+
+/// \file
+
+#include "../container/mintmr/mintmr.h"
+#include "../core/uthash.h"
+#include "common.h"
+
+/** \brief Maximum FragCount accepted by Reassembler.
+ */
+#define REASSEMBLER_MAX_FRAGMENTS 16
+
+/** \brief Default maximum number of partial packets in a Reassembler.
+ */
+#define REASSEMBLER_DEFAULT_CAPACITY 64
+
+/** \brief Default duration to wait for remaining fragments, in milliseconds.
+ */
+#define REASSEMBLER_DEFAULT_TIMEOUT 500
+
+/** \brief Partially received L3 packet in Reassembler.
+ */
+typedef struct ReassemblerPacket
+{
+  UT_hash_handle hh;
+  MinTmr tmr;
+  uint64_t seqNumBase; ///< key: sequence number of first fragment
+  uint16_t fragCount;
+  uint16_t nReceived; ///< number of received fragments
+  struct rte_mbuf* frags[REASSEMBLER_MAX_FRAGMENTS]; ///< fragments by FragIndex
+} ReassemblerPacket;
+
+/** \brief Reassembler that accepts out-of-order fragment arrival.
+ *
+ *  Fragments of the same L3 packet are identified by sequence number of the
+ *  first fragment, computed as Sequence minus FragIndex.
+ */
+typedef struct Reassembler
+{
+  ReassemblerPacket* table; ///< uthash table of partial packets
+  ReassemblerPacket** free; ///< stack of unused entries
+  MinSched* sched;          ///< timer scheduler for partial packet expiration
+  TscDuration timeout;      ///< duration to wait for remaining fragments
+  uint32_t capacity;        ///< maximum number of partial packets
+  uint32_t nFree;           ///< number of unused entries in .free
+  uint32_t nBuckets;        ///< number of hashtable buckets
+
+  uint64_t nAccepted;     ///< fragments received and accepted
+  uint64_t nDelivered;    ///< L3 packets delivered
+  uint64_t nTimeouts;     ///< partial packets discarded due to timeout
+  uint64_t nDuplicates;   ///< duplicate fragments dropped
+  uint64_t nBadFragCount; ///< fragments dropped due to inconsistent FragCount
+  uint64_t nTooManyFrags; ///< fragments dropped due to FragCount over limit
+  uint64_t nTableFull;    ///< fragments dropped due to capacity limit
+  uint64_t nChainErrs;    ///< L3 packets dropped due to too many segments
+  ReassemblerPacket entries[0];
+} Reassembler;
+
+/** \brief Create a reassembler.
+ *  \param capacity maximum number of partial packets.
+ *  \param timeout duration to wait for remaining fragments.
+ *  \return the reassembler, or NULL on allocation failure.
+ */
+Reassembler*
+Reassembler_New(uint32_t capacity, TscDuration timeout, int numaSocket);
+
+/** \brief Destroy a reassembler and free partial packets.
+ */
+void
+Reassembler_Close(Reassembler* r);
+
+/** \brief Discard partial packets whose timers have expired.
+ */
+static inline void
+Reassembler_ExpireTimers(Reassembler* r)
+{
+  MinSched_Trigger(r->sched);
+}
+
+/** \brief Receive an NDNLPv2 fragmented packet into the reassembler.
+ *  \param npkt the packet after \c Packet_ParseL2; its mbuf must point to LpPayload,
+ *              and \c Packet_GetLpHdr must be available.
+ *  \return reassembled packet, or NULL if still waiting for more fragments.
+ */
+Packet*
+Reassembler_Receive(Reassembler* r, Packet* npkt);
+
+#endif // NDN_DPDK_IFACE_REASSEMBLER_H
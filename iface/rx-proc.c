@@ -1,16 +1,81 @@
 #include "rx-proc.h"
 #include "../core/logger.h"
+#include "../core/urcu/urcu.h"
 #include "faceid.h"
 
 INIT_ZF_LOG(RxProc);
 
 int
-RxProc_Init(RxProc* rx, struct rte_mempool* nameMp)
+RxProc_Init(RxProc* rx, struct rte_mempool* nameMp, int numaSocket)
 {
   rx->nameMp = nameMp;
+  return RxProc_EnableThreads(rx, 1, numaSocket);
+}
+
+int
+RxProc_EnableThreads(RxProc* rx, int nThreads, int numaSocket)
+{
+  if (nThreads < 1 || nThreads > RXPROC_MAX_THREADS) {
+    return EINVAL;
+  }
+
+  for (int i = 0; i < nThreads; ++i) {
+    RxProcThread* rxt = &rx->threads[i];
+    if (rxt->reassembler != NULL) {
+      continue;
+    }
+    rxt->reassembler =
+      Reassembler_New(REASSEMBLER_DEFAULT_CAPACITY,
+                      TscDuration_FromMillis(REASSEMBLER_DEFAULT_TIMEOUT),
+                      numaSocket);
+    if (unlikely(rxt->reassembler == NULL)) {
+      return ENOMEM;
+    }
+  }
   return 0;
 }
 
+void
+RxProc_Close(RxProc* rx)
+{
+  for (int i = 0; i < RXPROC_MAX_THREADS; ++i) {
+    RxProcThread* rxt = &rx->threads[i];
+    if (rxt->reassembler != NULL) {
+      Reassembler_Close(rxt->reassembler);
+      rxt->reassembler = NULL;
+    }
+  }
+}
+
+/** \brief Enforce /localhost scope and Acl on an L3 packet.
+ *  \return whether the packet is allowed.
+ */
+static bool
+RxProc_CheckAcl(RxProc* rx, RxProcThread* rxt, Packet* npkt)
+{
+  const LName* name = Packet_GetL3Name(npkt);
+  if (!rx->isLocal && unlikely(Acl_IsLocalhostName(*name))) {
+    ++rxt->nLocalhostDrops;
+    return false;
+  }
+
+  const Acl* acl = rcu_dereference(rx->acl);
+  if (acl == NULL) {
+    return true;
+  }
+
+  int ruleIndex = Acl_Match(acl, *name);
+  if (ruleIndex == ACL_NO_MATCH) {
+    return true;
+  }
+  ++rxt->nAclHits[ruleIndex];
+  if (acl->rules[ruleIndex].deny) {
+    ++rxt->nAclDrops;
+    return false;
+  }
+  return true;
+}
+
 Packet*
 RxProc_Input(RxProc* rx, int thread, struct rte_mbuf* frame)
 {
@@ -30,20 +95,29 @@ RxProc_Input(RxProc* rx, int thread, struct rte_mbuf* frame)
     return NULL;
   }
 
+  LpReliability* rel = rcu_dereference(rx->rel);
+  if (rel != NULL) {
+    LpReliability_Rx(rel, &Packet_GetLpHdr(npkt)->l2);
+  }
+
   if (unlikely(frame->pkt_len == 0)) {
     ZF_LOGD("%" PRI_FaceId "-%d lp-no-payload", faceId, thread);
     rte_pktmbuf_free(frame);
     return NULL;
   }
 
+  Reassembler* reass = rxt->reassembler;
+  if (reass != NULL) {
+    Reassembler_ExpireTimers(reass);
+  }
+
   if (Packet_GetLpHdr(npkt)->l2.fragCount > 1) {
-    if (unlikely(thread != 0)) {
-      // currently reassembler is available on thread 0 only
-      ZF_LOGW("%" PRI_FaceId "-%d lp-reassembler-unavail", faceId, thread);
+    if (unlikely(reass == NULL)) {
+      ++rxt->nReassUnavail;
       rte_pktmbuf_free(frame);
       return NULL;
     }
-    npkt = InOrderReassembler_Receive(&rx->reassembler, npkt);
+    npkt = Reassembler_Receive(reass, npkt);
     if (npkt == NULL) {
       return NULL;
     }
@@ -58,6 +132,12 @@ RxProc_Input(RxProc* rx, int thread, struct rte_mbuf* frame)
     return NULL;
   }
 
+  if (unlikely(!RxProc_CheckAcl(rx, rxt, npkt))) {
+    ZF_LOGD("%" PRI_FaceId "-%d acl-drop", faceId, thread);
+    rte_pktmbuf_free(Packet_ToMbuf(npkt));
+    return NULL;
+  }
+
   L3PktType l3type = Packet_GetL3PktType(npkt);
   ++rxt->nFrames[l3type];
   return npkt;
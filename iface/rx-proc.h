@@ -3,7 +3,9 @@
 
 /// \file
 
-#include "in-order-reassembler.h"
+#include "acl.h"
+#include "lp-reliability.h"
+#include "reassembler.h"
 
 #define RXPROC_MAX_THREADS 8
 
@@ -21,8 +23,15 @@ typedef struct RxProcThread
   uint64_t nFrames[L3PktType_MAX];
   uint64_t nOctets; ///< input bytes
 
-  uint64_t nL2DecodeErr; ///< failed NDNLP decodings
-  uint64_t nL3DecodeErr; ///< failed Interest/Data/Nack decodings
+  Reassembler* reassembler; ///< reassembler, NULL if not enabled
+
+  uint64_t nL2DecodeErr;  ///< failed NDNLP decodings
+  uint64_t nReassUnavail; ///< fragments dropped due to missing reassembler
+  uint64_t nL3DecodeErr;  ///< failed Interest/Data/Nack decodings
+
+  uint64_t nAclHits[ACL_MAX_RULES]; ///< packets matching each Acl rule
+  uint64_t nAclDrops;               ///< packets dropped by Acl
+  uint64_t nLocalhostDrops;         ///< /localhost packets on non-local face
 } __rte_cache_aligned RxProcThread;
 
 /** \brief Incoming frame processing procedure.
@@ -30,8 +39,9 @@ typedef struct RxProcThread
 typedef struct RxProc
 {
   struct rte_mempool* nameMp; ///< mempool for allocating Name linearize mbufs
-
-  InOrderReassembler reassembler;
+  Acl* acl;                   ///< (RCU) access control list, NULL allows all
+  LpReliability* rel;         ///< (RCU) link-layer reliability, NULL if disabled
+  bool isLocal;               ///< whether /localhost packets are accepted
 
   RxProcThread threads[RXPROC_MAX_THREADS];
 } RxProc;
@@ -39,16 +49,37 @@ typedef struct RxProc
 /** \brief Initialize RX procedure.
  *  \pre *rx is zeroized.
  *  \param nameMp mempool for name linearize; dataroom must be at least NAME_MAX_LENGTH.
+ *  \retval 0 success
+ *  \retval ENOMEM reassembler allocation failure
+ *
+ *  This enables reassembler on RX thread 0 only.
+ */
+int
+RxProc_Init(RxProc* rx, struct rte_mempool* nameMp, int numaSocket);
+
+/** \brief Enable reassembler on RX threads 0 to \p nThreads-1.
+ *
+ *  Fragments of an L3 packet must arrive on the same RX thread.
+ *  \retval 0 success
+ *  \retval EINVAL \p nThreads is out of range
+ *  \retval ENOMEM reassembler allocation failure
  */
 int
-RxProc_Init(RxProc* rx, struct rte_mempool* nameMp);
+RxProc_EnableThreads(RxProc* rx, int nThreads, int numaSocket);
+
+/** \brief Release resources held by RX procedure.
+ */
+void
+RxProc_Close(RxProc* rx);
 
 /** \brief Process an incoming L2 frame.
  *  \param pkt incoming L2 frame, starting from NDNLP header;
  *             RxProc retains ownership of this packet
  *  \return L3 packet after \c Packet_ParseL3;
  *          RxProc releases ownership of this packet
- *  \retval NULL no L3 packet is ready at this moment
+ *  \retval NULL no L3 packet is ready at this moment, or the L3 packet
+ *               is rejected by /localhost scope or Acl
+ *  \pre Calling thread holds rcu_read_lock.
  */
 Packet*
 RxProc_Input(RxProc* rx, int thread, struct rte_mbuf* pkt);
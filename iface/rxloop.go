@@ -16,6 +16,10 @@ import (
 	"ndn-dpdk/dpdk"
 )
 
+// Maximum number of RX threads that can receive frames on the same face.
+// Each RxGroup serving a face must have a distinct RX thread number.
+const RXPROC_MAX_THREADS = C.RXPROC_MAX_THREADS
+
 // Receive channel for a group of faces.
 type IRxGroup interface {
 	GetPtr() unsafe.Pointer
@@ -109,15 +113,26 @@ func (rxg *ChanRxGroup) Rx(pkt dpdk.Packet) {
 	}
 }
 
+// Queue a burst of packets; packets that do not fit are dropped.
+func (rxg *ChanRxGroup) RxBurst(pkts []dpdk.Packet) {
+	for _, pkt := range pkts {
+		rxg.Rx(pkt)
+	}
+}
+
 //export go_ChanRxGroup_RxBurst
 func go_ChanRxGroup_RxBurst(rxg *C.RxGroup, pkts **C.struct_rte_mbuf, nPkts C.uint16_t) C.uint16_t {
-	select {
-	case pkt := <-TheChanRxGroup.queue:
-		*pkts = (*C.struct_rte_mbuf)(pkt.GetPtr())
-		return 1
-	default:
+	for i := C.uint16_t(0); i < nPkts; i++ {
+		select {
+		case pkt := <-TheChanRxGroup.queue:
+			pktsEle := (**C.struct_rte_mbuf)(unsafe.Pointer(uintptr(unsafe.Pointer(pkts)) +
+				uintptr(i)*unsafe.Sizeof(*pkts)))
+			*pktsEle = (*C.struct_rte_mbuf)(pkt.GetPtr())
+		default:
+			return i
+		}
 	}
-	return 0
+	return nPkts
 }
 
 var TheChanRxGroup = newChanRxGroup()
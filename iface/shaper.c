@@ -0,0 +1,81 @@
+#include "shaper.h"
+#include "cong-mark.h"
+
+static void
+TokenBucket_Refill(TokenBucket* tb, TscDuration elapsed)
+{
+  tb->tokens = RTE_MIN(tb->tokens + tb->rate * elapsed, tb->capacity);
+}
+
+static bool
+TokenBucket_HasTokens(TokenBucket* tb, double n)
+{
+  return tb->rate == 0 || tb->tokens >= n;
+}
+
+static void
+TokenBucket_Take(TokenBucket* tb, double n)
+{
+  if (tb->rate != 0) {
+    tb->tokens -= n;
+  }
+}
+
+uint16_t
+TxShaper_Dequeue(TxShaper* shaper,
+                 struct rte_ring* ring,
+                 Packet** npkts,
+                 uint16_t maxPkts,
+                 TscTime now)
+{
+  TscDuration elapsed = now - shaper->lastRefill;
+  shaper->lastRefill = now;
+  TokenBucket_Refill(&shaper->bytes, elapsed);
+  TokenBucket_Refill(&shaper->pkts, elapsed);
+
+  uint16_t nOut = 0;
+  while (nOut < maxPkts) {
+    if (shaper->nBacklog == 0) {
+      shaper->backlogHead = 0;
+      shaper->nBacklog = rte_ring_dequeue_burst(
+        ring, (void**)shaper->backlog, TXSHAPER_BACKLOG, NULL);
+      if (shaper->nBacklog == 0) {
+        break;
+      }
+    }
+
+    Packet* npkt = shaper->backlog[shaper->backlogHead];
+    uint32_t pktLen = Packet_ToMbuf(npkt)->pkt_len;
+    if (TokenBucket_HasTokens(&shaper->bytes, pktLen) &&
+        TokenBucket_HasTokens(&shaper->pkts, 1)) {
+      TokenBucket_Take(&shaper->bytes, pktLen);
+      TokenBucket_Take(&shaper->pkts, 1);
+      npkts[nOut++] = npkt;
+    } else if (CongMark_GetSojourn(npkt, now) > shaper->maxDelay) {
+      ++shaper->nDropped;
+      rte_pktmbuf_free(Packet_ToMbuf(npkt));
+    } else {
+      break;
+    }
+
+    ++shaper->backlogHead;
+    --shaper->nBacklog;
+    if (shaper->nBacklogSeen > 0) {
+      --shaper->nBacklogSeen;
+    }
+  }
+
+  shaper->nDelayed += shaper->nBacklog - shaper->nBacklogSeen;
+  shaper->nBacklogSeen = shaper->nBacklog;
+  return nOut;
+}
+
+void
+TxShaper_Clear(TxShaper* shaper)
+{
+  FreeMbufs((struct rte_mbuf**)&shaper->backlog[shaper->backlogHead],
+            shaper->nBacklog);
+  shaper->nDropped += shaper->nBacklog;
+  shaper->nBacklog = 0;
+  shaper->nBacklogSeen = 0;
+}
@@ -0,0 +1,190 @@
+package iface
+
+/*
+#include "face.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+
+	"ndn-dpdk/core/nnduration"
+	"ndn-dpdk/core/urcu"
+	"ndn-dpdk/dpdk"
+)
+
+// Per-face rate limit configuration.
+type RateLimitConfig struct {
+	BytesPerSecond   uint64                  // byte rate limit on L3 packets, 0 means unlimited
+	ByteBurst        uint64                  // byte bucket size, default is 10ms worth of BytesPerSecond but at least 9000; must not be less than face MTU
+	PacketsPerSecond uint64                  // packet rate limit, 0 means unlimited
+	PacketBurst      uint64                  // packet bucket size, default is 10ms worth of PacketsPerSecond but at least 1
+	MaxDelay         nnduration.Milliseconds // packets delayed longer than this are dropped, default is 100ms
+}
+
+func (cfg RateLimitConfig) Validate() error {
+	if cfg.BytesPerSecond == 0 && cfg.PacketsPerSecond == 0 {
+		return errors.New("either BytesPerSecond or PacketsPerSecond must be set")
+	}
+	// delay is measured with low 32 bits of TSC
+	if cfg.MaxDelay.Duration() > time.Second {
+		return errors.New("MaxDelay must not exceed 1s")
+	}
+	return nil
+}
+
+func (cfg RateLimitConfig) applyDefaults() RateLimitConfig {
+	burstOf := func(rate uint64, min uint64) uint64 {
+		if burst := rate / 100; burst > min {
+			return burst
+		}
+		return min
+	}
+	if cfg.BytesPerSecond > 0 && cfg.ByteBurst == 0 {
+		cfg.ByteBurst = burstOf(cfg.BytesPerSecond, 9000)
+	}
+	if cfg.PacketsPerSecond > 0 && cfg.PacketBurst == 0 {
+		cfg.PacketBurst = burstOf(cfg.PacketsPerSecond, 1)
+	}
+	if cfg.MaxDelay == 0 {
+		cfg.MaxDelay = 100
+	}
+	return cfg
+}
+
+// Traffic shaper counters.
+type ShaperCounters struct {
+	Delayed uint64 // packets delayed by the shaper
+	Dropped uint64 // packets dropped by the shaper
+}
+
+func (cnt ShaperCounters) String() string {
+	return fmt.Sprintf("%ddelayed %ddropped", cnt.Delayed, cnt.Dropped)
+}
+
+func (cnt ShaperCounters) add(other ShaperCounters) ShaperCounters {
+	cnt.Delayed += other.Delayed
+	cnt.Dropped += other.Dropped
+	return cnt
+}
+
+type shaperRecord struct {
+	cfg     *RateLimitConfig // current config, nil if disabled
+	cntBase ShaperCounters   // counters from previously installed instances
+}
+
+var (
+	shaperLock    sync.Mutex
+	shaperRecords = make(map[FaceId]*shaperRecord)
+)
+
+func shaperCountersFromC(shaperC *C.TxShaper) (cnt ShaperCounters) {
+	if shaperC == nil {
+		return cnt
+	}
+	cnt.Delayed = uint64(shaperC.nDelayed)
+	cnt.Dropped = uint64(shaperC.nDropped)
+	return cnt
+}
+
+func makeShaper(cfg RateLimitConfig, socket dpdk.NumaSocket) (shaperC *C.TxShaper) {
+	tscHz := float64(dpdk.ToTscDuration(time.Second))
+	initBucket := func(tb *C.TokenBucket, rate, capacity uint64) {
+		if rate == 0 {
+			return
+		}
+		tb.rate = C.double(float64(rate) / tscHz)
+		tb.capacity = C.double(capacity)
+		tb.tokens = tb.capacity
+	}
+
+	shaperC = (*C.TxShaper)(dpdk.Zmalloc("TxShaper", C.sizeof_TxShaper, socket))
+	initBucket(&shaperC.bytes, cfg.BytesPerSecond, cfg.ByteBurst)
+	initBucket(&shaperC.pkts, cfg.PacketsPerSecond, cfg.PacketBurst)
+	shaperC.lastRefill = C.TscTime(dpdk.TscNow())
+	shaperC.maxDelay = C.TscDuration(dpdk.ToTscDuration(cfg.MaxDelay.Duration()))
+	return shaperC
+}
+
+func freeShaper(shaperC *C.TxShaper) {
+	C.TxShaper_Clear(shaperC)
+	dpdk.Free(shaperC)
+}
+
+// Enable, reconfigure, or disable rate limiting on outgoing packets.
+// When enabled, TxLoop transmits L3 packets only if both byte and packet token buckets permit,
+// and drops packets that have been delayed for more than MaxDelay.
+// nil cfg disables rate limiting.
+func (face *FaceBase) SetRateLimit(cfg *RateLimitConfig) error {
+	faceC := face.getPtr()
+	if faceC.impl == nil {
+		return errors.New("face is closed")
+	}
+
+	shaperLock.Lock()
+	defer shaperLock.Unlock()
+	record := shaperRecords[face.id]
+	if record == nil {
+		record = new(shaperRecord)
+	}
+
+	var shaperC *C.TxShaper
+	if cfg != nil {
+		if e := cfg.Validate(); e != nil {
+			return e
+		}
+		c := cfg.applyDefaults()
+		// a packet longer than ByteBurst would never conform
+		if mtu := uint64(faceC.impl.tx.mtu); c.BytesPerSecond > 0 && c.ByteBurst < mtu {
+			return fmt.Errorf("ByteBurst must be at least MTU %d", mtu)
+		}
+		shaperC = makeShaper(c, face.GetNumaSocket())
+		record.cfg = &c
+	} else {
+		record.cfg = nil
+	}
+
+	oldShaperC := (*C.TxShaper)(urcu.NewPointer(&faceC.impl.tx.shaper).Xchg(unsafe.Pointer(shaperC)))
+	urcu.Synchronize()
+	if oldShaperC != nil {
+		C.TxShaper_Clear(oldShaperC)
+		record.cntBase = record.cntBase.add(shaperCountersFromC(oldShaperC))
+		dpdk.Free(oldShaperC)
+	}
+
+	shaperRecords[face.id] = record
+	return nil
+}
+
+// Get rate limit configuration.
+// Returns nil if rate limiting is disabled.
+func (face *FaceBase) GetRateLimit() *RateLimitConfig {
+	shaperLock.Lock()
+	defer shaperLock.Unlock()
+	if record := shaperRecords[face.id]; record != nil && record.cfg != nil {
+		cfg := *record.cfg
+		return &cfg
+	}
+	return nil
+}
+
+func (face FaceBase) readShaperCounters() (cnt ShaperCounters) {
+	shaperLock.Lock()
+	defer shaperLock.Unlock()
+	if record := shaperRecords[face.id]; record != nil {
+		cnt = record.cntBase
+	}
+	return cnt.add(shaperCountersFromC(face.getPtr().impl.tx.shaper))
+}
+
+func (face *FaceBase) clearShaper() {
+	shaperLock.Lock()
+	defer shaperLock.Unlock()
+	delete(shaperRecords, face.id)
+	if shaperC := face.getPtr().impl.tx.shaper; shaperC != nil {
+		freeShaper(shaperC)
+	}
+}
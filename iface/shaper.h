@@ -0,0 +1,65 @@
+#ifndef NDN_DPDK_IFACE_SHAPER_H
+#define NDN_DPDK_IFACE_SHAPER_H
+
+/// \file
+
+#include "common.h"
+
+/** \brief Capacity of TxShaper backlog.
+ *
+ *  This should be no less than TxLoop burst size.
+ */
+#define TXSHAPER_BACKLOG 64
+
+/** \brief Token bucket.
+ */
+typedef struct TokenBucket
+{
+  double tokens;   ///< available tokens
+  double rate;     ///< tokens added per TSC cycle, 0 means unlimited
+  double capacity; ///< bucket size
+} TokenBucket;
+
+/** \brief Per-face traffic shaper on outgoing L3 packets.
+ *
+ *  A packet conforms if both byte bucket and packet bucket have sufficient
+ *  tokens. Non-conforming packets wait in a backlog, while the remaining
+ *  packets stay in Face.txQueue. A packet that cannot conform within
+ *  \c maxDelay since enqueuing is dropped.
+ */
+typedef struct TxShaper
+{
+  TokenBucket bytes;    ///< byte bucket, counting L3 packet length
+  TokenBucket pkts;     ///< packet bucket
+  TscTime lastRefill;   ///< last time when tokens were added
+  TscDuration maxDelay; ///< max duration a packet can be delayed
+
+  uint16_t backlogHead;  ///< index of first packet in backlog
+  uint16_t nBacklog;     ///< number of packets in backlog
+  uint16_t nBacklogSeen; ///< number of backlog packets counted in nDelayed
+
+  uint64_t nDelayed; ///< packets that waited in backlog
+  uint64_t nDropped; ///< packets dropped because of exceeding maxDelay
+
+  Packet* backlog[TXSHAPER_BACKLOG];
+} TxShaper;
+
+/** \brief Dequeue conforming packets.
+ *  \param ring Face.txQueue.
+ *  \param[out] npkts conforming packets; TxShaper releases ownership.
+ *  \param maxPkts size of \p npkts array.
+ *  \return number of conforming packets.
+ */
+uint16_t
+TxShaper_Dequeue(TxShaper* shaper,
+                 struct rte_ring* ring,
+                 Packet** npkts,
+                 uint16_t maxPkts,
+                 TscTime now);
+
+/** \brief Drop packets in backlog.
+ */
+void
+TxShaper_Clear(TxShaper* shaper);
+
+#endif // NDN_DPDK_IFACE_SHAPER_H
@@ -2,23 +2,29 @@ package socketface
 
 import (
 	"fmt"
+	"sync/atomic"
 )
 
 // Extended counters.
 type ExCounters struct {
-	NRedials   int
-	TxQueueCap int
-	TxQueueLen int
+	NRedials    int
+	RxTruncated uint64 // datagrams dropped because they exceed mbuf dataroom
+	TxQueueCap  int
+	TxQueueLen  int
+	TxDropped   uint64 // frames dropped after leaving the queue
 }
 
 func (cnt ExCounters) String() string {
-	return fmt.Sprintf("%dredials, tx %dqueued %dmax", cnt.NRedials, cnt.TxQueueLen, cnt.TxQueueCap)
+	return fmt.Sprintf("%dredials, rx %dtruncated, tx %dqueued %dmax %ddropped",
+		cnt.NRedials, cnt.RxTruncated, cnt.TxQueueLen, cnt.TxQueueCap, cnt.TxDropped)
 }
 
 func (face *SocketFace) ReadExCounters() interface{} {
 	return ExCounters{
-		NRedials:   face.nRedials,
-		TxQueueCap: cap(face.txQueue),
-		TxQueueLen: len(face.txQueue),
+		NRedials:    face.nRedials,
+		RxTruncated: atomic.LoadUint64(&face.nRxTruncated),
+		TxQueueCap:  cap(face.txQueue),
+		TxQueueLen:  len(face.txQueue),
+		TxDropped:   atomic.LoadUint64(&face.nTxDropped),
 	}
 }
@@ -1,54 +1,203 @@
 package socketface
 
+/*
+#include "datagram.h"
+*/
 import "C"
 import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
 
 	"ndn-dpdk/dpdk"
 )
 
+// Maximum number of datagrams in a batched syscall.
+const DATAGRAM_BURST_SIZE = C.SOCKET_DATAGRAM_BURST_SIZE
+
+// If true, datagram sockets use one syscall per packet instead of batched syscalls.
+// This is intended for benchmarking.
+var DisableDatagramBatch = false
+
 // SocketFace implementation for datagram-oriented sockets.
+//
+// On a connected UDP or unixgram socket, packets are received with recvmmsg and transmitted with sendmmsg,
+// directly into and out of mbufs.
+// Other sockets, such as on-demand UDP faces sharing a listener socket, fall back to one packet per syscall.
 type datagramImpl struct{}
 
+// Get syscall.RawConn for batched syscalls, or nil if conn does not support them.
+func getBatchConn(conn net.Conn) syscall.RawConn {
+	if DisableDatagramBatch {
+		return nil
+	}
+	var sc syscall.Conn
+	switch c := conn.(type) {
+	case *net.UDPConn:
+		sc = c
+	case *net.UnixConn:
+		sc = c
+	default:
+		return nil
+	}
+	raw, e := sc.SyscallConn()
+	if e != nil {
+		return nil
+	}
+	return raw
+}
+
+// Construct an error similar to those returned by net.Conn.
+func makeBatchError(conn net.Conn, op, syscallName string, errno syscall.Errno) error {
+	return &net.OpError{
+		Op:     op,
+		Net:    conn.LocalAddr().Network(),
+		Source: conn.LocalAddr(),
+		Addr:   conn.RemoteAddr(),
+		Err:    os.NewSyscallError(syscallName, errno),
+	}
+}
+
 func (datagramImpl) RxLoop(face *SocketFace) {
+	rx := (*C.SocketDatagramRx)(dpdk.Zmalloc("SocketDatagramRx", C.sizeof_SocketDatagramRx, face.GetNumaSocket()))
+	rx.mp = (*C.struct_rte_mempool)(face.rxMp.GetPtr())
+	defer func() {
+		C.SocketDatagramRx_Close(rx)
+		dpdk.Free(rx)
+	}()
+
+	pkts := make([]dpdk.Packet, DATAGRAM_BURST_SIZE)
 	for {
-		mbuf, e := face.rxMp.Alloc()
-		if e != nil {
+		conn := face.GetConn()
+		var n int
+		var e error
+		if raw := getBatchConn(conn); raw != nil {
+			n, e = rxBatch(conn, raw, rx, pkts)
+			if rx.nTruncated > 0 {
+				atomic.AddUint64(&face.nRxTruncated, uint64(rx.nTruncated))
+				rx.nTruncated = 0
+			}
+		} else {
+			n, e = rxSingle(conn, face.rxMp, pkts)
+		}
+		if e == errRxAlloc {
 			face.logger.WithError(e).Error("RX alloc error")
+			time.Sleep(rxAllocRetryInterval)
 			continue
 		}
-
-		pkt := mbuf.AsPacket()
-		seg0 := pkt.GetFirstSegment()
-		seg0.SetHeadroom(0)
-
-		buf := seg0.AsByteSlice()
-		buf = buf[:cap(buf)]
-		nOctets, e := face.GetConn().Read(buf)
 		if e != nil {
 			if face.handleError("RX", e) {
-				pkt.Close()
 				return
 			}
 			continue
 		}
-		seg0.Append(buf[:nOctets])
+		if n > 0 {
+			face.rxBurst(pkts[:n])
+		}
+	}
+}
+
+var errRxAlloc = errors.New("mempool exhausted")
 
-		face.rxPkt(pkt)
+// Receive a burst of datagrams with recvmmsg.
+func rxBatch(conn net.Conn, raw syscall.RawConn, rx *C.SocketDatagramRx, pkts []dpdk.Packet) (n int, e error) {
+	var res C.int
+	e = raw.Read(func(fd uintptr) bool {
+		res = C.SocketDatagramRx_Burst(rx, C.int(fd),
+			(**C.struct_rte_mbuf)(unsafe.Pointer(&pkts[0])), C.uint16_t(len(pkts)))
+		return res != -C.EAGAIN
+	})
+	switch {
+	case e != nil:
+		return 0, e
+	case res == -C.ENOBUFS:
+		return 0, errRxAlloc
+	case res < 0:
+		return 0, makeBatchError(conn, "read", "recvmmsg", syscall.Errno(-res))
 	}
+	return int(res), nil
 }
 
-func (datagramImpl) Send(face *SocketFace, pkt dpdk.Packet) error {
-	var buf []byte
-	if pkt.CountSegments() > 1 {
-		buf = pkt.ReadAll()
-	} else {
-		buf = pkt.GetFirstSegment().AsByteSlice()
+// Receive one datagram with net.Conn.Read.
+func rxSingle(conn net.Conn, mp dpdk.PktmbufPool, pkts []dpdk.Packet) (n int, e error) {
+	mbuf, e := mp.Alloc()
+	if e != nil {
+		return 0, errRxAlloc
 	}
-	_, e := face.GetConn().Write(buf)
-	return e
+
+	pkt := mbuf.AsPacket()
+	seg0 := pkt.GetFirstSegment()
+	seg0.SetHeadroom(0)
+
+	buf := seg0.AsByteSlice()
+	buf = buf[:cap(buf)]
+	nOctets, e := conn.Read(buf)
+	if e != nil {
+		pkt.Close()
+		return 0, e
+	}
+	seg0.Append(buf[:nOctets])
+
+	pkts[0] = pkt
+	return 1, nil
+}
+
+func (impl datagramImpl) Send(face *SocketFace, pkt dpdk.Packet) error {
+	return impl.SendBurst(face, []dpdk.Packet{pkt})
+}
+
+func (datagramImpl) SendBurst(face *SocketFace, pkts []dpdk.Packet) error {
+	conn := face.GetConn()
+	if raw := getBatchConn(conn); raw != nil {
+		return txBatch(face, conn, raw, pkts)
+	}
+
+	for _, pkt := range pkts {
+		var buf []byte
+		if pkt.CountSegments() > 1 {
+			buf = pkt.ReadAll()
+		} else {
+			buf = pkt.GetFirstSegment().AsByteSlice()
+		}
+		if _, e := conn.Write(buf); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// Transmit a burst of frames with sendmmsg.
+// A frame with too many segments for sendmmsg is copied into a contiguous buffer and sent with net.Conn.Write.
+func txBatch(face *SocketFace, conn net.Conn, raw syscall.RawConn, pkts []dpdk.Packet) error {
+	for len(pkts) > 0 {
+		if pkts[0].CountSegments() > C.SOCKET_DATAGRAM_MAX_SEGS {
+			if _, e := conn.Write(pkts[0].ReadAll()); e != nil {
+				atomic.AddUint64(&face.nTxDropped, 1)
+				return e
+			}
+			pkts = pkts[1:]
+			continue
+		}
+
+		var res C.int
+		if e := raw.Write(func(fd uintptr) bool {
+			res = C.SocketDatagram_TxBurst(C.int(fd),
+				(**C.struct_rte_mbuf)(unsafe.Pointer(&pkts[0])), C.uint16_t(len(pkts)))
+			return res != -C.EAGAIN
+		}); e != nil {
+			return e
+		}
+		if res < 0 {
+			return makeBatchError(conn, "write", "sendmmsg", syscall.Errno(-res))
+		}
+		pkts = pkts[res:]
+	}
+	return nil
 }
 
 type udpImpl struct {
@@ -61,18 +210,18 @@ func (udpImpl) ValidateAddr(network, address string, isLocal bool) error {
 	return e
 }
 
-func (udpImpl) Dial(network, local, remote string) (net.Conn, error) {
-	raddr, e := net.ResolveUDPAddr(network, remote)
+func (udpImpl) Dial(loc Locator) (net.Conn, error) {
+	raddr, e := net.ResolveUDPAddr(loc.Scheme, loc.Remote)
 	if e != nil {
 		return nil, fmt.Errorf("Remote: %v", e)
 	}
 	laddr := &net.UDPAddr{Port: raddr.Port}
-	if local != "" {
-		if laddr, e = net.ResolveUDPAddr(network, local); e != nil {
+	if loc.Local != "" {
+		if laddr, e = net.ResolveUDPAddr(loc.Scheme, loc.Local); e != nil {
 			return nil, fmt.Errorf("Local: %v", e)
 		}
 	}
-	return net.DialUDP(network, laddr, raddr)
+	return net.DialUDP(loc.Scheme, laddr, raddr)
 }
 
 type unixgramImpl struct {
@@ -82,6 +231,6 @@ type unixgramImpl struct {
 	noLocalAddrRedialer
 }
 
-func (unixgramImpl) Dial(network, local, remote string) (net.Conn, error) {
+func (unixgramImpl) Dial(loc Locator) (net.Conn, error) {
 	return nil, errors.New("not implemented")
 }
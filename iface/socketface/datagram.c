@@ -0,0 +1,110 @@
+#define _GNU_SOURCE
+#include "datagram.h"
+
+#include <sys/socket.h>
+
+/** \brief Allocate spare mbufs, up to \p nPkts in total.
+ *
+ *  If the mempool cannot supply all of them, smaller batches are attempted.
+ */
+static void
+SocketDatagramRx_Refill(SocketDatagramRx* rx, uint16_t nPkts)
+{
+  if (rx->nSpare >= nPkts) {
+    return;
+  }
+  uint16_t n = nPkts - rx->nSpare;
+  while (n > 0 &&
+         rte_pktmbuf_alloc_bulk(rx->mp, &rx->spare[rx->nSpare], n) != 0) {
+    n /= 2;
+  }
+  rx->nSpare += n;
+}
+
+int
+SocketDatagramRx_Burst(SocketDatagramRx* rx,
+                       int fd,
+                       struct rte_mbuf** pkts,
+                       uint16_t nPkts)
+{
+  assert(nPkts <= SOCKET_DATAGRAM_BURST_SIZE);
+  SocketDatagramRx_Refill(rx, nPkts);
+  if (unlikely(rx->nSpare == 0)) {
+    return -ENOBUFS;
+  }
+  uint16_t nMsgs = RTE_MIN(rx->nSpare, nPkts);
+
+  struct iovec iov[SOCKET_DATAGRAM_BURST_SIZE];
+  struct mmsghdr msgs[SOCKET_DATAGRAM_BURST_SIZE];
+  memset(msgs, 0, sizeof(msgs[0]) * nMsgs);
+  for (uint16_t i = 0; i < nMsgs; ++i) {
+    struct rte_mbuf* pkt = rx->spare[i];
+    pkt->data_off = 0;
+    iov[i].iov_base = rte_pktmbuf_mtod(pkt, void*);
+    iov[i].iov_len = pkt->buf_len;
+    msgs[i].msg_hdr.msg_iov = &iov[i];
+    msgs[i].msg_hdr.msg_iovlen = 1;
+  }
+
+  int nRx = recvmmsg(fd, msgs, nMsgs, MSG_DONTWAIT, NULL);
+  if (unlikely(nRx < 0)) {
+    return -errno; // keep spare mbufs for next burst
+  }
+
+  int nValid = 0;
+  for (int i = 0; i < nRx; ++i) {
+    struct rte_mbuf* pkt = rx->spare[i];
+    if (unlikely(msgs[i].msg_hdr.msg_flags & MSG_TRUNC)) {
+      ++rx->nTruncated;
+      rte_pktmbuf_free(pkt);
+      continue;
+    }
+    pkt->data_len = msgs[i].msg_len;
+    pkt->pkt_len = msgs[i].msg_len;
+    pkts[nValid++] = pkt;
+  }
+  rx->nSpare -= nRx;
+  memmove(&rx->spare[0], &rx->spare[nRx], sizeof(rx->spare[0]) * rx->nSpare);
+  return nValid;
+}
+
+void
+SocketDatagramRx_Close(SocketDatagramRx* rx)
+{
+  FreeMbufs(rx->spare, rx->nSpare);
+  rx->nSpare = 0;
+}
+
+int
+SocketDatagram_TxBurst(int fd, struct rte_mbuf** pkts, uint16_t nPkts)
+{
+  assert(nPkts <= SOCKET_DATAGRAM_BURST_SIZE);
+  struct iovec iov[SOCKET_DATAGRAM_BURST_SIZE][SOCKET_DATAGRAM_MAX_SEGS];
+  struct mmsghdr msgs[SOCKET_DATAGRAM_BURST_SIZE];
+  int nMsgs = 0;
+  for (; nMsgs < nPkts; ++nMsgs) {
+    struct rte_mbuf* pkt = pkts[nMsgs];
+    if (unlikely(pkt->nb_segs > SOCKET_DATAGRAM_MAX_SEGS)) {
+      break;
+    }
+
+    int nSegs = 0;
+    for (struct rte_mbuf* seg = pkt; seg != NULL; seg = seg->next) {
+      iov[nMsgs][nSegs].iov_base = rte_pktmbuf_mtod(seg, void*);
+      iov[nMsgs][nSegs].iov_len = seg->data_len;
+      ++nSegs;
+    }
+    memset(&msgs[nMsgs], 0, sizeof(msgs[nMsgs]));
+    msgs[nMsgs].msg_hdr.msg_iov = iov[nMsgs];
+    msgs[nMsgs].msg_hdr.msg_iovlen = nSegs;
+  }
+  if (unlikely(nMsgs == 0)) {
+    return 0;
+  }
+
+  int nTx = sendmmsg(fd, msgs, nMsgs, MSG_DONTWAIT);
+  if (unlikely(nTx < 0)) {
+    return -errno;
+  }
+  return nTx;
+}
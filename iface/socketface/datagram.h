@@ -0,0 +1,71 @@
+#ifndef NDN_DPDK_IFACE_SOCKETFACE_DATAGRAM_H
+#define NDN_DPDK_IFACE_SOCKETFACE_DATAGRAM_H
+
+/// \file
+
+#include "../../dpdk/mbuf.h"
+
+#include <errno.h>
+
+/** \brief Maximum number of datagrams in a batched syscall.
+ */
+#define SOCKET_DATAGRAM_BURST_SIZE 64
+
+/** \brief Maximum number of segments in an outgoing frame.
+ */
+#define SOCKET_DATAGRAM_MAX_SEGS 8
+
+/** \brief Receive state of a datagram socket.
+ *
+ *  Mbufs allocated for a burst but not filled by recvmmsg(2) are kept as
+ *  spares for the next burst, so that an empty socket does not cause
+ *  repeated allocation and release of mbufs.
+ */
+typedef struct SocketDatagramRx
+{
+  struct rte_mempool* mp; ///< mempool for received frames
+  uint16_t nSpare;        ///< number of mbufs in \c spare
+  struct rte_mbuf* spare[SOCKET_DATAGRAM_BURST_SIZE];
+  uint64_t nTruncated; ///< datagrams dropped for exceeding mbuf dataroom
+} SocketDatagramRx;
+
+/** \brief Receive a burst of datagrams with recvmmsg(2).
+ *  \param fd a non-blocking datagram socket.
+ *  \param[out] pkts received frames; each datagram is received directly into
+ *                   the dataroom of one mbuf.
+ *  \param nPkts size of \p pkts, at most SOCKET_DATAGRAM_BURST_SIZE.
+ *  \return number of received frames, or negative errno.
+ *  \retval -EAGAIN no datagram is available.
+ *  \retval -ENOBUFS mempool is exhausted.
+ *
+ *  If the mempool cannot supply \p nPkts mbufs, this function receives into
+ *  as many mbufs as available.
+ *  A datagram truncated by the kernel (MSG_TRUNC) is dropped and counted in
+ *  \c rx->nTruncated; the return value may be zero in this case.
+ */
+int
+SocketDatagramRx_Burst(SocketDatagramRx* rx,
+                       int fd,
+                       struct rte_mbuf** pkts,
+                       uint16_t nPkts);
+
+/** \brief Release spare mbufs.
+ */
+void
+SocketDatagramRx_Close(SocketDatagramRx* rx);
+
+/** \brief Transmit a burst of frames with sendmmsg(2).
+ *  \param fd a non-blocking connected datagram socket.
+ *  \param pkts frames to transmit; segments are gathered without copying.
+ *  \param nPkts size of \p pkts, at most SOCKET_DATAGRAM_BURST_SIZE.
+ *  \return number of sent frames, or negative errno.
+ *  \retval -EAGAIN socket buffer is full.
+ *
+ *  Transmission stops before the first frame with more than
+ *  SOCKET_DATAGRAM_MAX_SEGS segments; the caller should send that frame
+ *  from a contiguous copy. This function does not free the frames.
+ */
+int
+SocketDatagram_TxBurst(int fd, struct rte_mbuf** pkts, uint16_t nPkts);
+
+#endif // NDN_DPDK_IFACE_SOCKETFACE_DATAGRAM_H
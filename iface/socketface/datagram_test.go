@@ -1,13 +1,19 @@
 package socketface_test
 
 import (
+	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"golang.org/x/sys/unix"
 
+	"ndn-dpdk/dpdk"
 	"ndn-dpdk/iface"
 	"ndn-dpdk/iface/ifacetestfixture"
 	"ndn-dpdk/iface/socketface"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
 )
 
 func TestDatagram(t *testing.T) {
@@ -28,6 +34,110 @@ func TestDatagram(t *testing.T) {
 	fixture.CheckCounters()
 }
 
+func TestDatagramTruncated(t *testing.T) {
+	assert, require := makeAR(t)
+
+	fd, e := unix.Socketpair(unix.AF_UNIX, unix.SOCK_DGRAM, 0)
+	require.NoError(e)
+	defer unix.Close(fd[1])
+
+	face, e := socketface.New(makeConnFromFd(fd[0]), socketfaceCfg)
+	require.NoError(e)
+	defer face.Close()
+
+	// datagram exceeds mbuf dataroom
+	_, e = unix.Write(fd[1], make([]byte, 60000))
+	require.NoError(e)
+	time.Sleep(100 * time.Millisecond)
+
+	cnt := face.ReadExCounters().(socketface.ExCounters)
+	assert.EqualValues(1, cnt.RxTruncated)
+}
+
+// Measure packet rate between two datagram SocketFaces, with and without batched syscalls.
+func BenchmarkDatagram(b *testing.B) {
+	for _, disableBatch := range []bool{true, false} {
+		name := "batch"
+		if disableBatch {
+			name = "single"
+		}
+		b.Run(name, func(b *testing.B) {
+			socketface.DisableDatagramBatch = disableBatch
+			defer func() { socketface.DisableDatagramBatch = false }()
+			benchmarkDatagram(b)
+		})
+	}
+}
+
+func benchmarkDatagram(b *testing.B) {
+	fd, e := unix.Socketpair(unix.AF_UNIX, unix.SOCK_DGRAM, 0)
+	if e != nil {
+		b.Fatal(e)
+	}
+	faceA, e := socketface.New(makeConnFromFd(fd[0]), socketfaceCfg)
+	if e != nil {
+		b.Fatal(e)
+	}
+	defer faceA.Close()
+	faceB, e := socketface.New(makeConnFromFd(fd[1]), socketfaceCfg)
+	if e != nil {
+		b.Fatal(e)
+	}
+	defer faceB.Close()
+
+	slaves := dpdk.ListSlaveLCores()
+	var nRx int64
+	rxl := iface.NewRxLoop(dpdk.NUMA_SOCKET_ANY)
+	rxl.SetLCore(slaves[0])
+	rxl.SetCallback(iface.WrapRxCb(func(burst iface.RxBurst) {
+		for _, interest := range burst.ListInterests() {
+			ndntestutil.ClosePacket(interest)
+			atomic.AddInt64(&nRx, 1)
+		}
+	}))
+	if e := rxl.Launch(); e != nil {
+		b.Fatal(e)
+	}
+	defer rxl.Close()
+	defer rxl.Stop()
+	time.Sleep(50 * time.Millisecond)
+	rxl.AddRxGroup(iface.TheChanRxGroup)
+	defer rxl.RemoveRxGroup(iface.TheChanRxGroup)
+
+	txl := iface.NewTxLoop(dpdk.NUMA_SOCKET_ANY)
+	txl.SetLCore(slaves[1])
+	if e := txl.Launch(); e != nil {
+		b.Fatal(e)
+	}
+	defer txl.Close()
+	defer txl.Stop()
+	txl.AddFace(faceA)
+	defer txl.RemoveFace(faceA)
+	time.Sleep(50 * time.Millisecond)
+
+	waitRx := func(target int) {
+		for deadline := time.Now().Add(10 * time.Second); atomic.LoadInt64(&nRx) < int64(target); {
+			if time.Now().After(deadline) {
+				b.Fatalf("received %d of %d packets", atomic.LoadInt64(&nRx), target)
+			}
+			time.Sleep(time.Microsecond)
+		}
+	}
+
+	const window = 48 // stay below queue capacities to avoid drops
+	b.ResetTimer()
+	for nTx := 0; nTx < b.N; {
+		waitRx(nTx - window)
+		burst := make([]ndn.Packet, 0, 16)
+		for ; len(burst) < cap(burst) && nTx < b.N; nTx++ {
+			burst = append(burst, ndntestutil.MakeInterest(fmt.Sprintf("/B/%d", nTx)).GetPacket())
+		}
+		faceA.TxBurst(burst)
+	}
+	waitRx(b.N)
+	b.StopTimer()
+}
+
 func TestUdp(t *testing.T) {
 	assert, require := makeAR(t)
 
@@ -43,3 +153,54 @@ func TestUdp(t *testing.T) {
 	assert.Equal("127.0.0.1:7000", loc.Remote)
 	ifacetestfixture.CheckLocatorMarshal(t, loc)
 }
+
+func TestMcastLocator(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	parse := func(input string) (loc socketface.Locator, e error) {
+		l, e := iface.ParseLocator(input)
+		if e != nil {
+			return loc, e
+		}
+		return l.(socketface.Locator), nil
+	}
+
+	loc, e := parse(`{ "Scheme": "udp4-mcast", "Interface": "eth1", "Ttl": 4, "Loopback": true }`)
+	if assert.NoError(e) {
+		assert.Equal("eth1", loc.Interface)
+		assert.Equal(4, loc.Ttl)
+		assert.True(loc.Loopback)
+		ifacetestfixture.CheckLocatorMarshal(t, loc)
+	}
+	_, e = parse(`{ "Scheme": "udp6-mcast", "Remote": "[ff02::114]:56363" }`)
+	assert.NoError(e)
+
+	_, e = parse(`{ "Scheme": "udp4-mcast", "Remote": "192.0.2.1:56363" }`)
+	assert.Error(e) // not multicast
+	_, e = parse(`{ "Scheme": "udp4-mcast", "Remote": "[ff02::114]:56363" }`)
+	assert.Error(e) // wrong address family
+	_, e = parse(`{ "Scheme": "udp4-mcast", "Local": "192.0.2.1:56363" }`)
+	assert.Error(e) // Local not allowed
+	_, e = parse(`{ "Scheme": "udp4-mcast", "Ttl": 256 }`)
+	assert.Error(e) // Ttl out of range
+	_, e = parse(`{ "Scheme": "udp", "Remote": "192.0.2.1:6363", "Ttl": 4 }`)
+	assert.Error(e) // multicast option on unicast scheme
+}
+
+func TestMcast(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	loc := iface.MustParseLocator(`{ "Scheme": "udp4-mcast", "Remote": "224.0.23.170:56363", "Loopback": true }`).(socketface.Locator)
+	face, e := socketface.Create(loc, socketfaceCfg)
+	if e != nil {
+		t.Skipf("cannot join multicast group: %v", e)
+	}
+	defer face.Close()
+
+	assert.False(face.IsLocal())
+	loc = face.GetLocator().(socketface.Locator)
+	assert.Equal("udp4-mcast", loc.Scheme)
+	assert.Equal("224.0.23.170:56363", loc.Remote)
+	assert.True(loc.Loopback)
+	ifacetestfixture.CheckLocatorMarshal(t, loc)
+}
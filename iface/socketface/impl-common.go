@@ -22,8 +22,8 @@ func (unixAddrValidator) ValidateAddr(network, address string, isLocal bool) (e
 // Provides a Dial function that only uses remote addr.
 type noLocalAddrDialer struct{}
 
-func (noLocalAddrDialer) Dial(network, local, remote string) (net.Conn, error) {
-	return net.Dial(network, remote)
+func (noLocalAddrDialer) Dial(loc Locator) (net.Conn, error) {
+	return net.Dial(loc.Scheme, loc.Remote)
 }
 
 // Provides a Redial function that reuses LocalAddr.
@@ -11,12 +11,12 @@ type iImpl interface {
 	ValidateAddr(network, address string, isLocal bool) error
 
 	// Dial the socket.
-	Dial(network, local, remote string) (net.Conn, error)
+	Dial(loc Locator) (net.Conn, error)
 
 	// Redial the socket.
 	Redial(oldConn net.Conn) (net.Conn, error)
 
-	// Receive packets on the socket and pass them to face.rxPkt.
+	// Receive packets on the socket and pass them to face.rxPkt or face.rxBurst.
 	// Loop until a fatal error occurs or face.rxQuit receives a message.
 	RxLoop(face *SocketFace)
 
@@ -24,4 +24,10 @@ type iImpl interface {
 	Send(face *SocketFace, pkt dpdk.Packet) error
 }
 
+// Optional interface of iImpl that can transmit several packets at once.
+type iBurstSender interface {
+	// Transmit a burst of packets on the socket.
+	SendBurst(face *SocketFace, pkts []dpdk.Packet) error
+}
+
 var implByNetwork = make(map[string]iImpl)
@@ -13,9 +13,13 @@ func init() {
 	implByNetwork["tcp4"] = tcpImpl{}
 	implByNetwork["tcp6"] = tcpImpl{}
 	implByNetwork["unix"] = unixImpl{}
+	implByNetwork["udp4-mcast"] = mcastImpl{}
+	implByNetwork["udp6-mcast"] = mcastImpl{}
 
 	iface.RegisterLocatorType(Locator{}, "udp")
 	iface.RegisterLocatorType(Locator{}, "unixgram")
 	iface.RegisterLocatorType(Locator{}, "tcp")
 	iface.RegisterLocatorType(Locator{}, "unix")
+	iface.RegisterLocatorType(Locator{}, "udp4-mcast")
+	iface.RegisterLocatorType(Locator{}, "udp6-mcast")
 }
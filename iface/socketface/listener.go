@@ -0,0 +1,429 @@
+package socketface
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"ndn-dpdk/core/logger"
+	"ndn-dpdk/core/nnduration"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+)
+
+// Listener configuration.
+type ListenerConfig struct {
+	Scheme string // "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6", or "unix"
+	Local  string // local address to listen on
+
+	// Idle timeout of on-demand UDP faces.
+	// An on-demand face is closed if it has not received a packet for this duration.
+	// Zero disables idle timeout.
+	IdleTimeout nnduration.Milliseconds `json:",omitempty"`
+
+	// Maximum number of faces accepted by this listener.
+	// Zero means DefaultListenerMaxFaces.
+	MaxFaces int `json:",omitempty"`
+}
+
+// Default maximum number of faces accepted by a listener.
+const DefaultListenerMaxFaces = 256
+
+func (lc ListenerConfig) Validate() error {
+	switch lc.Scheme {
+	case "udp", "udp4", "udp6":
+		_, e := net.ResolveUDPAddr(lc.Scheme, lc.Local)
+		return e
+	case "tcp", "tcp4", "tcp6":
+		_, e := net.ResolveTCPAddr(lc.Scheme, lc.Local)
+		return e
+	case "unix":
+		_, e := net.ResolveUnixAddr(lc.Scheme, lc.Local)
+		return e
+	}
+	return fmt.Errorf("scheme %s cannot listen", lc.Scheme)
+}
+
+func (lc ListenerConfig) getMaxFaces() int {
+	if lc.MaxFaces <= 0 {
+		return DefaultListenerMaxFaces
+	}
+	return lc.MaxFaces
+}
+
+// Listener that creates a SocketFace for each accepted peer.
+//
+// On a stream-oriented socket, each accepted connection becomes a face.
+// On a UDP socket, each remote endpoint becomes an "on-demand" face when its first packet arrives.
+// Accepted faces cannot redial; they are closed when the socket fails.
+type Listener struct {
+	cfg     ListenerConfig
+	faceCfg Config
+	logger  logrus.FieldLogger
+
+	ln      net.Listener // stream-oriented socket
+	udpConn *net.UDPConn // UDP socket
+
+	closing int32 // 1 if listener is closing, need atomic access
+	quit    chan struct{}
+	quitWg  sync.WaitGroup
+
+	facesLock sync.Mutex
+	faces     map[iface.FaceId]*SocketFace
+	udpPeers  map[string]*udpPeerConn
+}
+
+// Start listening.
+func Listen(lc ListenerConfig, cfg Config) (l *Listener, e error) {
+	if e = lc.Validate(); e != nil {
+		return nil, e
+	}
+
+	l = &Listener{
+		cfg:      lc,
+		faceCfg:  cfg,
+		quit:     make(chan struct{}),
+		faces:    make(map[iface.FaceId]*SocketFace),
+		udpPeers: make(map[string]*udpPeerConn),
+	}
+
+	switch lc.Scheme {
+	case "udp", "udp4", "udp6":
+		laddr, _ := net.ResolveUDPAddr(lc.Scheme, lc.Local)
+		if l.udpConn, e = net.ListenUDP(lc.Scheme, laddr); e != nil {
+			return nil, e
+		}
+		l.cfg.Local = l.udpConn.LocalAddr().String()
+	default:
+		if l.ln, e = net.Listen(lc.Scheme, lc.Local); e != nil {
+			return nil, e
+		}
+		l.cfg.Local = l.ln.Addr().String()
+	}
+	l.logger = logger.NewWithPrefix("socketface", fmt.Sprintf("listener %s %s", l.cfg.Scheme, l.cfg.Local))
+
+	if l.udpConn != nil {
+		l.quitWg.Add(2)
+		go l.udpRxLoop()
+		go l.idleLoop()
+	} else {
+		l.quitWg.Add(1)
+		go l.acceptLoop()
+	}
+	l.logger.Info("listening")
+	return l, nil
+}
+
+// Get listener configuration, where Local reflects the actual listening address.
+func (l *Listener) GetConfig() ListenerConfig {
+	return l.cfg
+}
+
+// List faces accepted by this listener.
+func (l *Listener) ListFaces() (list []*SocketFace) {
+	l.facesLock.Lock()
+	defer l.facesLock.Unlock()
+	for _, face := range l.faces {
+		list = append(list, face)
+	}
+	return list
+}
+
+// Stop listening and close accepted faces.
+func (l *Listener) Close() error {
+	if !atomic.CompareAndSwapInt32(&l.closing, 0, 1) {
+		return nil
+	}
+	close(l.quit)
+	if l.ln != nil {
+		l.ln.Close()
+	}
+	if l.udpConn != nil {
+		l.udpConn.Close()
+	}
+	l.quitWg.Wait()
+
+	for _, face := range l.ListFaces() {
+		face.Close()
+	}
+	l.logger.Info("closed")
+	return nil
+}
+
+// Determine whether the listener has reached MaxFaces.
+func (l *Listener) isFull() bool {
+	l.facesLock.Lock()
+	defer l.facesLock.Unlock()
+	return len(l.faces) >= l.cfg.getMaxFaces()
+}
+
+// Create a face for an accepted peer.
+func (l *Listener) acceptFace(conn net.Conn) (face *SocketFace, e error) {
+	if l.isFull() {
+		return nil, errors.New("too many faces")
+	}
+	if l.faceCfg.Locker != nil {
+		l.faceCfg.Locker.Lock()
+		defer l.faceCfg.Locker.Unlock()
+	}
+	if face, e = newFace(conn, l.faceCfg, l); e != nil {
+		return nil, e
+	}
+
+	l.facesLock.Lock()
+	defer l.facesLock.Unlock()
+	l.faces[face.GetFaceId()] = face
+	return face, nil
+}
+
+func (l *Listener) removeFace(face *SocketFace) {
+	l.facesLock.Lock()
+	defer l.facesLock.Unlock()
+	delete(l.faces, face.GetFaceId())
+}
+
+func (l *Listener) acceptLoop() {
+	defer l.quitWg.Done()
+	for {
+		conn, e := l.ln.Accept()
+		if e != nil {
+			if atomic.LoadInt32(&l.closing) != 0 {
+				return
+			}
+			if netErr, ok := e.(net.Error); ok && netErr.Temporary() {
+				l.logger.WithError(e).Warn("accept error")
+				continue
+			}
+			l.logger.WithError(e).Error("accept failed")
+			return
+		}
+
+		if _, e := l.acceptFace(conn); e != nil {
+			l.logger.WithError(e).Warn("face creation failed")
+			conn.Close()
+		}
+	}
+}
+
+func (l *Listener) udpRxLoop() {
+	defer l.quitWg.Done()
+	for {
+		mbuf, e := l.faceCfg.RxMp.Alloc()
+		if e != nil {
+			// datagram stays in socket buffer, retry after faces have released some mbufs
+			l.logger.WithError(e).Warn("RX alloc error")
+			select {
+			case <-l.quit:
+				return
+			case <-time.After(rxAllocRetryInterval):
+			}
+			continue
+		}
+
+		pkt := mbuf.AsPacket()
+		seg0 := pkt.GetFirstSegment()
+		seg0.SetHeadroom(0)
+
+		buf := seg0.AsByteSlice()
+		buf = buf[:cap(buf)]
+		nOctets, raddr, e := l.udpConn.ReadFromUDP(buf)
+		if e != nil {
+			pkt.Close()
+			if atomic.LoadInt32(&l.closing) != 0 {
+				return
+			}
+			if netErr, ok := e.(net.Error); ok && netErr.Temporary() {
+				continue
+			}
+			l.logger.WithError(e).Error("RX failed")
+			return
+		}
+		seg0.Append(buf[:nOctets])
+
+		if peer := l.getUdpPeer(raddr); peer != nil {
+			peer.deliver(pkt)
+		} else {
+			pkt.Close()
+		}
+	}
+}
+
+// Find or create on-demand face for a UDP remote endpoint.
+func (l *Listener) getUdpPeer(raddr *net.UDPAddr) *udpPeerConn {
+	key := raddr.String()
+	l.facesLock.Lock()
+	peer := l.udpPeers[key]
+	l.facesLock.Unlock()
+	if peer != nil {
+		return peer
+	}
+	if l.isFull() {
+		// silently drop, because logging every packet would flood the log
+		return nil
+	}
+
+	peer = newUdpPeerConn(l, raddr)
+	face, e := l.acceptFace(peer)
+	if e != nil {
+		l.logger.WithError(e).Warn("face creation failed")
+		return nil
+	}
+	peer.face = face
+
+	l.facesLock.Lock()
+	defer l.facesLock.Unlock()
+	l.udpPeers[key] = peer
+	return peer
+}
+
+func (l *Listener) removeUdpPeer(peer *udpPeerConn) {
+	l.facesLock.Lock()
+	defer l.facesLock.Unlock()
+	if l.udpPeers[peer.raddr.String()] == peer {
+		delete(l.udpPeers, peer.raddr.String())
+	}
+}
+
+// Close idle on-demand faces.
+func (l *Listener) idleLoop() {
+	defer l.quitWg.Done()
+	idleTimeout := l.cfg.IdleTimeout.Duration()
+	if idleTimeout == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(idleTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.quit:
+			return
+		case now := <-ticker.C:
+			var idleFaces []*SocketFace
+			l.facesLock.Lock()
+			for _, peer := range l.udpPeers {
+				if now.Sub(peer.getLastRx()) > idleTimeout {
+					idleFaces = append(idleFaces, peer.face)
+				}
+			}
+			l.facesLock.Unlock()
+
+			for _, face := range idleFaces {
+				face.logger.Info("closing idle on-demand face")
+				go face.Close()
+			}
+		}
+	}
+}
+
+const udpPeerRxQueueCapacity = 64
+
+// Connection to a UDP remote endpoint over Listener's socket.
+type udpPeerConn struct {
+	l       *Listener
+	raddr   *net.UDPAddr
+	face    *SocketFace
+	lastRx  int64 // UnixNano of last received packet, need atomic access
+	rxQueue chan dpdk.Packet
+	quit    chan struct{}
+	once    sync.Once
+}
+
+func newUdpPeerConn(l *Listener, raddr *net.UDPAddr) *udpPeerConn {
+	return &udpPeerConn{
+		l:       l,
+		raddr:   raddr,
+		lastRx:  time.Now().UnixNano(),
+		rxQueue: make(chan dpdk.Packet, udpPeerRxQueueCapacity),
+		quit:    make(chan struct{}),
+	}
+}
+
+func (conn *udpPeerConn) deliver(pkt dpdk.Packet) {
+	atomic.StoreInt64(&conn.lastRx, time.Now().UnixNano())
+	select {
+	case conn.rxQueue <- pkt:
+	default:
+		pkt.Close()
+	}
+}
+
+func (conn *udpPeerConn) getLastRx() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&conn.lastRx))
+}
+
+func (conn *udpPeerConn) Read(b []byte) (int, error) {
+	return 0, errors.New("udpPeerConn does not support Read")
+}
+
+func (conn *udpPeerConn) Write(b []byte) (int, error) {
+	return conn.l.udpConn.WriteToUDP(b, conn.raddr)
+}
+
+func (conn *udpPeerConn) Close() error {
+	conn.once.Do(func() {
+		conn.l.removeUdpPeer(conn)
+		close(conn.quit)
+	})
+	return nil
+}
+
+func (conn *udpPeerConn) LocalAddr() net.Addr {
+	return conn.l.udpConn.LocalAddr()
+}
+
+func (conn *udpPeerConn) RemoteAddr() net.Addr {
+	return conn.raddr
+}
+
+func (conn *udpPeerConn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (conn *udpPeerConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (conn *udpPeerConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+func (conn *udpPeerConn) getImpl() iImpl {
+	return udpPeerImpl{}
+}
+
+func (conn *udpPeerConn) getLocator() (loc Locator) {
+	loc.Scheme = conn.raddr.Network()
+	loc.Local = conn.LocalAddr().String()
+	loc.Remote = conn.raddr.String()
+	return loc
+}
+
+// SocketFace implementation for on-demand UDP faces.
+type udpPeerImpl struct {
+	udpImpl
+}
+
+func (udpPeerImpl) RxLoop(face *SocketFace) {
+	conn := face.GetConn().(*udpPeerConn)
+	for {
+		select {
+		case pkt := <-conn.rxQueue:
+			face.rxPkt(pkt)
+		case <-conn.quit:
+			for {
+				select {
+				case pkt := <-conn.rxQueue:
+					pkt.Close()
+				default:
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package socketface_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/socketface"
+)
+
+func TestListenerTcp(t *testing.T) {
+	assert, require := makeAR(t)
+
+	var newFaces []iface.FaceId
+	defer iface.OnFaceNew(func(id iface.FaceId) {
+		newFaces = append(newFaces, id)
+	}).Close()
+
+	l, e := socketface.Listen(socketface.ListenerConfig{Scheme: "tcp", Local: "127.0.0.1:0"}, socketfaceCfg)
+	require.NoError(e)
+	defer l.Close()
+	addr := l.GetConfig().Local
+
+	conn, e := net.Dial("tcp", addr)
+	require.NoError(e)
+	time.Sleep(100 * time.Millisecond)
+
+	faces := l.ListFaces()
+	require.Len(faces, 1)
+	face := faces[0]
+	assert.Equal([]iface.FaceId{face.GetFaceId()}, newFaces)
+	assert.True(face.IsLocal())
+	loc := face.GetLocator().(socketface.Locator)
+	assert.Equal("tcp", loc.Scheme)
+	assert.Equal(addr, loc.Local)
+	assert.Equal(conn.LocalAddr().String(), loc.Remote)
+
+	conn.Close() // accepted face should close instead of redialing
+	time.Sleep(100 * time.Millisecond)
+	assert.True(face.IsClosed())
+	assert.Len(l.ListFaces(), 0)
+}
+
+func TestListenerUdp(t *testing.T) {
+	assert, require := makeAR(t)
+
+	l, e := socketface.Listen(socketface.ListenerConfig{Scheme: "udp", Local: "127.0.0.1:0", IdleTimeout: 400}, socketfaceCfg)
+	require.NoError(e)
+	defer l.Close()
+	laddr, e := net.ResolveUDPAddr("udp", l.GetConfig().Local)
+	require.NoError(e)
+
+	connA, e := net.DialUDP("udp", nil, laddr)
+	require.NoError(e)
+	defer connA.Close()
+	connB, e := net.DialUDP("udp", nil, laddr)
+	require.NoError(e)
+	defer connB.Close()
+
+	connA.Write([]byte{0x05, 0x00})
+	connA.Write([]byte{0x05, 0x00})
+	connB.Write([]byte{0x05, 0x00})
+	time.Sleep(100 * time.Millisecond)
+
+	faces := l.ListFaces()
+	require.Len(faces, 2)
+	var faceA *socketface.SocketFace
+	for _, face := range faces {
+		loc := face.GetLocator().(socketface.Locator)
+		assert.Equal("udp", loc.Scheme)
+		assert.Equal(laddr.String(), loc.Local)
+		if loc.Remote == connA.LocalAddr().String() {
+			faceA = face
+		} else {
+			assert.Equal(connB.LocalAddr().String(), loc.Remote)
+		}
+	}
+	require.NotNil(faceA)
+
+	// faceA stays alive because connA keeps sending, faceB is closed after idle timeout
+	for i := 0; i < 6; i++ {
+		time.Sleep(100 * time.Millisecond)
+		connA.Write([]byte{0x05, 0x00})
+	}
+	if faces = l.ListFaces(); assert.Len(faces, 1) {
+		assert.Equal(faceA, faces[0])
+	}
+
+	l.Close()
+	assert.True(faceA.IsClosed())
+}
+
+func TestListenerMaxFaces(t *testing.T) {
+	assert, require := makeAR(t)
+
+	l, e := socketface.Listen(socketface.ListenerConfig{Scheme: "udp", Local: "127.0.0.1:0", MaxFaces: 1}, socketfaceCfg)
+	require.NoError(e)
+	defer l.Close()
+	laddr, e := net.ResolveUDPAddr("udp", l.GetConfig().Local)
+	require.NoError(e)
+
+	connA, e := net.DialUDP("udp", nil, laddr)
+	require.NoError(e)
+	defer connA.Close()
+	connB, e := net.DialUDP("udp", nil, laddr)
+	require.NoError(e)
+	defer connB.Close()
+
+	connA.Write([]byte{0x05, 0x00})
+	time.Sleep(100 * time.Millisecond)
+	connB.Write([]byte{0x05, 0x00})
+	time.Sleep(100 * time.Millisecond)
+
+	if faces := l.ListFaces(); assert.Len(faces, 1) {
+		assert.Equal(connA.LocalAddr().String(), faces[0].GetLocator().(socketface.Locator).Remote)
+	}
+
+	// a face slot becomes available after faceA is closed
+	l.ListFaces()[0].Close()
+	connB.Write([]byte{0x05, 0x00})
+	time.Sleep(100 * time.Millisecond)
+	if faces := l.ListFaces(); assert.Len(faces, 1) {
+		assert.Equal(connB.LocalAddr().String(), faces[0].GetLocator().(socketface.Locator).Remote)
+	}
+}
@@ -1,6 +1,7 @@
 package socketface
 
 import (
+	"errors"
 	"fmt"
 
 	"ndn-dpdk/iface"
@@ -10,6 +11,11 @@ type Locator struct {
 	iface.LocatorBase
 	Local  string
 	Remote string
+
+	// Multicast options, accepted only with "udp4-mcast" and "udp6-mcast" schemes.
+	Interface string `json:",omitempty"` // network interface name
+	Ttl       int    `json:",omitempty"` // TTL or hop limit of outgoing packets, zero means 1
+	Loopback  bool   `json:",omitempty"` // whether to loop back outgoing packets to local sockets
 }
 
 func (loc Locator) Validate() error {
@@ -26,6 +32,14 @@ func (loc Locator) Validate() error {
 	if e := impl.ValidateAddr(loc.Scheme, loc.Remote, false); e != nil {
 		return fmt.Errorf("Remote: %v", e)
 	}
+
+	if !isMcastScheme(loc.Scheme) {
+		if loc.Interface != "" || loc.Ttl != 0 || loc.Loopback {
+			return fmt.Errorf("multicast options are not supported with scheme %s", loc.Scheme)
+		}
+	} else if loc.Ttl < 0 || loc.Ttl > 255 {
+		return errors.New("Ttl must be between 0 and 255")
+	}
 	return nil
 }
 
@@ -35,7 +49,7 @@ func Create(loc Locator, cfg Config) (face *SocketFace, e error) {
 	}
 
 	impl := implByNetwork[loc.Scheme]
-	conn, e := impl.Dial(loc.Scheme, loc.Local, loc.Remote)
+	conn, e := impl.Dial(loc)
 	if e != nil {
 		return nil, e
 	}
@@ -0,0 +1,133 @@
+package socketface
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Default NDN multicast groups.
+const (
+	NDN_MCAST_IPV4 = "224.0.23.170:56363"
+	NDN_MCAST_IPV6 = "[ff02::114]:56363"
+)
+
+func isMcastScheme(scheme string) bool {
+	return scheme == "udp4-mcast" || scheme == "udp6-mcast"
+}
+
+// Connection on a UDP multicast group.
+// Write sends to the multicast group, and RemoteAddr returns the multicast group.
+type mcastConn struct {
+	*net.UDPConn
+	loc   Locator
+	group *net.UDPAddr
+}
+
+func (conn *mcastConn) Write(b []byte) (int, error) {
+	return conn.WriteToUDP(b, conn.group)
+}
+
+func (conn *mcastConn) RemoteAddr() net.Addr {
+	return conn.group
+}
+
+func (conn *mcastConn) getImpl() iImpl {
+	return implByNetwork[conn.loc.Scheme]
+}
+
+func (conn *mcastConn) getLocator() Locator {
+	return conn.loc
+}
+
+// SocketFace implementation for UDP multicast.
+type mcastImpl struct {
+	datagramImpl
+	nopRedialer
+}
+
+func (mcastImpl) ValidateAddr(network, address string, isLocal bool) error {
+	if isLocal {
+		return errors.New("not supported with multicast")
+	}
+	if address == "" {
+		return nil
+	}
+
+	group, e := net.ResolveUDPAddr(strings.TrimSuffix(network, "-mcast"), address)
+	if e != nil {
+		return e
+	}
+	if !group.IP.IsMulticast() {
+		return errors.New("not a multicast address")
+	}
+	return nil
+}
+
+func (mcastImpl) Dial(loc Locator) (net.Conn, error) {
+	network := strings.TrimSuffix(loc.Scheme, "-mcast")
+	isV6 := network == "udp6"
+	if loc.Remote == "" {
+		if isV6 {
+			loc.Remote = NDN_MCAST_IPV6
+		} else {
+			loc.Remote = NDN_MCAST_IPV4
+		}
+	}
+
+	group, e := net.ResolveUDPAddr(network, loc.Remote)
+	if e != nil {
+		return nil, fmt.Errorf("Remote: %v", e)
+	}
+
+	var ifi *net.Interface
+	if loc.Interface != "" {
+		if ifi, e = net.InterfaceByName(loc.Interface); e != nil {
+			return nil, fmt.Errorf("Interface: %v", e)
+		}
+	}
+
+	conn, e := net.ListenMulticastUDP(network, ifi, group)
+	if e != nil {
+		return nil, e
+	}
+	if e = setMcastOptions(conn, isV6, loc.Ttl, loc.Loopback); e != nil {
+		conn.Close()
+		return nil, e
+	}
+	return &mcastConn{conn, loc, group}, nil
+}
+
+// Set multicast TTL and loopback socket options.
+func setMcastOptions(conn *net.UDPConn, isV6 bool, ttl int, loopback bool) error {
+	if ttl == 0 {
+		ttl = 1
+	}
+	loop := 0
+	if loopback {
+		loop = 1
+	}
+
+	level, optTtl, optLoop := unix.IPPROTO_IP, unix.IP_MULTICAST_TTL, unix.IP_MULTICAST_LOOP
+	if isV6 {
+		level, optTtl, optLoop = unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_HOPS, unix.IPV6_MULTICAST_LOOP
+	}
+
+	raw, e := conn.SyscallConn()
+	if e != nil {
+		return e
+	}
+	var sockErr error
+	if e = raw.Control(func(fd uintptr) {
+		if sockErr = unix.SetsockoptInt(int(fd), level, optTtl, ttl); sockErr != nil {
+			return
+		}
+		sockErr = unix.SetsockoptInt(int(fd), level, optLoop, loop)
+	}); e != nil {
+		return e
+	}
+	return sockErr
+}
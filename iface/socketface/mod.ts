@@ -1,5 +1,37 @@
+import { Milliseconds } from "../../core/nnduration/mod";
+
 export interface Locator {
-  Scheme: "udp"|"unixgram"|"tcp"|"unix";
+  Scheme: "udp"|"unixgram"|"tcp"|"unix"|"udp4-mcast"|"udp6-mcast";
   Local?: string;
-  Remote: string;
+  Remote?: string;
+
+  Interface?: string;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   * @maximum 255
+   * @default 1
+   */
+  Ttl?: number;
+
+  /**
+   * @default false
+   */
+  Loopback?: boolean;
+}
+
+export interface ListenerConfig {
+  Scheme: "udp"|"udp4"|"udp6"|"tcp"|"tcp4"|"tcp6"|"unix";
+  Local: string;
+
+  /**
+   * @default 0
+   */
+  IdleTimeout?: Milliseconds;
+
+  /**
+   * @default 256
+   */
+  MaxFaces?: number;
 }
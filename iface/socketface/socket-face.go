@@ -26,8 +26,14 @@ type Config struct {
 	RxMp      dpdk.PktmbufPool // mempool for received frames, dataroom must fit NDNLP frame
 	TxqPkts   int              // before-TX queue capacity
 	TxqFrames int              // after-TX queue capacity
+
+	// If not nil, this lock is held while Listener creates a face for an accepted peer.
+	Locker sync.Locker
 }
 
+// Delay before retrying after an RX mbuf allocation error.
+const rxAllocRetryInterval = 10 * time.Millisecond
+
 // A face using socket as transport.
 type SocketFace struct {
 	iface.FaceBase
@@ -35,6 +41,8 @@ type SocketFace struct {
 	conn   atomic.Value
 	impl   iImpl
 
+	listener *Listener // Listener that accepted this face, nil if dialed
+
 	closing   int32          // 1 if face is closing, need atomic access
 	nRedials  int            // how many times face is redialed
 	redialing int32          // 1 if face is redialing, need atomic access
@@ -42,23 +50,40 @@ type SocketFace struct {
 
 	rxMp dpdk.PktmbufPool
 
+	nRxTruncated uint64 // datagrams truncated by the kernel, need atomic access
+	nTxDropped   uint64 // frames that could not be sent from a contiguous copy, need atomic access
+
 	txQueue chan dpdk.Packet
 }
 
+// net.Conn that provides its own SocketFace implementation and Locator.
+type customConn interface {
+	net.Conn
+	getImpl() iImpl
+	getLocator() Locator
+}
+
 // Create a SocketFace on a net.Conn.
 func New(conn net.Conn, cfg Config) (face *SocketFace, e error) {
+	return newFace(conn, cfg, nil)
+}
+
+func newFace(conn net.Conn, cfg Config, listener *Listener) (face *SocketFace, e error) {
 	face = new(SocketFace)
-	network := conn.LocalAddr().Network()
-	if impl, ok := implByNetwork[network]; ok {
+	face.listener = listener
+	if cc, ok := conn.(customConn); ok {
+		face.impl = cc.getImpl()
+	} else if impl, ok := implByNetwork[conn.LocalAddr().Network()]; ok {
 		face.impl = impl
 	} else {
-		return nil, fmt.Errorf("unknown network %s", network)
+		return nil, fmt.Errorf("unknown network %s", conn.LocalAddr().Network())
 	}
 
 	if e := face.InitFaceBase(iface.AllocId(iface.FaceKind_Socket), 0, dpdk.NUMA_SOCKET_ANY); e != nil {
 		return nil, e
 	}
 
+	face.SetLocal(isLocalConn(conn))
 	face.logger = newLogger(face.GetFaceId())
 	face.conn.Store(conn)
 	face.rxMp = cfg.RxMp
@@ -80,6 +105,19 @@ func New(conn net.Conn, cfg Config) (face *SocketFace, e error) {
 	return face, nil
 }
 
+// Determine whether a connection is local.
+// Unix sockets and connections to loopback addresses are local.
+func isLocalConn(conn net.Conn) bool {
+	switch raddr := conn.RemoteAddr().(type) {
+	case *net.UDPAddr:
+		return raddr.IP.IsLoopback()
+	case *net.TCPAddr:
+		return raddr.IP.IsLoopback()
+	}
+	network := conn.LocalAddr().Network()
+	return network == "unix" || network == "unixgram"
+}
+
 func (face *SocketFace) getPtr() *C.Face {
 	return (*C.Face)(face.GetPtr())
 }
@@ -90,6 +128,9 @@ func (face *SocketFace) GetConn() net.Conn {
 
 func (face *SocketFace) GetLocator() iface.Locator {
 	conn := face.GetConn()
+	if cc, ok := conn.(customConn); ok {
+		return cc.getLocator()
+	}
 	laddr, raddr := conn.LocalAddr(), conn.RemoteAddr()
 
 	var loc Locator
@@ -102,8 +143,13 @@ func (face *SocketFace) GetLocator() iface.Locator {
 }
 
 func (face *SocketFace) Close() error {
+	if !atomic.CompareAndSwapInt32(&face.closing, 0, 1) {
+		return nil
+	}
 	face.BeforeClose()
-	atomic.StoreInt32(&face.closing, 1)
+	if face.listener != nil {
+		face.listener.removeFace(face)
+	}
 	close(face.txQueue)
 	face.GetConn().Close() // ignore error
 	face.quitWg.Wait()
@@ -128,14 +174,37 @@ func (face *SocketFace) rxPkt(pkt dpdk.Packet) {
 	iface.TheChanRxGroup.Rx(pkt)
 }
 
+// Deliver a burst of received packets.
+func (face *SocketFace) rxBurst(pkts []dpdk.Packet) {
+	port, now := uint16(face.GetFaceId()), dpdk.TscNow()
+	for _, pkt := range pkts {
+		pkt.SetPort(port)
+		pkt.SetTimestamp(now)
+	}
+	iface.TheChanRxGroup.RxBurst(pkts)
+}
+
 func (face *SocketFace) txLoop() {
+	burstSender, canBurst := face.impl.(iBurstSender)
+	pkts := make([]dpdk.Packet, 0, DATAGRAM_BURST_SIZE)
 	for {
 		pkt, ok := <-face.txQueue
 		if !ok {
 			break
 		}
-		e := face.impl.Send(face, pkt)
-		pkt.Close()
+
+		var e error
+		if canBurst {
+			pkts = append(pkts[:0], pkt)
+			pkts = face.drainTxQueue(pkts)
+			e = burstSender.SendBurst(face, pkts)
+			for _, pkt := range pkts {
+				pkt.Close()
+			}
+		} else {
+			e = face.impl.Send(face, pkt)
+			pkt.Close()
+		}
 		if e != nil && face.handleError("TX", e) {
 			break
 		}
@@ -143,6 +212,22 @@ func (face *SocketFace) txLoop() {
 	face.quitWg.Done()
 }
 
+// Append packets from txQueue without blocking, until pkts is full.
+func (face *SocketFace) drainTxQueue(pkts []dpdk.Packet) []dpdk.Packet {
+	for len(pkts) < cap(pkts) {
+		select {
+		case pkt, ok := <-face.txQueue:
+			if !ok {
+				return pkts
+			}
+			pkts = append(pkts, pkt)
+		default:
+			return pkts
+		}
+	}
+	return pkts
+}
+
 // Handle socket error.
 // Return whether RxLoop or TxLoop should terminate (i.e. face closed).
 func (face *SocketFace) handleError(dir string, e error) bool {
@@ -155,6 +240,11 @@ func (face *SocketFace) handleError(dir string, e error) bool {
 	}
 	face.logger.WithError(e).Errorf("%s socket failed", dir)
 
+	if face.listener != nil { // accepted face cannot redial
+		go face.Close()
+		return true
+	}
+
 	if atomic.CompareAndSwapInt32(&face.redialing, 0, 1) {
 		defer atomic.StoreInt32(&face.redialing, 0)
 		for atomic.LoadInt32(&face.closing) == 0 {
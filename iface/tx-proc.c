@@ -1,6 +1,7 @@
 #include "tx-proc.h"
 
 #include "../core/logger.h"
+#include "../core/urcu/urcu.h"
 
 INIT_ZF_LOG(TxProc);
 
@@ -31,6 +32,7 @@ TxProc_Init(TxProc* tx,
          headroom + PrependLpHeader_GetHeadroom());
   tx->indirectMp = indirectMp;
   tx->headerMp = headerMp;
+  tx->mtu = mtu;
 
   if (mtu == 0) {
     tx->outputFunc = TxProc_OutputNoFrag;
@@ -76,6 +78,9 @@ TxProc_OutputFrag(TxProc* tx,
     return 0;
   }
 
+  LpReliability* rel = rcu_dereference(tx->rel);
+  TscTime now = rte_get_tsc_cycles();
+
   MbufLoc pos;
   MbufLoc_Init(&pos, pkt);
   LpHeader lph = { .l2 = { .fragCount = (uint16_t)nFragments } };
@@ -99,6 +104,9 @@ TxProc_OutputFrag(TxProc* tx,
 
     lph.l2.seqNum = ++tx->lastSeqNum;
     lph.l2.fragIndex = (uint16_t)i;
+    if (rel != NULL) {
+      LpReliability_Tx(rel, &lph, payload, 0, tx->indirectMp, now);
+    }
 
     struct rte_mbuf* frame = frames[i];
     frame->data_off = tx->headerHeadroom;
@@ -135,8 +143,12 @@ TxProc_OutputNoFrag(TxProc* tx,
   assert(payloadL > 0);
   assert(maxFrames >= 1);
 
+  // LpReliability retains a clone of the payload, so that LpHeader must be
+  // placed in a separate header mbuf
+  LpReliability* rel = rcu_dereference(tx->rel);
+
   struct rte_mbuf* frame;
-  if (RTE_MBUF_CLONED(pkt) || pkt->refcnt > 1 ||
+  if (rel != NULL || RTE_MBUF_CLONED(pkt) || pkt->refcnt > 1 ||
       rte_pktmbuf_headroom(pkt) < tx->headerHeadroom) {
     frame = rte_pktmbuf_alloc(tx->headerMp);
     if (unlikely(frame == NULL)) {
@@ -163,7 +175,95 @@ TxProc_OutputNoFrag(TxProc* tx,
 
   LpHeader lph = { .l2 = { .fragCount = 1 } };
   rte_memcpy(&lph.l3, Packet_InitLpL3Hdr(npkt), sizeof(lph.l3));
+  if (rel != NULL) {
+    LpReliability_Tx(rel, &lph, pkt, 0, tx->indirectMp, rte_get_tsc_cycles());
+  }
   PrependLpHeader(frame, &lph, payloadL);
   frames[0] = frame;
   return 1;
 }
+
+/** \brief Retransmit an expired frame.
+ *  \retval NULL the frame is given up.
+ */
+static struct rte_mbuf*
+TxProc_Retransmit(TxProc* tx,
+                  LpReliability* rel,
+                  LpRelEntry* entry,
+                  TscTime now)
+{
+  struct rte_mbuf* payload = entry->payload;
+  entry->payload = NULL;
+  if (entry->nRetx >= rel->maxRetx) {
+    ZF_LOGD("give-up txSeq=%" PRIu64, entry->txSeqNum);
+    ++rel->nLost;
+    rte_pktmbuf_free(payload);
+    return NULL;
+  }
+
+  struct rte_mbuf* frame = rte_pktmbuf_alloc(tx->headerMp);
+  if (unlikely(frame == NULL)) {
+    ++tx->nAllocFails;
+    ++rel->nLost;
+    rte_pktmbuf_free(payload);
+    return NULL;
+  }
+  frame->data_off = tx->headerHeadroom;
+
+  LpHeader lph = { .l3 = entry->l3,
+                   .l2 = { .seqNum = entry->seqNum,
+                           .fragIndex = entry->fragIndex,
+                           .fragCount = entry->fragCount } };
+  LpReliability_Tx(
+    rel, &lph, payload, entry->nRetx + 1, tx->indirectMp, now);
+  ++rel->nRetx;
+  ZF_LOGV("retx txSeq=%" PRIu64 " seq=%" PRIu64, lph.l2.txSeqNum, lph.l2.seqNum);
+
+  PrependLpHeader(frame, &lph, payload->pkt_len);
+  if (unlikely(rte_pktmbuf_chain(frame, payload) != 0)) {
+    ++tx->nL3OverLength;
+    rte_pktmbuf_free(frame);
+    rte_pktmbuf_free(payload);
+    return NULL;
+  }
+  frame->inner_l3_type = L3PktType_None;
+  return frame;
+}
+
+uint16_t
+TxProc_Poll(TxProc* tx, struct rte_mbuf** frames, uint16_t maxFrames)
+{
+  LpReliability* rel = rcu_dereference(tx->rel);
+  if (rel == NULL) {
+    return 0;
+  }
+  LpReliability_ProcessAcked(rel);
+
+  TscTime now = rte_get_tsc_cycles();
+  uint16_t nFrames = 0;
+  LpRelEntry* entry;
+  while (nFrames < maxFrames &&
+         (entry = LpReliability_GetExpired(rel, now)) != NULL) {
+    struct rte_mbuf* frame = TxProc_Retransmit(tx, rel, entry, now);
+    if (frame != NULL) {
+      frames[nFrames++] = frame;
+    }
+  }
+
+  while (nFrames < maxFrames && !rte_ring_empty(rel->ackQueue)) {
+    struct rte_mbuf* frame = rte_pktmbuf_alloc(tx->headerMp);
+    if (unlikely(frame == NULL)) {
+      ++tx->nAllocFails;
+      break;
+    }
+    frame->data_off = tx->headerHeadroom;
+
+    LpHeader lph = { .l2 = { .fragCount = 1 } };
+    LpReliability_CollectAcks(rel, &lph.l2);
+    PrependLpHeader(frame, &lph, 0);
+    frame->inner_l3_type = L3PktType_None;
+    frames[nFrames++] = frame;
+    ++rel->nAckOnly;
+  }
+  return nFrames;
+}
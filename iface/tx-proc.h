@@ -4,7 +4,9 @@
 /// \file
 
 #include "../core/running_stat/running-stat.h"
-#include "common.h"
+#include "cong-mark.h"
+#include "lp-reliability.h"
+#include "shaper.h"
 
 typedef struct TxProc TxProc;
 
@@ -20,7 +22,11 @@ typedef struct TxProc
   struct rte_mempool* indirectMp;
   struct rte_mempool* headerMp;
   TxProc_OutputFunc_ outputFunc;
+  LpReliability* rel; ///< (RCU) link-layer reliability, NULL if disabled
+  CongMark* congMark; ///< (RCU) congestion marking, NULL if disabled
+  TxShaper* shaper;   ///< (RCU) traffic shaper, NULL if disabled
 
+  uint16_t mtu;                 ///< transport MTU, 0 if unlimited
   uint16_t headerHeadroom;      ///< headroom for header mbuf
   uint16_t fragmentPayloadSize; ///< max payload size per fragment
 
@@ -29,6 +35,7 @@ typedef struct TxProc
   uint64_t nL3Fragmented; ///< L3 packets that required fragmentation
   uint64_t nL3OverLength; ///< dropped L3 packets due to over length
   uint64_t nAllocFails;   ///< dropped L3 packets due to allocation failure
+  uint64_t nCongMarked;   ///< L3 packets marked with CongestionMark
 
   uint64_t nFrames; ///< sent+dropped L2 frames
   uint64_t
@@ -66,6 +73,7 @@ TxProc_Init(TxProc* tx,
  *  \param[out] frames L2 frames to be transmitted; TxProc releases ownership
  *  \param maxFrames size of frames array
  *  \return number of L2 frames to be transmitted
+ *  \pre Calling thread holds rcu_read_lock.
  */
 static inline uint16_t
 TxProc_Output(TxProc* tx,
@@ -76,4 +84,17 @@ TxProc_Output(TxProc* tx,
   return (*tx->outputFunc)(tx, npkt, frames, maxFrames);
 }
 
+/** \brief Perform link-layer reliability maintenance.
+ *  \param[out] frames retransmitted frames and Ack-only frames to be
+ *                     transmitted; TxProc releases ownership
+ *  \param maxFrames size of frames array
+ *  \return number of L2 frames to be transmitted
+ *  \pre Calling thread holds rcu_read_lock.
+ *
+ *  This should be invoked after each burst of \c TxProc_Output. Pending Acks
+ *  that were not piggybacked onto outgoing frames are sent in Ack-only frames.
+ */
+uint16_t
+TxProc_Poll(TxProc* tx, struct rte_mbuf** frames, uint16_t maxFrames);
+
 #endif // NDN_DPDK_IFACE_TX_PROC_H
@@ -4,7 +4,10 @@
 static const int TX_BURST_FRAMES = 64;  // number of frames in a burst
 static const int TX_MAX_FRAGMENTS = 16; // max allowed number of fragments
 
-static void
+/** \brief Transmit frames on a face.
+ *  \return number of frames rejected by the NIC.
+ */
+static uint16_t
 TxLoop_TxFrames(Face* face, struct rte_mbuf** frames, uint16_t count)
 {
   assert(count > 0);
@@ -15,28 +18,48 @@ TxLoop_TxFrames(Face* face, struct rte_mbuf** frames, uint16_t count)
     tx->nOctets += frames[i]->pkt_len;
   }
 
+  FaceCapture* capture = rcu_dereference(face->capture);
+  if (unlikely(capture != NULL)) {
+    FaceCapture_PostFrames(capture, FaceCaptureDir_Tx, frames, count);
+  }
+
   uint16_t nQueued = (*face->txBurstOp)(face, frames, count);
   uint16_t nRejects = count - nQueued;
   if (unlikely(nRejects > 0)) {
     tx->nDroppedFrames += nRejects;
     tx->nDroppedOctets += FreeMbufs(&frames[nQueued], nRejects);
   }
+  return nRejects;
 }
 
 static void
 TxLoop_Transfer(Face* face)
 {
   TxProc* tx = &face->impl->tx;
+  TscTime now = rte_get_tsc_cycles();
   Packet* npkts[TX_BURST_FRAMES];
-  uint16_t count =
-    rte_ring_dequeue_burst(face->txQueue, (void**)npkts, TX_BURST_FRAMES, NULL);
+  uint16_t count;
+  TxShaper* shaper = rcu_dereference(tx->shaper);
+  if (shaper != NULL) {
+    count =
+      TxShaper_Dequeue(shaper, face->txQueue, npkts, TX_BURST_FRAMES, now);
+  } else {
+    count = rte_ring_dequeue_burst(
+      face->txQueue, (void**)npkts, TX_BURST_FRAMES, NULL);
+  }
+
+  FaceCapture* capture = rcu_dereference(face->capture);
+  if (unlikely(capture != NULL) && count > 0) {
+    FaceCapture_Post(capture, FaceCaptureDir_Tx, npkts, count);
+  }
 
   struct rte_mbuf* frames[TX_BURST_FRAMES + TX_MAX_FRAGMENTS];
   uint16_t nFrames = 0;
   HrlogEntry hrl[TX_BURST_FRAMES];
   uint16_t nHrls = 0;
+  uint16_t nRejects = 0;
 
-  TscTime now = rte_get_tsc_cycles();
+  CongMark* congMark = rcu_dereference(tx->congMark);
   for (uint16_t i = 0; i < count; ++i) {
     Packet* npkt = npkts[i];
     TscDuration latency = now - Packet_ToMbuf(npkt)->timestamp;
@@ -50,17 +73,28 @@ TxLoop_Transfer(Face* face)
         latency);
     }
 
+    if (congMark != NULL && l3type != L3PktType_None &&
+        CongMark_Check(congMark, npkt, l3type, now)) {
+      Packet_InitLpL3Hdr(npkt)->congMark = 1;
+      ++tx->nCongMarked;
+    }
+
     struct rte_mbuf** outFrames = &frames[nFrames];
     nFrames += TxProc_Output(tx, npkt, outFrames, TX_MAX_FRAGMENTS);
 
     if (unlikely(nFrames >= TX_BURST_FRAMES)) {
-      TxLoop_TxFrames(face, frames, nFrames);
+      nRejects += TxLoop_TxFrames(face, frames, nFrames);
       nFrames = 0;
     }
   }
 
+  nFrames += TxProc_Poll(tx, &frames[nFrames], RTE_DIM(frames) - nFrames);
+
   if (likely(nFrames > 0)) {
-    TxLoop_TxFrames(face, frames, nFrames);
+    nRejects += TxLoop_TxFrames(face, frames, nFrames);
+  }
+  if (congMark != NULL && count + nFrames > 0) {
+    congMark->txRejected = nRejects > 0;
   }
   if (likely(nHrls > 0)) {
     Hrlog_PostBulk(hrl, nHrls);
@@ -0,0 +1,25 @@
+package wsface
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Extended counters.
+type ExCounters struct {
+	RxDropped  uint64 // received text or oversized messages, which are dropped
+	TxQueueCap int
+	TxQueueLen int
+}
+
+func (cnt ExCounters) String() string {
+	return fmt.Sprintf("rx %ddropped, tx %dqueued %dmax", cnt.RxDropped, cnt.TxQueueLen, cnt.TxQueueCap)
+}
+
+func (face *WsFace) ReadExCounters() interface{} {
+	return ExCounters{
+		RxDropped:  atomic.LoadUint64(&face.nRxDropped),
+		TxQueueCap: cap(face.txQueue),
+		TxQueueLen: len(face.txQueue),
+	}
+}
@@ -0,0 +1,197 @@
+package wsface
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+
+	"ndn-dpdk/core/logger"
+	"ndn-dpdk/iface"
+)
+
+// Listener configuration.
+type ListenerConfig struct {
+	Local string // TCP address to listen on
+	Path  string `json:",omitempty"` // HTTP path of the WebSocket endpoint, default is "/"
+
+	// Origins of web pages allowed to connect, such as "https://example.com".
+	// "*" allows any origin.
+	// Cross-origin connections are rejected by default.
+	AllowedOrigins []string `json:",omitempty"`
+
+	// Maximum number of faces accepted by this listener.
+	// Zero means DefaultListenerMaxFaces.
+	MaxFaces int `json:",omitempty"`
+}
+
+// Default maximum number of faces accepted by a listener.
+const DefaultListenerMaxFaces = 256
+
+func (lc ListenerConfig) Validate() error {
+	if _, e := net.ResolveTCPAddr("tcp", lc.Local); e != nil {
+		return fmt.Errorf("Local: %v", e)
+	}
+	if lc.Path != "" && !strings.HasPrefix(lc.Path, "/") {
+		return errors.New("Path must start with '/'")
+	}
+	return nil
+}
+
+func (lc ListenerConfig) getMaxFaces() int {
+	if lc.MaxFaces <= 0 {
+		return DefaultListenerMaxFaces
+	}
+	return lc.MaxFaces
+}
+
+// Listener that accepts WebSocket connections, and creates a WsFace for each connection.
+type Listener struct {
+	cfg     ListenerConfig
+	faceCfg Config
+	logger  logrus.FieldLogger
+
+	ln      net.Listener
+	server  *http.Server
+	closing int32 // 1 if listener is closing, need atomic access
+	quitWg  sync.WaitGroup
+
+	facesLock sync.Mutex
+	faces     map[iface.FaceId]*WsFace
+}
+
+// Start listening.
+func Listen(lc ListenerConfig, cfg Config) (l *Listener, e error) {
+	if e = lc.Validate(); e != nil {
+		return nil, e
+	}
+	if lc.Path == "" {
+		lc.Path = "/"
+	}
+
+	l = &Listener{
+		cfg:     lc,
+		faceCfg: cfg,
+		faces:   make(map[iface.FaceId]*WsFace),
+	}
+	if l.ln, e = net.Listen("tcp", lc.Local); e != nil {
+		return nil, e
+	}
+	l.cfg.Local = l.ln.Addr().String()
+	l.logger = logger.NewWithPrefix("wsface", fmt.Sprintf("listener %s%s", l.cfg.Local, l.cfg.Path))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(l.cfg.Path, l.serveHTTP)
+	l.server = &http.Server{Handler: mux}
+
+	l.quitWg.Add(1)
+	go l.serve()
+	l.logger.Info("listening")
+	return l, nil
+}
+
+// Get listener configuration, where Local reflects the actual listening address.
+func (l *Listener) GetConfig() ListenerConfig {
+	return l.cfg
+}
+
+// List faces accepted by this listener.
+func (l *Listener) ListFaces() (list []*WsFace) {
+	l.facesLock.Lock()
+	defer l.facesLock.Unlock()
+	for _, face := range l.faces {
+		list = append(list, face)
+	}
+	return list
+}
+
+// Stop listening and close accepted faces.
+func (l *Listener) Close() error {
+	if !atomic.CompareAndSwapInt32(&l.closing, 0, 1) {
+		return nil
+	}
+	l.server.Close()
+	l.quitWg.Wait()
+
+	for _, face := range l.ListFaces() {
+		face.Close()
+	}
+	l.logger.Info("closed")
+	return nil
+}
+
+func (l *Listener) serve() {
+	defer l.quitWg.Done()
+	e := l.server.Serve(l.ln)
+	if atomic.LoadInt32(&l.closing) == 0 {
+		l.logger.WithError(e).Error("HTTP server failed")
+	}
+}
+
+func (l *Listener) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != l.cfg.Path {
+		http.NotFound(w, r)
+		return
+	}
+	if l.isFull() {
+		http.Error(w, "too many faces", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, e := Upgrade(w, r, l.cfg.AllowedOrigins)
+	if e != nil {
+		l.logger.WithError(e).Warn("upgrade failed")
+		return
+	}
+
+	if _, e := l.acceptFace(conn); e != nil {
+		l.logger.WithError(e).Warn("face creation failed")
+		conn.Close()
+	}
+}
+
+// Determine whether the listener has reached MaxFaces.
+func (l *Listener) isFull() bool {
+	l.facesLock.Lock()
+	defer l.facesLock.Unlock()
+	return len(l.faces) >= l.cfg.getMaxFaces()
+}
+
+// Create a face for an accepted connection.
+func (l *Listener) acceptFace(conn *Conn) (face *WsFace, e error) {
+	var loc Locator
+	loc.Scheme = locatorScheme
+	loc.Local = l.cfg.Local
+	loc.Remote = conn.RemoteAddr().String()
+	loc.Path = l.cfg.Path
+
+	if l.faceCfg.Locker != nil {
+		l.faceCfg.Locker.Lock()
+		defer l.faceCfg.Locker.Unlock()
+	}
+	if atomic.LoadInt32(&l.closing) != 0 {
+		return nil, errors.New("listener is closing")
+	}
+	if l.isFull() {
+		return nil, errors.New("too many faces")
+	}
+	if face, e = newFace(conn, loc, l.faceCfg, l); e != nil {
+		return nil, e
+	}
+
+	l.facesLock.Lock()
+	defer l.facesLock.Unlock()
+	l.faces[face.GetFaceId()] = face
+	return face, nil
+}
+
+func (l *Listener) removeFace(face *WsFace) {
+	l.facesLock.Lock()
+	defer l.facesLock.Unlock()
+	delete(l.faces, face.GetFaceId())
+}
@@ -0,0 +1,34 @@
+package wsface
+
+import (
+	"fmt"
+	"net"
+
+	"ndn-dpdk/iface"
+)
+
+const locatorScheme = "ws"
+
+type Locator struct {
+	iface.LocatorBase
+	Local  string // listening TCP address
+	Remote string // remote TCP endpoint of the WebSocket client
+	Path   string // HTTP path of the WebSocket endpoint
+}
+
+func (loc Locator) Validate() error {
+	if loc.Scheme != locatorScheme {
+		return fmt.Errorf("unknown scheme %s", loc.Scheme)
+	}
+	if _, e := net.ResolveTCPAddr("tcp", loc.Local); e != nil {
+		return fmt.Errorf("Local: %v", e)
+	}
+	if _, e := net.ResolveTCPAddr("tcp", loc.Remote); e != nil {
+		return fmt.Errorf("Remote: %v", e)
+	}
+	return nil
+}
+
+func init() {
+	iface.RegisterLocatorType(Locator{}, locatorScheme)
+}
@@ -0,0 +1,14 @@
+package wsface
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"ndn-dpdk/core/logger"
+	"ndn-dpdk/iface"
+)
+
+func newLogger(id iface.FaceId) logrus.FieldLogger {
+	return logger.NewWithPrefix("wsface", fmt.Sprintf("face %d", id))
+}
@@ -0,0 +1,33 @@
+import { Counter } from "../../core/mod";
+
+export interface Locator {
+  Scheme: "ws";
+  Local: string;
+  Remote: string;
+  Path: string;
+}
+
+export interface ListenerConfig {
+  Local: string;
+
+  /**
+   * @default "/"
+   */
+  Path?: string;
+
+  /**
+   * @default []
+   */
+  AllowedOrigins?: string[];
+
+  /**
+   * @default 256
+   */
+  MaxFaces?: number;
+}
+
+export interface ExCounters {
+  RxDropped: Counter;
+  TxQueueCap: number;
+  TxQueueLen: number;
+}
@@ -0,0 +1,24 @@
+package wsface_test
+
+import (
+	"os"
+	"testing"
+
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/iface/ifacetestfixture"
+	"ndn-dpdk/iface/wsface"
+)
+
+var wsfaceCfg wsface.Config
+
+func TestMain(m *testing.M) {
+	wsfaceCfg = wsface.Config{
+		TxqPkts:   64,
+		TxqFrames: 64,
+	}
+	wsfaceCfg.RxMp, wsfaceCfg.Mempools = ifacetestfixture.MakeMempools()
+
+	os.Exit(m.Run())
+}
+
+var makeAR = dpdktestenv.MakeAR
@@ -0,0 +1,387 @@
+package wsface
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes.
+const (
+	OP_CONTINUATION = 0x0
+	OP_TEXT         = 0x1
+	OP_BINARY       = 0x2
+	OP_CLOSE        = 0x8
+	OP_PING         = 0x9
+	OP_PONG         = 0xA
+)
+
+const (
+	wsGuid             = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsMaxControlLength = 125
+	wsCloseTimeout     = time.Second
+)
+
+var (
+	// ReadMessage returns this error when a message does not fit in the buffer.
+	// The message is discarded, and the connection remains usable.
+	ErrMessageTooLarge = errors.New("WebSocket message too large")
+
+	errProtocol = errors.New("WebSocket protocol error")
+)
+
+// WebSocket connection.
+//
+// This is a minimal RFC 6455 implementation: it does not support extensions or subprotocols.
+// ReadMessage may be called from one goroutine; WriteMessage is thread-safe.
+type Conn struct {
+	conn      net.Conn
+	br        *bufio.Reader
+	isClient  bool
+	wLock     sync.Mutex
+	closeOnce sync.Once
+}
+
+func computeAccept(key string) string {
+	h := sha1.Sum([]byte(key + wsGuid))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+func headerContains(h http.Header, key, token string) bool {
+	for _, value := range h[http.CanonicalHeaderKey(key)] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Determine whether the Origin of a WebSocket handshake is acceptable.
+// A request without Origin header does not come from a web browser, and is accepted.
+// A request whose Origin has the same host as the request is accepted.
+// Otherwise, Origin must appear in allowedOrigins, or allowedOrigins must contain "*".
+func checkOrigin(r *http.Request, allowedOrigins []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, e := url.Parse(origin); e == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Upgrade an HTTP request to a WebSocket connection.
+// Cross-origin requests are rejected unless Origin appears in allowedOrigins.
+// If the request is not a valid WebSocket handshake, an error response is written.
+func Upgrade(w http.ResponseWriter, r *http.Request, allowedOrigins []string) (c *Conn, e error) {
+	if r.Method != http.MethodGet || !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "WebSocket handshake expected", http.StatusBadRequest)
+		return nil, errors.New("not a WebSocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("unsupported WebSocket version")
+	}
+	if !checkOrigin(r, allowedOrigins) {
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return nil, fmt.Errorf("Origin %s not allowed", r.Header.Get("Origin"))
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "cannot upgrade connection", http.StatusInternalServerError)
+		return nil, errors.New("http.ResponseWriter is not http.Hijacker")
+	}
+	conn, brw, e := hj.Hijack()
+	if e != nil {
+		return nil, e
+	}
+
+	if _, e = fmt.Fprintf(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\n"+
+		"Connection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", computeAccept(key)); e != nil {
+		conn.Close()
+		return nil, e
+	}
+	return &Conn{conn: conn, br: brw.Reader}, nil
+}
+
+// Dial a WebSocket server at a "ws://" URL.
+// If origin is not empty, it is sent as the Origin header.
+// This is intended for testing and tools.
+func Dial(rawurl string, origin string) (c *Conn, e error) {
+	u, e := url.Parse(rawurl)
+	if e != nil {
+		return nil, e
+	}
+	if u.Scheme != "ws" {
+		return nil, fmt.Errorf("unsupported URL scheme %s", u.Scheme)
+	}
+
+	conn, e := net.Dial("tcp", u.Host)
+	if e != nil {
+		return nil, e
+	}
+
+	var nonce [16]byte
+	rand.Read(nonce[:])
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+
+	req, _ := http.NewRequest(http.MethodGet, "http://"+u.Host+u.RequestURI(), nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if e = req.Write(conn); e != nil {
+		conn.Close()
+		return nil, e
+	}
+
+	br := bufio.NewReader(conn)
+	res, e := http.ReadResponse(br, req)
+	if e != nil {
+		conn.Close()
+		return nil, e
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("WebSocket handshake failed: %s", res.Status)
+	}
+	if res.Header.Get("Sec-WebSocket-Accept") != computeAccept(key) {
+		conn.Close()
+		return nil, errors.New("WebSocket handshake failed: bad Sec-WebSocket-Accept")
+	}
+	return &Conn{conn: conn, br: br, isClient: true}, nil
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+type wsFrameHeader struct {
+	fin     bool
+	op      byte
+	masked  bool
+	maskKey [4]byte
+	length  uint64
+}
+
+func (c *Conn) readHeader() (h wsFrameHeader, e error) {
+	var b [8]byte
+	if _, e = io.ReadFull(c.br, b[:2]); e != nil {
+		return h, e
+	}
+	if b[0]&0x70 != 0 { // RSV bits require an extension
+		return h, errProtocol
+	}
+	h.fin = b[0]&0x80 != 0
+	h.op = b[0] & 0x0F
+	switch h.op {
+	case OP_CONTINUATION, OP_TEXT, OP_BINARY, OP_CLOSE, OP_PING, OP_PONG:
+	default: // reserved opcodes
+		return h, errProtocol
+	}
+	h.masked = b[1]&0x80 != 0
+	h.length = uint64(b[1] & 0x7F)
+
+	switch h.length {
+	case 126:
+		if _, e = io.ReadFull(c.br, b[:2]); e != nil {
+			return h, e
+		}
+		h.length = uint64(binary.BigEndian.Uint16(b[:2]))
+	case 127:
+		if _, e = io.ReadFull(c.br, b[:8]); e != nil {
+			return h, e
+		}
+		h.length = binary.BigEndian.Uint64(b[:8])
+		if h.length&(1<<63) != 0 { // most significant bit must be zero
+			return h, errProtocol
+		}
+	}
+
+	// client-to-server frames must be masked, server-to-client frames must not be masked
+	if h.masked == c.isClient {
+		return h, errProtocol
+	}
+	if h.masked {
+		if _, e = io.ReadFull(c.br, h.maskKey[:]); e != nil {
+			return h, e
+		}
+	}
+
+	if h.op >= OP_CLOSE && (!h.fin || h.length > wsMaxControlLength) {
+		return h, errProtocol
+	}
+	return h, nil
+}
+
+func applyMask(b []byte, key [4]byte) {
+	for i := range b {
+		b[i] ^= key[i%4]
+	}
+}
+
+// Read a data message into buf.
+// Control frames are processed internally. A Close frame is answered and causes io.EOF.
+// Returns the opcode (OP_BINARY or OP_TEXT) and message length.
+func (c *Conn) ReadMessage(buf []byte) (op byte, n int, e error) {
+	tooLarge := false
+	for {
+		h, e := c.readHeader()
+		if e != nil {
+			return 0, 0, e
+		}
+
+		if h.op >= OP_CLOSE {
+			if e = c.handleControl(h); e != nil {
+				return 0, 0, e
+			}
+			continue
+		}
+
+		if (op == 0) == (h.op == OP_CONTINUATION) {
+			return 0, 0, errProtocol
+		}
+		if op == 0 {
+			op = h.op
+		}
+
+		if tooLarge || uint64(len(buf)-n) < h.length {
+			tooLarge = true
+			if _, e = io.CopyN(ioutil.Discard, c.br, int64(h.length)); e != nil {
+				return 0, 0, e
+			}
+		} else {
+			payload := buf[n : n+int(h.length)]
+			if _, e = io.ReadFull(c.br, payload); e != nil {
+				return 0, 0, e
+			}
+			if h.masked {
+				applyMask(payload, h.maskKey)
+			}
+			n += len(payload)
+		}
+
+		if h.fin {
+			break
+		}
+	}
+
+	if tooLarge {
+		return op, 0, ErrMessageTooLarge
+	}
+	return op, n, nil
+}
+
+func (c *Conn) handleControl(h wsFrameHeader) error {
+	payload := make([]byte, h.length)
+	if _, e := io.ReadFull(c.br, payload); e != nil {
+		return e
+	}
+	if h.masked {
+		applyMask(payload, h.maskKey)
+	}
+
+	switch h.op {
+	case OP_PING:
+		return c.WriteMessage(OP_PONG, payload)
+	case OP_CLOSE:
+		c.closeWith(payload)
+		return io.EOF
+	}
+	return nil
+}
+
+// Write a message.
+// Payload is the concatenation of payloads, which are sent without copying on a server connection.
+func (c *Conn) WriteMessage(op byte, payloads ...[]byte) error {
+	length := 0
+	for _, payload := range payloads {
+		length += len(payload)
+	}
+
+	hdr := make([]byte, 2, 14)
+	hdr[0] = 0x80 | op
+	switch {
+	case length <= wsMaxControlLength:
+		hdr[1] = byte(length)
+	case length <= 0xFFFF:
+		hdr[1] = 126
+		hdr = append(hdr, 0, 0)
+		binary.BigEndian.PutUint16(hdr[2:], uint16(length))
+	default:
+		hdr[1] = 127
+		hdr = append(hdr, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(hdr[2:], uint64(length))
+	}
+
+	bufs := net.Buffers{hdr}
+	if c.isClient {
+		var maskKey [4]byte
+		rand.Read(maskKey[:])
+		hdr[1] |= 0x80
+		hdr = append(hdr, maskKey[:]...)
+		masked := make([]byte, 0, length)
+		for _, payload := range payloads {
+			masked = append(masked, payload...)
+		}
+		applyMask(masked, maskKey)
+		bufs = net.Buffers{hdr, masked}
+	} else {
+		bufs = append(bufs, payloads...)
+	}
+
+	c.wLock.Lock()
+	defer c.wLock.Unlock()
+	_, e := bufs.WriteTo(c.conn)
+	return e
+}
+
+func (c *Conn) closeWith(payload []byte) {
+	c.closeOnce.Do(func() {
+		c.conn.SetWriteDeadline(time.Now().Add(wsCloseTimeout))
+		c.WriteMessage(OP_CLOSE, payload) // ignore error
+		c.conn.Close()
+	})
+}
+
+// Send a Close frame with status 1000 (normal closure) and close the underlying connection.
+func (c *Conn) Close() error {
+	c.closeWith([]byte{0x03, 0xE8})
+	return nil
+}
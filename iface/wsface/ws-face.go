@@ -0,0 +1,208 @@
+package wsface
+
+/*
+#include "../face.h"
+uint16_t go_WsFace_TxBurst(Face* faceC, struct rte_mbuf** pkts, uint16_t nPkts);
+*/
+import "C"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/sirupsen/logrus"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+)
+
+// Configuration for creating WsFace.
+type Config struct {
+	iface.Mempools
+	RxMp      dpdk.PktmbufPool // mempool for received frames, dataroom must fit NDNLP frame
+	TxqPkts   int              // before-TX queue capacity
+	TxqFrames int              // after-TX queue capacity
+
+	// If not nil, this lock is held while Listener creates a face for an accepted client.
+	Locker sync.Locker
+}
+
+// Delay before retrying after an RX mbuf allocation error.
+const rxAllocRetryInterval = 10 * time.Millisecond
+
+// A face on a WebSocket connection.
+// Each binary message carries one NDNLPv2 frame.
+type WsFace struct {
+	iface.FaceBase
+	logger   logrus.FieldLogger
+	conn     *Conn
+	loc      Locator
+	listener *Listener
+
+	closing    int32          // 1 if face is closing, need atomic access
+	nRxDropped uint64         // dropped messages, need atomic access
+	quit       chan struct{}  // closed when face is closing
+	quitWg     sync.WaitGroup // wait until rxLoop and txLoop quits
+
+	rxMp dpdk.PktmbufPool
+
+	txQueue chan dpdk.Packet
+}
+
+func newFace(conn *Conn, loc Locator, cfg Config, listener *Listener) (face *WsFace, e error) {
+	face = new(WsFace)
+	face.conn = conn
+	face.loc = loc
+	face.listener = listener
+	face.quit = make(chan struct{})
+
+	if e := face.InitFaceBase(iface.AllocId(iface.FaceKind_Ws), 0, dpdk.NUMA_SOCKET_ANY); e != nil {
+		return nil, e
+	}
+
+	// a web page on any site can make the browser connect to a loopback address,
+	// so that a WebSocket client is never trusted as a local application
+	face.SetLocal(false)
+	face.logger = newLogger(face.GetFaceId())
+	face.rxMp = cfg.RxMp
+	face.txQueue = make(chan dpdk.Packet, cfg.TxqFrames)
+
+	faceC := face.getPtr()
+	faceC.txBurstOp = (C.FaceImpl_TxBurst)(C.go_WsFace_TxBurst)
+	if e := face.FinishInitFaceBase(cfg.TxqPkts, 0, 0, cfg.Mempools); e != nil {
+		return nil, e
+	}
+
+	iface.TheChanRxGroup.AddFace(face)
+	face.quitWg.Add(2)
+	go face.rxLoop()
+	go face.txLoop()
+
+	iface.Put(face)
+	face.logger.Infof("new face %s->%s%s", loc.Local, loc.Remote, loc.Path)
+	return face, nil
+}
+
+func (face *WsFace) getPtr() *C.Face {
+	return (*C.Face)(face.GetPtr())
+}
+
+func (face *WsFace) GetConn() *Conn {
+	return face.conn
+}
+
+func (face *WsFace) GetLocator() iface.Locator {
+	return face.loc
+}
+
+func (face *WsFace) Close() error {
+	if !atomic.CompareAndSwapInt32(&face.closing, 0, 1) {
+		return nil
+	}
+	face.BeforeClose()
+	if face.listener != nil {
+		face.listener.removeFace(face)
+	}
+	close(face.quit)
+	close(face.txQueue)
+	face.conn.Close() // ignore error
+	face.quitWg.Wait()
+	iface.TheChanRxGroup.RemoveFace(face)
+	face.CloseFaceBase()
+	return nil
+}
+
+func (face *WsFace) ListRxGroups() []iface.IRxGroup {
+	return []iface.IRxGroup{iface.TheChanRxGroup}
+}
+
+func (face *WsFace) isClosing() bool {
+	return atomic.LoadInt32(&face.closing) != 0
+}
+
+// Handle connection error.
+// The face is closed, because a WebSocket client cannot be redialed.
+func (face *WsFace) handleError(dir string, e error) {
+	if face.isClosing() {
+		return
+	}
+	face.logger.WithError(e).Errorf("%s connection failed", dir)
+	go face.Close()
+}
+
+func (face *WsFace) rxLoop() {
+	defer face.quitWg.Done()
+	for {
+		mbuf, e := face.rxMp.Alloc()
+		if e != nil {
+			// message stays in socket buffer, retry after faces have released some mbufs
+			face.logger.WithError(e).Warn("RX alloc error")
+			select {
+			case <-face.quit:
+				return
+			case <-time.After(rxAllocRetryInterval):
+			}
+			continue
+		}
+
+		pkt := mbuf.AsPacket()
+		seg0 := pkt.GetFirstSegment()
+		seg0.SetHeadroom(0)
+
+		buf := seg0.AsByteSlice()
+		buf = buf[:cap(buf)]
+		op, n, e := face.conn.ReadMessage(buf)
+		if (e == nil && op != OP_BINARY) || e == ErrMessageTooLarge {
+			pkt.Close()
+			atomic.AddUint64(&face.nRxDropped, 1)
+			continue
+		}
+		if e != nil {
+			pkt.Close()
+			face.handleError("RX", e)
+			return
+		}
+		seg0.Append(buf[:n])
+
+		pkt.SetPort(uint16(face.GetFaceId()))
+		pkt.SetTimestamp(dpdk.TscNow())
+		iface.TheChanRxGroup.Rx(pkt)
+	}
+}
+
+func (face *WsFace) txLoop() {
+	defer face.quitWg.Done()
+	failed := false
+	for pkt := range face.txQueue {
+		if !failed {
+			var payloads [][]byte
+			for seg, ok := pkt.GetFirstSegment(), true; ok; seg, ok = seg.GetNext() {
+				payloads = append(payloads, seg.AsByteSlice())
+			}
+			if e := face.conn.WriteMessage(OP_BINARY, payloads...); e != nil {
+				face.handleError("TX", e)
+				failed = true
+			}
+		}
+		pkt.Close()
+	}
+}
+
+//export go_WsFace_TxBurst
+func go_WsFace_TxBurst(faceC *C.Face, pkts **C.struct_rte_mbuf, nPkts C.uint16_t) C.uint16_t {
+	face := iface.Get(iface.FaceId(faceC.id)).(*WsFace)
+	nQueued := C.uint16_t(0)
+	for i := C.uint16_t(0); i < nPkts; i++ {
+		pktsEle := (**C.struct_rte_mbuf)(unsafe.Pointer(uintptr(unsafe.Pointer(pkts)) +
+			uintptr(i)*unsafe.Sizeof(*pkts)))
+		pkt := dpdk.MbufFromPtr(unsafe.Pointer(*pktsEle)).AsPacket()
+		select {
+		case face.txQueue <- pkt:
+			nQueued++
+		default:
+			return nQueued
+		}
+	}
+	return nQueued
+}
@@ -0,0 +1,167 @@
+package wsface_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/ifacetestfixture"
+	"ndn-dpdk/iface/wsface"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+func TestWsFace(t *testing.T) {
+	assert, require := makeAR(t)
+
+	l, e := wsface.Listen(wsface.ListenerConfig{Local: "127.0.0.1:0", Path: "/ndn"}, wsfaceCfg)
+	require.NoError(e)
+	defer l.Close()
+	addr := l.GetConfig().Local
+
+	_, e = wsface.Dial("ws://"+addr+"/other", "")
+	assert.Error(e)
+
+	client, e := wsface.Dial("ws://"+addr+"/ndn", "")
+	require.NoError(e)
+	time.Sleep(100 * time.Millisecond)
+
+	faces := l.ListFaces()
+	require.Len(faces, 1)
+	face := faces[0]
+	assert.Equal(iface.FaceKind_Ws, face.GetFaceId().GetKind())
+	assert.False(face.IsLocal())
+	loc := face.GetLocator().(wsface.Locator)
+	assert.Equal("ws", loc.Scheme)
+	assert.Equal(addr, loc.Local)
+	assert.Equal(client.LocalAddr().String(), loc.Remote)
+	assert.Equal("/ndn", loc.Path)
+	ifacetestfixture.CheckLocatorMarshal(t, loc)
+
+	var rxNames []string
+	rxl := iface.NewRxLoop(dpdk.NUMA_SOCKET_ANY)
+	rxl.SetLCore(dpdk.ListSlaveLCores()[0])
+	rxl.SetCallback(iface.WrapRxCb(func(burst iface.RxBurst) {
+		for _, interest := range burst.ListInterests() {
+			assert.Equal(face.GetFaceId(), iface.FaceId(interest.GetPacket().AsDpdkPacket().GetPort()))
+			rxNames = append(rxNames, interest.GetName().String())
+			ndntestutil.ClosePacket(interest)
+		}
+	}))
+	require.NoError(rxl.Launch())
+	defer rxl.Close()
+	defer rxl.Stop()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(rxl.AddRxGroup(iface.TheChanRxGroup))
+
+	txl := iface.NewTxLoop(dpdk.NUMA_SOCKET_ANY)
+	txl.SetLCore(dpdk.ListSlaveLCores()[1])
+	require.NoError(txl.Launch())
+	defer txl.Close()
+	defer txl.Stop()
+	txl.AddFace(face)
+	time.Sleep(50 * time.Millisecond)
+
+	// each binary message is an L2 frame; text message is dropped
+	require.NoError(client.WriteMessage(wsface.OP_BINARY,
+		dpdktestenv.BytesFromHex("050B name=0703080141 nonce=0A04A0A1A2A3")))
+	require.NoError(client.WriteMessage(wsface.OP_TEXT, []byte("hello")))
+	require.NoError(client.WriteMessage(wsface.OP_PING, []byte("ping")))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal([]string{"/A"}, rxNames)
+	assert.Equal(uint64(1), face.ReadExCounters().(wsface.ExCounters).RxDropped)
+
+	face.TxBurst([]ndn.Packet{ndntestutil.MakeInterest("/B").GetPacket()})
+	buf := make([]byte, 9000)
+	op, n, e := client.ReadMessage(buf)
+	require.NoError(e)
+	assert.Equal(byte(wsface.OP_BINARY), op)
+	assert.True(bytes.Contains(buf[:n], []byte{0x08, 0x01, 0x42}))
+
+	cnt := face.ReadCounters()
+	assert.Equal(uint64(1), cnt.RxInterests)
+	assert.Equal(uint64(1), cnt.TxInterests)
+
+	txl.RemoveFace(face)
+	client.Close() // face should close when client disconnects
+	time.Sleep(100 * time.Millisecond)
+	assert.True(face.IsClosed())
+	assert.Len(l.ListFaces(), 0)
+}
+
+func TestWsOrigin(t *testing.T) {
+	assert, require := makeAR(t)
+
+	l, e := wsface.Listen(wsface.ListenerConfig{Local: "127.0.0.1:0",
+		AllowedOrigins: []string{"https://app.example"}}, wsfaceCfg)
+	require.NoError(e)
+	defer l.Close()
+	url := "ws://" + l.GetConfig().Local + "/"
+
+	tests := []struct {
+		origin string
+		ok     bool
+	}{
+		{"", true},                              // not a browser
+		{"http://" + l.GetConfig().Local, true}, // same origin
+		{"https://app.example", true},
+		{"https://evil.example", false},
+		{"null", false},
+	}
+	nFaces := 0
+	for _, tt := range tests {
+		client, e := wsface.Dial(url, tt.origin)
+		if tt.ok {
+			if assert.NoError(e, tt.origin) {
+				defer client.Close()
+				nFaces++
+			}
+		} else {
+			assert.Error(e, tt.origin)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(l.ListFaces(), nFaces)
+}
+
+func TestWsProtocolError(t *testing.T) {
+	assert, require := makeAR(t)
+
+	l, e := wsface.Listen(wsface.ListenerConfig{Local: "127.0.0.1:0"}, wsfaceCfg)
+	require.NoError(e)
+	defer l.Close()
+
+	client, e := wsface.Dial("ws://"+l.GetConfig().Local+"/", "")
+	require.NoError(e)
+	defer client.Close()
+	time.Sleep(100 * time.Millisecond)
+	faces := l.ListFaces()
+	require.Len(faces, 1)
+
+	// reserved opcode causes the face to close
+	require.NoError(client.WriteMessage(0x3, []byte("reserved")))
+	time.Sleep(100 * time.Millisecond)
+	assert.True(faces[0].IsClosed())
+	assert.Len(l.ListFaces(), 0)
+}
+
+func TestWsMaxFaces(t *testing.T) {
+	assert, require := makeAR(t)
+
+	l, e := wsface.Listen(wsface.ListenerConfig{Local: "127.0.0.1:0", MaxFaces: 1}, wsfaceCfg)
+	require.NoError(e)
+	defer l.Close()
+	url := "ws://" + l.GetConfig().Local + "/"
+
+	client, e := wsface.Dial(url, "")
+	require.NoError(e)
+	defer client.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	_, e = wsface.Dial(url, "")
+	assert.Error(e)
+	assert.Len(l.ListFaces(), 1)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/bundleface"
 	"ndn-dpdk/iface/createface"
 )
 
@@ -26,6 +27,10 @@ func (FaceMgmt) Get(args IdArg, reply *FaceInfo) error {
 
 	reply.BasicInfo = makeBasicInfo(face)
 	reply.IsDown = face.IsDown()
+	reply.IsLocal = face.IsLocal()
+	reply.Acl = face.GetAcl()
+	reply.RateLimit = face.GetRateLimit()
+	reply.Capture = face.GetCapture()
 	reply.Counters = face.ReadCounters()
 	reply.ExCounters = face.ReadExCounters()
 
@@ -51,10 +56,98 @@ func (FaceMgmt) Destroy(args IdArg, reply *struct{}) error {
 	return face.Close()
 }
 
+func (FaceMgmt) SetAcl(args SetAclArg, reply *struct{}) error {
+	face := iface.Get(args.Id)
+	if face == nil {
+		return errors.New("face not found")
+	}
+
+	return face.SetAcl(args.Acl)
+}
+
+func (FaceMgmt) SetRateLimit(args SetRateLimitArg, reply *struct{}) error {
+	face := iface.Get(args.Id)
+	if face == nil {
+		return errors.New("face not found")
+	}
+
+	return face.SetRateLimit(args.RateLimit)
+}
+
+func (FaceMgmt) StartCapture(args StartCaptureArg, reply *struct{}) error {
+	face := iface.Get(args.Id)
+	if face == nil {
+		return errors.New("face not found")
+	}
+
+	return face.StartCapture(args.Filename, args.CaptureConfig)
+}
+
+func (FaceMgmt) StopCapture(args IdArg, reply *iface.CaptureCounters) (e error) {
+	face := iface.Get(args.Id)
+	if face == nil {
+		return errors.New("face not found")
+	}
+
+	*reply, e = face.StopCapture()
+	return e
+}
+
+func (FaceMgmt) AddBundleMember(args BundleMemberArg, reply *struct{}) error {
+	bundle, e := getBundle(args.Id)
+	if e != nil {
+		return e
+	}
+
+	return bundle.AddMember(args.Member)
+}
+
+func (FaceMgmt) RemoveBundleMember(args BundleMemberArg, reply *struct{}) error {
+	bundle, e := getBundle(args.Id)
+	if e != nil {
+		return e
+	}
+
+	return bundle.RemoveMember(args.Member)
+}
+
+func getBundle(id iface.FaceId) (*bundleface.BundleFace, error) {
+	face := iface.Get(id)
+	if face == nil {
+		return nil, errors.New("face not found")
+	}
+	bundle, ok := face.(*bundleface.BundleFace)
+	if !ok {
+		return nil, errors.New("face is not a bundle")
+	}
+	return bundle, nil
+}
+
 type IdArg struct {
 	Id iface.FaceId
 }
 
+type SetAclArg struct {
+	IdArg
+	Acl []iface.AclRule // empty list removes the ACL
+}
+
+type SetRateLimitArg struct {
+	IdArg
+	RateLimit *iface.RateLimitConfig // nil removes the rate limit
+}
+
+type StartCaptureArg struct {
+	IdArg
+	Filename string // pcapng file name in iface.CaptureDir, without path separators
+	iface.CaptureConfig
+}
+
+type BundleMemberArg struct {
+	IdArg
+	Member iface.FaceId // member face
+}
+
 type BasicInfo struct {
 	Id      iface.FaceId
 	Locator iface.LocatorWrapper
@@ -68,7 +161,17 @@ func makeBasicInfo(face iface.IFace) (b BasicInfo) {
 
 type FaceInfo struct {
 	BasicInfo
-	IsDown bool
+	IsDown  bool
+	IsLocal bool
+
+	// Access control list rules.
+	Acl []iface.AclRule
+
+	// Rate limit configuration, nil if unlimited.
+	RateLimit *iface.RateLimitConfig
+
+	// Packet capture configuration, nil if not capturing.
+	Capture *iface.CaptureConfig
 
 	// General counters.
 	Counters iface.Counters
@@ -0,0 +1,56 @@
+package facemgmt
+
+import (
+	"errors"
+
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/createface"
+	"ndn-dpdk/iface/socketface"
+)
+
+func (FaceMgmt) Listen(args socketface.ListenerConfig, reply *ListenerInfo) error {
+	l, e := createface.Listen(args)
+	if e != nil {
+		return e
+	}
+
+	*reply = makeListenerInfo(l)
+	return nil
+}
+
+func (FaceMgmt) ListListeners(args struct{}, reply *[]ListenerInfo) error {
+	result := make([]ListenerInfo, 0)
+	for _, l := range createface.ListListeners() {
+		result = append(result, makeListenerInfo(l))
+	}
+	*reply = result
+	return nil
+}
+
+func (FaceMgmt) CloseListener(args ListenerArg, reply *struct{}) error {
+	for _, l := range createface.ListListeners() {
+		if lc := l.GetConfig(); lc.Scheme == args.Scheme && lc.Local == args.Local {
+			return createface.CloseListener(l)
+		}
+	}
+	return errors.New("listener not found")
+}
+
+type ListenerArg struct {
+	Scheme string
+	Local  string
+}
+
+type ListenerInfo struct {
+	socketface.ListenerConfig
+	Faces []iface.FaceId // faces accepted by this listener
+}
+
+func makeListenerInfo(l *socketface.Listener) (info ListenerInfo) {
+	info.ListenerConfig = l.GetConfig()
+	info.Faces = make([]iface.FaceId, 0)
+	for _, face := range l.ListFaces() {
+		info.Faces = append(info.Faces, face.GetFaceId())
+	}
+	return info
+}
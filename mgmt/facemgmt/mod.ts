@@ -1,4 +1,5 @@
 import * as iface from "../../iface/mod";
+import * as socketface from "../../iface/socketface/mod";
 
 export interface IdArg {
   /**
@@ -11,17 +12,58 @@ export interface BasicInfo extends IdArg {
   Locator: iface.Locator;
 }
 
+export interface SetAclArg extends IdArg {
+  Acl?: iface.AclRule[];
+}
+
+export interface SetRateLimitArg extends IdArg {
+  RateLimit?: iface.RateLimitConfig;
+}
+
+export interface StartCaptureArg extends IdArg, iface.CaptureConfig {
+  Filename: string;
+}
+
+export interface BundleMemberArg extends IdArg {
+  /**
+   * @TJS-type integer
+   */
+  Member: number;
+}
+
 export interface FaceInfo extends BasicInfo {
   IsDown: boolean;
+  IsLocal: boolean;
+  Acl: iface.AclRule[];
+  RateLimit?: iface.RateLimitConfig;
+  Capture?: iface.CaptureConfig;
   Counters: iface.Counters;
   ExCounters: any;
 }
 
+export interface ListenerArg {
+  Scheme: string;
+  Local: string;
+}
+
+export interface ListenerInfo extends socketface.ListenerConfig {
+  Faces: number[];
+}
+
 export interface FaceMgmt {
   List: {args: {}; reply: BasicInfo[]};
   Get: {args: IdArg; reply: FaceInfo};
   Create: {args: iface.Locator; reply: BasicInfo};
   Destroy: {args: iface.Locator; reply: {}};
+  SetAcl: {args: SetAclArg; reply: {}};
+  SetRateLimit: {args: SetRateLimitArg; reply: {}};
+  StartCapture: {args: StartCaptureArg; reply: {}};
+  StopCapture: {args: IdArg; reply: iface.CaptureCounters};
+  AddBundleMember: {args: BundleMemberArg; reply: {}};
+  RemoveBundleMember: {args: BundleMemberArg; reply: {}};
+  Listen: {args: socketface.ListenerConfig; reply: ListenerInfo};
+  ListListeners: {args: {}; reply: ListenerInfo[]};
+  CloseListener: {args: ListenerArg; reply: {}};
 }
 
 export interface PortArg {
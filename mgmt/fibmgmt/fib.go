@@ -2,9 +2,16 @@ package fibmgmt
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"ndn-dpdk/container/fib"
+	"ndn-dpdk/container/rib"
 	"ndn-dpdk/container/strategycode"
+	"ndn-dpdk/core/nnduration"
 	"ndn-dpdk/iface"
 	"ndn-dpdk/ndn"
 )
@@ -12,6 +19,19 @@ import (
 type FibMgmt struct {
 	Fib               *fib.Fib
 	DefaultStrategyId int
+	Rib               *rib.Rib // if not nil, prefixes with RIB routes cannot be modified directly
+	SnapshotDir       string   // directory of snapshot files written by Save; if empty, Save is disabled
+}
+
+// Reject modification of a prefix managed by the RIB.
+func (mg FibMgmt) checkRib(name *ndn.Name) error {
+	if mg.Rib == nil {
+		return nil
+	}
+	if _, ok := mg.Rib.Find(name); ok {
+		return errors.New("prefix has RIB routes; use Rib.Add and Rib.Remove instead")
+	}
+	return nil
 }
 
 func (mg FibMgmt) Info(args struct{}, reply *FibInfo) error {
@@ -28,6 +48,9 @@ func (mg FibMgmt) List(args struct{}, reply *[]string) error {
 }
 
 func (mg FibMgmt) Insert(args InsertArg, reply *InsertReply) error {
+	if e := mg.checkRib(args.Name); e != nil {
+		return e
+	}
 	entry := new(fib.Entry)
 
 	entry.SetName(args.Name)
@@ -51,11 +74,22 @@ func (mg FibMgmt) Insert(args InsertArg, reply *InsertReply) error {
 		return e
 	}
 
+	var expiration time.Time
+	if args.ExpirationPeriod > 0 {
+		expiration = time.Now().Add(args.ExpirationPeriod.Duration())
+	}
+	if e = mg.Fib.SetExpiration(args.Name, expiration); e != nil {
+		return e
+	}
+
 	reply.IsNew = isNew
 	return nil
 }
 
 func (mg FibMgmt) Erase(args NameArg, reply *struct{}) error {
+	if e := mg.checkRib(args.Name); e != nil {
+		return e
+	}
 	return mg.Fib.Erase(args.Name)
 }
 
@@ -83,6 +117,33 @@ func (mg FibMgmt) ReadEntryCounters(args NameArg, reply *fib.EntryCounters) erro
 	return nil
 }
 
+func (mg FibMgmt) Save(args SaveArg, reply *struct{}) error {
+	if mg.SnapshotDir == "" {
+		return errors.New("snapshot directory is not configured")
+	}
+	filename := args.Filename
+	if filename == "" || filename == "." || filename == ".." ||
+		strings.ContainsRune(filename, '/') || strings.ContainsRune(filename, filepath.Separator) {
+		return errors.New("Filename must not contain path separators")
+	}
+
+	// write to a temporary file then rename, so that an existing snapshot is not corrupted on failure
+	file, e := ioutil.TempFile(mg.SnapshotDir, filename+".*")
+	if e != nil {
+		return e
+	}
+	defer os.Remove(file.Name())
+
+	if e = mg.Fib.Save(file); e != nil {
+		file.Close()
+		return e
+	}
+	if e = file.Close(); e != nil {
+		return e
+	}
+	return os.Rename(file.Name(), filepath.Join(mg.SnapshotDir, filename))
+}
+
 type FibInfo struct {
 	NEntries int // Number of entries.
 }
@@ -92,9 +153,14 @@ type NameArg struct {
 }
 
 type InsertArg struct {
-	Name       *ndn.Name
-	Nexthops   []iface.FaceId
-	StrategyId int
+	Name             *ndn.Name
+	Nexthops         []iface.FaceId
+	StrategyId       int
+	ExpirationPeriod nnduration.Milliseconds // zero means never expires
+}
+
+type SaveArg struct {
+	Filename string // snapshot file name in FibMgmt.SnapshotDir, without path separators
 }
 
 type InsertReply struct {
@@ -1,6 +1,7 @@
 import * as fib from "../../container/fib/mod";
 import * as strategycode from "../../container/strategycode/mod";
 import { Counter } from "../../core/mod";
+import { Milliseconds } from "../../core/nnduration/mod";
 import * as iface from "../../iface/mod";
 import * as ndn from "../../ndn/mod";
 
@@ -15,6 +16,15 @@ export interface NameArg {
 export interface InsertArg extends NameArg {
   Nexthops: iface.FaceId[];
   StrategyId?: strategycode.Id;
+
+  /**
+   * @default 0
+   */
+  ExpirationPeriod?: Milliseconds;
+}
+
+export interface SaveArg {
+  Filename: string;
 }
 
 export interface InsertReply {
@@ -42,4 +52,5 @@ export interface FibMgmt {
   Find: {args: NameArg; reply: LookupReply};
   Lpm: {args: NameArg; reply: LookupReply};
   ReadEntryCounters: {args: NameArg; reply: fib.EntryCounters};
+  Save: {args: SaveArg; reply: {}};
 }
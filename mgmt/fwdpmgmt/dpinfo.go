@@ -35,6 +35,15 @@ func (mg DpInfoMgmt) Fwd(arg IndexArg, reply *fwdp.FwdInfo) error {
 	return nil
 }
 
+func (mg DpInfoMgmt) Crypto(args struct{}, reply *fwdp.CryptoInfo) error {
+	reply1 := mg.Dp.ReadCryptoInfo()
+	if reply1 == nil {
+		return errors.New("crypto helper unavailable")
+	}
+	*reply = *reply1
+	return nil
+}
+
 func (mg DpInfoMgmt) Pit(arg IndexArg, reply *pit.Counters) error {
 	pcct := mg.Dp.GetFwdPcct(arg.Index)
 	if pcct == nil {
@@ -63,6 +72,7 @@ func (mg DpInfoMgmt) Cs(arg IndexArg, reply *CsCounters) error {
 	reply.MI = readCslCnt(theCs, cs.CSL_MI)
 	reply.NHits = pitCnt.NCsMatch
 	reply.NMisses = pitCnt.NInsert + pitCnt.NFound
+	reply.Disk = theCs.ReadDiskCounters()
 
 	return nil
 }
@@ -87,4 +97,6 @@ type CsCounters struct {
 
 	NHits   uint64
 	NMisses uint64
+
+	Disk cs.DiskCounters // second-tier DiskStore
 }
@@ -1,4 +1,5 @@
 import * as fwdp from "../../app/fwdp/mod";
+import * as cs from "../../container/cs/mod";
 import * as pit from "../../container/pit/mod";
 import { Counter, Index } from "../../core/mod";
 
@@ -21,12 +22,14 @@ export interface CsCounters {
   MI: CsListCounters;
   NHits: Counter;
   NMisses: Counter;
+  Disk: cs.DiskCounters;
 }
 
 export interface DpInfoMgmt {
   Global: {args: {}; reply: FwdpInfo};
   Input: {args: IndexArg; reply: fwdp.InputInfo};
   Fwd: {args: IndexArg; reply: fwdp.FwdInfo};
+  Crypto: {args: {}; reply: fwdp.CryptoInfo};
   Pit: {args: IndexArg; reply: pit.Counters};
   Cs: {args: IndexArg; reply: CsCounters};
 }
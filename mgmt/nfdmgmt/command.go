@@ -0,0 +1,177 @@
+package nfdmgmt
+
+import (
+	"reflect"
+
+	"ndn-dpdk/container/rib"
+	"ndn-dpdk/container/strategycode"
+	"ndn-dpdk/core/nnduration"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/mgmt/facemgmt"
+	"ndn-dpdk/mgmt/fibmgmt"
+	"ndn-dpdk/mgmt/ribmgmt"
+	"ndn-dpdk/ndn"
+)
+
+type commandHandler func(srv *Server, cp ControlParameters) (body ControlParameters, e error)
+
+var commands = map[string]commandHandler{
+	"faces/create":        (*Server).facesCreate,
+	"faces/destroy":       (*Server).facesDestroy,
+	"rib/register":        (*Server).ribRegister,
+	"rib/unregister":      (*Server).ribUnregister,
+	"strategy-choice/set": (*Server).strategyChoiceSet,
+}
+
+func (srv *Server) facesCreate(cp ControlParameters) (body ControlParameters, e error) {
+	if cp.Uri == "" {
+		return body, newControlError(status_BadRequest, "Uri is required")
+	}
+	loc, e := parseFaceUri(cp.Uri, cp.LocalUri)
+	if e != nil {
+		return body, e
+	}
+
+	for it := iface.IterFaces(); it.Valid(); it.Next() {
+		if reflect.DeepEqual(it.Face.GetLocator(), loc) {
+			return makeFaceParameters(it.Face), newControlError(status_Conflict, "face exists")
+		}
+	}
+
+	var info facemgmt.BasicInfo
+	if e = (facemgmt.FaceMgmt{}).Create(iface.LocatorWrapper{Locator: loc}, &info); e != nil {
+		return body, newControlError(status_BadRequest, "%v", e)
+	}
+	return makeFaceParameters(iface.Get(info.Id)), nil
+}
+
+func makeFaceParameters(face iface.IFace) (cp ControlParameters) {
+	cp.FaceId = face.GetFaceId()
+	cp.Uri, cp.LocalUri = makeFaceUri(face.GetLocator())
+	cp.FacePersistency = newNni(facePersistency_Persistent)
+	cp.Flags = newNni(0)
+	return cp
+}
+
+func (srv *Server) facesDestroy(cp ControlParameters) (body ControlParameters, e error) {
+	if cp.FaceId == srv.face.GetFaceId() {
+		return body, newControlError(status_BadRequest, "cannot destroy management face")
+	}
+	if iface.Get(cp.FaceId) != nil {
+		if e = (facemgmt.FaceMgmt{}).Destroy(facemgmt.IdArg{Id: cp.FaceId}, nil); e != nil {
+			return body, e
+		}
+	}
+	body.FaceId = cp.FaceId
+	return body, nil
+}
+
+// Find the FIB entry of a name, if it exists.
+func (srv *Server) findFibEntry(name *ndn.Name) (entry fibmgmt.LookupReply, e error) {
+	e = srv.fibMgmt.Find(fibmgmt.NameArg{Name: name}, &entry)
+	return entry, e
+}
+
+func (srv *Server) ribRegister(cp ControlParameters) (body ControlParameters, e error) {
+	if cp.Name == nil {
+		return body, newControlError(status_BadRequest, "Name is required")
+	}
+	if cp.FaceId == 0 {
+		return body, newControlError(status_BadRequest, "FaceId is required")
+	}
+	if iface.Get(cp.FaceId) == nil {
+		return body, newControlError(status_NotFound, "face not found")
+	}
+
+	body = cp
+	if body.Origin == nil {
+		body.Origin = newNni(0)
+	}
+	if body.Cost == nil {
+		body.Cost = newNni(0)
+	}
+	if body.Flags == nil {
+		body.Flags = newNni(uint64(rib.RouteFlag_ChildInherit))
+	}
+
+	var args ribmgmt.AddArg
+	args.Name = cp.Name
+	args.FaceId = cp.FaceId
+	args.Origin = int(*body.Origin)
+	args.Cost = int(*body.Cost)
+	args.Flags = rib.RouteFlags(*body.Flags)
+	if cp.ExpirationPeriod != nil {
+		args.ExpirationPeriod = nnduration.Milliseconds(*cp.ExpirationPeriod)
+	}
+	if e = srv.ribMgmt.Add(args, nil); e != nil {
+		return body, e
+	}
+	return body, nil
+}
+
+func (srv *Server) ribUnregister(cp ControlParameters) (body ControlParameters, e error) {
+	if cp.Name == nil {
+		return body, newControlError(status_BadRequest, "Name is required")
+	}
+	if cp.FaceId == 0 {
+		return body, newControlError(status_BadRequest, "FaceId is required")
+	}
+
+	body.Name = cp.Name
+	body.FaceId = cp.FaceId
+	body.Origin = cp.Origin
+	if body.Origin == nil {
+		body.Origin = newNni(0)
+	}
+
+	if e = srv.ribMgmt.Remove(ribmgmt.RemoveArg{
+		Name:   cp.Name,
+		FaceId: cp.FaceId,
+		Origin: int(*body.Origin),
+	}, nil); e != nil {
+		return body, e
+	}
+	return body, nil
+}
+
+// Strategy name prefix in NFD management protocol.
+// The component after this prefix is taken as the strategy short name.
+var strategyPrefix = ndn.MustParseName("/localhost/nfd/strategy")
+
+func (srv *Server) strategyChoiceSet(cp ControlParameters) (body ControlParameters, e error) {
+	if cp.Name == nil || cp.Strategy == nil {
+		return body, newControlError(status_BadRequest, "Name and Strategy are required")
+	}
+
+	var strategyName string
+	switch {
+	case cp.Strategy.Len() > strategyPrefix.Len() && cp.Strategy.GetPrefix(strategyPrefix.Len()).Equal(strategyPrefix):
+		strategyName = string(cp.Strategy.GetComp(strategyPrefix.Len()).GetValue())
+	case cp.Strategy.Len() == 1:
+		strategyName = string(cp.Strategy.GetComp(0).GetValue())
+	default:
+		return body, newControlError(status_NotFound, "strategy not found")
+	}
+
+	sc := strategycode.Find(strategyName)
+	if sc == nil {
+		return body, newControlError(status_NotFound, "strategy %s not found", strategyName)
+	}
+
+	// NDN-DPDK stores strategy choice in FIB entries, so that a FIB entry must exist.
+	entry, e := srv.findFibEntry(cp.Name)
+	if e != nil {
+		return body, e
+	}
+	if !entry.HasEntry {
+		return body, newControlError(status_NotFound, "FIB entry not found")
+	}
+	// go through the RIB, so that a concurrent route change does not revert the strategy choice
+	if e = srv.ribMgmt.Rib.SetStrategy(cp.Name, sc); e != nil {
+		return body, e
+	}
+
+	body.Name = cp.Name
+	body.Strategy = cp.Strategy
+	return body, nil
+}
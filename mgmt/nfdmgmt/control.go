@@ -0,0 +1,167 @@
+package nfdmgmt
+
+import (
+	"fmt"
+
+	"ndn-dpdk/iface"
+	"ndn-dpdk/ndn"
+)
+
+// ControlParameters of NFD management protocol.
+// Optional numeric fields are nil when absent.
+type ControlParameters struct {
+	Name             *ndn.Name
+	FaceId           iface.FaceId
+	Uri              string
+	LocalUri         string
+	Origin           *uint64
+	Cost             *uint64
+	Flags            *uint64
+	Strategy         *ndn.Name
+	ExpirationPeriod *uint64
+	FacePersistency  *uint64
+}
+
+func newNni(n uint64) *uint64 {
+	return &n
+}
+
+func (cp *ControlParameters) decode(wire ndn.TlvBytes) error {
+	element, tail := wire.ExtractElement()
+	if element == nil || len(tail) > 0 {
+		return fmt.Errorf("bad ControlParameters")
+	}
+	tt, afterT := element.DecodeVarNum()
+	if ndn.TlvType(tt) != tt_ControlParameters {
+		return fmt.Errorf("bad ControlParameters TLV-TYPE %v", ndn.TlvType(tt))
+	}
+	_, value := afterT.DecodeVarNum()
+
+	return decodeTlvs(value, func(tt ndn.TlvType, value ndn.TlvBytes) (e error) {
+		switch tt {
+		case ndn.TT_Name:
+			cp.Name, e = ndn.NewName(value)
+		case tt_FaceId:
+			var n uint64
+			if n, e = decodeNni(value); e == nil {
+				cp.FaceId = iface.FaceId(n)
+			}
+		case tt_Uri:
+			cp.Uri = string(value)
+		case tt_LocalUri:
+			cp.LocalUri = string(value)
+		case tt_Origin:
+			cp.Origin, e = decodeNniPtr(value)
+		case tt_Cost:
+			cp.Cost, e = decodeNniPtr(value)
+		case tt_Flags:
+			cp.Flags, e = decodeNniPtr(value)
+		case tt_Strategy:
+			e = decodeTlvs(value, func(tt ndn.TlvType, value ndn.TlvBytes) (e error) {
+				if tt == ndn.TT_Name {
+					cp.Strategy, e = ndn.NewName(value)
+				}
+				return e
+			})
+		case tt_ExpirationPeriod:
+			cp.ExpirationPeriod, e = decodeNniPtr(value)
+		case tt_FacePersistency:
+			cp.FacePersistency, e = decodeNniPtr(value)
+		}
+		if e != nil {
+			return fmt.Errorf("bad ControlParameters field %v: %v", tt, e)
+		}
+		return nil
+	})
+}
+
+func decodeNniPtr(value ndn.TlvBytes) (*uint64, error) {
+	n, e := decodeNni(value)
+	if e != nil {
+		return nil, e
+	}
+	return &n, nil
+}
+
+func (cp ControlParameters) encode() ndn.TlvBytes {
+	var fields []ndn.TlvBytes
+	if cp.Name != nil {
+		fields = append(fields, cp.Name.Encode())
+	}
+	if cp.FaceId != 0 {
+		fields = append(fields, encodeNniTlv(tt_FaceId, uint64(cp.FaceId)))
+	}
+	if cp.Uri != "" {
+		fields = append(fields, ndn.EncodeTlv(tt_Uri, ndn.TlvBytes(cp.Uri)))
+	}
+	if cp.LocalUri != "" {
+		fields = append(fields, ndn.EncodeTlv(tt_LocalUri, ndn.TlvBytes(cp.LocalUri)))
+	}
+	if cp.Origin != nil {
+		fields = append(fields, encodeNniTlv(tt_Origin, *cp.Origin))
+	}
+	if cp.Cost != nil {
+		fields = append(fields, encodeNniTlv(tt_Cost, *cp.Cost))
+	}
+	if cp.Flags != nil {
+		fields = append(fields, encodeNniTlv(tt_Flags, *cp.Flags))
+	}
+	if cp.Strategy != nil {
+		fields = append(fields, ndn.EncodeTlv(tt_Strategy, cp.Strategy.Encode()))
+	}
+	if cp.ExpirationPeriod != nil {
+		fields = append(fields, encodeNniTlv(tt_ExpirationPeriod, *cp.ExpirationPeriod))
+	}
+	if cp.FacePersistency != nil {
+		fields = append(fields, encodeNniTlv(tt_FacePersistency, *cp.FacePersistency))
+	}
+	return ndn.EncodeTlv(tt_ControlParameters, fields...)
+}
+
+// ControlResponse status codes.
+const (
+	status_OK          = 200
+	status_BadRequest  = 400
+	status_Forbidden   = 403
+	status_NotFound    = 404
+	status_Conflict    = 409
+	status_ServerError = 500
+	status_Unsupported = 501
+)
+
+// Error with a ControlResponse status code.
+type controlError struct {
+	Code int
+	Text string
+}
+
+func (e controlError) Error() string {
+	return fmt.Sprintf("%d %s", e.Code, e.Text)
+}
+
+func newControlError(code int, format string, args ...interface{}) error {
+	return controlError{code, fmt.Sprintf(format, args...)}
+}
+
+// Encode a ControlResponse.
+// If e is nil, the response indicates success and carries body parameters.
+// A Conflict response also carries body parameters, which describe the existing object.
+func encodeControlResponse(body ControlParameters, e error) ndn.TlvBytes {
+	code, text := status_OK, "OK"
+	if e != nil {
+		if ce, ok := e.(controlError); ok {
+			code, text = ce.Code, ce.Text
+		} else {
+			code, text = status_ServerError, e.Error()
+		}
+	}
+
+	fields := []ndn.TlvBytes{
+		encodeNniTlv(tt_StatusCode, uint64(code)),
+		ndn.EncodeTlv(tt_StatusText, ndn.TlvBytes(text)),
+	}
+	if code == status_OK || code == status_Conflict {
+		fields = append(fields, body.encode())
+	}
+	return ndn.EncodeTlv(tt_ControlResponse, fields...)
+}
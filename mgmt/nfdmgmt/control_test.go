@@ -0,0 +1,120 @@
+package nfdmgmt
+
+import (
+	"errors"
+	"testing"
+
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/ndn"
+)
+
+// Decode a ControlResponse.
+func decodeControlResponse(wire ndn.TlvBytes) (code int, text string, body *ControlParameters, e error) {
+	value, e := expectTlv(wire, tt_ControlResponse)
+	if e != nil {
+		return 0, "", nil, e
+	}
+	e = decodeTlvs(value, func(tt ndn.TlvType, value ndn.TlvBytes) (e error) {
+		switch tt {
+		case tt_StatusCode:
+			var n uint64
+			n, e = decodeNni(value)
+			code = int(n)
+		case tt_StatusText:
+			text = string(value)
+		case tt_ControlParameters:
+			body = new(ControlParameters)
+			e = body.decode(ndn.EncodeTlv(tt, value))
+		}
+		return e
+	})
+	return code, text, body, e
+}
+
+func TestControlParameters(t *testing.T) {
+	assert, require := makeAR(t)
+
+	tests := []struct {
+		cp   ControlParameters
+		wire string // expected encoding, empty to skip comparison
+	}{
+		{ControlParameters{}, "6800"},
+		{ControlParameters{FaceId: 300, Cost: newNni(10)}, "6807 6902012C 6A010A"},
+		{ControlParameters{Name: ndn.MustParseName("/A"), Origin: newNni(0), Flags: newNni(1),
+			ExpirationPeriod: newNni(3600000)}, "6811 0703080141 6F0100 6C0101 6D040036EE80"},
+		{ControlParameters{Uri: "udp4://192.0.2.1:6363", LocalUri: "udp4://0.0.0.0:6363",
+			FacePersistency: newNni(facePersistency_Persistent)}, ""},
+		{ControlParameters{Name: ndn.MustParseName("/A/B"),
+			Strategy: ndn.MustParseName("/localhost/nfd/strategy/best-route")}, ""},
+	}
+	for i, tt := range tests {
+		wire := tt.cp.encode()
+		if tt.wire != "" {
+			assert.Equal(ndn.TlvBytes(dpdktestenv.BytesFromHex(tt.wire)), wire, i)
+		}
+
+		var decoded ControlParameters
+		require.NoError(decoded.decode(wire), i)
+		assert.Equal(wire, decoded.encode(), i)
+		assert.Equal(tt.cp.FaceId, decoded.FaceId, i)
+		assert.Equal(tt.cp.Uri, decoded.Uri, i)
+		assert.Equal(tt.cp.LocalUri, decoded.LocalUri, i)
+		assert.Equal(tt.cp.Origin, decoded.Origin, i)
+		assert.Equal(tt.cp.Cost, decoded.Cost, i)
+		assert.Equal(tt.cp.Flags, decoded.Flags, i)
+		assert.Equal(tt.cp.ExpirationPeriod, decoded.ExpirationPeriod, i)
+		assert.Equal(tt.cp.FacePersistency, decoded.FacePersistency, i)
+		if tt.cp.Name == nil {
+			assert.Nil(decoded.Name, i)
+		} else if assert.NotNil(decoded.Name, i) {
+			assert.True(tt.cp.Name.Equal(decoded.Name), i)
+		}
+		if tt.cp.Strategy == nil {
+			assert.Nil(decoded.Strategy, i)
+		} else if assert.NotNil(decoded.Strategy, i) {
+			assert.True(tt.cp.Strategy.Equal(decoded.Strategy), i)
+		}
+	}
+
+	for _, wire := range []string{
+		"",                // empty
+		"0500",            // wrong TLV-TYPE
+		"6800 00",         // trailing octets
+		"6803 690201",     // truncated field
+		"6805 6A03000000", // bad NonNegativeInteger
+	} {
+		var decoded ControlParameters
+		assert.Error(decoded.decode(ndn.TlvBytes(dpdktestenv.BytesFromHex(wire))), wire)
+	}
+}
+
+func TestControlResponse(t *testing.T) {
+	assert, require := makeAR(t)
+
+	body := ControlParameters{FaceId: 300}
+	tests := []struct {
+		e       error
+		code    int
+		text    string
+		hasBody bool
+	}{
+		{nil, 200, "OK", true},
+		{newControlError(status_Conflict, "face %s", "exists"), 409, "face exists", true},
+		{newControlError(status_NotFound, "face not found"), 404, "face not found", false},
+		{newControlError(status_Forbidden, "bad signature"), 403, "bad signature", false},
+		{errors.New("internal"), 500, "internal", false},
+	}
+	for _, tt := range tests {
+		code, text, decodedBody, e := decodeControlResponse(encodeControlResponse(body, tt.e))
+		require.NoError(e, tt.text)
+		assert.Equal(tt.code, code, tt.text)
+		assert.Equal(tt.text, text, tt.text)
+		if tt.hasBody {
+			if assert.NotNil(decodedBody, tt.text) {
+				assert.Equal(body.FaceId, decodedBody.FaceId, tt.text)
+			}
+		} else {
+			assert.Nil(decodedBody, tt.text)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package nfdmgmt
+
+import (
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/ndn"
+)
+
+// Encode a Data packet signed with DigestSha256.
+// finalBlockId is a name component TLV, or nil to omit FinalBlockId.
+func encodeData(name *ndn.Name, freshnessPeriod time.Duration, finalBlockId ndn.TlvBytes,
+	content ndn.TlvBytes) ndn.TlvBytes {
+	metaFields := []ndn.TlvBytes{
+		encodeNniTlv(ndn.TT_FreshnessPeriod, uint64(freshnessPeriod/time.Millisecond)),
+	}
+	if finalBlockId != nil {
+		metaFields = append(metaFields, ndn.EncodeTlv(ndn.TT_FinalBlockId, finalBlockId))
+	}
+
+	signed := name.Encode().Join(
+		ndn.EncodeTlv(ndn.TT_MetaInfo, metaFields...),
+		ndn.EncodeTlv(ndn.TT_Content, content),
+		ndn.EncodeTlv(ndn.TT_SignatureInfo, encodeNniTlv(ndn.TT_SignatureType, 0)), // DigestSha256
+	)
+	digest := sha256.Sum256([]byte(signed))
+	return ndn.EncodeTlv(ndn.TT_Data, signed, ndn.EncodeTlv(ndn.TT_SignatureValue, digest[:]))
+}
+
+// Copy an encoded packet into an mbuf, leaving headroom for NDNLP header.
+func makePacket(mp dpdk.PktmbufPool, wire ndn.TlvBytes) (pkt ndn.Packet, e error) {
+	m, e := mp.Alloc()
+	if e != nil {
+		return pkt, e
+	}
+
+	seg0 := m.AsPacket().GetFirstSegment()
+	if e = seg0.SetHeadroom(ndn.PrependLpHeader_GetHeadroom()); e == nil {
+		e = seg0.Append([]byte(wire))
+	}
+	if e != nil {
+		m.Close()
+		return pkt, errors.New("packet does not fit in mbuf")
+	}
+	return ndn.PacketFromDpdk(m), nil
+}
+
+// Append components to a name.
+func appendName(name *ndn.Name, comps ...ndn.TlvBytes) *ndn.Name {
+	n, e := ndn.NewName(name.GetValue().Join(comps...))
+	if e != nil {
+		panic(e)
+	}
+	return n
+}
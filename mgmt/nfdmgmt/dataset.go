@@ -0,0 +1,140 @@
+package nfdmgmt
+
+import (
+	"time"
+
+	"ndn-dpdk/iface"
+	"ndn-dpdk/mgmt/facemgmt"
+	"ndn-dpdk/ndn"
+)
+
+const (
+	dataset_SegmentSize     = 1024
+	dataset_FreshnessPeriod = time.Second
+)
+
+type datasetHandler func(srv *Server) ndn.TlvBytes
+
+var datasets = map[string]datasetHandler{
+	"faces/list": (*Server).listFaces,
+	"fib/list":   (*Server).listFib,
+}
+
+// A version of generated status dataset, encoded as segmented Data packets.
+type datasetVersion struct {
+	versioned *ndn.Name
+	segments  []ndn.TlvBytes
+}
+
+// Serve a status dataset.
+// An Interest for the dataset prefix triggers generation of a new version and retrieves its first segment.
+// An Interest with version and segment number retrieves a segment of the latest version.
+func (srv *Server) serveDataset(interest *ndn.Interest, verb string, ds datasetHandler) {
+	name := interest.GetName()
+	prefixL := Prefix.Len() + 2
+	if name.Len() == prefixL {
+		srv.datasets[verb] = srv.makeDatasetVersion(name, ds(srv))
+		srv.reply(interest, srv.datasets[verb].segments[0])
+		return
+	}
+
+	dv := srv.datasets[verb]
+	if dv == nil || name.Len() != prefixL+2 || !name.GetPrefix(prefixL+1).Equal(dv.versioned) ||
+		name.GetComp(prefixL+1).GetType() != ndn.TT_SegmentNameComponent {
+		return
+	}
+	segNum, e := decodeNni(name.GetComp(prefixL + 1).GetValue())
+	if e != nil || segNum >= uint64(len(dv.segments)) {
+		return
+	}
+	srv.reply(interest, dv.segments[segNum])
+}
+
+func (srv *Server) makeDatasetVersion(prefix *ndn.Name, content ndn.TlvBytes) (dv *datasetVersion) {
+	dv = new(datasetVersion)
+	dv.versioned = appendName(prefix, ndn.EncodeTlv(ndn.TT_VersionNameComponent,
+		encodeNni(uint64(time.Now().UnixNano()/int64(time.Millisecond)))))
+
+	nSegments := (len(content) + dataset_SegmentSize - 1) / dataset_SegmentSize
+	if nSegments == 0 {
+		nSegments = 1
+	}
+	finalBlockId := ndn.EncodeTlv(ndn.TT_SegmentNameComponent, encodeNni(uint64(nSegments-1)))
+
+	for i := 0; i < nSegments; i++ {
+		chunk := content[i*dataset_SegmentSize:]
+		if len(chunk) > dataset_SegmentSize {
+			chunk = chunk[:dataset_SegmentSize]
+		}
+		segName := appendName(dv.versioned, ndn.EncodeTlv(ndn.TT_SegmentNameComponent, encodeNni(uint64(i))))
+		dv.segments = append(dv.segments, encodeData(segName, dataset_FreshnessPeriod, finalBlockId, chunk))
+	}
+	return dv
+}
+
+func (srv *Server) listFaces() (content ndn.TlvBytes) {
+	var list []facemgmt.BasicInfo
+	(facemgmt.FaceMgmt{}).List(struct{}{}, &list)
+	for _, basic := range list {
+		var info facemgmt.FaceInfo
+		if (facemgmt.FaceMgmt{}).Get(facemgmt.IdArg{Id: basic.Id}, &info) != nil {
+			continue
+		}
+		content = content.Join(makeFaceStatus(info))
+	}
+	return content
+}
+
+func makeFaceStatus(info facemgmt.FaceInfo) ndn.TlvBytes {
+	uri, localUri := makeFaceUri(info.Locator.Locator)
+	scope, linkType := uint64(faceScope_NonLocal), uint64(linkType_PointToPoint)
+	switch info.Id.GetKind() {
+	case iface.FaceKind_Mock:
+		scope = faceScope_Local
+	case iface.FaceKind_Eth:
+		linkType = linkType_MultiAccess
+	}
+
+	cnt := info.Counters
+	return ndn.EncodeTlv(tt_FaceStatus,
+		encodeNniTlv(tt_FaceId, uint64(info.Id)),
+		ndn.EncodeTlv(tt_Uri, ndn.TlvBytes(uri)),
+		ndn.EncodeTlv(tt_LocalUri, ndn.TlvBytes(localUri)),
+		encodeNniTlv(tt_FaceScope, scope),
+		encodeNniTlv(tt_FacePersistency, facePersistency_Persistent),
+		encodeNniTlv(tt_LinkType, linkType),
+		encodeNniTlv(tt_NInInterests, cnt.RxInterests),
+		encodeNniTlv(tt_NInData, cnt.RxData),
+		encodeNniTlv(tt_NInNacks, cnt.RxNacks),
+		encodeNniTlv(tt_NOutInterests, cnt.TxInterests),
+		encodeNniTlv(tt_NOutData, cnt.TxData),
+		encodeNniTlv(tt_NOutNacks, cnt.TxNacks),
+		encodeNniTlv(tt_NInBytes, cnt.RxOctets),
+		encodeNniTlv(tt_NOutBytes, cnt.TxOctets),
+		encodeNniTlv(tt_Flags, 0),
+	)
+}
+
+func (srv *Server) listFib() (content ndn.TlvBytes) {
+	var names []string
+	srv.fibMgmt.List(struct{}{}, &names)
+	for _, uri := range names {
+		name, e := ndn.ParseName(uri)
+		if e != nil {
+			continue
+		}
+		entry, e := srv.findFibEntry(name)
+		if e != nil || !entry.HasEntry {
+			continue
+		}
+
+		fields := []ndn.TlvBytes{entry.Name.Encode()}
+		for _, nh := range entry.Nexthops {
+			fields = append(fields, ndn.EncodeTlv(tt_NextHopRecord,
+				encodeNniTlv(tt_FaceId, uint64(nh)),
+				encodeNniTlv(tt_Cost, 0)))
+		}
+		content = content.Join(ndn.EncodeTlv(tt_FibEntry, fields...))
+	}
+	return content
+}
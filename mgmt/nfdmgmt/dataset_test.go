@@ -0,0 +1,80 @@
+package nfdmgmt
+
+import (
+	"bytes"
+	"testing"
+
+	"ndn-dpdk/ndn"
+)
+
+// Decode a Data packet encoded by encodeData.
+func decodeData(wire ndn.TlvBytes) (name *ndn.Name, finalBlockId ndn.TlvBytes, content ndn.TlvBytes, e error) {
+	value, e := expectTlv(wire, ndn.TT_Data)
+	if e != nil {
+		return nil, nil, nil, e
+	}
+	e = decodeTlvs(value, func(tt ndn.TlvType, value ndn.TlvBytes) (e error) {
+		switch tt {
+		case ndn.TT_Name:
+			name, e = ndn.NewName(value)
+		case ndn.TT_MetaInfo:
+			e = decodeTlvs(value, func(tt ndn.TlvType, value ndn.TlvBytes) error {
+				if tt == ndn.TT_FinalBlockId {
+					finalBlockId = value
+				}
+				return nil
+			})
+		case ndn.TT_Content:
+			content = value
+		}
+		return e
+	})
+	return name, finalBlockId, content, e
+}
+
+func TestDatasetSegmentation(t *testing.T) {
+	assert, require := makeAR(t)
+	prefix := appendName(Prefix, ndn.EncodeTlv(ndn.TT_GenericNameComponent, ndn.TlvBytes("faces")),
+		ndn.EncodeTlv(ndn.TT_GenericNameComponent, ndn.TlvBytes("list")))
+
+	tests := []struct {
+		contentL  int
+		nSegments int
+	}{
+		{0, 1},
+		{1, 1},
+		{dataset_SegmentSize, 1},
+		{dataset_SegmentSize + 1, 2},
+		{2*dataset_SegmentSize + 500, 3},
+	}
+	var srv Server
+	for _, tt := range tests {
+		content := make(ndn.TlvBytes, tt.contentL)
+		for i := range content {
+			content[i] = byte(i)
+		}
+
+		dv := srv.makeDatasetVersion(prefix, content)
+		require.Len(dv.segments, tt.nSegments, tt.contentL)
+		assert.Equal(prefix.Len()+1, dv.versioned.Len(), tt.contentL)
+		assert.True(dv.versioned.GetPrefix(prefix.Len()).Equal(prefix), tt.contentL)
+		assert.Equal(ndn.TT_VersionNameComponent, dv.versioned.GetComp(prefix.Len()).GetType(), tt.contentL)
+
+		lastSeg := ndn.EncodeTlv(ndn.TT_SegmentNameComponent, encodeNni(uint64(tt.nSegments-1)))
+		var joined []byte
+		for i, segment := range dv.segments {
+			name, finalBlockId, chunk, e := decodeData(segment)
+			require.NoError(e, tt.contentL)
+			assert.True(name.GetPrefix(dv.versioned.Len()).Equal(dv.versioned), tt.contentL)
+			assert.Equal(dv.versioned.Len()+1, name.Len(), tt.contentL)
+			segComp := name.GetComp(dv.versioned.Len())
+			assert.Equal(ndn.TT_SegmentNameComponent, segComp.GetType(), tt.contentL)
+			segNum, _ := decodeNni(segComp.GetValue())
+			assert.Equal(uint64(i), segNum, tt.contentL)
+			assert.Equal(lastSeg, finalBlockId, tt.contentL)
+			assert.True(len(chunk) <= dataset_SegmentSize, tt.contentL)
+			joined = append(joined, chunk...)
+		}
+		assert.True(bytes.Equal(content, joined), tt.contentL)
+	}
+}
@@ -0,0 +1,11 @@
+package nfdmgmt
+
+import (
+	"ndn-dpdk/core/logger"
+)
+
+var (
+	log           = logger.New("nfdmgmt")
+	makeLogFields = logger.MakeFields
+	addressOf     = logger.AddressOf
+)
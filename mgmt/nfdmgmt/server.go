@@ -0,0 +1,179 @@
+package nfdmgmt
+
+import (
+	"io"
+
+	"ndn-dpdk/app/fwdp"
+	"ndn-dpdk/container/fib"
+	"ndn-dpdk/container/rib"
+	"ndn-dpdk/core/nnduration"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/createface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/mgmt/fibmgmt"
+	"ndn-dpdk/mgmt/ribmgmt"
+	"ndn-dpdk/ndn"
+)
+
+// Name prefix of NFD management protocol.
+var Prefix = ndn.MustParseName("/localhost/nfd")
+
+type Config struct {
+	Fib               *fib.Fib
+	Rib               *rib.Rib // rib/register and rib/unregister commands operate on this RIB
+	DefaultStrategyId int
+	DataMp            dpdk.PktmbufPool // mempool for response Data, dataroom must fit a segment
+	QueueCapacity     int              // capacity of pending command Interests
+
+	TrustAnchors   []fwdp.TrustAnchor      // keys trusted to sign command Interests; if empty, every command is rejected
+	TimestampGrace nnduration.Milliseconds // tolerance of command Interest timestamp, default is 60 seconds
+}
+
+// An in-forwarder management face that serves NFD management protocol.
+type Server struct {
+	face      *mockface.MockFace
+	fibMgmt   fibmgmt.FibMgmt
+	ribMgmt   ribmgmt.RibMgmt
+	validator *commandValidator
+	dataMp    dpdk.PktmbufPool
+	queue     chan *ndn.Interest
+	closers   []io.Closer
+	datasets  map[string]*datasetVersion
+}
+
+// Create the management face and register Prefix toward it.
+// The createface package must have mock faces enabled.
+func New(cfg Config) (srv *Server, e error) {
+	grace := cfg.TimestampGrace.Duration()
+	if grace == 0 {
+		grace = defaultTimestampGrace
+	}
+	validator, e := newCommandValidator(cfg.TrustAnchors, grace)
+	if e != nil {
+		return nil, e
+	}
+
+	face, e := createface.Create(mockface.NewLocator())
+	if e != nil {
+		return nil, e
+	}
+
+	srv = &Server{
+		face: face.(*mockface.MockFace),
+		fibMgmt: fibmgmt.FibMgmt{
+			Fib:               cfg.Fib,
+			DefaultStrategyId: cfg.DefaultStrategyId,
+			Rib:               cfg.Rib,
+		},
+		ribMgmt:   ribmgmt.RibMgmt{Rib: cfg.Rib},
+		validator: validator,
+		dataMp:    cfg.DataMp,
+		queue:     make(chan *ndn.Interest, cfg.QueueCapacity),
+		datasets:  make(map[string]*datasetVersion),
+	}
+
+	srv.face.DisableTxRecorders()
+	srv.closers = []io.Closer{
+		srv.face.OnTxInterest(srv.enqueue),
+		srv.face.OnTxData(func(data *ndn.Data) { data.GetPacket().AsDpdkPacket().Close() }),
+		srv.face.OnTxNack(func(nack *ndn.Nack) { nack.GetPacket().AsDpdkPacket().Close() }),
+		srv.face.OnTxBadPkt(func(pkt ndn.Packet) { pkt.AsDpdkPacket().Close() }),
+	}
+
+	var reply fibmgmt.InsertReply
+	if e = srv.fibMgmt.Insert(fibmgmt.InsertArg{Name: Prefix, Nexthops: []iface.FaceId{srv.face.GetFaceId()}}, &reply); e != nil {
+		srv.face.Close()
+		return nil, e
+	}
+
+	go srv.run()
+	log.WithFields(makeLogFields("face", srv.face.GetFaceId(), "prefix", Prefix)).Info("NFD management face started")
+	return srv, nil
+}
+
+// Get FaceId of the management face.
+func (srv *Server) GetFaceId() iface.FaceId {
+	return srv.face.GetFaceId()
+}
+
+// Unregister Prefix and close the management face.
+func (srv *Server) Close() error {
+	srv.fibMgmt.Erase(fibmgmt.NameArg{Name: Prefix}, nil)
+	e := srv.face.Close()
+	for _, closer := range srv.closers {
+		closer.Close()
+	}
+	close(srv.queue)
+	return e
+}
+
+// Receive a command Interest from the send path of the management face.
+// This runs in the TxLoop thread, so that the Interest is processed asynchronously.
+func (srv *Server) enqueue(interest *ndn.Interest) {
+	select {
+	case srv.queue <- interest:
+	default:
+		log.WithField("name", interest.GetName()).Warn("command queue full, Interest dropped")
+		interest.GetPacket().AsDpdkPacket().Close()
+	}
+}
+
+func (srv *Server) run() {
+	for interest := range srv.queue {
+		srv.processInterest(interest)
+		interest.GetPacket().AsDpdkPacket().Close()
+	}
+}
+
+func (srv *Server) processInterest(interest *ndn.Interest) {
+	name := interest.GetName()
+	if name.Len() < Prefix.Len()+2 || !name.GetPrefix(Prefix.Len()).Equal(Prefix) {
+		return
+	}
+	verb := string(name.GetComp(Prefix.Len()).GetValue()) + "/" + string(name.GetComp(Prefix.Len()+1).GetValue())
+	logEntry := log.WithFields(makeLogFields("name", name, "verb", verb))
+
+	if ds, ok := datasets[verb]; ok {
+		srv.serveDataset(interest, verb, ds)
+		return
+	}
+
+	var body ControlParameters
+	var e error
+	if cmd, ok := commands[verb]; !ok {
+		e = newControlError(status_Unsupported, "unknown command %s", verb)
+	} else if e = srv.validator.validate(interest); e != nil {
+		// command Interest is rejected before ControlParameters is processed
+	} else if name.Len() <= Prefix.Len()+2 {
+		e = newControlError(status_BadRequest, "missing ControlParameters")
+	} else {
+		var cp ControlParameters
+		if e = cp.decode(name.GetComp(Prefix.Len() + 2).GetValue()); e != nil {
+			e = newControlError(status_BadRequest, "%v", e)
+		} else {
+			body, e = cmd(srv, cp)
+		}
+	}
+
+	if e != nil {
+		logEntry.WithError(e).Info("command failed")
+	} else {
+		logEntry.Info("command succeeded")
+	}
+	srv.reply(interest, encodeData(name, 0, nil, encodeControlResponse(body, e)))
+}
+
+// Send a Data in reply to an Interest.
+func (srv *Server) reply(interest *ndn.Interest, wire ndn.TlvBytes) {
+	pkt, e := makePacket(srv.dataMp, wire)
+	if e != nil {
+		log.WithField("name", interest.GetName()).WithError(e).Warn("reply Data error")
+		return
+	}
+
+	lpl3 := pkt.GetLpL3()
+	*lpl3 = ndn.LpL3{}
+	lpl3.SetPitToken(interest.GetPacket().GetLpL3().GetPitToken())
+	srv.face.Rx(pkt)
+}
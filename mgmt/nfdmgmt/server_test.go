@@ -0,0 +1,299 @@
+package nfdmgmt
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"ndn-dpdk/app/fwdp"
+	"ndn-dpdk/container/fib/fibtest"
+	"ndn-dpdk/container/rib"
+	"ndn-dpdk/container/strategycode"
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/createface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+type serverFixture struct {
+	t       *testing.T
+	fib     *fibtest.Fixture
+	rib     *rib.Rib
+	srv     *Server
+	signer  *testSigner
+	replies chan ndn.TlvBytes
+}
+
+func newServerFixture(t *testing.T) (fixture *serverFixture) {
+	_, require := makeAR(t)
+	fixture = &serverFixture{
+		t:       t,
+		signer:  newHmacSigner("/operator/KEY/1"),
+		replies: make(chan ndn.TlvBytes, 16),
+	}
+
+	faceCfg := createface.GetDefaultConfig()
+	faceCfg.EnableEth = false
+	faceCfg.EnableSock = true
+	faceCfg.EnableMock = true
+	require.NoError(faceCfg.Apply())
+	createface.AddMempools(dpdk.NUMA_SOCKET_ANY, theRxMp, theMempools)
+
+	txl := iface.NewTxLoop(dpdk.NUMA_SOCKET_ANY)
+	txl.SetLCore(dpdk.ListSlaveLCores()[0])
+	require.NoError(txl.Launch())
+	createface.AddTxLoop(txl)
+
+	rxl := iface.NewRxLoop(dpdk.NUMA_SOCKET_ANY)
+	rxl.SetLCore(dpdk.ListSlaveLCores()[1])
+	rxl.SetCallback(iface.WrapRxCb(fixture.rxCb))
+	require.NoError(rxl.Launch())
+	createface.AddRxLoop(rxl)
+	time.Sleep(50 * time.Millisecond)
+
+	fixture.fib = fibtest.NewFixture(2, 2, 1)
+	multicast := strategycode.MakeEmpty("multicast")
+	strategycode.MakeEmpty("best-route")
+	fixture.rib = rib.New(fixture.fib.Fib, multicast.GetId())
+
+	srv, e := New(Config{
+		Fib:               fixture.fib.Fib,
+		Rib:               fixture.rib,
+		DefaultStrategyId: multicast.GetId(),
+		DataMp:            theRxMp,
+		QueueCapacity:     16,
+		TrustAnchors:      []fwdp.TrustAnchor{fixture.signer.TrustAnchor()},
+	})
+	require.NoError(e)
+	fixture.srv = srv
+	return fixture
+}
+
+func (fixture *serverFixture) Close() error {
+	fixture.srv.Close()
+	createface.CloseAll()
+	fixture.rib.Close()
+	return fixture.fib.Close()
+}
+
+// Collect Data replies from the management face.
+func (fixture *serverFixture) rxCb(burst iface.RxBurst) {
+	for _, interest := range burst.ListInterests() {
+		ndntestutil.ClosePacket(interest)
+	}
+	for _, data := range burst.ListData() {
+		select {
+		case fixture.replies <- ndn.TlvBytes(data.GetPacket().AsDpdkPacket().ReadAll()):
+		default:
+		}
+		ndntestutil.ClosePacket(data)
+	}
+	for _, nack := range burst.ListNacks() {
+		ndntestutil.ClosePacket(nack)
+	}
+}
+
+// Send an Interest to the management face, and wait for the Data reply.
+func (fixture *serverFixture) Request(wire ndn.TlvBytes) (name *ndn.Name, finalBlockId ndn.TlvBytes, content ndn.TlvBytes) {
+	_, require := makeAR(fixture.t)
+	fixture.srv.enqueue(ndntestutil.MakeInterest([]byte(wire)))
+	select {
+	case reply := <-fixture.replies:
+		name, finalBlockId, content, e := decodeData(reply)
+		require.NoError(e)
+		return name, finalBlockId, content
+	case <-time.After(time.Second):
+		require.FailNow("no reply")
+	}
+	return nil, nil, nil
+}
+
+// Send a command Interest, and decode the ControlResponse.
+func (fixture *serverFixture) Command(wire ndn.TlvBytes) (code int, body *ControlParameters) {
+	_, require := makeAR(fixture.t)
+	_, _, content := fixture.Request(wire)
+	code, _, body, e := decodeControlResponse(content)
+	require.NoError(e)
+	return code, body
+}
+
+// Send a signed command Interest.
+func (fixture *serverFixture) SignedCommand(verb string, cp ControlParameters) (code int, body *ControlParameters) {
+	return fixture.Command(fixture.signer.makeCommand(verb, cp))
+}
+
+func TestFacesCreate(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := newServerFixture(t)
+	defer fixture.Close()
+
+	cp := ControlParameters{Uri: "udp4://127.0.0.1:7001"}
+	countFaces := func() (n int) {
+		for it := iface.IterFaces(); it.Valid(); it.Next() {
+			n++
+		}
+		return n
+	}
+	nFaces := countFaces()
+
+	code, _ := fixture.Command(makeUnsignedCommand("faces/create", cp))
+	assert.Equal(status_Forbidden, code)
+	code, _ = fixture.Command(tamper(fixture.signer.makeCommand("faces/create", cp), cp))
+	assert.Equal(status_Forbidden, code)
+	assert.Equal(nFaces, countFaces())
+
+	code, body := fixture.SignedCommand("faces/create", cp)
+	require.Equal(status_OK, code)
+	require.NotNil(body)
+	faceId := body.FaceId
+	assert.NotNil(iface.Get(faceId))
+	assert.Equal("udp4://127.0.0.1:7001", body.Uri)
+	if assert.NotNil(body.FacePersistency) {
+		assert.Equal(uint64(facePersistency_Persistent), *body.FacePersistency)
+	}
+
+	fixture.signer.v03 = true
+	code, body = fixture.SignedCommand("faces/create", cp)
+	assert.Equal(status_Conflict, code)
+	if assert.NotNil(body) {
+		assert.Equal(faceId, body.FaceId)
+	}
+
+	code, _ = fixture.SignedCommand("faces/create", ControlParameters{})
+	assert.Equal(status_BadRequest, code)
+	code, _ = fixture.SignedCommand("faces/create", ControlParameters{Uri: "ether://[01:00:5e:00:17:aa]"})
+	assert.Equal(status_Unsupported, code)
+
+	code, _ = fixture.SignedCommand("faces/destroy", ControlParameters{FaceId: faceId})
+	assert.Equal(status_OK, code)
+	assert.Nil(iface.Get(faceId))
+}
+
+func TestRibRegister(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := newServerFixture(t)
+	defer fixture.Close()
+
+	face, e := createface.Create(mockface.NewLocator())
+	require.NoError(e)
+	faceId := face.GetFaceId()
+	nameA := ndn.MustParseName("/A")
+	fibNexthops := func() []iface.FaceId {
+		if entry := fixture.fib.Fib.Find(nameA); entry != nil {
+			return entry.GetNexthops()
+		}
+		return nil
+	}
+
+	code, _ := fixture.Command(makeUnsignedCommand("rib/register", ControlParameters{Name: nameA, FaceId: faceId}))
+	assert.Equal(status_Forbidden, code)
+	assert.Nil(fibNexthops())
+
+	registerA := fixture.signer.makeCommand("rib/register", ControlParameters{Name: nameA, FaceId: faceId, Cost: newNni(5)})
+	code, body := fixture.Command(registerA)
+	require.Equal(status_OK, code)
+	require.NotNil(body)
+	if assert.NotNil(body.Origin) && assert.NotNil(body.Cost) && assert.NotNil(body.Flags) {
+		assert.Equal(uint64(0), *body.Origin)
+		assert.Equal(uint64(5), *body.Cost)
+		assert.Equal(uint64(rib.RouteFlag_ChildInherit), *body.Flags)
+	}
+	assert.Equal([]iface.FaceId{faceId}, fibNexthops())
+	if entry, ok := fixture.rib.Find(nameA); assert.True(ok) {
+		assert.Len(entry.Routes, 1)
+	}
+
+	// replayed command is rejected
+	code, _ = fixture.Command(registerA)
+	assert.Equal(status_Forbidden, code)
+
+	code, _ = fixture.SignedCommand("rib/register", ControlParameters{FaceId: faceId})
+	assert.Equal(status_BadRequest, code)
+	code, _ = fixture.SignedCommand("rib/register", ControlParameters{Name: nameA})
+	assert.Equal(status_BadRequest, code)
+	code, _ = fixture.SignedCommand("rib/register", ControlParameters{Name: nameA, FaceId: 0xFFFE})
+	assert.Equal(status_NotFound, code)
+
+	code, _ = fixture.SignedCommand("rib/unregister", ControlParameters{Name: nameA, FaceId: faceId})
+	assert.Equal(status_OK, code)
+	assert.Nil(fibNexthops())
+}
+
+func TestStrategyChoiceSet(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := newServerFixture(t)
+	defer fixture.Close()
+
+	face, e := createface.Create(mockface.NewLocator())
+	require.NoError(e)
+	nameA := ndn.MustParseName("/A")
+	bestRoute := ndn.MustParseName("/localhost/nfd/strategy/best-route/%FD%01")
+	getStrategy := func() string {
+		if entry := fixture.fib.Fib.Find(nameA); entry != nil {
+			return entry.GetStrategy().GetName()
+		}
+		return ""
+	}
+
+	code, _ := fixture.SignedCommand("strategy-choice/set", ControlParameters{Name: nameA, Strategy: bestRoute})
+	assert.Equal(status_NotFound, code) // FIB entry does not exist
+
+	code, _ = fixture.SignedCommand("rib/register", ControlParameters{Name: nameA, FaceId: face.GetFaceId()})
+	require.Equal(status_OK, code)
+	assert.Equal("multicast", getStrategy())
+
+	code, _ = fixture.Command(makeUnsignedCommand("strategy-choice/set", ControlParameters{Name: nameA, Strategy: bestRoute}))
+	assert.Equal(status_Forbidden, code)
+	assert.Equal("multicast", getStrategy())
+
+	fixture.signer.v03 = true
+	code, body := fixture.SignedCommand("strategy-choice/set", ControlParameters{Name: nameA, Strategy: bestRoute})
+	assert.Equal(status_OK, code)
+	if assert.NotNil(body) && assert.NotNil(body.Strategy) {
+		assert.True(bestRoute.Equal(body.Strategy))
+	}
+	assert.Equal("best-route", getStrategy())
+
+	code, _ = fixture.SignedCommand("strategy-choice/set", ControlParameters{Name: nameA,
+		Strategy: ndn.MustParseName("/localhost/nfd/strategy/no-such-strategy")})
+	assert.Equal(status_NotFound, code)
+	code, _ = fixture.SignedCommand("strategy-choice/set", ControlParameters{Name: nameA})
+	assert.Equal(status_BadRequest, code)
+	assert.Equal("best-route", getStrategy())
+}
+
+func TestFacesList(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := newServerFixture(t)
+	defer fixture.Close()
+
+	// status dataset does not need signature
+	datasetName := appendName(Prefix, makeGenericComp(ndn.TlvBytes("faces")), makeGenericComp(ndn.TlvBytes("list")))
+	name, finalBlockId, content := fixture.Request(ndn.EncodeTlv(ndn.TT_Interest, datasetName.Encode(),
+		ndn.EncodeTlv(ndn.TT_Nonce, ndn.TlvBytes{0xA0, 0xA1, 0xA2, 0xA3})))
+	require.Equal(datasetName.Len()+2, name.Len())
+	assert.Equal(ndn.TT_VersionNameComponent, name.GetComp(datasetName.Len()).GetType())
+	assert.Equal(ndn.TlvBytes(name.GetComp(datasetName.Len()+1)), finalBlockId)
+
+	found := false
+	require.NoError(decodeTlvs(content, func(tt ndn.TlvType, value ndn.TlvBytes) error {
+		assert.Equal(tt_FaceStatus, tt)
+		return decodeTlvs(value, func(tt ndn.TlvType, value ndn.TlvBytes) error {
+			if tt == tt_FaceId {
+				faceId, e := decodeNni(value)
+				found = found || iface.FaceId(faceId) == fixture.srv.GetFaceId()
+				return e
+			}
+			return nil
+		})
+	}))
+	assert.True(found)
+
+	// retrieve the same segment by name
+	name2, _, content2 := fixture.Request(ndn.EncodeTlv(ndn.TT_Interest, name.Encode(),
+		ndn.EncodeTlv(ndn.TT_Nonce, ndn.TlvBytes{0xB0, 0xB1, 0xB2, 0xB3})))
+	assert.True(name.Equal(name2))
+	assert.True(bytes.Equal(content, content2))
+}
@@ -0,0 +1,24 @@
+package nfdmgmt
+
+import (
+	"os"
+	"testing"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/ifacetestfixture"
+)
+
+var (
+	theRxMp     dpdk.PktmbufPool
+	theMempools iface.Mempools
+)
+
+func TestMain(m *testing.M) {
+	theRxMp, theMempools = ifacetestfixture.MakeMempools()
+
+	os.Exit(m.Run())
+}
+
+var makeAR = dpdktestenv.MakeAR
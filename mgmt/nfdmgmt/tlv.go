@@ -0,0 +1,107 @@
+package nfdmgmt
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"ndn-dpdk/ndn"
+)
+
+// TLV-TYPE numbers of NFD management protocol.
+const (
+	tt_ControlParameters ndn.TlvType = 0x68
+	tt_FaceId            ndn.TlvType = 0x69
+	tt_Cost              ndn.TlvType = 0x6A
+	tt_Strategy          ndn.TlvType = 0x6B
+	tt_Flags             ndn.TlvType = 0x6C
+	tt_ExpirationPeriod  ndn.TlvType = 0x6D
+	tt_Origin            ndn.TlvType = 0x6F
+	tt_Uri               ndn.TlvType = 0x72
+	tt_LocalUri          ndn.TlvType = 0x81
+	tt_FacePersistency   ndn.TlvType = 0x85
+
+	tt_ControlResponse ndn.TlvType = 0x65
+	tt_StatusCode      ndn.TlvType = 0x66
+	tt_StatusText      ndn.TlvType = 0x67
+
+	tt_FaceStatus    ndn.TlvType = 0x80
+	tt_FaceScope     ndn.TlvType = 0x84
+	tt_LinkType      ndn.TlvType = 0x86
+	tt_NInInterests  ndn.TlvType = 0x90
+	tt_NInData       ndn.TlvType = 0x91
+	tt_NOutInterests ndn.TlvType = 0x92
+	tt_NOutData      ndn.TlvType = 0x93
+	tt_NInBytes      ndn.TlvType = 0x94
+	tt_NOutBytes     ndn.TlvType = 0x95
+	tt_NInNacks      ndn.TlvType = 0x97
+	tt_NOutNacks     ndn.TlvType = 0x98
+
+	tt_FibEntry      ndn.TlvType = 0x80
+	tt_NextHopRecord ndn.TlvType = 0x81
+)
+
+// Enum values of NFD management protocol.
+const (
+	faceScope_NonLocal         = 0
+	faceScope_Local            = 1
+	facePersistency_Persistent = 0
+	linkType_PointToPoint      = 0
+	linkType_MultiAccess       = 1
+)
+
+// Encode a NonNegativeInteger TLV-VALUE.
+func encodeNni(n uint64) ndn.TlvBytes {
+	switch {
+	case n <= 0xFF:
+		return ndn.TlvBytes{byte(n)}
+	case n <= 0xFFFF:
+		b := make(ndn.TlvBytes, 2)
+		binary.BigEndian.PutUint16(b, uint16(n))
+		return b
+	case n <= 0xFFFFFFFF:
+		b := make(ndn.TlvBytes, 4)
+		binary.BigEndian.PutUint32(b, uint32(n))
+		return b
+	}
+	b := make(ndn.TlvBytes, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return b
+}
+
+// Decode a NonNegativeInteger TLV-VALUE.
+func decodeNni(b ndn.TlvBytes) (n uint64, e error) {
+	switch len(b) {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	case 8:
+		return binary.BigEndian.Uint64(b), nil
+	}
+	return 0, errors.New("bad NonNegativeInteger")
+}
+
+// Encode a TLV element whose TLV-VALUE is a NonNegativeInteger.
+func encodeNniTlv(tt ndn.TlvType, n uint64) ndn.TlvBytes {
+	return ndn.EncodeTlv(tt, encodeNni(n))
+}
+
+// Decode a sequence of TLV elements.
+// cb is invoked with TLV-TYPE and TLV-VALUE of each element.
+func decodeTlvs(b ndn.TlvBytes, cb func(tt ndn.TlvType, value ndn.TlvBytes) error) error {
+	for len(b) > 0 {
+		element, tail := b.ExtractElement()
+		if element == nil {
+			return errors.New("bad TLV")
+		}
+		tt, afterT := element.DecodeVarNum()
+		_, value := afterT.DecodeVarNum()
+		if e := cb(ndn.TlvType(tt), value); e != nil {
+			return e
+		}
+		b = tail
+	}
+	return nil
+}
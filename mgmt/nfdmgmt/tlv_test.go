@@ -0,0 +1,75 @@
+package nfdmgmt
+
+import (
+	"testing"
+
+	"ndn-dpdk/dpdk/dpdktestenv"
+	"ndn-dpdk/ndn"
+)
+
+func TestNni(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	tests := []struct {
+		n    uint64
+		wire string
+	}{
+		{0x00, "00"},
+		{0xFF, "FF"},
+		{0x0100, "0100"},
+		{0xFFFF, "FFFF"},
+		{0x00010000, "00010000"},
+		{0xFFFFFFFF, "FFFFFFFF"},
+		{0x0000000100000000, "0000000100000000"},
+		{0xFFFFFFFFFFFFFFFF, "FFFFFFFFFFFFFFFF"},
+	}
+	for _, tt := range tests {
+		wire := ndn.TlvBytes(dpdktestenv.BytesFromHex(tt.wire))
+		assert.Equal(wire, encodeNni(tt.n), tt.wire)
+		n, e := decodeNni(wire)
+		if assert.NoError(e, tt.wire) {
+			assert.Equal(tt.n, n, tt.wire)
+		}
+	}
+
+	for _, wire := range []string{"", "000000", "0000000000", "000000000000000000"} {
+		_, e := decodeNni(ndn.TlvBytes(dpdktestenv.BytesFromHex(wire)))
+		assert.Error(e, wire)
+	}
+
+	assert.Equal(ndn.TlvBytes(dpdktestenv.BytesFromHex("6902 0100")), encodeNniTlv(tt_FaceId, 0x0100))
+}
+
+func TestDecodeTlvs(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	type element struct {
+		tt    ndn.TlvType
+		value string
+	}
+	tests := []struct {
+		wire     string
+		ok       bool
+		elements []element
+	}{
+		{"", true, nil},
+		{"6901 05", true, []element{{tt_FaceId, "05"}}},
+		{"6901 05 7200 8103 414243", true, []element{{tt_FaceId, "05"}, {tt_Uri, ""}, {tt_LocalUri, "414243"}}},
+		{"6902 05", false, nil},                           // truncated TLV-VALUE
+		{"6901 05 72", false, nil},                        // truncated TLV-LENGTH
+		{"FD0320 01 05", true, []element{{0x0320, "05"}}}, // 3-octet TLV-TYPE
+	}
+	for _, tt := range tests {
+		var elements []element
+		e := decodeTlvs(ndn.TlvBytes(dpdktestenv.BytesFromHex(tt.wire)), func(typ ndn.TlvType, value ndn.TlvBytes) error {
+			elements = append(elements, element{typ, value.String()})
+			return nil
+		})
+		if tt.ok {
+			assert.NoError(e, tt.wire)
+			assert.Equal(tt.elements, elements, tt.wire)
+		} else {
+			assert.Error(e, tt.wire)
+		}
+	}
+}
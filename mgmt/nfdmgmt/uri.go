@@ -0,0 +1,69 @@
+package nfdmgmt
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/ethface"
+	"ndn-dpdk/iface/socketface"
+)
+
+// Convert NFD FaceUri to face locator.
+// Only socket faces can be created from FaceUri.
+func parseFaceUri(remote, local string) (loc iface.Locator, e error) {
+	u, e := url.Parse(remote)
+	if e != nil {
+		return nil, newControlError(status_BadRequest, "bad Uri: %v", e)
+	}
+
+	var sloc socketface.Locator
+	sloc.Scheme = u.Scheme
+	switch u.Scheme {
+	case "udp4", "udp6", "tcp4", "tcp6":
+		sloc.Remote = u.Host
+		if local != "" {
+			lu, e := url.Parse(local)
+			if e != nil || lu.Scheme != u.Scheme {
+				return nil, newControlError(status_BadRequest, "bad LocalUri")
+			}
+			sloc.Local = lu.Host
+		}
+	case "unix":
+		sloc.Remote = u.Path
+	default:
+		return nil, newControlError(status_Unsupported, "unsupported FaceUri scheme %s", u.Scheme)
+	}
+
+	if e = sloc.Validate(); e != nil {
+		return nil, newControlError(status_BadRequest, "bad Uri: %v", e)
+	}
+	return sloc, nil
+}
+
+// Convert face locator to NFD FaceUri and LocalUri.
+func makeFaceUri(loc iface.Locator) (remote, local string) {
+	switch l := loc.(type) {
+	case socketface.Locator:
+		if l.Scheme == "unix" || l.Scheme == "unixgram" {
+			return "unix://" + l.Remote, "fd://0"
+		}
+		local = "fd://0"
+		if l.Local != "" {
+			local = fmt.Sprintf("%s://%s", l.Scheme, l.Local)
+		}
+		return fmt.Sprintf("%s://%s", l.Scheme, l.Remote), local
+	case ethface.Locator:
+		return fmt.Sprintf("ether://[%s]", l.Remote), fmt.Sprintf("dev://%s", l.Port)
+	case ethface.UdpLocator:
+		scheme := "udp6"
+		if l.RemoteIP.To4() != nil {
+			scheme = "udp4"
+		}
+		return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(l.RemoteIP.String(), strconv.Itoa(l.RemoteUDP))),
+			fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(l.LocalIP.String(), strconv.Itoa(l.LocalUDP)))
+	}
+	return fmt.Sprintf("%s://", loc.GetScheme()), fmt.Sprintf("%s://", loc.GetScheme())
+}
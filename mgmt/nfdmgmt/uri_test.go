@@ -0,0 +1,94 @@
+package nfdmgmt
+
+import (
+	"net"
+	"testing"
+
+	"ndn-dpdk/dpdk"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/iface/ethface"
+	"ndn-dpdk/iface/mockface"
+	"ndn-dpdk/iface/socketface"
+)
+
+func TestParseFaceUri(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	makeSloc := func(scheme, local, remote string) (loc socketface.Locator) {
+		loc.Scheme = scheme
+		loc.Local = local
+		loc.Remote = remote
+		return loc
+	}
+
+	tests := []struct {
+		remote string
+		local  string
+		loc    iface.Locator
+		code   int
+	}{
+		{"udp4://192.0.2.1:6363", "", makeSloc("udp4", "", "192.0.2.1:6363"), 0},
+		{"udp4://192.0.2.1:6363", "udp4://192.0.2.2:7000", makeSloc("udp4", "192.0.2.2:7000", "192.0.2.1:6363"), 0},
+		{"udp6://[2001:db8::1]:6363", "", makeSloc("udp6", "", "[2001:db8::1]:6363"), 0},
+		{"tcp4://192.0.2.1:6363", "", makeSloc("tcp4", "", "192.0.2.1:6363"), 0},
+		{"tcp6://[2001:db8::1]:6363", "", makeSloc("tcp6", "", "[2001:db8::1]:6363"), 0},
+		{"unix:///run/nfd.sock", "", makeSloc("unix", "", "/run/nfd.sock"), 0},
+		{"udp4://192.0.2.1:6363", "tcp4://192.0.2.2:7000", nil, status_BadRequest},
+		{"udp4://192.0.2.1:6363", "%%", nil, status_BadRequest},
+		{"udp4://not-an-address", "", nil, status_BadRequest},
+		{"%%", "", nil, status_BadRequest},
+		{"ether://[01:00:5e:00:17:aa]", "", nil, status_Unsupported},
+		{"fd://3", "", nil, status_Unsupported},
+	}
+	for _, tt := range tests {
+		loc, e := parseFaceUri(tt.remote, tt.local)
+		if tt.code == 0 {
+			if assert.NoError(e, tt.remote) {
+				assert.Equal(tt.loc, loc, tt.remote)
+			}
+		} else if assert.Error(e, tt.remote) {
+			if ce, ok := e.(controlError); assert.True(ok, tt.remote) {
+				assert.Equal(tt.code, ce.Code, tt.remote)
+			}
+		}
+	}
+}
+
+func TestMakeFaceUri(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	var sloc socketface.Locator
+	sloc.Scheme = "udp4"
+	sloc.Remote = "192.0.2.1:6363"
+	slocLocal := sloc
+	slocLocal.Local = "192.0.2.2:7000"
+	var slocUnix socketface.Locator
+	slocUnix.Scheme = "unix"
+	slocUnix.Remote = "/run/nfd.sock"
+
+	var eloc ethface.Locator
+	eloc.Scheme = "ether"
+	eloc.Port = "net_af_packet0"
+	eloc.Remote, _ = dpdk.ParseEtherAddr("02:00:00:00:00:01")
+	uloc := ethface.NewUdpLocator(eloc, net.ParseIP("192.0.2.2"), net.ParseIP("192.0.2.1"))
+	uloc.LocalUDP = 6363
+	uloc.RemoteUDP = 7000
+
+	tests := []struct {
+		loc    iface.Locator
+		remote string
+		local  string
+	}{
+		{sloc, "udp4://192.0.2.1:6363", "fd://0"},
+		{slocLocal, "udp4://192.0.2.1:6363", "udp4://192.0.2.2:7000"},
+		{slocUnix, "unix:///run/nfd.sock", "fd://0"},
+		{eloc, "ether://[02:00:00:00:00:01]", "dev://net_af_packet0"},
+		{uloc, "udp4://192.0.2.1:7000", "udp4://192.0.2.2:6363"},
+		{mockface.NewLocator(), "mock://", "mock://"},
+	}
+	for _, tt := range tests {
+		remote, local := makeFaceUri(tt.loc)
+		assert.Equal(tt.remote, remote, tt.remote)
+		assert.Equal(tt.local, local, tt.remote)
+	}
+}
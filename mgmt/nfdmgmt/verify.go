@@ -0,0 +1,292 @@
+package nfdmgmt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"ndn-dpdk/app/fwdp"
+	"ndn-dpdk/ndn"
+)
+
+// Default tolerance of command Interest timestamp.
+const defaultTimestampGrace = 60 * time.Second
+
+// SignatureType values accepted on command Interests.
+const (
+	sigType_Sha256WithEcdsa = 3
+	sigType_HmacWithSha256  = 4
+)
+
+// A trusted key for command Interests.
+type commandKey struct {
+	keyName *ndn.Name
+	sigType uint64
+	ecdsa   *ecdsa.PublicKey
+	hmac    []byte
+}
+
+func newCommandKey(ta fwdp.TrustAnchor) (key commandKey, e error) {
+	if ta.KeyName == nil || ta.KeyName.Len() == 0 {
+		return key, errors.New("KeyName is missing")
+	}
+	key.keyName = ta.KeyName
+
+	switch ta.Type {
+	case fwdp.TrustAnchor_Ecdsa:
+		pub, e := x509.ParsePKIXPublicKey(ta.Key)
+		if e != nil {
+			return key, e
+		}
+		ecPub, ok := pub.(*ecdsa.PublicKey)
+		if !ok || ecPub.Curve != elliptic.P256() {
+			return key, errors.New("key is not ECDSA P-256")
+		}
+		key.sigType, key.ecdsa = sigType_Sha256WithEcdsa, ecPub
+	case fwdp.TrustAnchor_Hmac:
+		if len(ta.Key) == 0 {
+			return key, errors.New("HMAC key is empty")
+		}
+		key.sigType, key.hmac = sigType_HmacWithSha256, ta.Key
+	default:
+		return key, fmt.Errorf("unknown trust anchor type %s", ta.Type)
+	}
+	return key, nil
+}
+
+func (key commandKey) verify(cmd signedCommand) bool {
+	switch key.sigType {
+	case sigType_Sha256WithEcdsa:
+		var sig struct{ R, S *big.Int }
+		if rest, e := asn1.Unmarshal(cmd.sigValue, &sig); e != nil || len(rest) > 0 {
+			return false
+		}
+		digest := sha256.Sum256(cmd.signed)
+		return ecdsa.Verify(key.ecdsa, digest[:], sig.R, sig.S)
+	case sigType_HmacWithSha256:
+		mac := hmac.New(sha256.New, key.hmac)
+		mac.Write(cmd.signed)
+		return hmac.Equal(mac.Sum(nil), cmd.sigValue)
+	}
+	return false
+}
+
+// Command Interest validator.
+// It accepts a command Interest if it is signed by a trusted key, and its timestamp is
+// within the grace period and greater than the last accepted timestamp of the same key.
+type commandValidator struct {
+	keys           []commandKey
+	grace          time.Duration
+	lastTimestamps map[string]uint64 // KeyLocator name => last accepted timestamp
+}
+
+func newCommandValidator(anchors []fwdp.TrustAnchor, grace time.Duration) (v *commandValidator, e error) {
+	v = &commandValidator{
+		grace:          grace,
+		lastTimestamps: make(map[string]uint64),
+	}
+	for i, ta := range anchors {
+		key, e := newCommandKey(ta)
+		if e != nil {
+			return nil, fmt.Errorf("TrustAnchors[%d]: %v", i, e)
+		}
+		v.keys = append(v.keys, key)
+	}
+	return v, nil
+}
+
+// Validate a command Interest.
+// Returns a controlError with status 403 if the command should be rejected.
+func (v *commandValidator) validate(interest *ndn.Interest) error {
+	cmd, e := parseSignedCommand(ndn.TlvBytes(interest.GetPacket().AsDpdkPacket().ReadAll()))
+	if e != nil {
+		return newControlError(status_Forbidden, "%v", e)
+	}
+
+	var key *commandKey
+	for i, k := range v.keys {
+		if k.sigType == cmd.sigType && cmd.keyName.Len() >= k.keyName.Len() &&
+			cmd.keyName.GetPrefix(k.keyName.Len()).Equal(k.keyName) {
+			key = &v.keys[i]
+			break
+		}
+	}
+	if key == nil {
+		return newControlError(status_Forbidden, "untrusted key %s", cmd.keyName)
+	}
+	if !key.verify(cmd) {
+		return newControlError(status_Forbidden, "bad signature")
+	}
+
+	now := time.Now()
+	ts := time.Unix(0, int64(cmd.timestamp)*int64(time.Millisecond))
+	if ts.Before(now.Add(-v.grace)) || ts.After(now.Add(v.grace)) {
+		return newControlError(status_Forbidden, "timestamp out of grace period")
+	}
+	keyUri := cmd.keyName.String()
+	if cmd.timestamp <= v.lastTimestamps[keyUri] {
+		return newControlError(status_Forbidden, "timestamp is not increasing")
+	}
+	v.lastTimestamps[keyUri] = cmd.timestamp
+	return nil
+}
+
+// A parsed signed command Interest.
+type signedCommand struct {
+	signed    []byte // signed portion
+	sigType   uint64
+	keyName   *ndn.Name
+	sigValue  []byte
+	timestamp uint64 // milliseconds since Unix epoch
+}
+
+// Parse a signed command Interest.
+// Both signed Interest formats are accepted:
+// in NDN packet format v0.3, the signature is in InterestSignatureInfo and InterestSignatureValue,
+// and the timestamp is SignatureTime;
+// in the older format, the last four name components are timestamp, nonce, SignatureInfo, and SignatureValue.
+func parseSignedCommand(wire ndn.TlvBytes) (cmd signedCommand, e error) {
+	element, _ := wire.ExtractElement()
+	if element == nil {
+		return cmd, errors.New("bad Interest")
+	}
+	tt, afterT := element.DecodeVarNum()
+	if ndn.TlvType(tt) != ndn.TT_Interest {
+		return cmd, errors.New("bad Interest")
+	}
+	_, value := afterT.DecodeVarNum()
+
+	var comps []ndn.TlvBytes
+	var params, sigInfo, sigValue ndn.TlvBytes
+	for len(value) > 0 {
+		element, tail := value.ExtractElement()
+		if element == nil {
+			return cmd, errors.New("bad Interest")
+		}
+		tt, afterT := element.DecodeVarNum()
+		_, v := afterT.DecodeVarNum()
+		switch ndn.TlvType(tt) {
+		case ndn.TT_Name:
+			if comps, e = splitTlvs(v); e != nil {
+				return cmd, errors.New("bad Name")
+			}
+		case ndn.TT_ApplicationParameters:
+			params = element
+		case ndn.TT_InterestSignatureInfo:
+			sigInfo = element
+		case ndn.TT_InterestSignatureValue:
+			sigValue = v
+		}
+		value = tail
+	}
+
+	if sigInfo != nil {
+		if params == nil || sigValue == nil {
+			return cmd, errors.New("bad signed Interest")
+		}
+		for _, comp := range comps {
+			if tt, _ := comp.DecodeVarNum(); ndn.TlvType(tt) != ndn.TT_ParametersSha256DigestComponent {
+				cmd.signed = append(cmd.signed, comp...)
+			}
+		}
+		cmd.signed = append(append(cmd.signed, params...), sigInfo...)
+		cmd.sigValue = []byte(sigValue)
+		return cmd, cmd.parseSigInfo(sigInfo, true)
+	}
+
+	nComps := len(comps)
+	if nComps < 4 {
+		return cmd, errors.New("command Interest is not signed")
+	}
+	for _, comp := range comps[:nComps-1] {
+		cmd.signed = append(cmd.signed, comp...)
+	}
+	if cmd.timestamp, e = decodeNni(componentValue(comps[nComps-4])); e != nil {
+		return cmd, errors.New("bad timestamp")
+	}
+	if sigValue, e = expectTlv(componentValue(comps[nComps-1]), ndn.TT_SignatureValue); e != nil {
+		return cmd, e
+	}
+	cmd.sigValue = []byte(sigValue)
+	return cmd, cmd.parseSigInfo(componentValue(comps[nComps-2]), false)
+}
+
+// Parse SignatureInfo or InterestSignatureInfo element.
+func (cmd *signedCommand) parseSigInfo(element ndn.TlvBytes, isV03 bool) error {
+	expectType := ndn.TT_SignatureInfo
+	if isV03 {
+		expectType = ndn.TT_InterestSignatureInfo
+	}
+	value, e := expectTlv(element, expectType)
+	if e != nil {
+		return e
+	}
+
+	hasSigType, hasTime := false, false
+	e = decodeTlvs(value, func(tt ndn.TlvType, value ndn.TlvBytes) (e error) {
+		switch tt {
+		case ndn.TT_SignatureType:
+			cmd.sigType, e = decodeNni(value)
+			hasSigType = true
+		case ndn.TT_KeyLocator:
+			e = decodeTlvs(value, func(tt ndn.TlvType, value ndn.TlvBytes) (e error) {
+				if tt == ndn.TT_Name {
+					cmd.keyName, e = ndn.NewName(value)
+				}
+				return e
+			})
+		case ndn.TT_SignatureTime:
+			cmd.timestamp, e = decodeNni(value)
+			hasTime = true
+		}
+		return e
+	})
+	switch {
+	case e != nil || !hasSigType:
+		return errors.New("bad SignatureInfo")
+	case cmd.keyName == nil:
+		return errors.New("KeyLocator is missing")
+	case isV03 && !hasTime:
+		return errors.New("SignatureTime is missing")
+	}
+	return nil
+}
+
+// Split a sequence of TLV elements.
+func splitTlvs(b ndn.TlvBytes) (elements []ndn.TlvBytes, e error) {
+	for len(b) > 0 {
+		element, tail := b.ExtractElement()
+		if element == nil {
+			return nil, errors.New("bad TLV")
+		}
+		elements = append(elements, element)
+		b = tail
+	}
+	return elements, nil
+}
+
+// Get TLV-VALUE of an element.
+func componentValue(element ndn.TlvBytes) ndn.TlvBytes {
+	_, afterT := element.DecodeVarNum()
+	_, value := afterT.DecodeVarNum()
+	return value
+}
+
+// Get TLV-VALUE of an element that must have a certain TLV-TYPE and no trailing octets.
+func expectTlv(b ndn.TlvBytes, expectType ndn.TlvType) (value ndn.TlvBytes, e error) {
+	element, tail := b.ExtractElement()
+	if element == nil || len(tail) > 0 {
+		return nil, fmt.Errorf("bad %v", expectType)
+	}
+	if tt, _ := element.DecodeVarNum(); ndn.TlvType(tt) != expectType {
+		return nil, fmt.Errorf("bad %v", expectType)
+	}
+	return componentValue(element), nil
+}
@@ -0,0 +1,216 @@
+package nfdmgmt
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"ndn-dpdk/app/fwdp"
+	"ndn-dpdk/ndn"
+	"ndn-dpdk/ndn/ndntestutil"
+)
+
+// Command Interest signer.
+type testSigner struct {
+	keyName   *ndn.Name
+	hmacKey   []byte            // HMAC key, or nil to use ECDSA
+	ecdsaKey  *ecdsa.PrivateKey // ECDSA key
+	v03       bool              // whether to use NDN packet format v0.3 signed Interest
+	timestamp uint64            // last used timestamp
+	offset    time.Duration     // clock offset
+}
+
+func newHmacSigner(keyName string) *testSigner {
+	return &testSigner{
+		keyName: ndn.MustParseName(keyName),
+		hmacKey: []byte(keyName),
+	}
+}
+
+func newEcdsaSigner(keyName string) *testSigner {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		panic(e)
+	}
+	return &testSigner{
+		keyName:  ndn.MustParseName(keyName),
+		ecdsaKey: key,
+	}
+}
+
+// Get trust anchor of this signer.
+func (signer *testSigner) TrustAnchor() (ta fwdp.TrustAnchor) {
+	ta.KeyName = signer.keyName
+	if signer.hmacKey != nil {
+		ta.Type = fwdp.TrustAnchor_Hmac
+		ta.Key = signer.hmacKey
+		return ta
+	}
+	ta.Type = fwdp.TrustAnchor_Ecdsa
+	ta.Key, _ = x509.MarshalPKIXPublicKey(&signer.ecdsaKey.PublicKey)
+	return ta
+}
+
+func (signer *testSigner) nextTimestamp() uint64 {
+	ts := uint64(time.Now().Add(signer.offset).UnixNano() / int64(time.Millisecond))
+	if ts <= signer.timestamp {
+		ts = signer.timestamp + 1
+	}
+	signer.timestamp = ts
+	return ts
+}
+
+func (signer *testSigner) makeSigInfo(tt ndn.TlvType, timestamp uint64) ndn.TlvBytes {
+	sigType := uint64(sigType_Sha256WithEcdsa)
+	if signer.hmacKey != nil {
+		sigType = sigType_HmacWithSha256
+	}
+	fields := []ndn.TlvBytes{
+		encodeNniTlv(ndn.TT_SignatureType, sigType),
+		ndn.EncodeTlv(ndn.TT_KeyLocator, signer.keyName.Encode()),
+	}
+	if tt == ndn.TT_InterestSignatureInfo {
+		fields = append(fields, encodeNniTlv(ndn.TT_SignatureTime, timestamp))
+	}
+	return ndn.EncodeTlv(tt, fields...)
+}
+
+func (signer *testSigner) sign(signed ndn.TlvBytes) []byte {
+	if signer.hmacKey != nil {
+		mac := hmac.New(sha256.New, signer.hmacKey)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+	digest := sha256.Sum256(signed)
+	r, s, e := ecdsa.Sign(rand.Reader, signer.ecdsaKey, digest[:])
+	if e != nil {
+		panic(e)
+	}
+	sig, _ := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	return sig
+}
+
+func makeGenericComp(value ndn.TlvBytes) ndn.TlvBytes {
+	return ndn.EncodeTlv(ndn.TT_GenericNameComponent, value)
+}
+
+// Make a command Interest name prefix, including ControlParameters.
+func makeCommandComps(verb string, cp ControlParameters) (comps ndn.TlvBytes) {
+	comps = Prefix.GetValue()
+	for _, s := range strings.Split(verb, "/") {
+		comps = comps.Join(makeGenericComp(ndn.TlvBytes(s)))
+	}
+	return comps.Join(makeGenericComp(cp.encode()))
+}
+
+// Make an unsigned command Interest.
+func makeUnsignedCommand(verb string, cp ControlParameters) ndn.TlvBytes {
+	return ndn.EncodeTlv(ndn.TT_Interest,
+		ndn.EncodeTlv(ndn.TT_Name, makeCommandComps(verb, cp)),
+		ndn.EncodeTlv(ndn.TT_Nonce, ndn.TlvBytes{0xA0, 0xA1, 0xA2, 0xA3}))
+}
+
+// Make a signed command Interest.
+func (signer *testSigner) makeCommand(verb string, cp ControlParameters) ndn.TlvBytes {
+	comps := makeCommandComps(verb, cp)
+	nonce := ndn.EncodeTlv(ndn.TT_Nonce, ndn.TlvBytes{0xA0, 0xA1, 0xA2, 0xA3})
+	ts := signer.nextTimestamp()
+
+	if signer.v03 {
+		params := ndn.EncodeTlv(ndn.TT_ApplicationParameters)
+		sigInfo := signer.makeSigInfo(ndn.TT_InterestSignatureInfo, ts)
+		sigValue := ndn.EncodeTlv(ndn.TT_InterestSignatureValue, signer.sign(comps.Join(params, sigInfo)))
+		paramsDigest := sha256.Sum256(params.Join(sigInfo, sigValue))
+		name := ndn.EncodeTlv(ndn.TT_Name, comps,
+			ndn.EncodeTlv(ndn.TT_ParametersSha256DigestComponent, paramsDigest[:]))
+		return ndn.EncodeTlv(ndn.TT_Interest, name, nonce, params, sigInfo, sigValue)
+	}
+
+	var cmdNonce [8]byte
+	rand.Read(cmdNonce[:])
+	comps = comps.Join(makeGenericComp(encodeNni(ts)), makeGenericComp(cmdNonce[:]),
+		makeGenericComp(signer.makeSigInfo(ndn.TT_SignatureInfo, ts)))
+	sigValue := makeGenericComp(ndn.EncodeTlv(ndn.TT_SignatureValue, signer.sign(comps)))
+	return ndn.EncodeTlv(ndn.TT_Interest, ndn.EncodeTlv(ndn.TT_Name, comps, sigValue), nonce)
+}
+
+// Modify the last octet of ControlParameters, which is within the signed portion.
+func tamper(wire ndn.TlvBytes, cp ControlParameters) ndn.TlvBytes {
+	cpWire := cp.encode()
+	tampered := append(ndn.TlvBytes{}, wire...)
+	tampered[bytes.Index(tampered, cpWire)+len(cpWire)-1] ^= 0x01
+	return tampered
+}
+
+func TestValidator(t *testing.T) {
+	assert, require := makeAR(t)
+
+	hmacSigner := newHmacSigner("/operator/KEY/hmac")
+	ecdsaSigner := newEcdsaSigner("/operator/KEY/ecdsa")
+	untrustedSigner := newHmacSigner("/attacker/KEY/hmac")
+	wrongKeySigner := newHmacSigner("/operator/KEY/hmac")
+	wrongKeySigner.hmacKey = []byte("wrong")
+
+	v, e := newCommandValidator([]fwdp.TrustAnchor{
+		{KeyName: ndn.MustParseName("/operator"), Type: fwdp.TrustAnchor_Hmac, Key: hmacSigner.hmacKey},
+		ecdsaSigner.TrustAnchor(),
+	}, 10*time.Second)
+	require.NoError(e)
+
+	_, e = newCommandValidator([]fwdp.TrustAnchor{{KeyName: ndn.MustParseName("/K"), Type: fwdp.TrustAnchor_Ecdsa,
+		Key: []byte{0x01}}}, time.Second)
+	assert.Error(e)
+	_, e = newCommandValidator([]fwdp.TrustAnchor{{Type: fwdp.TrustAnchor_Hmac, Key: []byte{0x01}}}, time.Second)
+	assert.Error(e)
+
+	check := func(wire ndn.TlvBytes, ok bool, msg string) {
+		interest := ndntestutil.MakeInterest([]byte(wire))
+		defer ndntestutil.ClosePacket(interest)
+		e := v.validate(interest)
+		if ok {
+			assert.NoError(e, msg)
+		} else if assert.Error(e, msg) {
+			if ce, ok := e.(controlError); assert.True(ok, msg) {
+				assert.Equal(status_Forbidden, ce.Code, msg)
+			}
+		}
+	}
+	cp := ControlParameters{FaceId: 300}
+
+	for _, v03 := range []bool{false, true} {
+		hmacSigner.v03, ecdsaSigner.v03, untrustedSigner.v03, wrongKeySigner.v03 = v03, v03, v03, v03
+		format := map[bool]string{false: "v0.2 ", true: "v0.3 "}[v03]
+
+		check(hmacSigner.makeCommand("faces/destroy", cp), true, format+"HMAC")
+		check(ecdsaSigner.makeCommand("faces/destroy", cp), true, format+"ECDSA")
+		check(tamper(hmacSigner.makeCommand("faces/destroy", cp), cp), false, format+"tampered HMAC")
+		check(tamper(ecdsaSigner.makeCommand("faces/destroy", cp), cp), false, format+"tampered ECDSA")
+		check(untrustedSigner.makeCommand("faces/destroy", cp), false, format+"untrusted key")
+		check(wrongKeySigner.makeCommand("faces/destroy", cp), false, format+"wrong HMAC key")
+
+		replayed := hmacSigner.makeCommand("faces/destroy", cp)
+		check(replayed, true, format+"before replay")
+		check(replayed, false, format+"replay")
+		accepted := hmacSigner.timestamp
+
+		hmacSigner.timestamp = 0
+		hmacSigner.offset = -time.Minute
+		check(hmacSigner.makeCommand("faces/destroy", cp), false, format+"old timestamp")
+		hmacSigner.offset = time.Minute
+		check(hmacSigner.makeCommand("faces/destroy", cp), false, format+"future timestamp")
+		hmacSigner.offset = 0
+		hmacSigner.timestamp = accepted
+		check(hmacSigner.makeCommand("faces/destroy", cp), true, format+"after rejected timestamps")
+	}
+
+	check(makeUnsignedCommand("faces/destroy", cp), false, "unsigned")
+}
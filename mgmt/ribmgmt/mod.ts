@@ -0,0 +1,63 @@
+import * as rib from "../../container/rib/mod";
+import { Counter } from "../../core/mod";
+import { Milliseconds } from "../../core/nnduration/mod";
+import * as iface from "../../iface/mod";
+import * as ndn from "../../ndn/mod";
+
+export interface RibInfo {
+  NEntries: Counter;
+}
+
+export interface NameArg {
+  Name: ndn.Name;
+}
+
+export interface RemoveArg extends NameArg {
+  FaceId: iface.FaceId;
+
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   * @default 0
+   */
+  Origin?: number;
+}
+
+export interface AddArg extends RemoveArg {
+  /**
+   * @TJS-type integer
+   * @minimum 0
+   * @default 0
+   */
+  Cost?: number;
+
+  /**
+   * @default 0
+   */
+  Flags?: rib.RouteFlags;
+
+  /**
+   * @default 0
+   */
+  ExpirationPeriod?: Milliseconds;
+}
+
+interface LookupReplyNo {
+  HasEntry: false;
+}
+
+interface LookupReplyYes {
+  HasEntry: true;
+  Routes: rib.Route[];
+  Nexthops: iface.FaceId[];
+}
+
+export type LookupReply = LookupReplyNo | LookupReplyYes;
+
+export interface RibMgmt {
+  Info: {args: {}; reply: RibInfo};
+  List: {args: {}; reply: rib.Entry[]};
+  Find: {args: NameArg; reply: LookupReply};
+  Add: {args: AddArg; reply: {}};
+  Remove: {args: RemoveArg; reply: {}};
+}
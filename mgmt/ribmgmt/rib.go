@@ -0,0 +1,91 @@
+package ribmgmt
+
+import (
+	"errors"
+	"time"
+
+	"ndn-dpdk/container/rib"
+	"ndn-dpdk/core/nnduration"
+	"ndn-dpdk/iface"
+	"ndn-dpdk/ndn"
+)
+
+type RibMgmt struct {
+	Rib *rib.Rib
+}
+
+func (mg RibMgmt) Info(args struct{}, reply *RibInfo) error {
+	reply.NEntries = mg.Rib.Len()
+	return nil
+}
+
+func (mg RibMgmt) List(args struct{}, reply *[]rib.Entry) error {
+	*reply = make([]rib.Entry, 0)
+	*reply = append(*reply, mg.Rib.List()...)
+	return nil
+}
+
+func (mg RibMgmt) Find(args NameArg, reply *LookupReply) error {
+	if args.Name == nil {
+		return errors.New("Name is required")
+	}
+	if entry, ok := mg.Rib.Find(args.Name); ok {
+		reply.HasEntry = true
+		reply.Routes = entry.Routes
+		reply.Nexthops = mg.Rib.GetNexthops(args.Name)
+	}
+	return nil
+}
+
+func (mg RibMgmt) Add(args AddArg, reply *struct{}) error {
+	if args.Name == nil {
+		return errors.New("Name is required")
+	}
+	if args.Origin < 0 || args.Cost < 0 {
+		return errors.New("Origin and Cost must not be negative")
+	}
+	route := rib.Route{
+		FaceId: args.FaceId,
+		Origin: args.Origin,
+		Cost:   args.Cost,
+		Flags:  args.Flags,
+	}
+	if args.ExpirationPeriod > 0 {
+		route.Expiration = time.Now().Add(args.ExpirationPeriod.Duration())
+	}
+	return mg.Rib.Add(args.Name, route)
+}
+
+func (mg RibMgmt) Remove(args RemoveArg, reply *struct{}) error {
+	if args.Name == nil {
+		return errors.New("Name is required")
+	}
+	return mg.Rib.Remove(args.Name, args.FaceId, args.Origin)
+}
+
+type RibInfo struct {
+	NEntries int // Number of entries.
+}
+
+type NameArg struct {
+	Name *ndn.Name
+}
+
+type RemoveArg struct {
+	Name   *ndn.Name
+	FaceId iface.FaceId
+	Origin int
+}
+
+type AddArg struct {
+	RemoveArg
+	Cost             int
+	Flags            rib.RouteFlags
+	ExpirationPeriod nnduration.Milliseconds // zero means never expires
+}
+
+type LookupReply struct {
+	HasEntry bool
+	Routes   []rib.Route
+	Nexthops []iface.FaceId
+}
@@ -4,6 +4,7 @@ import * as fwdpmgmt from "./fwdpmgmt/mod";
 import * as hrlog from "./hrlog/mod";
 import * as ndtmgmt from "./ndtmgmt/mod";
 import * as pingmgmt from "./pingmgmt/mod";
+import * as ribmgmt from "./ribmgmt/mod";
 import * as strategymgmt from "./strategymgmt/mod";
 import * as versionmgmt from "./versionmgmt/mod";
 
@@ -16,6 +17,7 @@ export interface Mgmt {
   Hrlog: hrlog.HrlogMgmt;
   Ndt: ndtmgmt.NdtMgmt;
   PingClient: pingmgmt.PingClientMgmt;
+  Rib: ribmgmt.RibMgmt;
   Strategy: strategymgmt.StrategyMgmt;
   Version: versionmgmt.VersionMgmt;
 }
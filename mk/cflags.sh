@@ -1,7 +1,7 @@
 export CC=${CC:-gcc}
 export CGO_CFLAGS_ALLOW='.*'
 CFLAGS='-Werror -Wno-error=deprecated-declarations -m64 -pthread -O3 -g '$(pkg-config --cflags libdpdk | sed 's/-include [^ ]*//')
-LIBS='-L/usr/local/lib -lurcu-qsbr -lurcu-cds -lubpf -lspdk -lspdk_env_dpdk -lrte_bus_pci -lrte_bus_vdev -lrte_pmd_ring '$(pkg-config --libs libdpdk)' -lnuma -lm'
+LIBS='-L/usr/local/lib -lurcu-qsbr -lurcu-cds -lubpf -lspdk -lspdk_env_dpdk -lrte_bus_pci -lrte_bus_vdev -lrte_pmd_ring '$(pkg-config --libs libdpdk)' -lcrypto -lnuma -lm'
 
 if ! [[ $MK_CGOFLAGS ]]; then
   CFLAGS='-Wall '$CFLAGS
@@ -2,6 +2,68 @@
 #include "interest.h"
 #include "packet.h"
 
+/** \brief Expected order of Data elements.
+ */
+typedef enum PDataElementOrder
+{
+  PDATA_ORDER_UNRECOGNIZED = 0,
+  PDATA_ORDER_NAME,
+  PDATA_ORDER_METAINFO,
+  PDATA_ORDER_CONTENT,
+  PDATA_ORDER_SIGINFO,
+  PDATA_ORDER_SIGVALUE,
+} PDataElementOrder;
+
+static PDataElementOrder
+PData_GetElementOrder(uint32_t type)
+{
+  switch (type) {
+    case TT_MetaInfo:
+      return PDATA_ORDER_METAINFO;
+    case TT_Content:
+      return PDATA_ORDER_CONTENT;
+    case TT_SignatureInfo:
+      return PDATA_ORDER_SIGINFO;
+    case TT_SignatureValue:
+      return PDATA_ORDER_SIGVALUE;
+  }
+  return PDATA_ORDER_UNRECOGNIZED;
+}
+
+static NdnError
+PData_ParseMetaInfo(PData* data, const TlvElement* metaEle)
+{
+  // expected order: ContentType, FreshnessPeriod, FinalBlockId
+  uint32_t lastType = TT_Invalid;
+  MbufLoc d2;
+  TlvElement_MakeValueDecoder(metaEle, &d2);
+  while (!MbufLoc_IsEnd(&d2)) {
+    TlvElement metaChild;
+    NdnError e = TlvElement_Decode(&metaChild, &d2, TT_Invalid);
+    RETURN_IF_ERROR;
+
+    // ContentType < FreshnessPeriod < FinalBlockId in TLV-TYPE numbers
+    bool isRecognized = metaChild.type == TT_ContentType ||
+                        metaChild.type == TT_FreshnessPeriod ||
+                        metaChild.type == TT_FinalBlockId;
+    if (!isRecognized || metaChild.type <= lastType) {
+      if (TlvType_IsCritical(metaChild.type)) {
+        return NdnError_UnknownCriticalType;
+      }
+      continue;
+    }
+    lastType = metaChild.type;
+
+    if (metaChild.type == TT_FreshnessPeriod) {
+      uint64_t fpV;
+      e = TlvElement_ReadNonNegativeInteger(&metaChild, &fpV);
+      RETURN_IF_ERROR;
+      data->freshnessPeriod = (uint32_t)RTE_MIN(UINT32_MAX, fpV);
+    }
+  }
+  return NdnError_OK;
+}
+
 NdnError
 PData_FromPacket(PData* data, struct rte_mbuf* pkt, struct rte_mempool* nameMp)
 {
@@ -11,6 +73,7 @@ PData_FromPacket(PData* data, struct rte_mbuf* pkt, struct rte_mempool* nameMp)
   NdnError e = TlvElement_Decode(&dataEle, &d0, TT_Data);
   RETURN_IF_ERROR;
   data->size = dataEle.size;
+  data->sigChecked = false;
 
   MbufLoc d1;
   TlvElement_MakeValueDecoder(&dataEle, &d1);
@@ -29,29 +92,25 @@ PData_FromPacket(PData* data, struct rte_mbuf* pkt, struct rte_mempool* nameMp)
   }
 
   data->freshnessPeriod = 0;
-  TlvElement metaEle;
-  e = TlvElement_Decode(&metaEle, &d1, TT_MetaInfo);
-  if (e == NdnError_Incomplete || e == NdnError_BadType) {
-    return NdnError_OK; // MetaInfo not present
-  }
-  RETURN_IF_ERROR;
-
-  MbufLoc d2;
-  TlvElement_MakeValueDecoder(&metaEle, &d2);
-  while (!MbufLoc_IsEnd(&d2)) {
-    TlvElement metaChild;
-    e = TlvElement_Decode(&metaChild, &d2, TT_Invalid);
+  PDataElementOrder lastOrder = PDATA_ORDER_NAME;
+  while (!MbufLoc_IsEnd(&d1)) {
+    TlvElement ele1;
+    e = TlvElement_Decode(&ele1, &d1, TT_Invalid);
     RETURN_IF_ERROR;
 
-    if (metaChild.type != TT_FreshnessPeriod) {
-      continue; // ignore other children of MetaInfo
+    PDataElementOrder order = PData_GetElementOrder(ele1.type);
+    if (order <= lastOrder) { // unrecognized or out of order
+      if (TlvType_IsCritical(ele1.type)) {
+        return NdnError_UnknownCriticalType;
+      }
+      continue;
     }
+    lastOrder = order;
 
-    uint64_t fpV;
-    e = TlvElement_ReadNonNegativeInteger(&metaChild, &fpV);
-    RETURN_IF_ERROR;
-    data->freshnessPeriod = (uint32_t)RTE_MIN(UINT32_MAX, fpV);
-    break;
+    if (ele1.type == TT_MetaInfo) {
+      e = PData_ParseMetaInfo(data, &ele1);
+      RETURN_IF_ERROR;
+    }
   }
 
   return NdnError_OK;
@@ -14,6 +14,7 @@ typedef struct PData
   uint32_t size;            ///< size of Data TLV
 
   bool hasDigest;
+  bool sigChecked; ///< signature has been verified
   uint8_t digest[32];
 } PData;
 
@@ -23,6 +23,12 @@ func TestDataDecode(t *testing.T) {
 		{input: "0602 name=0700", name: "/"},
 		{input: "0605 name=0703080141", name: "/A"},
 		{input: "0615 name=0703080142 meta=140C (180102 fp=190201FF 1A03080142) content=1500", name: "/B", freshness: 0x01FF},
+		{input: "0607 name=0703080141 unknown=3000", name: "/A"},                                    // unrecognized non-critical element
+		{input: "0607 name=0703080141 unknown=3100", bad: true},                                     // unrecognized critical element
+		{input: "0609 name=0703080141 content=1500 meta=1400", bad: true},                           // out of order critical element
+		{input: "060D name=0703080142 meta=1406 (3000 fp=190201FF)", name: "/B", freshness: 0x01FF}, // unrecognized non-critical MetaInfo child
+		{input: "0609 name=0703080142 meta=1402 (3100)", bad: true},                                 // unrecognized critical MetaInfo child
+		{input: "0610 name=0703080141 content=1500 siginfo=16031B0100 sigvalue=1700 unknown=3000", name: "/A"},
 	}
 	for _, tt := range tests {
 		pkt := packetFromHex(tt.input)
@@ -13,8 +13,6 @@ static const uint8_t FAKESIG[] = {
 };
 // clang-format on
 
-const uint16_t EncodeData_FakeSigLen_ = sizeof(FAKESIG);
-
 static void
 EncodeData_AppendNameNoSuffix(TlvEncoder* en,
                               uint16_t namePrefixL,
@@ -30,10 +28,11 @@ EncodeData_AppendNameNoSuffix(TlvEncoder* en,
 }
 
 static void
-EncodeData_AppendFreshnessContentSignature(TlvEncoder* en,
-                                           uint32_t freshnessPeriod,
-                                           uint16_t contentL,
-                                           const uint8_t* contentV)
+EncodeData_AppendFreshnessContentSigInfo(TlvEncoder* en,
+                                         uint32_t freshnessPeriod,
+                                         uint16_t contentL,
+                                         const uint8_t* contentV,
+                                         const Signer* signer)
 {
   struct rte_mbuf* m = TlvEncoder_AsMbuf(en);
 
@@ -62,9 +61,29 @@ EncodeData_AppendFreshnessContentSignature(TlvEncoder* en,
     rte_memcpy(rte_pktmbuf_append(m, contentL), contentV, contentL);
   }
 
-  rte_memcpy(rte_pktmbuf_append(m, EncodeData_FakeSigLen_),
-             FAKESIG,
-             EncodeData_FakeSigLen_);
+  if (signer == NULL) {
+    rte_memcpy(rte_pktmbuf_append(m, sizeof(FAKESIG)), FAKESIG, sizeof(FAKESIG));
+  } else {
+    rte_memcpy(
+      rte_pktmbuf_append(m, signer->sigInfoL), signer->sigInfo, signer->sigInfoL);
+  }
+}
+
+/** \brief Sign \p pkt and append SignatureValue to \p tail .
+ *  \param tail last segment of \p pkt, or a segment to be chained after it;
+ *              must have \c SIGNER_MAX_SIGVALUE_LENGTH in tailroom.
+ */
+static void
+EncodeData_AppendSigValue(const Signer* signer,
+                          const struct rte_mbuf* pkt,
+                          struct rte_mbuf* tail)
+{
+  assert(rte_pktmbuf_tailroom(tail) >= 2 + signer->maxValueL);
+  uint8_t* room = rte_pktmbuf_mtod_offset(tail, uint8_t*, tail->data_len);
+  uint8_t valueL = Signer_Sign(signer, pkt, room + 2);
+  room[0] = TT_SignatureValue;
+  room[1] = valueL;
+  rte_pktmbuf_append(tail, 2 + valueL);
 }
 
 static void
@@ -83,19 +102,23 @@ EncodeData_(struct rte_mbuf* m,
             const uint8_t* nameSuffixV,
             uint32_t freshnessPeriod,
             uint16_t contentL,
-            const uint8_t* contentV)
+            const uint8_t* contentV,
+            const Signer* signer)
 {
   assert(rte_pktmbuf_headroom(m) >= EncodeData_GetHeadroom());
   assert(rte_pktmbuf_tailroom(m) >=
-         EncodeData_GetTailroom(namePrefixL + nameSuffixL, contentL));
+         EncodeData_GetTailroom(namePrefixL + nameSuffixL, contentL, signer));
 
   TlvEncoder* en = MakeTlvEncoder(m);
   EncodeData_AppendNameNoSuffix(en, namePrefixL, namePrefixV, nameSuffixL);
   if (likely(nameSuffixL > 0)) {
     rte_memcpy(rte_pktmbuf_append(m, nameSuffixL), nameSuffixV, nameSuffixL);
   }
-  EncodeData_AppendFreshnessContentSignature(
-    en, freshnessPeriod, contentL, contentV);
+  EncodeData_AppendFreshnessContentSigInfo(
+    en, freshnessPeriod, contentL, contentV, signer);
+  if (signer != NULL) {
+    EncodeData_AppendSigValue(signer, m, m);
+  }
   EncodeData_PrependDataTypeLength(en);
 }
 
@@ -105,7 +128,8 @@ MakeDataGen_(struct rte_mbuf* m,
              const uint8_t* nameSuffixV,
              uint32_t freshnessPeriod,
              uint16_t contentL,
-             const uint8_t* contentV)
+             const uint8_t* contentV,
+             const Signer* signer)
 {
   assert(rte_pktmbuf_tailroom(m) >=
          DataGen_GetTailroom1(nameSuffixL, contentL));
@@ -114,10 +138,11 @@ MakeDataGen_(struct rte_mbuf* m,
   if (nameSuffixL > 0) {
     rte_memcpy(rte_pktmbuf_append(m, nameSuffixL), nameSuffixV, nameSuffixL);
   }
-  EncodeData_AppendFreshnessContentSignature(
-    en, freshnessPeriod, contentL, contentV);
+  EncodeData_AppendFreshnessContentSigInfo(
+    en, freshnessPeriod, contentL, contentV, signer);
 
   m->vlan_tci = nameSuffixL;
+  m->userdata = (void*)signer;
   return (DataGen*)m;
 }
 
@@ -131,6 +156,7 @@ void
 DataGen_Encode_(DataGen* gen,
                 struct rte_mbuf* seg0,
                 struct rte_mbuf* seg1,
+                struct rte_mbuf* seg2,
                 uint16_t namePrefixL,
                 const uint8_t* namePrefixV)
 {
@@ -138,10 +164,16 @@ DataGen_Encode_(DataGen* gen,
 
   struct rte_mbuf* tailTpl = (struct rte_mbuf*)gen;
   uint16_t nameSuffixL = tailTpl->vlan_tci;
+  const Signer* signer = tailTpl->userdata;
   rte_pktmbuf_attach(seg1, tailTpl);
 
   TlvEncoder* en = MakeTlvEncoder(seg0);
   EncodeData_AppendNameNoSuffix(en, namePrefixL, namePrefixV, nameSuffixL);
   rte_pktmbuf_chain(seg0, seg1);
+  if (signer != NULL) {
+    assert(seg2 != NULL);
+    EncodeData_AppendSigValue(signer, seg0, seg2);
+    rte_pktmbuf_chain(seg0, seg2);
+  }
   EncodeData_PrependDataTypeLength(en);
 }
@@ -16,8 +16,8 @@ func EncodeData_GetHeadroom() int {
 	return int(C.EncodeData_GetHeadroom())
 }
 
-func EncodeData_GetTailroom(nameL int, contentL int) int {
-	return int(C.EncodeData_GetTailroom(C.uint16_t(nameL), C.uint16_t(contentL)))
+func EncodeData_GetTailroom(nameL int, contentL int, signer *Signer) int {
+	return int(C.EncodeData_GetTailroom(C.uint16_t(nameL), C.uint16_t(contentL), (*C.Signer)(signer.GetPtr())))
 }
 
 func EncodeData_GetTailroomMax() int {
@@ -25,12 +25,14 @@ func EncodeData_GetTailroomMax() int {
 }
 
 // Encode a Data.
-func EncodeData(m dpdk.IMbuf, namePrefix *Name, nameSuffix *Name, freshnessPeriod time.Duration, content TlvBytes) {
+// signer can be nil to attach a fake DigestSha256 signature.
+func EncodeData(m dpdk.IMbuf, namePrefix *Name, nameSuffix *Name, freshnessPeriod time.Duration, content TlvBytes, signer *Signer) {
 	C.EncodeData_((*C.struct_rte_mbuf)(m.GetPtr()),
 		C.uint16_t(namePrefix.Size()), namePrefix.getValuePtr(),
 		C.uint16_t(nameSuffix.Size()), nameSuffix.getValuePtr(),
 		C.uint32_t(freshnessPeriod/time.Millisecond),
-		C.uint16_t(len(content)), (*C.uint8_t)(content.GetPtr()))
+		C.uint16_t(len(content)), (*C.uint8_t)(content.GetPtr()),
+		(*C.Signer)(signer.GetPtr()))
 }
 
 // Encode a Data from flexible arguments.
@@ -43,6 +45,7 @@ func MakeData(m dpdk.IMbuf, name string, args ...interface{}) (*Data, error) {
 	}
 	var freshnessPeriod time.Duration
 	var content TlvBytes
+	var signer *Signer
 
 	for _, arg := range args {
 		switch a := arg.(type) {
@@ -50,13 +53,15 @@ func MakeData(m dpdk.IMbuf, name string, args ...interface{}) (*Data, error) {
 			freshnessPeriod = a
 		case TlvBytes:
 			content = a
+		case *Signer:
+			signer = a
 		default:
 			m.Close()
 			return nil, fmt.Errorf("unrecognized argument type %T", a)
 		}
 	}
 
-	EncodeData(m, n, nil, freshnessPeriod, content)
+	EncodeData(m, n, nil, freshnessPeriod, content, signer)
 
 	pkt := PacketFromDpdk(m)
 	e = pkt.ParseL2()
@@ -84,15 +89,22 @@ func DataGen_GetTailroom1(nameSuffixL, contentL int) int {
 	return int(C.DataGen_GetTailroom1(C.uint16_t(nameSuffixL), C.uint16_t(contentL)))
 }
 
+func DataGen_GetTailroom2() int {
+	return int(C.DataGen_GetTailroom2())
+}
+
 type DataGen struct {
 	c *C.DataGen
 }
 
-func NewDataGen(m dpdk.IMbuf, nameSuffix *Name, freshnessPeriod time.Duration, content TlvBytes) (gen DataGen) {
+// Create DataGen template.
+// signer can be nil to attach a fake DigestSha256 signature; otherwise, it must remain open until DataGen is closed.
+func NewDataGen(m dpdk.IMbuf, nameSuffix *Name, freshnessPeriod time.Duration, content TlvBytes, signer *Signer) (gen DataGen) {
 	gen.c = C.MakeDataGen_((*C.struct_rte_mbuf)(m.GetPtr()),
 		C.uint16_t(nameSuffix.Size()), nameSuffix.getValuePtr(),
 		C.uint32_t(freshnessPeriod/time.Millisecond),
-		C.uint16_t(len(content)), (*C.uint8_t)(content.GetPtr()))
+		C.uint16_t(len(content)), (*C.uint8_t)(content.GetPtr()),
+		(*C.Signer)(signer.GetPtr()))
 	return gen
 }
 
@@ -109,8 +121,19 @@ func (gen DataGen) Close() error {
 	return nil
 }
 
-func (gen DataGen) Encode(seg0, seg1 dpdk.IMbuf, namePrefix *Name) {
+// Determine whether Encode requires seg2.
+func (gen DataGen) IsSigned() bool {
+	return bool(C.DataGen_IsSigned(gen.c))
+}
+
+// Encode Data.
+// seg2 is required if gen.IsSigned(), and is ignored otherwise.
+func (gen DataGen) Encode(seg0, seg1, seg2 dpdk.IMbuf, namePrefix *Name) {
+	var seg2ptr *C.struct_rte_mbuf
+	if seg2 != nil {
+		seg2ptr = (*C.struct_rte_mbuf)(seg2.GetPtr())
+	}
 	C.DataGen_Encode_(gen.c,
-		(*C.struct_rte_mbuf)(seg0.GetPtr()), (*C.struct_rte_mbuf)(seg1.GetPtr()),
+		(*C.struct_rte_mbuf)(seg0.GetPtr()), (*C.struct_rte_mbuf)(seg1.GetPtr()), seg2ptr,
 		C.uint16_t(namePrefix.Size()), namePrefix.getValuePtr())
 }
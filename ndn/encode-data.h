@@ -3,7 +3,7 @@
 
 /// \file
 
-#include "name.h"
+#include "signer.h"
 
 static inline uint16_t
 EncodeData_GetHeadroom()
@@ -11,24 +11,25 @@ EncodeData_GetHeadroom()
   return 1 + 5; // Data TL
 }
 
-extern const uint16_t EncodeData_FakeSigLen_;
-
+/** \brief Get required tailroom for EncodeData output mbuf.
+ *  \param signer the signer, or NULL for fake signature.
+ */
 static inline uint16_t
-EncodeData_GetTailroom(uint16_t nameL, uint16_t contentL)
+EncodeData_GetTailroom(uint16_t nameL, uint16_t contentL, const Signer* signer)
 {
-  return 1 + 3 + nameL +         // Name
-         1 + 1 + 1 + 1 + 4 +     // MetaInfo with FreshnessPeriod
-         1 + 3 + contentL +      // Content
-         EncodeData_FakeSigLen_; // SignatureInfo + SignatureValue
+  return 1 + 3 + nameL +              // Name
+         1 + 1 + 1 + 1 + 4 +          // MetaInfo with FreshnessPeriod
+         1 + 3 + contentL +           // Content
+         Signer_GetTailroom(signer); // SignatureInfo + SignatureValue
 }
 
 /** \brief Get required tailroom for EncodeData output mbuf,
- *         assuming max name length and empty payload.
+ *         assuming max name length, empty payload, and fake signature.
  */
 static inline uint16_t
 EncodeData_GetTailroomMax()
 {
-  return EncodeData_GetTailroom(NAME_MAX_LENGTH, 0);
+  return EncodeData_GetTailroom(NAME_MAX_LENGTH, 0, NULL);
 }
 
 void
@@ -39,15 +40,17 @@ EncodeData_(struct rte_mbuf* m,
             const uint8_t* nameSuffixV,
             uint32_t freshnessPeriod,
             uint16_t contentL,
-            const uint8_t* contentV);
+            const uint8_t* contentV,
+            const Signer* signer);
 
 /** \brief Encode a Data.
  *  \param m output mbuf, must be empty and is the only segment, must have
  *           \c EncodeData_GetHeadroom() in headroom and
  *           <tt>EncodeData_GetTailroom(namePrefix.length + nameSuffix.length,
- *           contentL)</tt> in tailroom; headroom for Ethernet and NDNLP
- *           headers may be included if needed.
+ *           contentL, signer)</tt> in tailroom; headroom for Ethernet and
+ *           NDNLP headers may be included if needed.
  *  \param contentV the payload, will be copied.
+ *  \param signer the signer, or NULL to attach a fake DigestSha256 signature.
  */
 static inline void
 EncodeData(struct rte_mbuf* m,
@@ -55,7 +58,8 @@ EncodeData(struct rte_mbuf* m,
            LName nameSuffix,
            uint32_t freshnessPeriod,
            uint16_t contentL,
-           const uint8_t* contentV)
+           const uint8_t* contentV,
+           const Signer* signer)
 {
   EncodeData_(m,
               namePrefix.length,
@@ -64,7 +68,8 @@ EncodeData(struct rte_mbuf* m,
               nameSuffix.value,
               freshnessPeriod,
               contentL,
-              contentV);
+              contentV,
+              signer);
 }
 
 /** \brief Data encoder optimized for NdnpingServer.
@@ -73,6 +78,9 @@ EncodeData(struct rte_mbuf* m,
  *  except name prefix into a template, and then creates two-segment packets
  *  where the second segment references the template. It's faster for traffic
  *  generator use case, but does not allow changing Content payload.
+ *
+ *  When a signer is used, the signature covers the full name and cannot be
+ *  part of the template, so that a third segment carries the SignatureValue.
  */
 typedef struct DataGen
 {
@@ -91,13 +99,22 @@ DataGen_GetTailroom0(uint16_t namePrefixL)
          1 + 3 + namePrefixL; // Name
 }
 
+/** \brief Get required tailroom for DataGen template mbuf,
+ *         assuming the longest SignatureInfo.
+ */
 static inline uint16_t
 DataGen_GetTailroom1(uint16_t nameSuffixL, uint16_t contentL)
 {
-  return nameSuffixL +           // Name
-         1 + 1 + 1 + 1 + 4 +     // MetaInfo with FreshnessPeriod
-         1 + 3 + contentL +      // Content
-         EncodeData_FakeSigLen_; // SignatureInfo + SignatureValue
+  return nameSuffixL +              // Name
+         1 + 1 + 1 + 1 + 4 +        // MetaInfo with FreshnessPeriod
+         1 + 3 + contentL +         // Content
+         SIGNER_MAX_SIGINFO_LENGTH; // SignatureInfo (+ fake SignatureValue)
+}
+
+static inline uint16_t
+DataGen_GetTailroom2()
+{
+  return SIGNER_MAX_SIGVALUE_LENGTH;
 }
 
 DataGen*
@@ -106,19 +123,31 @@ MakeDataGen_(struct rte_mbuf* m,
              const uint8_t* nameSuffixV,
              uint32_t freshnessPeriod,
              uint16_t contentL,
-             const uint8_t* contentV);
+             const uint8_t* contentV,
+             const Signer* signer);
 
 /** \brief Prepare DataGen template.
  *  \param m template mbuf, must be empty and is the only segment, must have
  *           <tt>DataGen_GetTailroom1(nameSuffix.length, contentL)</tt> in
  *           tailroom. DataGen takes ownership of this mbuf.
+ *  \param signer the signer, or NULL to attach a fake DigestSha256 signature.
+ *                 It must remain valid until DataGen is closed.
  */
 DataGen*
 MakeDataGen(struct rte_mbuf* m,
             LName nameSuffix,
             uint32_t freshnessPeriod,
             uint16_t contentL,
-            const uint8_t* contentV);
+            const uint8_t* contentV,
+            const Signer* signer);
+
+/** \brief Determine whether DataGen requires a SignatureValue segment.
+ */
+static inline bool
+DataGen_IsSigned(const DataGen* gen)
+{
+  return ((const struct rte_mbuf*)gen)->userdata != NULL;
+}
 
 void
 DataGen_Close(DataGen* gen);
@@ -127,6 +156,7 @@ void
 DataGen_Encode_(DataGen* gen,
                 struct rte_mbuf* seg0,
                 struct rte_mbuf* seg1,
+                struct rte_mbuf* seg2,
                 uint16_t namePrefixL,
                 const uint8_t* namePrefixV);
 
@@ -136,14 +166,20 @@ DataGen_Encode_(DataGen* gen,
  *              <tt>DataGen_GetTailroom0(namePrefix.length)</tt> in tailroom.
  *              This becomes the encoded Data packet.
  *  \param seg1 segment 1 indirect mbuf. This is chained onto \p seg0 .
+ *  \param seg2 segment 2 mbuf for SignatureValue, must be empty and have
+ *              \c DataGen_GetTailroom2() in tailroom, if
+ *              \c DataGen_IsSigned(gen) ; otherwise it is ignored and may
+ *              be NULL. This is chained onto \p seg0 .
  */
 static inline void
 DataGen_Encode(DataGen* gen,
                struct rte_mbuf* seg0,
                struct rte_mbuf* seg1,
+               struct rte_mbuf* seg2,
                LName namePrefix)
 {
-  DataGen_Encode_(gen, seg0, seg1, namePrefix.length, namePrefix.value);
+  DataGen_Encode_(
+    gen, seg0, seg1, seg2, namePrefix.length, namePrefix.value);
 }
 
 #endif // NDN_DPDK_NDN_ENCODE_DATA_H
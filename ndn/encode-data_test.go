@@ -1,6 +1,14 @@
 package ndn_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"math/big"
 	"testing"
 	"time"
 
@@ -23,7 +31,7 @@ func TestEncodeData(t *testing.T) {
 	freshnessPeriod := 11742 * time.Millisecond
 	content := ndn.TlvBytes{0xC0, 0xC1, 0xC2, 0xC3, 0xC4, 0xC5, 0xC6, 0xC7}
 
-	ndn.EncodeData(m, namePrefix, nameSuffix, freshnessPeriod, content)
+	ndn.EncodeData(m, namePrefix, nameSuffix, freshnessPeriod, content, nil)
 	pkt := ndn.PacketFromDpdk(m)
 	e = pkt.ParseL3(theMp)
 	require.NoError(e)
@@ -47,9 +55,10 @@ func TestDataGen(t *testing.T) {
 	freshnessPeriod := 11742 * time.Millisecond
 	content := ndn.TlvBytes{0xC0, 0xC1, 0xC2, 0xC3, 0xC4, 0xC5, 0xC6, 0xC7}
 
-	gen := ndn.NewDataGen(mbufs[1], nameSuffix, freshnessPeriod, content)
+	gen := ndn.NewDataGen(mbufs[1], nameSuffix, freshnessPeriod, content, nil)
 	defer gen.Close()
-	gen.Encode(mbufs[0], mi, namePrefix)
+	assert.False(gen.IsSigned())
+	gen.Encode(mbufs[0], mi, nil, namePrefix)
 
 	pkt := ndn.PacketFromDpdk(mbufs[0])
 	defer mbufs[0].Close()
@@ -60,3 +69,92 @@ func TestDataGen(t *testing.T) {
 	ndntestutil.NameEqual(assert, "/A/B/C", data)
 	assert.Equal(freshnessPeriod, data.GetFreshnessPeriod())
 }
+
+// Split encoded Data into signed portion and SignatureValue TLV-VALUE.
+func splitSignedData(wire []byte) (signed []byte, sigValue []byte) {
+	// every TLV-LENGTH is less than 253 in these tests
+	for pos := 2; pos+2 <= len(wire); pos += 2 + int(wire[pos+1]) {
+		if wire[pos] == 0x17 {
+			return wire[2:pos], wire[pos+2:]
+		}
+	}
+	return nil, nil
+}
+
+func TestEncodeDataSigned(t *testing.T) {
+	assert, require := makeAR(t)
+
+	keyName, e := ndn.ParseName("/K/KEY/1")
+	require.NoError(e)
+	hmacKey := []byte{0xA0, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7}
+	ecKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(e)
+	ecKeyDer, e := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(e)
+
+	tests := []struct {
+		cfg    ndn.SignerConfig
+		verify func(signed, sigValue []byte) bool
+	}{
+		{ndn.SignerConfig{Type: ndn.Signer_Sha256},
+			func(signed, sigValue []byte) bool {
+				digest := sha256.Sum256(signed)
+				return hmac.Equal(digest[:], sigValue)
+			}},
+		{ndn.SignerConfig{Type: ndn.Signer_Hmac, KeyName: keyName, Key: hmacKey},
+			func(signed, sigValue []byte) bool {
+				h := hmac.New(sha256.New, hmacKey)
+				h.Write(signed)
+				return hmac.Equal(h.Sum(nil), sigValue)
+			}},
+		{ndn.SignerConfig{Type: ndn.Signer_Ecdsa, KeyName: keyName, Key: ecKeyDer},
+			func(signed, sigValue []byte) bool {
+				var rs struct{ R, S *big.Int }
+				if _, e := asn1.Unmarshal(sigValue, &rs); e != nil {
+					return false
+				}
+				digest := sha256.Sum256(signed)
+				return ecdsa.Verify(&ecKey.PublicKey, digest[:], rs.R, rs.S)
+			}},
+	}
+
+	namePrefix, e := ndn.ParseName("/A/B")
+	require.NoError(e)
+	nameSuffix, e := ndn.ParseName("/C")
+	require.NoError(e)
+	content := ndn.TlvBytes{0xC0, 0xC1, 0xC2, 0xC3}
+
+	for i, tt := range tests {
+		signer, e := ndn.NewSigner(tt.cfg, dpdk.NUMA_SOCKET_ANY)
+		require.NoError(e, "%d", i)
+
+		m := dpdktestenv.Alloc(dpdktestenv.MPID_DIRECT)
+		ndn.EncodeData(m, namePrefix, nameSuffix, time.Second, content, signer)
+		pkt := ndn.PacketFromDpdk(m)
+		wire := pkt.AsDpdkPacket().ReadAll()
+		if e = pkt.ParseL3(theMp); assert.NoError(e, "%d", i) {
+			ndntestutil.NameEqual(assert, "/A/B/C", pkt.AsData(), "%d", i)
+		}
+		signed, sigValue := splitSignedData(wire)
+		assert.True(tt.verify(signed, sigValue), "%d", i)
+		m.Close()
+
+		mbufs := make([]dpdk.Mbuf, 3)
+		dpdktestenv.AllocBulk(dpdktestenv.MPID_DIRECT, mbufs)
+		mi := dpdktestenv.Alloc(dpdktestenv.MPID_INDIRECT)
+		gen := ndn.NewDataGen(mbufs[1], nameSuffix, time.Second, content, signer)
+		assert.True(gen.IsSigned(), "%d", i)
+		gen.Encode(mbufs[0], mi, mbufs[2], namePrefix)
+		pkt = ndn.PacketFromDpdk(mbufs[0])
+		wire = pkt.AsDpdkPacket().ReadAll()
+		if e = pkt.ParseL3(theMp); assert.NoError(e, "%d", i) {
+			ndntestutil.NameEqual(assert, "/A/B/C", pkt.AsData(), "%d", i)
+		}
+		signed, sigValue = splitSignedData(wire)
+		assert.True(tt.verify(signed, sigValue), "%d", i)
+		mbufs[0].Close()
+		gen.Close()
+
+		signer.Close()
+	}
+}
@@ -2,8 +2,52 @@
 #include "packet.h"
 #include "tlv-encoder.h"
 
+#include <openssl/sha.h>
 #include <rte_random.h>
 
+/** \brief Expected order of Interest elements.
+ */
+typedef enum PInterestElementOrder
+{
+  PINTEREST_ORDER_UNRECOGNIZED = 0,
+  PINTEREST_ORDER_NAME,
+  PINTEREST_ORDER_CANBEPREFIX,
+  PINTEREST_ORDER_MUSTBEFRESH,
+  PINTEREST_ORDER_FORWARDINGHINT,
+  PINTEREST_ORDER_NONCE,
+  PINTEREST_ORDER_INTERESTLIFETIME,
+  PINTEREST_ORDER_HOPLIMIT,
+  PINTEREST_ORDER_APPLICATIONPARAMETERS,
+  PINTEREST_ORDER_SIGINFO,
+  PINTEREST_ORDER_SIGVALUE,
+} PInterestElementOrder;
+
+static PInterestElementOrder
+PInterest_GetElementOrder(uint32_t type)
+{
+  switch (type) {
+    case TT_CanBePrefix:
+      return PINTEREST_ORDER_CANBEPREFIX;
+    case TT_MustBeFresh:
+      return PINTEREST_ORDER_MUSTBEFRESH;
+    case TT_ForwardingHint:
+      return PINTEREST_ORDER_FORWARDINGHINT;
+    case TT_Nonce:
+      return PINTEREST_ORDER_NONCE;
+    case TT_InterestLifetime:
+      return PINTEREST_ORDER_INTERESTLIFETIME;
+    case TT_HopLimit:
+      return PINTEREST_ORDER_HOPLIMIT;
+    case TT_ApplicationParameters:
+      return PINTEREST_ORDER_APPLICATIONPARAMETERS;
+    case TT_InterestSignatureInfo:
+      return PINTEREST_ORDER_SIGINFO;
+    case TT_InterestSignatureValue:
+      return PINTEREST_ORDER_SIGVALUE;
+  }
+  return PINTEREST_ORDER_UNRECOGNIZED;
+}
+
 NdnError
 PInterest_FromPacket(PInterest* interest,
                      struct rte_mbuf* pkt,
@@ -38,97 +82,166 @@ PInterest_FromPacket(PInterest* interest,
   interest->mustBeFresh = false;
   interest->nFhs = 0;
   interest->activeFh = -1;
+  interest->sigChecked = false;
+  interest->hasParams = false;
+  interest->paramsOff = 0;
+  interest->diskSlotId = 0;
   interest->diskData = NULL;
 
-#define D1_NEXT                                                                \
-  do {                                                                         \
-    if (MbufLoc_IsEnd(&d1)) {                                                  \
-      return NdnError_OK;                                                      \
-    }                                                                          \
-    e = TlvElement_Decode(&ele1, &d1, TT_Invalid);                             \
-    RETURN_IF_ERROR;                                                           \
-  } while (false)
-
-  D1_NEXT;
-  if (ele1.type == TT_CanBePrefix) {
-    interest->canBePrefix = true;
-    interest->guiderOff += ele1.size;
-    D1_NEXT;
-  }
-
-  if (ele1.type == TT_MustBeFresh) {
-    interest->mustBeFresh = true;
-    interest->guiderOff += ele1.size;
-    D1_NEXT;
-  }
+  uint32_t off = ele1.size; // offset of next element within Interest TLV-VALUE
+  uint32_t guiderEnd = 0;   // offset past last guider, 0 if no guider
+  PInterestElementOrder lastOrder = PINTEREST_ORDER_NAME;
+  while (!MbufLoc_IsEnd(&d1)) {
+    e = TlvElement_Decode(&ele1, &d1, TT_Invalid);
+    RETURN_IF_ERROR;
+
+    PInterestElementOrder order = PInterest_GetElementOrder(ele1.type);
+    if (order <= lastOrder) { // unrecognized or out of order
+      if (TlvType_IsCritical(ele1.type)) {
+        return NdnError_UnknownCriticalType;
+      }
+      off += ele1.size;
+      continue;
+    }
+    lastOrder = order;
 
-  if (ele1.type == TT_ForwardingHint) {
-    MbufLoc d2;
-    TlvElement_MakeValueDecoder(&ele1, &d2);
-    for (int i = 0; i < INTEREST_MAX_FHS; ++i) {
-      if (MbufLoc_IsEnd(&d2)) {
+    switch (ele1.type) {
+      case TT_CanBePrefix:
+        interest->canBePrefix = true;
+        break;
+      case TT_MustBeFresh:
+        interest->mustBeFresh = true;
+        break;
+      case TT_ForwardingHint: {
+        MbufLoc d2;
+        TlvElement_MakeValueDecoder(&ele1, &d2);
+        for (int i = 0; i < INTEREST_MAX_FHS; ++i) {
+          if (MbufLoc_IsEnd(&d2)) {
+            break;
+          }
+          TlvElement delegationEle;
+          e = TlvElement_Decode(&delegationEle, &d2, TT_Delegation);
+          RETURN_IF_ERROR;
+
+          MbufLoc d3;
+          TlvElement_MakeValueDecoder(&delegationEle, &d3);
+          TlvElement ele3;
+          e = TlvElement_Decode(&ele3, &d3, TT_Preference);
+          RETURN_IF_ERROR;
+          e = TlvElement_Decode(&ele3, &d3, TT_Name);
+          RETURN_IF_ERROR;
+          interest->fhNameV[i] =
+            TlvElement_LinearizeValue(&ele3, pkt, nameMp, &d3);
+          RETURN_IF_NULL(interest->fhNameV[i], NdnError_AllocError);
+          interest->fhNameL[i] = ele3.length;
+          ++interest->nFhs;
+          MbufLoc_CopyPos(&d2, &d3);
+        }
+        MbufLoc_Advance(&d2, d2.rem); // ignore remaining delegations
+        MbufLoc_CopyPos(&d1, &d2);
         break;
       }
-      TlvElement delegationEle;
-      e = TlvElement_Decode(&delegationEle, &d2, TT_Delegation);
-      RETURN_IF_ERROR;
-
-      MbufLoc d3;
-      TlvElement_MakeValueDecoder(&delegationEle, &d3);
-      TlvElement ele3;
-      e = TlvElement_Decode(&ele3, &d3, TT_Preference);
-      RETURN_IF_ERROR;
-      e = TlvElement_Decode(&ele3, &d3, TT_Name);
-      interest->fhNameV[i] = TlvElement_LinearizeValue(&ele3, pkt, nameMp, &d3);
-      RETURN_IF_NULL(interest->fhNameV[i], NdnError_AllocError);
-      interest->fhNameL[i] = ele3.length;
-      ++interest->nFhs;
-      MbufLoc_CopyPos(&d2, &d3);
+      case TT_Nonce: {
+        rte_le32_t nonceV;
+        if (unlikely(ele1.length != sizeof(nonceV))) {
+          return NdnError_BadNonceLength;
+        }
+        // overwriting ele1.value, but it's okay because we don't need it later
+        bool ok __rte_unused = MbufLoc_ReadU32(&ele1.value, &nonceV);
+        assert(ok); // must succeed because length is checked
+        interest->nonce = rte_le_to_cpu_32(nonceV);
+        break;
+      }
+      case TT_InterestLifetime: {
+        uint64_t lifetimeV = 0;
+        e = TlvElement_ReadNonNegativeInteger(&ele1, &lifetimeV);
+        if (unlikely(e != NdnError_OK || lifetimeV >= UINT32_MAX)) {
+          return NdnError_BadInterestLifetime;
+        }
+        interest->lifetime = (uint32_t)lifetimeV;
+        break;
+      }
+      case TT_HopLimit: {
+        if (unlikely(ele1.length != sizeof(interest->hopLimit))) {
+          return NdnError_BadHopLimitLength;
+        }
+        const uint8_t* hopLimitV = TlvElement_GetLinearValue(&ele1);
+        if (unlikely(*hopLimitV == 0)) {
+          return NdnError_HopLimitZero;
+        }
+        interest->hopLimit = *hopLimitV;
+        break;
+      }
+      case TT_ApplicationParameters:
+        interest->hasParams = true;
+        interest->paramsOff = interestEle.size - interestEle.length + off;
+        break;
     }
-    MbufLoc_CopyPos(&d1, &d2);
-    interest->guiderOff += ele1.size;
-    D1_NEXT;
-  }
 
-  if (ele1.type == TT_Nonce) {
-    rte_le32_t nonceV;
-    if (unlikely(ele1.length != sizeof(nonceV))) {
-      return NdnError_BadNonceLength;
+    if (order < PINTEREST_ORDER_NONCE) {
+      interest->guiderOff = off + ele1.size;
+    } else if (order <= PINTEREST_ORDER_HOPLIMIT) {
+      if (guiderEnd == 0) {
+        interest->guiderOff = off;
+      }
+      guiderEnd = off + ele1.size;
     }
-    // overwriting ele1.value, but it's okay because we don't need it later
-    bool ok __rte_unused = MbufLoc_ReadU32(&ele1.value, &nonceV);
-    assert(ok); // must succeed because length is checked
-    interest->nonce = rte_le_to_cpu_32(nonceV);
-    interest->guiderSize += ele1.size;
-    D1_NEXT;
+    off += ele1.size;
   }
 
-  if (ele1.type == TT_InterestLifetime) {
-    uint64_t lifetimeV = 0;
-    e = TlvElement_ReadNonNegativeInteger(&ele1, &lifetimeV);
-    if (unlikely(e != NdnError_OK || lifetimeV >= UINT32_MAX)) {
-      return NdnError_BadInterestLifetime;
-    }
-    interest->lifetime = (uint32_t)lifetimeV;
-    interest->guiderSize += ele1.size;
-    D1_NEXT;
+  if (guiderEnd > 0) {
+    interest->guiderSize = guiderEnd - interest->guiderOff;
+  }
+  if (unlikely(interest->hasParams != interest->name.p.hasParamsDigest)) {
+    return NdnError_BadParamsDigest;
   }
+  return NdnError_OK;
+}
 
-  if (ele1.type == TT_HopLimit) {
-    if (unlikely(ele1.length != sizeof(interest->hopLimit))) {
-      return NdnError_BadHopLimitLength;
-    }
-    const uint8_t* hopLimitV = TlvElement_GetLinearValue(&ele1);
-    if (unlikely(*hopLimitV == 0)) {
-      return NdnError_HopLimitZero;
+static void
+PInterest_DigestSegment(void* ctx,
+                        const struct rte_mbuf* m,
+                        uint16_t off,
+                        uint16_t len)
+{
+  SHA256_Update(ctx, rte_pktmbuf_mtod_offset(m, const uint8_t*, off), len);
+}
+
+bool
+PInterest_CheckParamsDigest(const PInterest* interest,
+                            const struct rte_mbuf* pkt)
+{
+  if (!interest->hasParams) {
+    return true;
+  }
+
+  const uint8_t* digestComp = NULL;
+  for (uint16_t i = 0; i < interest->name.p.nComps; ++i) {
+    NameComp comp = Name_GetComp(&interest->name, i);
+    if (*comp.tlv == TT_ParametersSha256DigestComponent) {
+      digestComp = comp.tlv;
+      break;
     }
-    interest->hopLimit = *hopLimitV;
-    interest->guiderSize += ele1.size;
-    D1_NEXT;
   }
+  assert(digestComp != NULL); // guaranteed by PInterest_FromPacket
 
-  return NdnError_OK;
-#undef D1_NEXT
+  MbufLoc ml;
+  MbufLoc_Init(&ml, pkt);
+  TlvElement interestEle;
+  NdnError e = TlvElement_DecodeTL(&interestEle, &ml, TT_Interest);
+  if (unlikely(e != NdnError_OK)) {
+    return false;
+  }
+  MbufLoc_Init(&ml, pkt);
+  MbufLoc_Advance(&ml, interest->paramsOff);
+  ml.rem = interestEle.size - interest->paramsOff;
+
+  SHA256_CTX ctx;
+  SHA256_Init(&ctx);
+  MbufLoc_Walk_(&ml, ml.rem, PInterest_DigestSegment, &ctx);
+  uint8_t digest[SHA256_DIGEST_LENGTH];
+  SHA256_Final(digest, &ctx);
+  return memcmp(RTE_PTR_ADD(digestComp, 2), digest, sizeof(digest)) == 0;
 }
 
 NdnError
@@ -260,6 +373,12 @@ ModifyInterest(Packet* npkt,
   outInterest->nonce = nonce;
   outInterest->lifetime = lifetime;
   outInterest->guiderSize = sizeof(GuiderF);
+  if (outInterest->hasParams) {
+    uint32_t inTlSize = interestEle.size - interestEle.length;
+    outInterest->paramsOff = header->data_len +
+                             (inInterest->paramsOff - inTlSize) -
+                             inInterest->guiderSize + sizeof(GuiderF);
+  }
   return outNpkt;
 }
 
@@ -273,14 +392,17 @@ EncodeInterest_(struct rte_mbuf* m,
   m->data_off = tpl->headroom;
   TlvEncoder* en = MakeTlvEncoder(m);
   AppendVarNum(en, TT_Name);
-  AppendVarNum(en, tpl->prefixL + suffixL);
+  AppendVarNum(en, tpl->prefixL + suffixL + tpl->paramsDigestL);
 
-  uint8_t* room = TlvEncoder_Append(en, tpl->prefixL + suffixL + tpl->midLen);
+  uint8_t* room = TlvEncoder_Append(
+    en, tpl->prefixL + suffixL + tpl->paramsDigestL + tpl->midLen);
   assert(room != NULL);
   rte_memcpy(room, tpl->prefixV, tpl->prefixL);
   room = RTE_PTR_ADD(room, tpl->prefixL);
   rte_memcpy(room, suffixV, suffixL);
   room = RTE_PTR_ADD(room, suffixL);
+  rte_memcpy(room, tpl->paramsDigestV, tpl->paramsDigestL);
+  room = RTE_PTR_ADD(room, tpl->paramsDigestL);
   rte_memcpy(room, tpl->midBuf, tpl->midLen);
   *(unaligned_uint32_t*)RTE_PTR_ADD(room, tpl->nonceOff) =
     rte_cpu_to_le_32(nonce);
@@ -22,6 +22,7 @@ PInterest_Unpack(const PInterest* p, PInterestUnpacked* u)
 */
 import "C"
 import (
+	"crypto/sha256"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -87,6 +88,16 @@ func (interest *Interest) GetHopLimit() uint8 {
 	return uint8(interest.p.hopLimit)
 }
 
+// Determine whether Interest has ApplicationParameters.
+func (interest *Interest) HasParams() bool {
+	return bool(interest.p.hasParams)
+}
+
+// Determine whether ParametersSha256DigestComponent matches ApplicationParameters.
+func (interest *Interest) CheckParamsDigest() bool {
+	return bool(C.PInterest_CheckParamsDigest(interest.p, (*C.struct_rte_mbuf)(interest.m.GetPtr())))
+}
+
 func (interest *Interest) GetFhs() (fhs []*Name) {
 	var u C.PInterestUnpacked
 	C.PInterest_Unpack(interest.p, &u)
@@ -153,6 +164,9 @@ type FHDelegation struct {
 
 type ActiveFHDelegation int
 
+// ApplicationParameters TLV-VALUE.
+type ApplicationParameters TlvBytes
+
 func InterestTemplateFromPtr(ptr unsafe.Pointer) *InterestTemplate {
 	return (*InterestTemplate)(ptr)
 }
@@ -165,12 +179,15 @@ func InterestTemplateFromPtr(ptr unsafe.Pointer) *InterestTemplate {
 // Specify ForwardingHint with FHDelegation (repeatable).
 // Specify InterestLifetime with time.Duration.
 // Specify HopLimit with uint8.
-// ApplicationParameters and Signature are not supported.
+// Specify ApplicationParameters with ApplicationParameters; ParametersSha256DigestComponent is appended
+// to the name automatically.
+// Signature is not supported.
 func (tpl *InterestTemplate) Init(args ...interface{}) (e error) {
 	tpl.Headroom = Interest_Headroom
 	cbp := false
 	mbf := false
 	var fh TlvBytes
+	var params TlvBytes
 	lifetime := uint32(C.DEFAULT_INTEREST_LIFETIME)
 	hopLimit := uint8(0xFF)
 
@@ -202,6 +219,8 @@ func (tpl *InterestTemplate) Init(args ...interface{}) (e error) {
 			lifetime = uint32(a / time.Millisecond)
 		case uint8:
 			hopLimit = a
+		case ApplicationParameters:
+			params = EncodeTlv(TT_ApplicationParameters, TlvBytes(a))
 		default:
 			return fmt.Errorf("unrecognized argument type %T", a)
 		}
@@ -230,6 +249,19 @@ func (tpl *InterestTemplate) Init(args ...interface{}) (e error) {
 	if hopLimit != 0xFF {
 		mid = mid.Join(EncodeTlv(TT_HopLimit, TlvBytes{hopLimit}))
 	}
+	tpl.ParamsDigestL = 0
+	if params != nil {
+		mid = mid.Join(params)
+		digest := sha256.Sum256(([]byte)(params))
+		tpl.ParamsDigestL = uint16(copy(tpl.ParamsDigestV[:],
+			([]byte)(EncodeTlv(TT_ParametersSha256DigestComponent, TlvBytes(digest[:])))))
+	}
+	if int(tpl.PrefixL)+int(tpl.ParamsDigestL) > NAME_MAX_LENGTH {
+		return errors.New("name too long")
+	}
+	if len(mid) > len(tpl.MidBuf) {
+		return errors.New("InterestTemplate buffer too small")
+	}
 	tpl.MidLen = uint16(copy(tpl.MidBuf[:], ([]byte)(mid)))
 	return nil
 }
@@ -252,6 +284,7 @@ func (tpl *InterestTemplate) Encode(m dpdk.IMbuf, suffix *Name, nonce uint32) {
 // In addition to argument types supported by `func (tpl *InterestTemplate) Init`:
 // Specify Nonce with uint32.
 // Choose active ForwardingHint delegation with ActiveFHDelegation.
+// Specify ApplicationParameters with ApplicationParameters.
 func MakeInterest(m dpdk.IMbuf, args ...interface{}) (interest *Interest, e error) {
 	nonce := rand.Uint32()
 	activeFh := -1
@@ -30,6 +30,9 @@ typedef struct PInterest
     uint8_t nFhs : 3;    ///< number of fwhints, up to INTEREST_MAX_FHS
     int8_t activeFh : 3; ///< index of active fwhint, -1 for none
   } __rte_packed;
+  bool sigChecked;    ///< signature has been verified
+  bool hasParams;     ///< has ApplicationParameters?
+  uint32_t paramsOff; ///< offset of ApplicationParameters within packet
 
   Name name;
 
@@ -54,6 +57,15 @@ PInterest_FromPacket(PInterest* interest,
                      struct rte_mbuf* pkt,
                      struct rte_mempool* nameMp);
 
+/** \brief Determine whether ParametersSha256DigestComponent matches
+ *         ApplicationParameters and following elements.
+ *  \param pkt the Interest packet.
+ *  \return true if digest matches or Interest has no ApplicationParameters.
+ */
+bool
+PInterest_CheckParamsDigest(const PInterest* interest,
+                            const struct rte_mbuf* pkt);
+
 /** \brief Set active forwarding hint.
  *  \param index fwhint index, must be less than \c interest->nFhs, or -1 for none.
  *  \post interest->activeFh == index
@@ -103,7 +115,9 @@ typedef struct InterestTemplate
   uint16_t prefixL;                         ///< Name prefix length
   uint16_t midLen;                          ///< midBuffer length
   uint16_t nonceOff;                        ///< NonceV offset within midBuffer
+  uint16_t paramsDigestL;                   ///< paramsDigestV length, 0 or 34
   uint8_t prefixV[NAME_MAX_LENGTH];         ///< Name prefix
+  uint8_t paramsDigestV[34];                ///< ParamsSha256DigestComponent
   uint8_t midBuf[INTEREST_TEMPLATE_BUFLEN]; ///< "middle" field
 } InterestTemplate;
 
@@ -9,6 +9,14 @@ import (
 	"ndn-dpdk/ndn/ndntestutil"
 )
 
+// ParametersSha256DigestComponent of "2402C0C1" and "2404E0E1E2E3"
+const (
+	paramsDigestC0    = "0220 23BA53A4876C7605FB177BEFF799AAECC565D4E7C76C51766F4EB60412FB3271"
+	paramsDigestE0    = "0220 23CA541724951FBBEDF1C396849A66B2297C1C13C817BB5701501B071FF00E90"
+	paramsDigestC0Uri = "/2=%23%BA%53%A4%87%6C%76%05%FB%17%7B%EF%F7%99%AA%EC" +
+		"%C5%65%D4%E7%C7%6C%51%76%6F%4E%B6%04%12%FB%32%71"
+)
+
 func TestInterestDecode(t *testing.T) {
 	assert, _ := makeAR(t)
 
@@ -22,6 +30,7 @@ func TestInterestDecode(t *testing.T) {
 		hasNonce    bool
 		lifetime    int
 		hopLimit    uint8
+		hasParams   bool
 	}{
 		{input: "", bad: true},
 		{input: "0500", bad: true},
@@ -29,11 +38,11 @@ func TestInterestDecode(t *testing.T) {
 		{input: "0502 name=0700", bad: true},          // Name is empty
 		{input: "0508 name=0706080141080142", name: "/A/B",
 			lifetime: 4000, hopLimit: 0xFF},
-		{input: "0528 name=0706080141080142 canbeprefix=2100 mustbefresh=1200 " +
+		{input: "054A name=0728080141080142" + paramsDigestC0 + " canbeprefix=2100 mustbefresh=1200 " +
 			"fh=1E0A (del=1F08 pref=1E0100 name=0703080147) nonce=0A04A0A1A2A3 " +
-			"lifetime=0C01FF hoplimit=220120 parameters=2302C0C1",
-			name: "/A/B", canBePrefix: true, mustBeFresh: true, fhs: []string{"/G"},
-			hasNonce: true, lifetime: 255, hopLimit: 0x20},
+			"lifetime=0C01FF hoplimit=220120 parameters=2402C0C1",
+			name: "/A/B" + paramsDigestC0Uri, canBePrefix: true, mustBeFresh: true, fhs: []string{"/G"},
+			hasNonce: true, lifetime: 255, hopLimit: 0x20, hasParams: true},
 		{input: "050D name=0706080141080142 nonce=0A03A0A1A2", bad: true}, // Nonce wrong length
 		{input: "0512 name=0706080141080142 lifetime=0C080000000100000000",
 			bad: true}, // InterestLifetime too large
@@ -41,6 +50,25 @@ func TestInterestDecode(t *testing.T) {
 			bad: true}, // HopLimit wrong length
 		{input: "050B name=0706080141080142 hoplimit=220100",
 			bad: true}, // HopLimit is zero
+		{input: "0510 name=0706080141080142 unknown=3000 nonce=0A04A0A1A2A3", name: "/A/B",
+			hasNonce: true, lifetime: 4000, hopLimit: 0xFF}, // unrecognized non-critical element
+		{input: "050A name=0706080141080142 unknown=3100",
+			bad: true}, // unrecognized critical element
+		{input: "050A name=0706080141080142 parameters=2300",
+			bad: true}, // Parameters from packet format 0.2 is critical
+		{input: "0510 name=0706080141080142 nonce=0A04A0A1A2A3 canbeprefix=2100",
+			bad: true}, // out of order critical element
+		{input: "0514 name=0706080141080142 nonce=0A04A0A1A2A3 nonce=0A04A0A1A2A3",
+			bad: true}, // repeated critical element
+		{input: "0537 name=0728080141080142" + paramsDigestC0 + " nonce=0A04A0A1A2A3 parameters=2402C0C1 hoplimit=220120",
+			name: "/A/B" + paramsDigestC0Uri, hasNonce: true, lifetime: 4000,
+			hopLimit: 0xFF, hasParams: true}, // out of order non-critical element is ignored
+		{input: "050C name=0706080141080142 parameters=2402C0C1",
+			bad: true}, // ApplicationParameters without ParametersSha256DigestComponent
+		{input: "0530 name=0728080141080142" + paramsDigestC0 + " nonce=0A04A0A1A2A3",
+			bad: true}, // ParametersSha256DigestComponent without ApplicationParameters
+		{input: "050A name=07060801410201AA parameters=2400",
+			bad: true}, // ParametersSha256DigestComponent wrong length
 	}
 	for _, tt := range tests {
 		pkt := packetFromHex(tt.input)
@@ -76,6 +104,8 @@ func TestInterestDecode(t *testing.T) {
 			}
 			assert.EqualValues(tt.lifetime, interest.GetLifetime()/time.Millisecond, tt.input)
 			assert.Equal(tt.hopLimit, interest.GetHopLimit(), tt.input)
+			assert.Equal(tt.hasParams, interest.HasParams(), tt.input)
+			assert.True(interest.CheckParamsDigest(), tt.input)
 		}
 	}
 }
@@ -94,16 +124,20 @@ func TestInterestModify(t *testing.T) {
 		{"6413 pittoken=6208B0B1B2B3B4B5B6B7 payload=5007 " +
 			"0505 name=0703080141",
 			"0514 name=0703080141" + ins0},
-		{"050B name=0703080141 parameters=2304E0E1E2E3",
-			"051A name=0703080141" + ins0 + " parameters=2304E0E1E2E3"},
+		{"052D name=0725080141" + paramsDigestE0 + " parameters=2404E0E1E2E3",
+			"053C name=0725080141" + paramsDigestE0 + ins0 + " parameters=2404E0E1E2E3"},
 		{"0507 name=0703080141 cbp=2100",
 			"0516 name=0703080141 cbp=2100" + ins0},
 		{"0507 name=0703080141 mbf=1200",
 			"0516 name=0703080141 mbf=1200" + ins0},
 		{"0511 name=0703080141 fh=1E0A1F081E01000703080147",
 			"0520 name=0703080141 fh=1E0A1F081E01000703080147" + ins0},
-		{"0518 name=0703080141 nonce=0A04A0A1A2A3 lifetime=0C02C0C1 hop=220180  parameters=2304E0E1E2E3",
-			"051A name=0703080141" + ins0 + " parameters=2304E0E1E2E3"},
+		{"053A name=0725080141" + paramsDigestE0 + " nonce=0A04A0A1A2A3 lifetime=0C02C0C1 hop=220180 parameters=2404E0E1E2E3",
+			"053C name=0725080141" + paramsDigestE0 + ins0 + " parameters=2404E0E1E2E3"},
+		{"050A name=0703080141 unknown=3000 hop=220180",
+			"0516 name=0703080141 unknown=3000" + ins0},
+		{"050D name=0703080141 nonce=0A04A0A1A2A3 unknown=3000",
+			"0516 name=0703080141" + ins0 + " unknown=3000"},
 	}
 	for i, tt := range tests {
 		pkt := packetFromHex(tt.input)
@@ -124,6 +158,7 @@ func TestInterestModify(t *testing.T) {
 			defer pkt.Close()
 
 			assert.Equal(dpdktestenv.BytesFromHex(tt.output), pkt.ReadAll(), tt.input)
+			assert.True(modified.CheckParamsDigest(), tt.input)
 			if i == 0 {
 				assert.Equal(pitToken0, npkt.GetLpL3().GetPitToken(), tt.input)
 			}
@@ -153,3 +188,29 @@ func TestMakeInterest(t *testing.T) {
 		"fh=1E1A(1F0B pref=1E0400003CF1 name=0703080145)(1F0B pref=1E04000018B3 name=0703080146) "+
 		"nonce=0A04A3A2A1A0 lifetime=0C0400002328 hoplimit=22017D"), encoded2)
 }
+
+func TestInterestParams(t *testing.T) {
+	assert, require := makeAR(t)
+
+	m1 := dpdktestenv.Alloc(dpdktestenv.MPID_DIRECT)
+	interest1, e := ndn.MakeInterest(m1, "/A", uint32(0xA0A1A2A3),
+		ndn.ApplicationParameters{0xC0, 0xC1})
+	require.NoError(e)
+	defer m1.Close()
+	assert.Equal(dpdktestenv.BytesFromHex("0531 name=0725080141"+paramsDigestC0+
+		" nonce=0A04A3A2A1A0 parameters=2402C0C1"), m1.AsPacket().ReadAll())
+	assert.True(interest1.HasParams())
+	assert.True(interest1.CheckParamsDigest())
+
+	pkt2 := packetFromHex("0531 name=0725080141" + paramsDigestE0 +
+		" nonce=0A04A3A2A1A0 parameters=2402C0C1")
+	defer pkt2.AsDpdkPacket().Close()
+	require.NoError(pkt2.ParseL3(theMp))
+	interest2 := pkt2.AsInterest()
+	assert.True(interest2.HasParams())
+	assert.False(interest2.CheckParamsDigest())
+
+	var tpl ndn.InterestTemplate
+	assert.NoError(tpl.Init("/A", ndn.ApplicationParameters{0xC0, 0xC1}))
+	assert.Error(tpl.Init("/A", ndn.ApplicationParameters(make([]byte, 2*ndn.NAME_MAX_LENGTH+256))))
+}
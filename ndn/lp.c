@@ -103,6 +103,34 @@ LpHeader_FromPacket(LpHeader* lph,
         lph->l3.congMark = v;
         break;
       }
+      case TT_LpAck: {
+        if (unlikely(ele1.length != 8)) {
+          return NdnError_BadLpAck;
+        }
+        if (lph->l2.nAcks >= LP_MAX_ACKS) {
+          break;
+        }
+        MbufLoc d2;
+        TlvElement_MakeValueDecoder(&ele1, &d2);
+        rte_be64_t v = 0;
+        MbufLoc_ReadU64(&d2, &v);
+        lph->l2.acks[lph->l2.nAcks++] = rte_be_to_cpu_64(v);
+        break;
+      }
+      case TT_LpTxSequence: {
+        if (unlikely(ele1.length != 8)) {
+          return NdnError_BadLpTxSequence;
+        }
+        MbufLoc d2;
+        TlvElement_MakeValueDecoder(&ele1, &d2);
+        rte_be64_t v = 0;
+        MbufLoc_ReadU64(&d2, &v);
+        lph->l2.txSeqNum = rte_be_to_cpu_64(v);
+        if (unlikely(lph->l2.txSeqNum == 0)) {
+          return NdnError_BadLpTxSequence;
+        }
+        break;
+      }
       default:
         if (!CanIgnoreLpHeader(ele1.type)) {
           return NdnError_UnknownCriticalLpHeader;
@@ -134,6 +162,29 @@ PrependLpHeader(struct rte_mbuf* m, const LpHeader* lph, uint32_t payloadL)
   }
   uint16_t size1 = m->data_len;
 
+  typedef struct Lp8OctetF
+  {
+    uint8_t t[3];
+    uint8_t l;
+    rte_be64_t v;
+  } __rte_packed Lp8OctetF;
+
+  if (lph->l2.txSeqNum != 0) {
+    Lp8OctetF* f = (Lp8OctetF*)TlvEncoder_Prepend(en, sizeof(Lp8OctetF));
+    assert(SizeofVarNum(TT_LpTxSequence) == sizeof(f->t));
+    EncodeVarNum(f->t, TT_LpTxSequence);
+    f->l = 8;
+    *(unaligned_uint64_t*)&f->v = rte_cpu_to_be_64(lph->l2.txSeqNum);
+  }
+
+  for (int i = (int)lph->l2.nAcks - 1; i >= 0; --i) {
+    Lp8OctetF* f = (Lp8OctetF*)TlvEncoder_Prepend(en, sizeof(Lp8OctetF));
+    assert(SizeofVarNum(TT_LpAck) == sizeof(f->t));
+    EncodeVarNum(f->t, TT_LpAck);
+    f->l = 8;
+    *(unaligned_uint64_t*)&f->v = rte_cpu_to_be_64(lph->l2.acks[i]);
+  }
+
   if (lph->l2.fragIndex == 0) {
     if (lph->l3.congMark != 0) {
       typedef struct CongMarkF
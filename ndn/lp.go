@@ -43,6 +43,16 @@ func (lph *LpHeader) GetFragFields() (seqNum uint64, fragIndex uint16, fragCount
 	return uint64(lph.l2.seqNum), uint16(lph.l2.fragIndex), uint16(lph.l2.fragCount)
 }
 
+// Get link-layer reliability fields.
+// txSeqNum is zero if TxSequence is absent.
+func (lph *LpHeader) GetReliabilityFields() (txSeqNum uint64, acks []uint64) {
+	acks = make([]uint64, int(lph.l2.nAcks))
+	for i := range acks {
+		acks[i] = uint64(lph.l2.acks[i])
+	}
+	return uint64(lph.l2.txSeqNum), acks
+}
+
 func PrependLpHeader_GetHeadroom() int {
 	return int(C.PrependLpHeader_GetHeadroom())
 }
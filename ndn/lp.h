@@ -5,6 +5,12 @@
 
 #include "tlv-element.h"
 
+/** \brief Maximum number of Acks in one LpPacket.
+ *
+ *  When decoding, Acks beyond this limit are ignored.
+ */
+#define LP_MAX_ACKS 4
+
 /** \brief NDNLPv2 layer 2 fields.
  */
 typedef struct LpL2
@@ -12,6 +18,9 @@ typedef struct LpL2
   uint64_t seqNum;
   uint16_t fragIndex;
   uint16_t fragCount;
+  uint8_t nAcks;              ///< number of Acks
+  uint64_t txSeqNum;          ///< TxSequence, 0 means absent
+  uint64_t acks[LP_MAX_ACKS]; ///< acknowledged TxSequence numbers
 } LpL2;
 
 /** \brief NDNLPv2 layer 3 fields.
@@ -41,6 +50,7 @@ typedef struct LpHeader
  *  \li indexed fragmentation-reassembly
  *  \li network nack
  *  \li congestion mark
+ *  \li link-layer reliability (TxSequence and Ack)
  *
  *  This function does not check whether header fields are applicable to network layer packet type,
  *  because network layer type is unknown before reassembly. For example, it would accept Nack
@@ -50,6 +60,8 @@ typedef struct LpHeader
  *  \retval NdnError_LengthOverflow FragIndex, FragCount, NackReason, or CongestionMark
  *          number is too large to be stored in the header field.
  *  \retval NdnError_FragIndexExceedFragCount FragIndex is not less than FragCount.
+ *  \retval NdnError_BadLpTxSequence TxSequence is not 8-octet or is zero.
+ *  \retval NdnError_BadLpAck Ack is not 8-octet.
  *  \retval NdnError_LpHasTrailer found trailer fields after LpFragment.
  */
 NdnError
@@ -68,6 +80,8 @@ PrependLpHeader_GetHeadroom()
          1 + 1 + 8 +         // PitToken
          3 + 1 + 3 + 1 + 1 + // Nack
          3 + 1 + 1 +         // CongestionMark
+         LP_MAX_ACKS * 12 +  // Ack
+         3 + 1 + 8 +         // TxSequence
          1 + 5;              // Payload TL
 }
 
@@ -26,6 +26,8 @@ func TestLpHeaderDecode(t *testing.T) {
 		pitToken   uint64
 		nackReason ndn.NackReason
 		congMark   ndn.CongMark
+		txSeqNum   uint64
+		acks       []uint64
 		payloadL   int
 	}{
 		{input: "", bad: true},
@@ -52,6 +54,13 @@ func TestLpHeaderDecode(t *testing.T) {
 			fragCount: 1, nackReason: ndn.NackReason_NoRoute, payloadL: payloadInterestL},
 		{input: "640E congmark=FD03400104 payload=" + payloadInterest,
 			fragCount: 1, congMark: 4, payloadL: payloadInterestL},
+		{input: "6415 txseq=FD034808B0B1B2B3B4B5B6B7 payload=" + payloadInterest,
+			fragCount: 1, txSeqNum: 0xB0B1B2B3B4B5B6B7, payloadL: payloadInterestL},
+		{input: "640C txseq=FD0348080000000000000000", bad: true}, // TxSequence is zero
+		{input: "6408 txseq=FD034804B0B1B2B3", bad: true},         // TxSequence is not 8-octet
+		{input: "6418 ack=FD034408C0C1C2C3C4C5C6C7 ack=FD034408D0D1D2D3D4D5D6D7",
+			fragCount: 1, acks: []uint64{0xC0C1C2C3C4C5C6C7, 0xD0D1D2D3D4D5D6D7}},
+		{input: "6406 ack=FD034402C0C1", bad: true}, // Ack is not 8-octet
 	}
 	for _, tt := range tests {
 		pkt := packetFromHex(tt.input)
@@ -71,6 +80,13 @@ func TestLpHeaderDecode(t *testing.T) {
 			assert.Equal(tt.pitToken, lph.GetPitToken(), tt.input)
 			assert.Equal(tt.nackReason, lph.GetNackReason(), tt.input)
 			assert.Equal(tt.congMark, lph.GetCongMark(), tt.input)
+			txSeqNum, acks := lph.GetReliabilityFields()
+			assert.Equal(tt.txSeqNum, txSeqNum, tt.input)
+			if tt.acks == nil {
+				assert.Len(acks, 0, tt.input)
+			} else {
+				assert.Equal(tt.acks, acks, tt.input)
+			}
 			assert.Equal(tt.payloadL, pkt.AsDpdkPacket().Len(), tt.input)
 		}
 	}
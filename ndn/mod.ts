@@ -1,5 +1,14 @@
 export type Name = string;
 
+export interface SignerConfig {
+  Type: "sha256"|"hmac"|"ecdsa";
+  KeyName?: Name;
+  /**
+   * HMAC secret key, or ECDSA P-256 private key in PKCS#8 or SEC1 format; base64 encoded.
+   */
+  Key?: string;
+}
+
 export enum NackReason {
   None = 0,
   Congestion = 50,
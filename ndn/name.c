@@ -78,6 +78,7 @@ PName_Parse(PName* n, uint32_t length, const uint8_t* value)
   n->nOctets = length;
   n->nComps = 0;
   n->hasDigestComp = false;
+  n->hasParamsDigest = false;
   n->hasHashes = false;
 
   uint32_t off = 0;
@@ -109,6 +110,14 @@ PName_Parse(PName* n, uint32_t length, const uint8_t* value)
         return NdnError_BadDigestComponentLength;
       }
       n->hasDigestComp = true;
+    } else if (unlikely(compT == TT_ParametersSha256DigestComponent)) {
+      if (unlikely(compL != 32)) {
+        return NdnError_BadDigestComponentLength;
+      }
+      if (unlikely(n->hasParamsDigest)) {
+        return NdnError_MultipleParamsDigest;
+      }
+      n->hasParamsDigest = true;
     }
 
     if (likely(n->nComps < PNAME_N_CACHED_COMPS)) {
@@ -65,9 +65,10 @@ LName_ToString(LName n, char* buf, size_t bufsz);
  */
 typedef struct PName
 {
-  uint16_t nOctets;   ///< TLV-LENGTH of Name element
-  uint16_t nComps;    ///< number of components
-  bool hasDigestComp; ///< ends with digest component?
+  uint16_t nOctets;     ///< TLV-LENGTH of Name element
+  uint16_t nComps;      ///< number of components
+  bool hasDigestComp;   ///< ends with digest component?
+  bool hasParamsDigest; ///< has ParametersSha256DigestComponent?
 
   bool hasHashes;                      ///< (pvt) are hash[i] computed?
   uint16_t comp[PNAME_N_CACHED_COMPS]; ///< (pvt) end offset of i-th component
@@ -27,6 +27,9 @@ func TestNameDecode(t *testing.T) {
 		{input: "0120(DC6D6840C6FAFB773D583CDBF465661C7B4B968E04ACD4D9015B1C4E53E59D6A)",
 			nComps: 1, hasDigest: true},
 		{input: "0102 DDDD", err: ndn.NdnError_BadDigestComponentLength},
+		{input: "0220(DC6D6840C6FAFB773D583CDBF465661C7B4B968E04ACD4D9015B1C4E53E59D6A) " +
+			"0220(DC6D6840C6FAFB773D583CDBF465661C7B4B968E04ACD4D9015B1C4E53E59D6A)",
+			err: ndn.NdnError_MultipleParamsDigest},
 	}
 	for _, tt := range tests {
 		n, e := ndn.NewName(TlvBytesFromHex(tt.input))
@@ -0,0 +1,175 @@
+#include "signature.h"
+
+static NdnError
+PSignature_ParseSigInfo(PSignature* sig,
+                        const TlvElement* sigInfoEle,
+                        uint8_t* keyNameV)
+{
+  MbufLoc d2;
+  TlvElement_MakeValueDecoder(sigInfoEle, &d2);
+  TlvElement typeEle;
+  NdnError e = TlvElement_Decode(&typeEle, &d2, TT_SignatureType);
+  if (unlikely(e != NdnError_OK)) {
+    return NdnError_BadSigInfo;
+  }
+  uint64_t sigType;
+  e = TlvElement_ReadNonNegativeInteger(&typeEle, &sigType);
+  if (unlikely(e != NdnError_OK || sigType > UINT8_MAX)) {
+    return NdnError_BadSigInfo;
+  }
+  sig->sigType = (uint8_t)sigType;
+
+  sig->keyNameL = 0;
+  while (!MbufLoc_IsEnd(&d2)) {
+    TlvElement ele2;
+    e = TlvElement_Decode(&ele2, &d2, TT_Invalid);
+    RETURN_IF_ERROR;
+    if (ele2.type != TT_KeyLocator) {
+      continue;
+    }
+
+    MbufLoc d3;
+    TlvElement_MakeValueDecoder(&ele2, &d3);
+    TlvElement nameEle;
+    e = TlvElement_Decode(&nameEle, &d3, TT_Invalid);
+    if (unlikely(e != NdnError_OK)) {
+      return NdnError_BadSigInfo;
+    }
+    if (nameEle.type != TT_Name) {
+      break; // KeyDigest is not supported
+    }
+    if (unlikely(nameEle.length > NAME_MAX_LENGTH)) {
+      return NdnError_NameTooLong;
+    }
+    MbufLoc_ReadTo(&nameEle.value, keyNameV, nameEle.length);
+    sig->keyNameL = nameEle.length;
+    break;
+  }
+  return NdnError_OK;
+}
+
+static NdnError
+PSignature_ParseSigValue(PSignature* sig, const TlvElement* sigValueEle)
+{
+  if (unlikely(sigValueEle->length > SIG_MAX_VALUE_LENGTH)) {
+    return NdnError_BadSigValue;
+  }
+  MbufLoc vd;
+  TlvElement_MakeValueDecoder(sigValueEle, &vd);
+  MbufLoc_ReadTo(&vd, sig->value, sigValueEle->length);
+  sig->valueL = sigValueEle->length;
+  return NdnError_OK;
+}
+
+NdnError
+PSignature_FromData(PSignature* sig, struct rte_mbuf* pkt, uint8_t* keyNameV)
+{
+  MbufLoc d0;
+  MbufLoc_Init(&d0, pkt);
+  TlvElement dataEle;
+  NdnError e = TlvElement_Decode(&dataEle, &d0, TT_Data);
+  RETURN_IF_ERROR;
+
+  // offset within packet of an element decoded from d1
+#define OFFSET_OF(ele) (dataEle.size - (ele).first.rem)
+
+  MbufLoc d1;
+  TlvElement_MakeValueDecoder(&dataEle, &d1);
+  TlvElement ele1;
+  e = TlvElement_Decode(&ele1, &d1, TT_Name);
+  RETURN_IF_ERROR;
+  sig->signedOff[0] = OFFSET_OF(ele1);
+  sig->signedOff[1] = 0;
+  sig->signedLen[1] = 0;
+
+  bool hasSigInfo = false;
+  while (!MbufLoc_IsEnd(&d1)) {
+    e = TlvElement_Decode(&ele1, &d1, TT_Invalid);
+    RETURN_IF_ERROR;
+    switch (ele1.type) {
+      case TT_SignatureInfo:
+        e = PSignature_ParseSigInfo(sig, &ele1, keyNameV);
+        RETURN_IF_ERROR;
+        sig->signedLen[0] = OFFSET_OF(ele1) + ele1.size - sig->signedOff[0];
+        hasSigInfo = true;
+        break;
+      case TT_SignatureValue:
+        if (unlikely(!hasSigInfo)) {
+          return NdnError_SigMissing;
+        }
+        return PSignature_ParseSigValue(sig, &ele1);
+    }
+  }
+  return NdnError_SigMissing;
+#undef OFFSET_OF
+}
+
+NdnError
+PSignature_FromInterest(PSignature* sig,
+                        struct rte_mbuf* pkt,
+                        uint8_t* keyNameV)
+{
+  MbufLoc d0;
+  MbufLoc_Init(&d0, pkt);
+  TlvElement interestEle;
+  NdnError e = TlvElement_Decode(&interestEle, &d0, TT_Interest);
+  RETURN_IF_ERROR;
+
+  // offset within packet of an element decoded from d1
+#define OFFSET_OF(ele) (interestEle.size - (ele).first.rem)
+
+  MbufLoc d1;
+  TlvElement_MakeValueDecoder(&interestEle, &d1);
+  TlvElement nameEle;
+  e = TlvElement_Decode(&nameEle, &d1, TT_Name);
+  RETURN_IF_ERROR;
+
+  // signed portion includes name components except ParametersSha256DigestComponent,
+  // which must be the last component
+  uint32_t nameValueOff = OFFSET_OF(nameEle) + nameEle.size - nameEle.length;
+  sig->signedOff[0] = nameValueOff;
+  sig->signedLen[0] = nameEle.length;
+  MbufLoc d2;
+  TlvElement_MakeValueDecoder(&nameEle, &d2);
+  while (!MbufLoc_IsEnd(&d2)) {
+    TlvElement compEle;
+    e = TlvElement_Decode(&compEle, &d2, TT_Invalid);
+    RETURN_IF_ERROR;
+    if (compEle.type == TT_ParametersSha256DigestComponent) {
+      if (unlikely(!MbufLoc_IsEnd(&d2))) {
+        return NdnError_BadSigInfo;
+      }
+      sig->signedLen[0] = nameEle.length - compEle.size;
+    }
+  }
+
+  // signed portion includes ApplicationParameters through InterestSignatureInfo
+  bool hasSigInfo = false;
+  sig->signedOff[1] = 0;
+  while (!MbufLoc_IsEnd(&d1)) {
+    TlvElement ele1;
+    e = TlvElement_Decode(&ele1, &d1, TT_Invalid);
+    RETURN_IF_ERROR;
+    switch (ele1.type) {
+      case TT_ApplicationParameters:
+        sig->signedOff[1] = OFFSET_OF(ele1);
+        break;
+      case TT_InterestSignatureInfo:
+        if (unlikely(sig->signedOff[1] == 0)) {
+          return NdnError_BadSigInfo;
+        }
+        e = PSignature_ParseSigInfo(sig, &ele1, keyNameV);
+        RETURN_IF_ERROR;
+        sig->signedLen[1] = OFFSET_OF(ele1) + ele1.size - sig->signedOff[1];
+        hasSigInfo = true;
+        break;
+      case TT_InterestSignatureValue:
+        if (unlikely(!hasSigInfo)) {
+          return NdnError_SigMissing;
+        }
+        return PSignature_ParseSigValue(sig, &ele1);
+    }
+  }
+  return NdnError_SigMissing;
+#undef OFFSET_OF
+}
@@ -0,0 +1,66 @@
+#ifndef NDN_DPDK_NDN_SIGNATURE_H
+#define NDN_DPDK_NDN_SIGNATURE_H
+
+/// \file
+
+#include "name.h"
+
+/** \brief SignatureType values.
+ */
+typedef enum SigType
+{
+  SigType_Sha256 = 0,
+  SigType_Sha256WithRsa = 1,
+  SigType_Sha256WithEcdsa = 3,
+  SigType_HmacWithSha256 = 4,
+} SigType;
+
+/** \brief Maximum supported SignatureValue TLV-LENGTH.
+ *
+ *  This is enough for DER-encoded ECDSA P-256 signature and HMAC-SHA256.
+ */
+#define SIG_MAX_VALUE_LENGTH 72
+
+/** \brief Parsed signature of a Data or a signed Interest.
+ */
+typedef struct PSignature
+{
+  uint32_t signedOff[2]; ///< offsets of signed portion ranges within packet
+  uint32_t signedLen[2]; ///< lengths of signed portion ranges; Data has one range
+  uint16_t keyNameL;     ///< KeyLocator Name TLV-LENGTH, 0 if absent
+  uint8_t sigType;       ///< SignatureType
+  uint8_t valueL;        ///< SignatureValue TLV-LENGTH
+  uint8_t value[SIG_MAX_VALUE_LENGTH]; ///< SignatureValue TLV-VALUE
+} PSignature;
+
+/** \brief Parse signature of a Data packet.
+ *  \param[out] sig the parsed signature.
+ *  \param pkt the Data packet.
+ *  \param[out] keyNameV buffer for KeyLocator Name TLV-VALUE,
+ *                       must have \c NAME_MAX_LENGTH room.
+ *  \retval NdnError_SigMissing SignatureInfo or SignatureValue is missing.
+ */
+NdnError
+PSignature_FromData(PSignature* sig, struct rte_mbuf* pkt, uint8_t* keyNameV);
+
+/** \brief Parse signature of a signed Interest.
+ *  \param[out] sig the parsed signature.
+ *  \param pkt the Interest packet.
+ *  \param[out] keyNameV buffer for KeyLocator Name TLV-VALUE,
+ *                       must have \c NAME_MAX_LENGTH room.
+ *  \retval NdnError_SigMissing Interest is not signed.
+ */
+NdnError
+PSignature_FromInterest(PSignature* sig,
+                        struct rte_mbuf* pkt,
+                        uint8_t* keyNameV);
+
+/** \brief Get total length of signed portion.
+ */
+static inline uint32_t
+PSignature_GetSignedLength(const PSignature* sig)
+{
+  return sig->signedLen[0] + sig->signedLen[1];
+}
+
+#endif // NDN_DPDK_NDN_SIGNATURE_H
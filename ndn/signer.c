@@ -0,0 +1,150 @@
+#include "signer.h"
+#include "tlv-varnum.h"
+
+#include <openssl/bn.h>
+#include <openssl/ec.h>
+#include <openssl/ecdsa.h>
+#include <openssl/obj_mac.h>
+#include <openssl/sha.h>
+
+void
+Signer_Init(Signer* signer,
+            SigType sigType,
+            uint16_t keyNameL,
+            const uint8_t* keyNameV)
+{
+  signer->sigType = sigType;
+  switch (sigType) {
+    case SigType_Sha256:
+    case SigType_HmacWithSha256:
+      signer->maxValueL = SHA256_DIGEST_LENGTH;
+      break;
+    default:
+      signer->maxValueL = SIG_MAX_VALUE_LENGTH;
+      break;
+  }
+
+  uint32_t sigTypeSize = 1 + 1 + 1;
+  uint32_t nameSize = 0, klSize = 0;
+  if (keyNameL > 0) {
+    nameSize = 1 + SizeofVarNum(keyNameL) + keyNameL;
+    klSize = 1 + SizeofVarNum(nameSize) + nameSize;
+  }
+  uint32_t sigInfoValueL = sigTypeSize + klSize;
+
+  uint8_t* p = signer->sigInfo;
+  p = EncodeVarNum(p, TT_SignatureInfo);
+  p = EncodeVarNum(p, sigInfoValueL);
+  p = EncodeVarNum(p, TT_SignatureType);
+  p = EncodeVarNum(p, 1);
+  *p++ = sigType;
+  if (keyNameL > 0) {
+    p = EncodeVarNum(p, TT_KeyLocator);
+    p = EncodeVarNum(p, nameSize);
+    p = EncodeVarNum(p, TT_Name);
+    p = EncodeVarNum(p, keyNameL);
+    rte_memcpy(p, keyNameV, keyNameL);
+    p += keyNameL;
+  }
+  signer->sigInfoL = p - signer->sigInfo;
+}
+
+void
+Signer_SetHmacKey(Signer* signer, const uint8_t* key, size_t keyLen)
+{
+  uint8_t keyBlock[SIGNER_HMAC_BLOCK_SIZE] = { 0 };
+  if (keyLen > SIGNER_HMAC_BLOCK_SIZE) {
+    SHA256(key, keyLen, keyBlock);
+  } else {
+    rte_memcpy(keyBlock, key, keyLen);
+  }
+
+  for (int i = 0; i < SIGNER_HMAC_BLOCK_SIZE; ++i) {
+    signer->hmacIpad[i] = keyBlock[i] ^ 0x36;
+    signer->hmacOpad[i] = keyBlock[i] ^ 0x5C;
+  }
+}
+
+bool
+Signer_SetEcdsaKey(Signer* signer, const uint8_t* priv, size_t privLen)
+{
+  Signer_Clear(signer);
+  EC_KEY* key = EC_KEY_new_by_curve_name(NID_X9_62_prime256v1);
+  if (key == NULL) {
+    return false;
+  }
+  BIGNUM* d = BN_bin2bn(priv, privLen, NULL);
+  if (d == NULL || EC_KEY_set_private_key(key, d) != 1) {
+    BN_free(d);
+    EC_KEY_free(key);
+    return false;
+  }
+  BN_free(d);
+  signer->ecKey = key;
+  return true;
+}
+
+void
+Signer_Clear(Signer* signer)
+{
+  if (signer->ecKey != NULL) {
+    EC_KEY_free(signer->ecKey);
+    signer->ecKey = NULL;
+  }
+}
+
+static void
+Signer_DigestMbuf(SHA256_CTX* ctx, const struct rte_mbuf* pkt)
+{
+  for (const struct rte_mbuf* m = pkt; m != NULL; m = m->next) {
+    SHA256_Update(ctx, rte_pktmbuf_mtod(m, const uint8_t*), m->data_len);
+  }
+}
+
+uint8_t
+Signer_Sign(const Signer* signer, const struct rte_mbuf* pkt, uint8_t* value)
+{
+  SHA256_CTX ctx;
+  SHA256_Init(&ctx);
+
+  switch (signer->sigType) {
+    case SigType_Sha256:
+      Signer_DigestMbuf(&ctx, pkt);
+      SHA256_Final(value, &ctx);
+      return SHA256_DIGEST_LENGTH;
+
+    case SigType_HmacWithSha256: {
+      uint8_t inner[SHA256_DIGEST_LENGTH];
+      SHA256_Update(&ctx, signer->hmacIpad, SIGNER_HMAC_BLOCK_SIZE);
+      Signer_DigestMbuf(&ctx, pkt);
+      SHA256_Final(inner, &ctx);
+
+      SHA256_Init(&ctx);
+      SHA256_Update(&ctx, signer->hmacOpad, SIGNER_HMAC_BLOCK_SIZE);
+      SHA256_Update(&ctx, inner, sizeof(inner));
+      SHA256_Final(value, &ctx);
+      return SHA256_DIGEST_LENGTH;
+    }
+
+    case SigType_Sha256WithEcdsa: {
+      uint8_t digest[SHA256_DIGEST_LENGTH];
+      Signer_DigestMbuf(&ctx, pkt);
+      SHA256_Final(digest, &ctx);
+
+      unsigned int sigLen = signer->maxValueL;
+      if (unlikely(signer->ecKey == NULL ||
+                   ECDSA_sign(0,
+                              digest,
+                              sizeof(digest),
+                              value,
+                              &sigLen,
+                              signer->ecKey) != 1)) {
+        return 0;
+      }
+      return sigLen;
+    }
+
+    default:
+      return 0;
+  }
+}
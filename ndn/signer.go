@@ -0,0 +1,107 @@
+package ndn
+
+/*
+#include "signer.h"
+*/
+import "C"
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"ndn-dpdk/dpdk"
+)
+
+// Signer types.
+const (
+	Signer_Sha256 = "sha256"
+	Signer_Hmac   = "hmac"
+	Signer_Ecdsa  = "ecdsa"
+)
+
+// Signer config.
+type SignerConfig struct {
+	Type    string // Signer_Sha256, Signer_Hmac, or Signer_Ecdsa
+	KeyName *Name  // KeyLocator name, ignored for Signer_Sha256
+	Key     []byte // HMAC secret key, or ECDSA P-256 private key in PKCS#8 or SEC1 format
+}
+
+// Packet signer.
+type Signer struct {
+	c *C.Signer
+}
+
+func NewSigner(cfg SignerConfig, socket dpdk.NumaSocket) (signer *Signer, e error) {
+	var sigType C.SigType
+	switch cfg.Type {
+	case Signer_Sha256:
+		sigType = C.SigType_Sha256
+		cfg.KeyName = nil
+	case Signer_Hmac:
+		sigType = C.SigType_HmacWithSha256
+	case Signer_Ecdsa:
+		sigType = C.SigType_Sha256WithEcdsa
+	default:
+		return nil, fmt.Errorf("unknown signer type %s", cfg.Type)
+	}
+	if cfg.KeyName.Size() > NAME_MAX_LENGTH {
+		return nil, errors.New("KeyName too long")
+	}
+
+	signer = new(Signer)
+	signer.c = (*C.Signer)(dpdk.Zmalloc("Signer", C.sizeof_Signer, socket))
+	C.Signer_Init(signer.c, sigType, C.uint16_t(cfg.KeyName.Size()), cfg.KeyName.getValuePtr())
+
+	switch sigType {
+	case C.SigType_HmacWithSha256:
+		if len(cfg.Key) == 0 {
+			signer.Close()
+			return nil, errors.New("HMAC key is empty")
+		}
+		C.Signer_SetHmacKey(signer.c, (*C.uint8_t)(unsafe.Pointer(&cfg.Key[0])), C.size_t(len(cfg.Key)))
+	case C.SigType_Sha256WithEcdsa:
+		priv, e := parseEcdsaPrivateKey(cfg.Key)
+		if e != nil {
+			signer.Close()
+			return nil, e
+		}
+		d := priv.D.Bytes()
+		if len(d) == 0 || !bool(C.Signer_SetEcdsaKey(signer.c, (*C.uint8_t)(unsafe.Pointer(&d[0])), C.size_t(len(d)))) {
+			signer.Close()
+			return nil, errors.New("Signer_SetEcdsaKey error")
+		}
+	}
+	return signer, nil
+}
+
+func parseEcdsaPrivateKey(der []byte) (priv *ecdsa.PrivateKey, e error) {
+	if key, e := x509.ParsePKCS8PrivateKey(der); e == nil {
+		var ok bool
+		if priv, ok = key.(*ecdsa.PrivateKey); !ok {
+			return nil, errors.New("key is not ECDSA")
+		}
+	} else if priv, e = x509.ParseECPrivateKey(der); e != nil {
+		return nil, e
+	}
+	if priv.Curve != elliptic.P256() {
+		return nil, errors.New("key is not on P-256 curve")
+	}
+	return priv, nil
+}
+
+// Get native *C.Signer pointer, or nil if signer is nil.
+func (signer *Signer) GetPtr() unsafe.Pointer {
+	if signer == nil {
+		return nil
+	}
+	return unsafe.Pointer(signer.c)
+}
+
+func (signer *Signer) Close() error {
+	C.Signer_Clear(signer.c)
+	dpdk.Free(signer.c)
+	return nil
+}
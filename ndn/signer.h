@@ -0,0 +1,87 @@
+#ifndef NDN_DPDK_NDN_SIGNER_H
+#define NDN_DPDK_NDN_SIGNER_H
+
+/// \file
+
+#include "signature.h"
+
+/** \brief Maximum encoded size of SignatureInfo produced by a Signer.
+ */
+#define SIGNER_MAX_SIGINFO_LENGTH                                              \
+  (1 + 3 + 1 + 1 + 1 + 1 + 3 + 1 + 3 + NAME_MAX_LENGTH)
+
+/** \brief Maximum encoded size of SignatureValue produced by a Signer.
+ */
+#define SIGNER_MAX_SIGVALUE_LENGTH (1 + 1 + SIG_MAX_VALUE_LENGTH)
+
+#define SIGNER_HMAC_BLOCK_SIZE 64
+
+/** \brief Packet signer.
+ *
+ *  Signer supports DigestSha256, SignatureHmacWithSha256, and
+ *  SignatureSha256WithEcdsa with P-256 curve.
+ */
+typedef struct Signer
+{
+  uint8_t sigType;   ///< SignatureType
+  uint8_t maxValueL; ///< maximum SignatureValue TLV-LENGTH
+  uint16_t sigInfoL; ///< encoded SignatureInfo size
+  void* ecKey;       ///< EC_KEY* for ECDSA
+
+  uint8_t hmacIpad[SIGNER_HMAC_BLOCK_SIZE]; ///< HMAC key XOR ipad
+  uint8_t hmacOpad[SIGNER_HMAC_BLOCK_SIZE]; ///< HMAC key XOR opad
+
+  uint8_t sigInfo[SIGNER_MAX_SIGINFO_LENGTH]; ///< encoded SignatureInfo
+} Signer;
+
+/** \brief Initialize a Signer and encode its SignatureInfo.
+ *  \param keyNameL KeyLocator Name TLV-LENGTH; zero omits KeyLocator.
+ *  \param keyNameV KeyLocator Name TLV-VALUE.
+ *  \pre signer is zeroed.
+ */
+void
+Signer_Init(Signer* signer,
+            SigType sigType,
+            uint16_t keyNameL,
+            const uint8_t* keyNameV);
+
+/** \brief Set HMAC-SHA256 secret key.
+ *  \pre signer->sigType == SigType_HmacWithSha256
+ */
+void
+Signer_SetHmacKey(Signer* signer, const uint8_t* key, size_t keyLen);
+
+/** \brief Set ECDSA P-256 private key.
+ *  \param priv private key as big endian integer.
+ *  \pre signer->sigType == SigType_Sha256WithEcdsa
+ */
+bool
+Signer_SetEcdsaKey(Signer* signer, const uint8_t* priv, size_t privLen);
+
+/** \brief Release keys held by a Signer.
+ */
+void
+Signer_Clear(Signer* signer);
+
+/** \brief Get required room for SignatureInfo and SignatureValue.
+ *  \param signer the signer, or NULL for fake DigestSha256 signature.
+ */
+static inline uint16_t
+Signer_GetTailroom(const Signer* signer)
+{
+  if (signer == NULL) {
+    return 5 + 2 + 32;
+  }
+  return signer->sigInfoL + 2 + signer->maxValueL;
+}
+
+/** \brief Compute signature over the whole packet.
+ *  \param pkt signed portion, may have multiple segments.
+ *  \param[out] value SignatureValue TLV-VALUE, must have
+ *                    \c signer->maxValueL room.
+ *  \return SignatureValue TLV-LENGTH, or 0 on failure.
+ */
+uint8_t
+Signer_Sign(const Signer* signer, const struct rte_mbuf* pkt, uint8_t* value);
+
+#endif // NDN_DPDK_NDN_SIGNER_H
@@ -6,6 +6,18 @@
 #include "tlv-type.h"
 #include "tlv-varnum.h"
 
+/** \brief Determine whether a TLV-TYPE is critical.
+ *
+ *  Per NDN packet format 0.3 evolvability rules, an unrecognized or
+ *  out-of-order element must cause the packet to be rejected if its TLV-TYPE
+ *  is critical, and should be ignored otherwise.
+ */
+static inline bool
+TlvType_IsCritical(uint32_t type)
+{
+  return type <= 31 || (type & 0x01) != 0;
+}
+
 /** \brief TLV element
  */
 typedef struct TlvElement